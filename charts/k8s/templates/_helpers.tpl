@@ -51,10 +51,16 @@ Whether to create a cluster role or not
         ((index .Values.sync "legacy-storageclasses") | default (dict "enabled" false))
     "enabled")
     (include "vcluster.syncIngressclassesEnabled" . )
+    (include "vcluster.syncGatewayAPIEnabled" . )
+    (include "vcluster.syncIstioEnabled" . )
+    .Values.sync.pods.openshift
+    .Values.sync.pods.waitForHostCapacity
+    .Values.sync.services.nodePortEndpoints
     .Values.sync.nodes.enabled
     .Values.sync.persistentvolumes.enabled
     .Values.sync.storageclasses.enabled
     .Values.sync.priorityclasses.enabled
+    .Values.sync.runtimeclasses.enabled
     .Values.sync.volumesnapshots.enabled
     .Values.proxy.metricsServer.nodes.enabled
     .Values.multiNamespaceMode.enabled -}}
@@ -62,6 +68,29 @@ Whether to create a cluster role or not
 {{- end -}}
 {{- end -}}
 
+{{/*
+Whether the gateway api syncers should be enabled
+*/}}
+{{- define "vcluster.syncGatewayAPIEnabled" -}}
+{{- if or
+    .Values.sync.gateways.enabled
+    .Values.sync.httproutes.enabled
+    .Values.sync.grpcroutes.enabled -}}
+    {{- true -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+Whether the istio syncers should be enabled
+*/}}
+{{- define "vcluster.syncIstioEnabled" -}}
+{{- if or
+    .Values.sync.virtualservices.enabled
+    .Values.sync.destinationrules.enabled -}}
+    {{- true -}}
+{{- end -}}
+{{- end -}}
+
 {{- define "vcluster.clusterRoleName" -}}
 {{- printf "vc-%s-v-%s" .Release.Name .Release.Namespace | trunc 63 | trimSuffix "-" -}}
 {{- end -}}
@@ -189,3 +218,87 @@ Host cluster service mapping
 - '--map-host-service={{ $value.from }}={{ $value.to }}'
 {{- end }}
 {{- end -}}
+
+{{/*
+Egress selector configuration of the virtual api server, which dials its connections
+to the cluster, e.g. to webhooks, aggregated apis and kubelets, through the tunnel server
+*/}}
+{{- define "vcluster.tunnel.egressSelectorConfiguration" -}}
+apiVersion: apiserver.k8s.io/v1beta1
+kind: EgressSelectorConfiguration
+egressSelections:
+- name: cluster
+  connection:
+    proxyProtocol: HTTPConnect
+    transport:
+      tcp:
+        {{- if .Values.tunnel.secretName }}
+        url: https://{{ .Values.tunnel.server }}
+        tlsConfig:
+          caBundle: /run/vcluster-tunnel/certs/ca.crt
+          clientCert: /run/vcluster-tunnel/certs/tls.crt
+          clientKey: /run/vcluster-tunnel/certs/tls.key
+        {{- else }}
+        url: http://{{ .Values.tunnel.server }}
+        {{- end }}
+{{- end -}}
+
+{{/*
+Syncer args of the tunnel server
+*/}}
+{{- define "vcluster.tunnel.syncerArgs" -}}
+{{- if .Values.tunnel.server }}
+- --tunnel-server={{ .Values.tunnel.server }}
+{{- if .Values.tunnel.secretName }}
+- --tunnel-ca-file=/run/vcluster-tunnel/certs/ca.crt
+- --tunnel-cert-file=/run/vcluster-tunnel/certs/tls.crt
+- --tunnel-key-file=/run/vcluster-tunnel/certs/tls.key
+{{- end }}
+{{- end }}
+{{- end -}}
+
+{{/*
+Volume of the tunnel certificates
+*/}}
+{{- define "vcluster.tunnel.certsVolumes" -}}
+{{- if and .Values.tunnel.server .Values.tunnel.secretName }}
+- name: tunnel-certs
+  secret:
+    secretName: {{ .Values.tunnel.secretName }}
+{{- end }}
+{{- end -}}
+
+{{/*
+Volumes of the virtual api server for the tunnel
+*/}}
+{{- define "vcluster.tunnel.apiServerVolumes" -}}
+{{- if .Values.tunnel.server }}
+- name: tunnel-config
+  configMap:
+    name: {{ .Release.Name }}-tunnel
+{{- end }}
+{{- include "vcluster.tunnel.certsVolumes" . }}
+{{- end -}}
+
+{{/*
+Volume mounts of the tunnel certificates
+*/}}
+{{- define "vcluster.tunnel.certsVolumeMounts" -}}
+{{- if and .Values.tunnel.server .Values.tunnel.secretName }}
+- name: tunnel-certs
+  mountPath: /run/vcluster-tunnel/certs
+  readOnly: true
+{{- end }}
+{{- end -}}
+
+{{/*
+Volume mounts of the virtual api server for the tunnel
+*/}}
+{{- define "vcluster.tunnel.apiServerVolumeMounts" -}}
+{{- if .Values.tunnel.server }}
+- name: tunnel-config
+  mountPath: /run/vcluster-tunnel/config
+  readOnly: true
+{{- end }}
+{{- include "vcluster.tunnel.certsVolumeMounts" . }}
+{{- end -}}
@@ -16,6 +16,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -118,9 +119,25 @@ func ExecuteCerts(ctx context.Context, options *CertsCmd) error {
 	}
 
 	secretName := options.Prefix + "-certs"
-	_, err = kubeClient.CoreV1().Secrets(options.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	existingSecret, err := kubeClient.CoreV1().Secrets(options.Namespace).Get(ctx, secretName, metav1.GetOptions{})
 	if err == nil {
-		klog.Infof("Certs secret already exists, skip generation")
+		// secrets of older versions don't contain the scheduler client credentials yet
+		if len(existingSecret.Data[certs.SchedulerClientCertName]) > 0 || len(existingSecret.Data[certs.SchedulerKubeConfigFileName]) == 0 {
+			klog.Infof("Certs secret already exists, skip generation")
+			return nil
+		}
+
+		err = addSchedulerClientCerts(existingSecret)
+		if err != nil {
+			return err
+		}
+
+		_, err = kubeClient.CoreV1().Secrets(options.Namespace).Update(ctx, existingSecret, metav1.UpdateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "update certs secret")
+		}
+
+		klog.Infof("Added scheduler client credentials to certs secret %s/%s", options.Namespace, secretName)
 		return nil
 	}
 
@@ -175,6 +192,10 @@ func ExecuteCerts(ctx context.Context, options *CertsCmd) error {
 
 		secret.Data[toName] = data
 	}
+	err = addSchedulerClientCerts(secret)
+	if err != nil {
+		return err
+	}
 
 	// finally create the secret
 	_, err = kubeClient.CoreV1().Secrets(options.Namespace).Create(ctx, secret, metav1.CreateOptions{})
@@ -185,3 +206,24 @@ func ExecuteCerts(ctx context.Context, options *CertsCmd) error {
 	klog.Infof("Successfully created certs secret %s/%s", options.Namespace, secretName)
 	return nil
 }
+
+// addSchedulerClientCerts stores the client credentials of the scheduler's kubeconfig as separate files, so that the
+// scheduler can also present them to the scheduler extender of the syncer
+func addSchedulerClientCerts(secret *corev1.Secret) error {
+	config, err := clientcmd.Load(secret.Data[certs.SchedulerKubeConfigFileName])
+	if err != nil {
+		return errors.Wrap(err, "load scheduler kube config")
+	}
+
+	for _, authInfo := range config.AuthInfos {
+		if len(authInfo.ClientCertificateData) == 0 || len(authInfo.ClientKeyData) == 0 {
+			continue
+		}
+
+		secret.Data[certs.SchedulerClientCertName] = authInfo.ClientCertificateData
+		secret.Data[certs.SchedulerClientKeyName] = authInfo.ClientKeyData
+		return nil
+	}
+
+	return errors.New("scheduler kube config has no client certificate")
+}
@@ -39,6 +39,11 @@ const (
 
 	// naming format <pod_name>_<namespace>_<container_name>-<containerdID(hash, with <docker/cri>:// prefix removed)>.log
 	ContainerSymlinkSourceTemplate = "%s_%s_%s-%s.log"
+
+	// DockerRuntimePrefix is the container id prefix used by the docker runtime. Only docker
+	// keeps its own container log directory, containerd (containerd://) and cri-o (cri-o://)
+	// write their logs to /var/log/pods only, which is covered by the pod log symlinks
+	DockerRuntimePrefix = "docker"
 )
 
 func podNodeIndexer(obj client.Object) []string {
@@ -84,6 +89,7 @@ func NewHostpathMapperCommand() *cobra.Command {
 	cmd.Flags().StringVar(&options.TargetNamespace, "target-namespace", "", "The namespace to run the virtual cluster in (defaults to current namespace)")
 
 	cmd.Flags().StringVar(&options.Name, "name", "vcluster", "The name of the virtual cluster")
+	cmd.Flags().BoolVar(&options.MapDockerContainerLogs, "map-docker-container-logs", false, "If enabled, the docker container directories (/var/lib/docker/containers) of the virtual pods will be mapped as well. Only needed on nodes using the docker runtime")
 
 	return cmd
 }
@@ -106,6 +112,7 @@ func MapHostPaths(ctx context.Context, options *context2.VirtualClusterOptions)
 	options.VirtualLogsPath = filepath.Join(virtualPath, "log")
 	options.VirtualPodLogsPath = filepath.Join(options.VirtualLogsPath, "pods")
 	options.VirtualContainerLogsPath = filepath.Join(options.VirtualLogsPath, "containers")
+	options.VirtualDockerContainersPath = filepath.Join(virtualPath, "docker", "containers")
 	err = os.Mkdir(options.VirtualContainerLogsPath, os.ModeDir)
 	if err != nil {
 		if !os.IsExist(err) {
@@ -113,6 +120,13 @@ func MapHostPaths(ctx context.Context, options *context2.VirtualClusterOptions)
 			return err
 		}
 	}
+	if options.MapDockerContainerLogs {
+		err = os.MkdirAll(options.VirtualDockerContainersPath, os.ModeDir)
+		if err != nil {
+			klog.Errorf("error creating docker containers dir: %v", err)
+			return err
+		}
+	}
 
 	inClusterConfig := ctrl.GetConfigOrDie()
 
@@ -218,6 +232,7 @@ func mapHostPaths(ctx context.Context, pManager, vManager manager.Manager) {
 		existingVPodsWithNamespace := make(map[string]bool)
 		existingPodsPath := make(map[string]bool)
 		existingKubeletPodsPath := make(map[string]bool)
+		existingDockerContainersPath := make(map[string]bool)
 
 		for _, vPod := range vPodList.Items {
 			existingVPodsWithNamespace[fmt.Sprintf("%s_%s", vPod.Name, vPod.Namespace)] = true
@@ -247,6 +262,12 @@ func mapHostPaths(ctx context.Context, pManager, vManager manager.Manager) {
 				containerSymlinkTargetDir := filepath.Join(PodLogsMountPath,
 					fmt.Sprintf("%s_%s_%s", vPod.Namespace, vPod.Name, string(vPod.UID)))
 				createContainerToPodSymlink(ctx, vPod, podDetail, containerSymlinkTargetDir)
+
+				// create docker container dir symlinks, containerd and cri-o
+				// only write logs to /var/log/pods which is mapped above
+				if options.MapDockerContainerLogs {
+					createDockerContainerSymlinks(ctx, vPod, existingDockerContainersPath)
+				}
 			}
 		}
 
@@ -266,6 +287,13 @@ func mapHostPaths(ctx context.Context, pManager, vManager manager.Manager) {
 			klog.Errorf("error cleaning up old kubelet pod paths: %v", err)
 		}
 
+		if options.MapDockerContainerLogs {
+			err = cleanupOldPodPath(ctx, options.VirtualDockerContainersPath, existingDockerContainersPath)
+			if err != nil {
+				klog.Errorf("error cleaning up old docker container paths: %v", err)
+			}
+		}
+
 		klog.Infof("successfully reconciled mapper")
 
 	}, time.Second*5)
@@ -281,6 +309,9 @@ func cleanupOldContainerPaths(ctx context.Context, existingVPodsWithNS map[strin
 
 	for _, vPodContainerOnDisk := range vPodsContainersOnDisk {
 		nameParts := strings.Split(vPodContainerOnDisk.Name(), "_")
+		if len(nameParts) < 3 {
+			continue
+		}
 		vPodOnDiskName, vPodOnDiskNS := nameParts[0], nameParts[1]
 
 		if _, ok := existingVPodsWithNS[fmt.Sprintf("%s_%s", vPodOnDiskName, vPodOnDiskNS)]; !ok {
@@ -296,9 +327,54 @@ func cleanupOldContainerPaths(ctx context.Context, existingVPodsWithNS map[strin
 		}
 	}
 
+	// containers of pods that still exist leave a symlink behind on every
+	// restart, which no longer resolves once kubelet rotated the old log
+	return cleanupDanglingSymlinks(options.VirtualContainerLogsPath)
+}
+
+// cleanupDanglingSymlinks removes all symlinks within dir whose target doesn't exist anymore
+func cleanupDanglingSymlinks(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Type()&fs.ModeSymlink == 0 {
+			continue
+		}
+
+		fullPath := filepath.Join(dir, entry.Name())
+		_, err := os.Stat(fullPath)
+		if err == nil || !os.IsNotExist(err) {
+			continue
+		}
+
+		// symlink no longer resolves, hence delete
+		klog.Infof("cleaning up dangling symlink %s", fullPath)
+		err = os.Remove(fullPath)
+		if err != nil {
+			klog.Errorf("error deleting symlink %s: %v", fullPath, err)
+		}
+	}
+
 	return nil
 }
 
+// removeEmptyDir removes dir if it has no entries left
+func removeEmptyDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) > 0 {
+		return
+	}
+
+	klog.Infof("cleaning up empty dir %s", dir)
+	err = os.Remove(dir)
+	if err != nil {
+		klog.Errorf("error deleting empty dir %s: %v", dir, err)
+	}
+}
+
 func createKubeletVirtualToPhysicalPodLinks(vPodDirName, pPodDirName string) {
 	err := os.MkdirAll(vPodDirName, os.ModeDir)
 	if err != nil {
@@ -350,23 +426,14 @@ func cleanupOldPodPath(ctx context.Context, cleanupDirPath string, existingPodPa
 				// depend on it and we don't want to delete the virtual paths
 				// which the physical paths are still not cleaned up by the
 				// kubelet
-				symlinks, err := os.ReadDir(fullVPodDirDiskPath)
+				err := cleanupDanglingSymlinks(fullVPodDirDiskPath)
 				if err != nil {
 					klog.Errorf("error iterating over vpod dir %s: %v", fullVPodDirDiskPath, err)
+					continue
 				}
 
-				for _, sl := range symlinks {
-					target := filepath.Join(fullVPodDirDiskPath, sl.Name())
-					_, readLinkErr := os.Readlink(target)
-					if readLinkErr != nil {
-						// symlink no longer resolves, hence delete
-						klog.Infof("cleaning up %s", target)
-						err := os.RemoveAll(target)
-						if err != nil {
-							klog.Errorf("error deleting symlink %s: %v", target, err)
-						}
-					}
-				}
+				// once kubelet removed the physical pod dir, the virtual one is empty
+				removeEmptyDir(fullVPodDirDiskPath)
 				continue
 			}
 
@@ -426,6 +493,38 @@ func createContainerToPodSymlink(ctx context.Context, vPod corev1.Pod, pPodDetai
 	}
 }
 
+// createDockerContainerSymlinks links the container directories written by the
+// docker runtime, e.g. /var/lib/docker/containers/<containerID>, into the virtual
+// docker containers path. Container ids are the same for the virtual and physical
+// pod, so only the containers of pods belonging to this vcluster get exposed.
+func createDockerContainerSymlinks(ctx context.Context, vPod corev1.Pod, existingPaths map[string]bool) {
+	options := ctx.Value(optionsKey).(*context2.VirtualClusterOptions)
+
+	containerStatuses := append([]corev1.ContainerStatus{}, vPod.Status.InitContainerStatuses...)
+	containerStatuses = append(containerStatuses, vPod.Status.ContainerStatuses...)
+	for _, containerStatus := range containerStatuses {
+		runtime, containerID, found := strings.Cut(containerStatus.ContainerID, "://")
+		if !found || runtime != DockerRuntimePrefix || containerID == "" {
+			continue
+		}
+
+		source := filepath.Join(options.VirtualDockerContainersPath, containerID)
+		target := filepath.Join(podtranslate.PhysicalDockerContainersVolumeMountPath, containerID)
+		existingPaths[source] = true
+
+		err := os.Symlink(target, source)
+		if err != nil {
+			if !os.IsExist(err) {
+				klog.Errorf("error creating docker container symlink %s -> %s: %v", source, target, err)
+			}
+
+			continue
+		}
+
+		klog.Infof("created docker container symlink %s -> %s", source, target)
+	}
+}
+
 // we need to get the info that which log file in the physical pod dir
 // should this virtual container symlink point to. for eg.
 // <physical_container> -> /var/log/pods/<pod>/<container>/xxx.log
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	context2 "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCreateDockerContainerSymlinks(t *testing.T) {
+	testCases := []struct {
+		name             string
+		initContainerIDs []string
+		containerIDs     []string
+		expectedLinks    []string
+	}{
+		{
+			name:          "docker containers",
+			containerIDs:  []string{"docker://abc", "docker://def"},
+			expectedLinks: []string{"abc", "def"},
+		},
+		{
+			name:             "docker init containers",
+			initContainerIDs: []string{"docker://init"},
+			containerIDs:     []string{"docker://abc"},
+			expectedLinks:    []string{"abc", "init"},
+		},
+		{
+			name:          "non docker runtimes are skipped",
+			containerIDs:  []string{"containerd://abc", "cri-o://def", "docker://ghi"},
+			expectedLinks: []string{"ghi"},
+		},
+		{
+			name:          "missing container ids are skipped",
+			containerIDs:  []string{"", "docker://"},
+			expectedLinks: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		virtualPath := t.TempDir()
+		ctx := context.WithValue(context.Background(), optionsKey, &context2.VirtualClusterOptions{
+			VirtualDockerContainersPath: virtualPath,
+		})
+
+		vPod := corev1.Pod{}
+		for _, id := range testCase.initContainerIDs {
+			vPod.Status.InitContainerStatuses = append(vPod.Status.InitContainerStatuses, corev1.ContainerStatus{ContainerID: id})
+		}
+		for _, id := range testCase.containerIDs {
+			vPod.Status.ContainerStatuses = append(vPod.Status.ContainerStatuses, corev1.ContainerStatus{ContainerID: id})
+		}
+
+		existingPaths := map[string]bool{}
+		createDockerContainerSymlinks(ctx, vPod, existingPaths)
+
+		entries, err := os.ReadDir(virtualPath)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		links := []string{}
+		for _, entry := range entries {
+			target, err := os.Readlink(filepath.Join(virtualPath, entry.Name()))
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+			assert.Equal(t, target, filepath.Join(podtranslate.PhysicalDockerContainersVolumeMountPath, entry.Name()), "unexpected link target in test case %s", testCase.name)
+			assert.Assert(t, existingPaths[filepath.Join(virtualPath, entry.Name())], "link %s not recorded in test case %s", entry.Name(), testCase.name)
+			links = append(links, entry.Name())
+		}
+		sort.Strings(links)
+		assert.Assert(t, cmp.DeepEqual(links, testCase.expectedLinks), "unexpected links in test case %s", testCase.name)
+		assert.Equal(t, len(existingPaths), len(testCase.expectedLinks), "unexpected existing paths in test case %s", testCase.name)
+	}
+}
+
+func TestCleanupOldKubeletPodPath(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		existing    bool
+		resolving   []string
+		dangling    []string
+		expectedDir bool
+		expected    []string
+	}{
+		{
+			name:        "existing pod",
+			existing:    true,
+			dangling:    []string{"volumes"},
+			expectedDir: true,
+			expected:    []string{"volumes"},
+		},
+		{
+			name:        "deleted pod with physical dir",
+			resolving:   []string{"volumes"},
+			dangling:    []string{"plugins"},
+			expectedDir: true,
+			expected:    []string{"volumes"},
+		},
+		{
+			name:     "deleted pod without physical dir",
+			dangling: []string{"volumes", "plugins"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		physicalPath := t.TempDir()
+		virtualPath := t.TempDir()
+		ctx := context.WithValue(context.Background(), optionsKey, &context2.VirtualClusterOptions{
+			VirtualKubeletPodPath: virtualPath,
+		})
+
+		vPodDir := filepath.Join(virtualPath, "uid")
+		assert.NilError(t, os.Mkdir(vPodDir, 0755), "unexpected error in test case %s", testCase.name)
+		for _, name := range testCase.resolving {
+			assert.NilError(t, os.Mkdir(filepath.Join(physicalPath, name), 0755), "unexpected error in test case %s", testCase.name)
+			assert.NilError(t, os.Symlink(filepath.Join(physicalPath, name), filepath.Join(vPodDir, name)), "unexpected error in test case %s", testCase.name)
+		}
+		for _, name := range testCase.dangling {
+			assert.NilError(t, os.Symlink(filepath.Join(physicalPath, name), filepath.Join(vPodDir, name)), "unexpected error in test case %s", testCase.name)
+		}
+
+		existingPaths := map[string]bool{}
+		if testCase.existing {
+			existingPaths[vPodDir] = true
+		}
+		err := cleanupOldPodPath(ctx, virtualPath, existingPaths)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		entries, err := os.ReadDir(vPodDir)
+		if !testCase.expectedDir {
+			assert.Assert(t, os.IsNotExist(err), "expected %s to be removed in test case %s", vPodDir, testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		sort.Strings(names)
+		assert.Assert(t, cmp.DeepEqual(names, testCase.expected), "unexpected entries in test case %s", testCase.name)
+	}
+}
+
+func TestCleanupOldContainerPaths(t *testing.T) {
+	physicalPath := t.TempDir()
+	virtualPath := t.TempDir()
+	ctx := context.WithValue(context.Background(), optionsKey, &context2.VirtualClusterOptions{
+		VirtualContainerLogsPath: virtualPath,
+	})
+
+	assert.NilError(t, os.WriteFile(filepath.Join(physicalPath, "1.log"), []byte{}, 0644))
+	links := map[string]string{
+		// running container of an existing pod
+		"pod_default_app-1.log": "1.log",
+		// restarted container of an existing pod
+		"pod_default_app-0.log": "0.log",
+		// container of a deleted pod
+		"deleted_default_app-2.log": "1.log",
+	}
+	for source, target := range links {
+		assert.NilError(t, os.Symlink(filepath.Join(physicalPath, target), filepath.Join(virtualPath, source)))
+	}
+	assert.NilError(t, os.WriteFile(filepath.Join(virtualPath, "invalid"), []byte{}, 0644))
+
+	err := cleanupOldContainerPaths(ctx, map[string]bool{"pod_default": true})
+	assert.NilError(t, err)
+
+	entries, err := os.ReadDir(virtualPath)
+	assert.NilError(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	assert.Assert(t, cmp.DeepEqual(names, []string{"invalid", "pod_default_app-1.log"}))
+}
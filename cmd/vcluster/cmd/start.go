@@ -9,12 +9,16 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/leaderelection"
 	"github.com/loft-sh/vcluster/pkg/metricsapiservice"
+	"github.com/loft-sh/vcluster/pkg/operations"
 	"github.com/loft-sh/vcluster/pkg/server"
+	"github.com/loft-sh/vcluster/pkg/server/cert"
 	"github.com/loft-sh/vcluster/pkg/telemetry"
 	telemetrytypes "github.com/loft-sh/vcluster/pkg/telemetry/types"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/memory"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -27,12 +31,16 @@ import (
 	context2 "github.com/loft-sh/vcluster/cmd/vcluster/context"
 	"github.com/loft-sh/vcluster/pkg/apis"
 	"github.com/loft-sh/vcluster/pkg/controllers"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/namespaces"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/priorityclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/services"
 	"github.com/loft-sh/vcluster/pkg/coredns"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	"github.com/loft-sh/vcluster/pkg/util/resourcenames"
 	"github.com/loft-sh/vcluster/pkg/util/servicecidr"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -52,6 +60,9 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	custommetricsv1beta2 "k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+	externalmetricsv1beta1 "k8s.io/metrics/pkg/apis/external_metrics/v1beta1"
+	"k8s.io/metrics/pkg/apis/metrics"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -107,6 +118,68 @@ func ExecuteStart(ctx context.Context, options *context2.VirtualClusterOptions)
 		return fmt.Errorf("invalid argument enforce-pod-security-standard=%s, must be one of: privileged, baseline, restricted", options.EnforcePodSecurityStandard)
 	}
 
+	// network isolation maintains a single network policy in the target namespace
+	if options.EnforceNetworkIsolation && options.MultiNamespaceMode {
+		return fmt.Errorf("enforce-network-isolation is not supported in multi-namespace mode")
+	}
+
+	// check the value of the user annotation policy
+	if options.UserAnnotation != "" && options.UserAnnotation != podtranslate.UserAnnotationPlain && options.UserAnnotation != podtranslate.UserAnnotationHashed {
+		return fmt.Errorf("invalid argument user-annotation=%s, must be one of: plain, hashed", options.UserAnnotation)
+	}
+
+	// check the namespace deletion policy
+	if options.NamespaceDeletionPolicy != namespaces.DeletionPolicyDelete && options.NamespaceDeletionPolicy != namespaces.DeletionPolicyGrace && options.NamespaceDeletionPolicy != namespaces.DeletionPolicyRetain && options.NamespaceDeletionPolicy != namespaces.DeletionPolicyOrphan {
+		return fmt.Errorf("invalid argument namespace-deletion-policy=%s, must be one of: delete, grace, retain, orphan", options.NamespaceDeletionPolicy)
+	} else if options.NamespaceDeletionGracePeriod < 0 {
+		return fmt.Errorf("invalid argument namespace-deletion-grace-period=%s, must not be negative", options.NamespaceDeletionGracePeriod)
+	}
+
+	// check the discovery cache ttl
+	if options.DiscoveryCacheTTL < 0 {
+		return fmt.Errorf("invalid argument discovery-cache-ttl=%s, must not be negative", options.DiscoveryCacheTTL)
+	}
+
+	// check the stale finalizer timeout
+	if options.StaleFinalizerTimeout < 0 {
+		return fmt.Errorf("invalid argument stale-finalizer-timeout=%s, must not be negative", options.StaleFinalizerTimeout)
+	}
+
+	// check the priority class value range
+	if options.PriorityClassMaxValue > priorityclasses.MaxValue {
+		return fmt.Errorf("invalid argument priority-class-max-value=%d, must not be greater than %d", options.PriorityClassMaxValue, priorityclasses.MaxValue)
+	} else if options.PriorityClassMinValue > options.PriorityClassMaxValue {
+		return fmt.Errorf("invalid argument priority-class-min-value=%d, must not be greater than priority-class-max-value=%d", options.PriorityClassMinValue, options.PriorityClassMaxValue)
+	}
+
+	// the scheduler extender is only called by the virtual scheduler
+	if options.SchedulerExtender && !options.EnableScheduler {
+		return fmt.Errorf("invalid argument scheduler-extender: requires enable-scheduler")
+	}
+
+	// check the passed through api services
+	proxiedAPIServices, err := metricsapiservice.ParseProxiedAPIServices(options.ProxyAPIServices)
+	if err != nil {
+		return fmt.Errorf("invalid argument proxy-api-services: %w", err)
+	}
+	for _, groupVersion := range proxiedAPIServices {
+		if (options.ProxyMetricsServer && groupVersion.Group == metrics.GroupName) || (options.ProxyCustomMetricsServer && groupVersion.Group == custommetricsv1beta2.GroupName) || (options.ProxyExternalMetricsServer && groupVersion.Group == externalmetricsv1beta1.GroupName) {
+			return fmt.Errorf("invalid argument proxy-api-services: %s is already proxied by the metrics server proxy", groupVersion.Group)
+		}
+	}
+
+	// check the resource name mappings
+	resourceNames, err := resourcenames.Parse(options.ResourceNameMapping)
+	if err != nil {
+		return fmt.Errorf("invalid argument resource-name-mapping: %w", err)
+	}
+
+	// configure the garbage collector
+	err = memory.Configure(options.GCPercent, options.MemoryLimit, options.MemoryBallast)
+	if err != nil {
+		return err
+	}
+
 	// set suffix
 	translate.Suffix = options.Name
 	if translate.Suffix == "" {
@@ -116,6 +189,21 @@ func ExecuteStart(ctx context.Context, options *context2.VirtualClusterOptions)
 		translate.Suffix = "vcluster"
 	}
 
+	// set annotations that are owned by host controllers
+	translate.SyncBackAnnotations = options.SyncBackAnnotations
+
+	// set how labels are synced per resource
+	translate.LabelSyncPolicies, err = translate.ParseLabelSyncPolicies(options.LabelSyncPolicy)
+	if err != nil {
+		return fmt.Errorf("invalid argument label-sync-policy: %w", err)
+	}
+
+	// set which finalizers are propagated per resource
+	translate.FinalizerSyncPolicies, err = translate.ParseFinalizerSyncPolicies(options.FinalizerSyncPolicy)
+	if err != nil {
+		return fmt.Errorf("invalid argument finalizer-sync-policy: %w", err)
+	}
+
 	// set service name
 	if options.ServiceName == "" {
 		options.ServiceName = translate.Suffix
@@ -156,6 +244,7 @@ func ExecuteStart(ctx context.Context, options *context2.VirtualClusterOptions)
 	if err != nil {
 		return err
 	}
+	controllerCtx.ResourceNames = resourceNames
 
 	// start proxy
 	err = StartProxy(controllerCtx)
@@ -279,22 +368,37 @@ func BuildControllerContext(ctx context.Context, options *context2.VirtualCluste
 		}
 	}
 
+	// memory tuning of the caches
+	cacheOptions, clientOptions, err := cacheAndClientOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
 	klog.Info("Using physical cluster at " + inClusterConfig.Host)
 	localManager, err := ctrl.NewManager(inClusterConfig, ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: options.HostMetricsBindAddress,
 		LeaderElection:     false,
 		Namespace:          options.TargetNamespace,
+		Cache:              cacheOptions,
+		Client:             clientOptions,
 		NewClient:          pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	cacheOptions, clientOptions, err = cacheAndClientOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
 	virtualClusterManager, err := ctrl.NewManager(virtualClusterConfig, ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: options.VirtualMetricsBindAddress,
 		LeaderElection:     false,
+		Cache:              cacheOptions,
+		Client:             clientOptions,
 		NewClient:          pluginhookclient.NewVirtualPluginClientFactory(blockingcacheclient.NewCacheClient),
 	})
 	if err != nil {
@@ -322,6 +426,27 @@ func BuildControllerContext(ctx context.Context, options *context2.VirtualCluste
 	return controllerCtx, nil
 }
 
+// cacheAndClientOptions returns the cache and client options of a manager. They are created
+// per manager as the managers fill in their own defaults.
+func cacheAndClientOptions(options *context2.VirtualClusterOptions) (cache.Options, client.Options, error) {
+	cacheOptions := cache.Options{}
+	if options.CacheStripManagedFields {
+		cacheOptions.DefaultTransform = memory.StripManagedFields
+	}
+
+	clientOptions := client.Options{}
+	if len(options.UncachedResources) > 0 {
+		uncachedObjects, err := memory.UncachedObjects(scheme, options.UncachedResources)
+		if err != nil {
+			return cache.Options{}, client.Options{}, errors.Wrap(err, "parse uncached resources")
+		}
+
+		clientOptions.Cache = &client.CacheOptions{DisableFor: uncachedObjects}
+	}
+
+	return cacheOptions, clientOptions, nil
+}
+
 func WaitForClientConfig(ctx context.Context, options *context2.VirtualClusterOptions) (clientcmd.ClientConfig, error) {
 	// wait until kube config is available
 	var clientConfig clientcmd.ClientConfig
@@ -380,6 +505,12 @@ func RegisterOrDeregisterAPIService(ctx *context2.ControllerContext) {
 	if err != nil {
 		klog.Errorf("Error registering metrics apiservice: %v", err)
 	}
+
+	// check api-service for operations api
+	err = operations.RegisterOrDeregisterAPIService(ctx.Context, ctx.Options.OperationsAPI, ctx.VirtualManager.GetClient())
+	if err != nil {
+		klog.Errorf("Error registering operations apiservice: %v", err)
+	}
 }
 
 func EnsureServiceCIDR(ctx context.Context, workspaceNamespaceClient, currentNamespaceClient kubernetes.Interface, workspaceNamespace, currentNamespace, vClusterName string) error {
@@ -561,7 +692,7 @@ func CreateVClusterKubeConfig(config *api.Config, options *context2.VirtualClust
 		}
 
 		if options.KubeConfigServer != "" {
-			config.Clusters[i].Server = options.KubeConfigServer
+			config.Clusters[i].Server = cert.KubeConfigServerURL(options.KubeConfigServer)
 		} else {
 			config.Clusters[i].Server = fmt.Sprintf("https://localhost:%d", options.Port)
 		}
@@ -3,8 +3,11 @@ package context
 import (
 	"context"
 
+	"github.com/loft-sh/vcluster/pkg/operations"
+	"github.com/loft-sh/vcluster/pkg/scheduler"
 	servertypes "github.com/loft-sh/vcluster/pkg/server/types"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/resourcenames"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
@@ -28,6 +31,9 @@ type ControllerContext struct {
 	Controllers             sets.Set[string]
 	AdditionalServerFilters []servertypes.Filter
 	Options                 *VirtualClusterOptions
+	Operations              *operations.Registry
+	HostCache               *scheduler.HostCache
+	ResourceNames           *resourcenames.Mapping
 	StopChan                <-chan struct{}
 }
 
@@ -75,8 +81,10 @@ func NewControllerContext(
 		CurrentNamespace:       currentNamespace,
 		CurrentNamespaceClient: currentNamespaceClient,
 
-		StopChan: stopChan,
-		Options:  options,
+		StopChan:   stopChan,
+		Options:    options,
+		Operations: operations.NewRegistry(),
+		HostCache:  scheduler.NewHostCache(localManager),
 	}, nil
 }
 
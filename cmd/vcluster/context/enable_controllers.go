@@ -15,6 +15,7 @@ var ExistingControllers = sets.New(
 	"configmaps",
 	"secrets",
 	"endpoints",
+	"endpointslices",
 	"pods",
 	"events",
 	"fake-nodes",
@@ -22,14 +23,22 @@ var ExistingControllers = sets.New(
 	"persistentvolumeclaims",
 	"ingresses",
 	"ingressclasses",
+	"gateways",
+	"httproutes",
+	"grpcroutes",
+	"virtualservices",
+	"destinationrules",
+	"routes",
 	"nodes",
 	"persistentvolumes",
 	"storageclasses",
 	"hoststorageclasses",
 	"priorityclasses",
+	"runtimeclasses",
 	"networkpolicies",
 	"volumesnapshots",
 	"poddisruptionbudgets",
+	"leases",
 	"serviceaccounts",
 	"csinodes",
 	"csidrivers",
@@ -144,6 +153,16 @@ func parseControllers(options *VirtualClusterOptions) (sets.Set[string], error)
 		return nil, fmt.Errorf("node sync needs to be enabled when using --sync-all-nodes OR --enable-scheduler flags")
 	}
 
+	// check if nodes controller is enabled when daemon set pods are bound to host nodes
+	if options.BindDaemonSetPods && !enabledControllers.Has("nodes") {
+		return nil, fmt.Errorf("node sync needs to be enabled when using --bind-daemonset-pods")
+	}
+
+	// check if service accounts are synced when host issued tokens are requested
+	if len(options.HostServiceAccountTokenAudiences) > 0 && !enabledControllers.Has("serviceaccounts") {
+		return nil, fmt.Errorf("serviceaccounts sync needs to be enabled when using --host-service-account-token-audiences")
+	}
+
 	// check if storage classes and host storage classes are enabled at the same time
 	if enabledControllers.HasAll("storageclasses", "hoststorageclasses") {
 		return nil, fmt.Errorf("you cannot sync storageclasses and hoststorageclasses at the same time. Choose only one of them")
@@ -106,6 +106,40 @@ func TestEnableControllers(t *testing.T) {
 			expectDisabled: []string{},
 			expectError:    false,
 		},
+		{
+			desc: "bind daemonset pods, nodes not enabled",
+			optsModifier: func(v *VirtualClusterOptions) {
+				v.BindDaemonSetPods = true
+			},
+			expectError:  true,
+			errSubString: "bind-daemonset-pods",
+		},
+		{
+			desc: "bind daemonset pods, nodes enabled",
+			optsModifier: func(v *VirtualClusterOptions) {
+				v.Controllers = []string{"nodes"}
+				v.BindDaemonSetPods = true
+			},
+			expectEnabled: []string{"nodes"},
+			expectError:   false,
+		},
+		{
+			desc: "host service account token audiences, serviceaccounts not enabled",
+			optsModifier: func(v *VirtualClusterOptions) {
+				v.HostServiceAccountTokenAudiences = []string{"sts.amazonaws.com"}
+			},
+			expectError:  true,
+			errSubString: "serviceaccounts",
+		},
+		{
+			desc: "host service account token audiences, serviceaccounts enabled",
+			optsModifier: func(v *VirtualClusterOptions) {
+				v.Controllers = []string{"serviceaccounts"}
+				v.HostServiceAccountTokenAudiences = []string{"sts.amazonaws.com"}
+			},
+			expectEnabled: []string{"serviceaccounts"},
+			expectError:   false,
+		},
 	}
 
 	for _, tc := range testTable {
@@ -1,6 +1,9 @@
 package context
 
 import (
+	"math"
+	"time"
+
 	"github.com/spf13/pflag"
 )
 
@@ -25,6 +28,13 @@ type VirtualClusterOptions struct {
 	KubeConfigSecretNamespace string   `json:"kubeConfigSecretNamespace,omitempty"`
 	KubeConfigServer          string   `json:"kubeConfigServer,omitempty"`
 	Tolerations               []string `json:"tolerations,omitempty"`
+	HideNodeTaints            []string `json:"hideNodeTaints,omitempty"`
+	NodeTaintRewrites         []string `json:"nodeTaintRewrites,omitempty"`
+	SyncNodeLabels            []string `json:"syncNodeLabels,omitempty"`
+	HideNodeLabels            []string `json:"hideNodeLabels,omitempty"`
+	SyncNodeConditions        []string `json:"syncNodeConditions,omitempty"`
+	HideNodeConditions        []string `json:"hideNodeConditions,omitempty"`
+	NodeConditionRewrites     []string `json:"nodeConditionRewrites,omitempty"`
 
 	BindAddress string `json:"bindAddress,omitempty"`
 	Port        int    `json:"port,omitempty"`
@@ -36,16 +46,41 @@ type VirtualClusterOptions struct {
 
 	SetOwner bool `json:"setOwner,omitempty"`
 
-	SyncAllNodes        bool     `json:"syncAllNodes,omitempty"`
-	EnableScheduler     bool     `json:"enableScheduler,omitempty"`
-	DisableFakeKubelets bool     `json:"disableFakeKubelets,omitempty"`
-	FakeKubeletIPs      bool     `json:"fakeKubeletIPs,omitempty"`
-	ClearNodeImages     bool     `json:"clearNodeImages,omitempty"`
-	TranslateImages     []string `json:"translateImages,omitempty"`
-
-	NodeSelector        string `json:"nodeSelector,omitempty"`
-	EnforceNodeSelector bool   `json:"enforceNodeSelector,omitempty"`
-	ServiceAccount      string `json:"serviceAccount,omitempty"`
+	SyncAllNodes                bool     `json:"syncAllNodes,omitempty"`
+	BindDaemonSetPods           bool     `json:"bindDaemonSetPods,omitempty"`
+	ResourceNameMapping         []string `json:"resourceNameMapping,omitempty"`
+	NodeAllocatableFactors      []string `json:"nodeAllocatableFactors,omitempty"`
+	SyncPersistentVolumesUpOnly bool     `json:"syncPersistentVolumesUpOnly,omitempty"`
+	HostStorageClassSelector    string   `json:"hostStorageClassSelector,omitempty"`
+	EnableScheduler             bool     `json:"enableScheduler,omitempty"`
+	DisableFakeKubelets         bool     `json:"disableFakeKubelets,omitempty"`
+	FakeKubeletIPs              bool     `json:"fakeKubeletIPs,omitempty"`
+	HideNodeExternalAddresses   bool     `json:"hideNodeExternalAddresses,omitempty"`
+	FakeNodeTopology            bool     `json:"fakeNodeTopology,omitempty"`
+	FakeNodeResources           []string `json:"fakeNodeResources,omitempty"`
+	FakeNodeLabels              []string `json:"fakeNodeLabels,omitempty"`
+	SyncNodeLeases              bool     `json:"syncNodeLeases,omitempty"`
+	SchedulerExtender           bool     `json:"schedulerExtender,omitempty"`
+	ExternalSchedulers          []string `json:"externalSchedulers,omitempty"`
+	ClearNodeImages             bool     `json:"clearNodeImages,omitempty"`
+	NodeImagesLimit             int      `json:"nodeImagesLimit,omitempty"`
+	NodeInterruptionTaints      []string `json:"nodeInterruptionTaints,omitempty"`
+	NodeInterruptionConditions  []string `json:"nodeInterruptionConditions,omitempty"`
+	EvictPodsOnNodeInterruption bool     `json:"evictPodsOnNodeInterruption,omitempty"`
+	WaitForHostCapacity         bool     `json:"waitForHostCapacity,omitempty"`
+	RescheduleHostEvictedPods   bool     `json:"rescheduleHostEvictedPods,omitempty"`
+	NodePools                   string   `json:"nodePools,omitempty"`
+	NodePodCIDR                 string   `json:"nodePodCIDR,omitempty"`
+	NodePodCIDRMaskSize         int      `json:"nodePodCIDRMaskSize,omitempty"`
+	TranslateImages             []string `json:"translateImages,omitempty"`
+
+	NodeDeletionGracePeriod time.Duration `json:"nodeDeletionGracePeriod,omitempty"`
+
+	NodeSelector        string   `json:"nodeSelector,omitempty"`
+	EnforceNodeSelector bool     `json:"enforceNodeSelector,omitempty"`
+	NodeSelectorWeight  int      `json:"nodeSelectorWeight,omitempty"`
+	NodeSelectorGroups  []string `json:"nodeSelectorGroups,omitempty"`
+	ServiceAccount      string   `json:"serviceAccount,omitempty"`
 
 	OverrideHosts               bool   `json:"overrideHosts,omitempty"`
 	OverrideHostsContainerImage string `json:"overrideHostsContainerImage,omitempty"`
@@ -65,28 +100,101 @@ type VirtualClusterOptions struct {
 
 	EnforcePodSecurityStandard string `json:"enforcePodSecurityStandard,omitempty"`
 
+	EnforceNetworkIsolation           bool     `json:"enforceNetworkIsolation,omitempty"`
+	NetworkIsolationAllowedNamespaces []string `json:"networkIsolationAllowedNamespaces,omitempty"`
+
 	MapHostServices    []string `json:"mapHostServices,omitempty"`
 	MapVirtualServices []string `json:"mapVirtualServices,omitempty"`
+	HostServiceDNS     []string `json:"hostServiceDNS,omitempty"`
+	HostClusterDomain  string   `json:"hostClusterDomain,omitempty"`
+
+	HostLimitRangeDefaults bool `json:"hostLimitRangeDefaults,omitempty"`
+	ServiceMeshMode        bool `json:"serviceMeshMode,omitempty"`
+	OpenshiftMode          bool `json:"openshiftMode,omitempty"`
+	OwnerLabels            bool `json:"ownerLabels,omitempty"`
 
-	SyncLabels []string `json:"syncLabels,omitempty"`
+	SyncLabels          []string `json:"syncLabels,omitempty"`
+	SyncNamespaceLabels []string `json:"syncNamespaceLabels,omitempty"`
+	SyncBackAnnotations []string `json:"syncBackAnnotations,omitempty"`
+	LabelSyncPolicy     []string `json:"labelSyncPolicy,omitempty"`
+	FinalizerSyncPolicy []string `json:"finalizerSyncPolicy,omitempty"`
+
+	HostConfigImportSelector  string `json:"hostConfigImportSelector,omitempty"`
+	HostConfigImportNamespace string `json:"hostConfigImportNamespace,omitempty"`
 
 	// hostpath mapper options
-	RewriteHostPaths         bool `json:"rewriteHostPaths,omitempty"`
-	VirtualLogsPath          string
-	VirtualPodLogsPath       string
-	VirtualContainerLogsPath string
-	VirtualKubeletPodPath    string
+	RewriteHostPaths            bool `json:"rewriteHostPaths,omitempty"`
+	VirtualLogsPath             string
+	VirtualPodLogsPath          string
+	VirtualContainerLogsPath    string
+	VirtualKubeletPodPath       string
+	VirtualDockerContainersPath string
+	MapDockerContainerLogs      bool
+
+	ManageHostpathMapper         bool     `json:"manageHostpathMapper,omitempty"`
+	HostpathMapperImage          string   `json:"hostpathMapperImage,omitempty"`
+	HostpathMapperServiceAccount string   `json:"hostpathMapperServiceAccount,omitempty"`
+	HostpathMapperNodeSelector   []string `json:"hostpathMapperNodeSelector,omitempty"`
+	HostpathMapperLimits         []string `json:"hostpathMapperLimits,omitempty"`
 
 	HostMetricsBindAddress    string `json:"hostMetricsBindAddress,omitempty"`
 	VirtualMetricsBindAddress string `json:"virtualMetricsBindAddress,omitempty"`
 
-	MultiNamespaceMode bool     `json:"multiNamespaceMode,omitempty"`
-	NamespaceLabels    []string `json:"namespaceLabels,omitempty"`
-	SyncAllSecrets     bool     `json:"syncAllSecrets,omitempty"`
-	SyncAllConfigMaps  bool     `json:"syncAllConfigMaps,omitempty"`
+	MultiNamespaceMode           bool          `json:"multiNamespaceMode,omitempty"`
+	NamespaceLabels              []string      `json:"namespaceLabels,omitempty"`
+	NamespaceDeletionPolicy      string        `json:"namespaceDeletionPolicy,omitempty"`
+	NamespaceDeletionGracePeriod time.Duration `json:"namespaceDeletionGracePeriod,omitempty"`
+	SyncAllSecrets               bool          `json:"syncAllSecrets,omitempty"`
+	SkipSecretTypes              []string      `json:"skipSecretTypes,omitempty"`
+	SecretTransforms             []string      `json:"secretTransforms,omitempty"`
+	SyncAllConfigMaps            bool          `json:"syncAllConfigMaps,omitempty"`
+	IngressClassMapping          []string      `json:"ingressClassMapping,omitempty"`
+	StorageClassMapping          []string      `json:"storageClassMapping,omitempty"`
+	ExternalNameMapping          []string      `json:"externalNameMapping,omitempty"`
+	RemapConflictingNodePorts    bool          `json:"remapConflictingNodePorts,omitempty"`
+	NodePortEndpoints            bool          `json:"nodePortEndpoints,omitempty"`
+	DNSServiceIP                 string        `json:"dnsServiceIP,omitempty"`
+	EnforceVirtualResourceQuotas bool          `json:"enforceVirtualResourceQuotas,omitempty"`
+	PriorityClassMinValue        int32         `json:"priorityClassMinValue,omitempty"`
+	PriorityClassMaxValue        int32         `json:"priorityClassMaxValue,omitempty"`
+
+	ExposeDomainTemplate   string `json:"exposeDomainTemplate,omitempty"`
+	ExposeIngressClassName string `json:"exposeIngressClassName,omitempty"`
+	ExposeTLSSecret        string `json:"exposeTLSSecret,omitempty"`
+
+	ProxyMetricsServer         bool     `json:"proxyMetricsServer,omitempty"`
+	ProxyCustomMetricsServer   bool     `json:"proxyCustomMetricsServer,omitempty"`
+	ProxyExternalMetricsServer bool     `json:"proxyExternalMetricsServer,omitempty"`
+	ProxyAPIServices           []string `json:"proxyAPIServices,omitempty"`
+	ServiceAccountTokenSecrets bool     `json:"serviceAccountTokenSecrets,omitempty"`
+
+	HostServiceAccountTokenAudiences []string `json:"hostServiceAccountTokenAudiences,omitempty"`
+
+	TunnelServer   string `json:"tunnelServer,omitempty"`
+	TunnelCAFile   string `json:"tunnelCAFile,omitempty"`
+	TunnelCertFile string `json:"tunnelCertFile,omitempty"`
+	TunnelKeyFile  string `json:"tunnelKeyFile,omitempty"`
+
+	OperationsAPI bool `json:"operationsAPI,omitempty"`
+
+	DiscoveryCacheTTL time.Duration `json:"discoveryCacheTTL,omitempty"`
+
+	StreamIdleTimeout time.Duration `json:"streamIdleTimeout,omitempty"`
+
+	StaleFinalizerTimeout time.Duration `json:"staleFinalizerTimeout,omitempty"`
+
+	PublishRootCA bool `json:"publishRootCA,omitempty"`
+
+	UserAnnotation string `json:"userAnnotation,omitempty"`
+
+	AuditExec             bool `json:"auditExec,omitempty"`
+	ExecRequireAnnotation bool `json:"execRequireAnnotation,omitempty"`
 
-	ProxyMetricsServer         bool `json:"proxyMetricsServer,omitempty"`
-	ServiceAccountTokenSecrets bool `json:"serviceAccountTokenSecrets,omitempty"`
+	GCPercent               int      `json:"gcPercent,omitempty"`
+	MemoryLimit             string   `json:"memoryLimit,omitempty"`
+	MemoryBallast           string   `json:"memoryBallast,omitempty"`
+	CacheStripManagedFields bool     `json:"cacheStripManagedFields,omitempty"`
+	UncachedResources       []string `json:"uncachedResources,omitempty"`
 
 	// DEPRECATED FLAGS
 	DeprecatedSyncNodeChanges          bool `json:"syncNodeChanges"`
@@ -112,7 +220,7 @@ func AddFlags(flags *pflag.FlagSet, options *VirtualClusterOptions) {
 
 	flags.StringVar(&options.KubeConfigSecret, "out-kube-config-secret", "", "If specified, the virtual cluster will write the generated kube config to the given secret")
 	flags.StringVar(&options.KubeConfigSecretNamespace, "out-kube-config-secret-namespace", "", "If specified, the virtual cluster will write the generated kube config in the given namespace")
-	flags.StringVar(&options.KubeConfigServer, "out-kube-config-server", "", "If specified, the virtual cluster will use this server for the generated kube config (e.g. https://my-vcluster.domain.com)")
+	flags.StringVar(&options.KubeConfigServer, "out-kube-config-server", "", "If specified, the virtual cluster will use this server for the generated kube config (e.g. https://my-vcluster.domain.com or my-vcluster.domain.com:443)")
 
 	flags.StringVar(&options.TargetNamespace, "target-namespace", "", "The namespace to run the virtual cluster in (defaults to current namespace)")
 	flags.StringVar(&options.ServiceName, "service-name", "", "The service name where the vcluster proxy will be available")
@@ -123,14 +231,45 @@ func AddFlags(flags *pflag.FlagSet, options *VirtualClusterOptions) {
 	flags.IntVar(&options.Port, "port", 8443, "The port to bind to")
 
 	flags.BoolVar(&options.SyncAllNodes, "sync-all-nodes", false, "If enabled and --fake-nodes is false, the virtual cluster will sync all nodes instead of only the needed ones")
+	flags.StringSliceVar(&options.NodeAllocatableFactors, "node-allocatable-factor", []string{}, "Scales the allocatable resources of synced nodes, e.g. 0.5 or cpu=0.8. Factors without a resource name apply to all other resources. Format: \"[resource=]factor\". Multiple values can be passed in a comma-separated string.")
+	flags.StringSliceVar(&options.ResourceNameMapping, "resource-name-mapping", []string{}, "Maps extended resource names of the virtual cluster to host resource names, e.g. vendor.example/gpu=nvidia.com/gpu. Container resources are mapped when pods are synced and node capacities are mapped back. Format: \"virtualName=hostName\". Multiple values can be passed in a comma-separated string.")
+	flags.BoolVar(&options.BindDaemonSetPods, "bind-daemonset-pods", false, "If enabled, pods of virtual daemon sets are bound directly to the synced host node they were created for instead of being placed by the host scheduler, so every synced node runs a pod of each daemon set")
+	flags.BoolVar(&options.SyncPersistentVolumesUpOnly, "sync-persistent-volumes-up-only", false, "If enabled and the persistentvolumes syncer is enabled, only host persistent volumes bound to virtual persistent volume claims are synced into the virtual cluster. Persistent volumes created in the virtual cluster are not synced to the host cluster")
+	flags.StringVar(&options.HostStorageClassSelector, "host-storage-class-selector", "", "If set, only host storage classes matching this label selector are synced into the virtual cluster by the hoststorageclasses syncer. E.g. vcluster.loft.sh/tenant=a")
 	flags.BoolVar(&options.EnableScheduler, "enable-scheduler", false, "If enabled, will expect a scheduler running in the virtual cluster")
 	flags.BoolVar(&options.DisableFakeKubelets, "disable-fake-kubelets", false, "If disabled, the virtual cluster will not create fake kubelet endpoints to support metrics-servers")
 	flags.BoolVar(&options.FakeKubeletIPs, "fake-kubelet-ips", true, "If enabled, virtual cluster will assign fake ips of type NodeInternalIP to fake the kubelets")
+	flags.BoolVar(&options.HideNodeExternalAddresses, "hide-node-external-addresses", false, "If enabled, the external ips and dns names of the host nodes are removed from synced nodes. Only has an effect if fake kubelets are enabled")
+	flags.BoolVar(&options.FakeNodeTopology, "fake-node-topology", false, "If enabled, fake nodes will get the topology labels of the host node or, if host nodes are not readable, its zone, which is read from the host EndpointSlices")
+	flags.StringSliceVar(&options.FakeNodeResources, "fake-node-resources", []string{}, "Capacity and allocatable of fake nodes in the form [node:]resource=capacity[/allocatable], e.g. cpu=8, memory=32Gi/30Gi or node-1:nvidia.com/gpu=4. Resources without a node apply to all fake nodes")
+	flags.BoolVar(&options.SchedulerExtender, "scheduler-extender", false, "If enabled, the syncer serves a scheduler extender under /vcluster/scheduler-extender on its https port, that scores virtual nodes by the free capacity of the host nodes. Only the virtual kube-scheduler may call it. Requires node sync and the virtual scheduler")
+	flags.StringSliceVar(&options.ExternalSchedulers, "external-scheduler", []string{}, "Names of schedulers tenants run inside the virtual cluster. Pods with one of these scheduler names are only synced once they are bound to a node, and bindings are validated against the nodes of the virtual cluster")
+	flags.BoolVar(&options.SyncNodeLeases, "sync-node-leases", false, "If enabled, the syncer renews the kube-node-lease leases of the virtual nodes as long as the host node is ready, so the node lifecycle controller of the virtual cluster can rely on lease freshness")
+	flags.StringSliceVar(&options.FakeNodeLabels, "fake-node-labels", []string{}, "Labels of fake nodes in the form [node:]key=value, e.g. node.kubernetes.io/instance-type=m5.large. Labels without a node apply to all fake nodes")
 	flags.BoolVar(&options.ClearNodeImages, "node-clear-image-status", false, "If enabled, when syncing real nodes, the status.images data will be removed from the vcluster nodes")
+	flags.IntVar(&options.NodeImagesLimit, "node-images-limit", 0, "If set, only this many images of the status.images data of real nodes are synced, the largest images first. 0 syncs all images")
+	flags.StringSliceVar(&options.NodeInterruptionTaints, "node-interruption-taint", []string{}, "Additional taint keys that mark a host node as about to be reclaimed by the cloud provider, e.g. of spot or preemptible nodes. A key ending with * matches all keys with that prefix. The taints of the aws node termination handler and gke are always detected")
+	flags.StringSliceVar(&options.NodeInterruptionConditions, "node-interruption-condition", []string{}, "Node condition types that mark a host node as about to be reclaimed by the cloud provider if their status is True, e.g. set by the node problem detector")
+	flags.BoolVar(&options.WaitForHostCapacity, "wait-for-host-capacity", false, "If enabled, virtual pods are kept pending in the virtual cluster until a ready host node matching the pod has enough free capacity for its requests, instead of creating host pods that stay pending. Requires permissions to list nodes and pods in the host cluster")
+	flags.BoolVar(&options.RescheduleHostEvictedPods, "reschedule-host-evicted-pods", false, "If enabled, virtual pods whose physical pod was evicted or preempted by the host cluster are annotated with the eviction reason and, if they have a controller, deleted immediately, so the controller can replace them without waiting for the host pod to terminate")
+	flags.StringVar(&options.NodePools, "node-pools", "", "Node pools in yaml or json that group host nodes by their labels. Virtual nodes of a pool get the vcluster.loft.sh/node-pool label and the labels, hidden taints and allocatable factors of the pool, pods selecting the pool get its host node selector and tolerations")
+	flags.StringVar(&options.NodePodCIDR, "node-pod-cidr", "", "If set, virtual nodes get a stable podCIDR of this range assigned, e.g. 10.244.0.0/16, instead of the podCIDR of the host node")
+	flags.IntVar(&options.NodePodCIDRMaskSize, "node-pod-cidr-mask-size", 24, "Mask size of the podCIDRs that are assigned to virtual nodes from --node-pod-cidr")
+	flags.DurationVar(&options.NodeDeletionGracePeriod, "node-deletion-grace-period", 0, "If set, virtual nodes whose host node disappears are marked not ready and only removed if the host node does not return within this period, e.g. during upgrades. If 0, virtual nodes are removed immediately")
+	flags.BoolVar(&options.EvictPodsOnNodeInterruption, "evict-pods-on-node-interruption", false, "If enabled, virtual pods on a synced host node that is about to be reclaimed by the cloud provider are evicted, respecting the pod disruption budgets of the virtual cluster")
 
 	flags.StringSliceVar(&options.TranslateImages, "translate-image", []string{}, "Translates image names from the virtual pod to the physical pod (e.g. coredns/coredns=mirror.io/coredns/coredns)")
 	flags.BoolVar(&options.EnforceNodeSelector, "enforce-node-selector", true, "If enabled and --node-selector is set then the virtual cluster will ensure that no pods are scheduled outside of the node selector")
+	flags.IntVar(&options.NodeSelectorWeight, "node-selector-weight", 0, "If set to a weight between 1 and 100, the enforced node selector is added to pods as preferred node affinity with this weight instead of a required node selector, so pods can run on other nodes when the selected nodes are full")
+	flags.StringSliceVar(&options.NodeSelectorGroups, "node-selector-group", []string{}, "Node selectors of host node groups in the form [weight:]selector, e.g. 100:pool=a. Nodes of all groups are synced, pods are limited to the nodes of the groups and prefer the nodes of groups with a higher weight. Can't be used together with --node-selector")
 	flags.StringSliceVar(&options.Tolerations, "enforce-toleration", []string{}, "If set will apply the provided tolerations to all pods in the vcluster")
+	flags.StringSliceVar(&options.HideNodeTaints, "hide-node-taint", []string{}, "Taints of synced host nodes that are hidden in the vcluster in the form key[:effect]. A key ending with * matches all keys with that prefix")
+	flags.StringSliceVar(&options.SyncNodeLabels, "sync-node-label", []string{}, "If set, only these labels of synced host nodes are visible in the vcluster. A label ending with * matches all labels with that prefix, e.g. kubernetes.io/*. Zone, region and csi topology labels are always visible")
+	flags.StringSliceVar(&options.HideNodeLabels, "hide-node-label", []string{}, "Labels of synced host nodes that are hidden in the vcluster, e.g. cloud account identifiers. A label ending with * matches all labels with that prefix. Takes precedence over --sync-node-label. Zone, region and csi topology labels can't be hidden")
+	flags.StringSliceVar(&options.SyncNodeConditions, "sync-node-condition", []string{}, "If set, only these condition types of synced host nodes are visible in the vcluster. A type ending with * matches all types with that prefix. The Ready condition is always visible")
+	flags.StringSliceVar(&options.HideNodeConditions, "hide-node-condition", []string{}, "Condition types of synced host nodes that are hidden in the vcluster, e.g. DiskPressure. A type ending with * matches all types with that prefix. Takes precedence over --sync-node-condition. The Ready condition can't be hidden")
+	flags.StringSliceVar(&options.NodeConditionRewrites, "rewrite-node-condition-message", []string{}, "Messages of synced host node conditions that are replaced in the vcluster in the form type=message, e.g. KernelDeadlock=kernel has no deadlock. A type ending with * matches all types with that prefix")
+	flags.StringSliceVar(&options.NodeTaintRewrites, "rewrite-node-taint", []string{}, "Taints of synced host nodes that are rewritten in the vcluster in the form key[:effect]=[newKey][:newEffect], e.g. example.com/dedicated:NoSchedule=:PreferNoSchedule")
 	flags.StringVar(&options.NodeSelector, "node-selector", "", "If nodes sync is enabled, nodes with the given node selector will be synced to the virtual cluster. If fake nodes are used, and --enforce-node-selector flag is set, then vcluster will ensure that no pods are scheduled outside of the node selector.")
 	flags.StringVar(&options.ServiceAccount, "service-account", "", "If set, will set this host service account on the synced pods")
 
@@ -150,23 +289,82 @@ func AddFlags(flags *pflag.FlagSet, options *VirtualClusterOptions) {
 	flags.StringVar(&options.DefaultImageRegistry, "default-image-registry", "", "This address will be prepended to all deployed system images by vcluster")
 
 	flags.StringVar(&options.EnforcePodSecurityStandard, "enforce-pod-security-standard", "", "This can be set to 'privileged', 'baseline', or 'restricted' to make vcluster enforce these policies during translation.")
+	flags.BoolVar(&options.EnforceNetworkIsolation, "enforce-network-isolation", false, "If enabled, vcluster maintains a network policy in the host namespace that only allows the synced pods to talk to each other, the vcluster control plane and DNS")
+	flags.StringSliceVar(&options.NetworkIsolationAllowedNamespaces, "network-isolation-allowed-namespace", []string{}, "Host namespaces that are allowed to connect to the synced pods if network isolation is enforced, e.g. the namespace of an ingress controller")
+	flags.BoolVar(&options.HostLimitRangeDefaults, "host-limit-range-defaults", false, "If enabled, the container defaults of the limit ranges in the host namespace are applied during pod translation and the resulting resources are reflected in the vcluster.loft.sh/host-resources annotation of the virtual pod")
+	flags.BoolVar(&options.ServiceMeshMode, "service-mesh-mode", false, "If enabled, the labels service meshes like istio add to host pods when injecting their sidecars are preserved when updating the host pods")
+	flags.BoolVar(&options.OpenshiftMode, "openshift-mode", false, "If enabled, the user, fsGroup and supplemental group ids of synced pods are fitted to the uid and group ranges OpenShift assigned to the host namespace, so pods are admitted by the restricted security context constraints")
+	flags.BoolVar(&options.OwnerLabels, "owner-labels", false, "If enabled, the kind, name and uid of the controller owner of virtual pods are added as labels to the physical pods, so host cluster tooling can aggregate pods by workload")
 	flags.StringSliceVar(&options.SyncLabels, "sync-labels", []string{}, "The specified labels will be synced to physical resources, in addition to their vcluster translated versions.")
+	flags.StringSliceVar(&options.SyncNamespaceLabels, "sync-namespace-labels", []string{}, "The specified labels of virtual namespaces will be added to the physical pods of the namespace and in multi-namespace mode to the host namespace, so host cluster policies can select them.")
+	flags.StringSliceVar(&options.SyncBackAnnotations, "sync-back-annotations", []string{}, "Annotations host controllers add to physical resources that are synced back to the virtual resources and never overwritten in the host cluster. A key ending with * matches all annotations with that prefix.")
+	flags.StringSliceVar(&options.LabelSyncPolicy, "label-sync-policy", []string{}, "Controls per resource how labels are synced between the virtual and the host cluster. Format: \"resource:direction=key\", where resource is e.g. pods or * for all resources and direction is down (synced to host objects as is), up (owned by host tooling and synced back) or immutable (not changed after the host object was created). A key ending with * matches all labels with that prefix. Multiple values can be passed in a comma-separated string.")
+	flags.StringSliceVar(&options.FinalizerSyncPolicy, "finalizer-sync-policy", []string{}, "Controls per resource which finalizers are propagated between the virtual and the host cluster, all other finalizers are stripped. Format: \"resource:direction=finalizer\", where resource is e.g. services or * for all resources and direction is down (finalizers of virtual controllers are added to host objects) or up (finalizers of host controllers are added to virtual objects). A finalizer ending with * matches all finalizers with that prefix. Multiple values can be passed in a comma-separated string.")
+	flags.StringVar(&options.HostConfigImportSelector, "host-config-import-selector", "", "If set, config maps and secrets of the host namespace that match this label selector are mirrored read-only into the virtual namespace set by --host-config-import-namespace")
+	flags.StringVar(&options.HostConfigImportNamespace, "host-config-import-namespace", "default", "The virtual namespace host config maps and secrets selected by --host-config-import-selector are mirrored into. The namespace is created if it doesn't exist")
 	flags.StringSliceVar(&options.Plugins, "plugins", []string{}, "The plugins to wait for during startup")
 
 	flags.StringSliceVar(&options.MapVirtualServices, "map-virtual-service", []string{}, "Maps a given service inside the virtual cluster to a service inside the host cluster. E.g. default/test=physical-service")
 	flags.StringSliceVar(&options.MapHostServices, "map-host-service", []string{}, "Maps a given service inside the host cluster to a service inside the virtual cluster. E.g. other-namespace/my-service=my-vcluster-namespace/my-service")
+	flags.StringSliceVar(&options.HostServiceDNS, "host-service-dns", []string{}, "Host services that can be resolved from inside the virtual cluster as <service>.<namespace>.svc.host.<cluster-domain>, in the form namespace/service or namespace/* for all services of a namespace")
+	flags.StringVar(&options.HostClusterDomain, "host-cluster-domain", "", "The cluster domain of the host cluster the services of --host-service-dns are resolved in. Defaults to the cluster domain in the DNS search list of the syncer")
 
 	flags.StringVar(&options.HostMetricsBindAddress, "host-metrics-bind-address", "0", "If set, metrics for the controller manager for the resources managed in the host cluster will be exposed at this address")
 	flags.StringVar(&options.VirtualMetricsBindAddress, "virtual-metrics-bind-address", "0", "If set, metrics for the controller manager for the resources managed in the virtual cluster will be exposed at this address")
 
 	flags.BoolVar(&options.RewriteHostPaths, "rewrite-host-paths", false, "If enabled, syncer will rewite hostpaths in synced pod volumes")
+	flags.BoolVar(&options.MapDockerContainerLogs, "map-docker-container-logs", false, "If enabled, host paths of the docker container directories (/var/lib/docker/containers) are rewritten and mapped by the hostpath mapper as well. Only needed on nodes using the docker runtime")
+	flags.BoolVar(&options.ManageHostpathMapper, "manage-hostpath-mapper", false, "If enabled, syncer will deploy and reconcile the hostpath mapper daemonset and report its health on the vcluster service")
+	flags.StringVar(&options.HostpathMapperImage, "hostpath-mapper-image", "", "The image of the hostpath mapper daemonset deployed by --manage-hostpath-mapper")
+	flags.StringVar(&options.HostpathMapperServiceAccount, "hostpath-mapper-service-account", "", "The service account of the hostpath mapper daemonset (defaults to vc-<name>)")
+	flags.StringSliceVar(&options.HostpathMapperNodeSelector, "hostpath-mapper-node-selector", []string{}, "The node selector of the hostpath mapper daemonset. E.g. kubernetes.io/os=linux")
+	flags.StringSliceVar(&options.HostpathMapperLimits, "hostpath-mapper-limits", []string{}, "The resource limits of the hostpath mapper container. E.g. cpu=100m,memory=128Mi")
 	flags.BoolVar(&options.MultiNamespaceMode, "multi-namespace-mode", false, "If enabled, syncer will create a namespace for each virtual namespace and use the original names for the synced namespaced resources")
 	flags.StringSliceVar(&options.NamespaceLabels, "namespace-labels", []string{}, "Defines one or more labels that will be added to the namespaces synced in the multi-namespace mode. Format: \"labelKey=labelValue\". Multiple values can be passed in a comma-separated string.")
+	flags.StringVar(&options.NamespaceDeletionPolicy, "namespace-deletion-policy", "delete", "Defines what happens to the host namespace when a virtual namespace is deleted in the multi-namespace mode. Either delete, grace (delete after the grace period) retain (keep the host namespace and the objects in it for the grace period) or orphan (keep the host namespace and the objects in it and label it with vcluster.loft.sh/orphaned=true). Recreating the virtual namespace undeletes the host namespace and restores the retained configmaps, secrets and persistent volume claims")
+	flags.DurationVar(&options.NamespaceDeletionGracePeriod, "namespace-deletion-grace-period", 10*time.Minute, "The time host namespaces are kept after their virtual namespace was deleted with the grace and retain namespace deletion policies")
 	flags.BoolVar(&options.SyncAllConfigMaps, "sync-all-configmaps", false, "Sync all configmaps from virtual to host cluster")
 	flags.BoolVar(&options.SyncAllSecrets, "sync-all-secrets", false, "Sync all secrets from virtual to host cluster")
+	flags.StringSliceVar(&options.SkipSecretTypes, "skip-secret-types", []string{}, "Secrets of these types are never synced to the host cluster, even if they are used by pods or ingresses, e.g. kubernetes.io/service-account-token")
+	flags.StringSliceVar(&options.SecretTransforms, "secret-transform", []string{}, "Transforms secrets of a type on the way to the host cluster. Format: \"secretType:strip-key=key\" removes a data key, where a key ending with * matches all keys with that prefix, and \"secretType:type=newType\" changes the secret type. Multiple values can be passed in a comma-separated string.")
+	flags.StringSliceVar(&options.IngressClassMapping, "ingress-class-mapping", []string{}, "Maps virtual ingress class names to host ingress class names. Format: \"virtualClass=hostClass\". Multiple values can be passed in a comma-separated string.")
+	flags.StringSliceVar(&options.StorageClassMapping, "storage-class-mapping", []string{}, "Maps virtual storage class names of persistent volume claims to host storage class names. Format: \"virtualClass=hostClass\". Multiple values can be passed in a comma-separated string.")
+	flags.StringSliceVar(&options.ExternalNameMapping, "external-name-mapping", []string{}, "Maps external names of virtual ExternalName services to host names, e.g. to the host cluster dns name of a service. External names without a mapping are passed through. Format: \"virtualName=hostName\". Multiple values can be passed in a comma-separated string.")
+	flags.BoolVar(&options.RemapConflictingNodePorts, "remap-conflicting-node-ports", false, "If enabled, node ports of virtual services that are already allocated in the host cluster are remapped to the node ports allocated by the host cluster and recorded in the vcluster.loft.sh/remapped-node-ports annotation")
+	flags.BoolVar(&options.NodePortEndpoints, "node-port-endpoints", false, "If enabled, the addresses of the ready host nodes and the node ports allocated in the host cluster are written to the vcluster.loft.sh/node-port-endpoints annotation of virtual NodePort services. Requires permissions to list nodes in the host cluster")
+	flags.StringVar(&options.DNSServiceIP, "dns-service-ip", "", "If set, the host service of the kube-dns service in the vcluster is created with this cluster ip and it is used as nameserver for all synced pods. Has to be a free ip in the service cidr of the host cluster")
+	flags.StringVar(&options.ExposeDomainTemplate, "expose-domain-template", "", "If set, virtual services with the vcluster.loft.sh/expose annotation are exposed through an ingress in the host cluster at this domain. {name}, {namespace} and {vcluster} are replaced with the name and namespace of the service and the name of the vcluster, e.g. {name}-{namespace}.apps.example.com")
+	flags.StringVar(&options.ExposeIngressClassName, "expose-ingress-class", "", "The ingress class of the host ingresses of exposed services")
+	flags.StringVar(&options.ExposeTLSSecret, "expose-tls-secret", "", "The name of a TLS secret in the host namespace that is used by the host ingresses of exposed services")
+	flags.BoolVar(&options.EnforceVirtualResourceQuotas, "enforce-virtual-resource-quotas", false, "If enabled, objects are not synced to the host cluster while a resource quota of their virtual namespace they count towards is exceeded, e.g. because the quota was lowered after the objects were created")
+	flags.Int32Var(&options.PriorityClassMinValue, "priority-class-min-value", math.MinInt32, "Values of virtual priority classes below this value are raised to it in the host cluster")
+	flags.Int32Var(&options.PriorityClassMaxValue, "priority-class-max-value", 1000000000, "Values of virtual priority classes above this value are lowered to it in the host cluster. Must not be greater than 1000000000, which is the highest value of user defined priority classes")
 
 	flags.BoolVar(&options.ProxyMetricsServer, "proxy-metrics-server", false, "Proxy the host cluster metrics server")
+	flags.BoolVar(&options.ProxyCustomMetricsServer, "proxy-custom-metrics-server", false, "Proxy pod, service, persistent volume claim and ingress metrics of the host cluster custom metrics api (custom.metrics.k8s.io), so horizontal pod autoscalers can scale on custom metrics")
+	flags.BoolVar(&options.ProxyExternalMetricsServer, "proxy-external-metrics-server", false, "Proxy the host cluster external metrics api (external.metrics.k8s.io), so horizontal pod autoscalers can scale on external metrics")
+	flags.StringSliceVar(&options.ProxyAPIServices, "proxy-api-services", []string{}, "Aggregated apis of the host cluster that are passed through into the virtual cluster. Requests to namespaced resources are proxied to the physical namespace and the returned objects are mapped back to virtual names. Format: \"version.group\", e.g. v1beta1.external.metrics.k8s.io. Multiple values can be passed in a comma-separated string.")
+	flags.StringVar(&options.TunnelServer, "tunnel-server", "", "If set, the syncer dials its connections to the host api server for kubelet requests (logs, exec, attach, port-forward and metrics) and to the admission webhooks of requests it handles itself through this HTTP CONNECT tunnel server, e.g. a konnectivity server in http-connect mode. The connections of the virtual cluster api server are tunneled through its egress selector configuration, which the chart sets up with the tunnel.server value. Format: host:port")
+	flags.StringVar(&options.TunnelCAFile, "tunnel-ca-file", "", "The path to the ca certificate of the tunnel server. If this or a client certificate is set, the connection to the tunnel server uses TLS")
+	flags.StringVar(&options.TunnelCertFile, "tunnel-cert-file", "", "The path to the client certificate used to authenticate to the tunnel server")
+	flags.StringVar(&options.TunnelKeyFile, "tunnel-key-file", "", "The path to the client key used to authenticate to the tunnel server")
 	flags.BoolVar(&options.ServiceAccountTokenSecrets, "service-account-token-secrets", false, "Create secrets for pod service account tokens instead of injecting it as annotations")
+	flags.StringSliceVar(&options.HostServiceAccountTokenAudiences, "host-service-account-token-audiences", []string{}, "Projected service account tokens with one of these audiences are issued by the host cluster for the synced service account, e.g. sts.amazonaws.com for IAM roles for service accounts. Requires the serviceaccounts syncer")
+	flags.DurationVar(&options.DiscoveryCacheTTL, "discovery-cache-ttl", 0, "If set, discovery and openapi documents of the virtual cluster are served from a syncer cache for this time. Changed custom resource definitions and api services invalidate the cache immediately. Disabled by default")
+	flags.DurationVar(&options.StreamIdleTimeout, "stream-idle-timeout", 4*time.Hour, "Streaming connections (exec, attach, port-forward) proxied by the syncer are closed after no data was sent in either direction for this duration. If 0, idle streams are kept open")
+	flags.DurationVar(&options.StaleFinalizerTimeout, "stale-finalizer-timeout", 0, "If set, finalizers of custom resources that are terminating for longer than this timeout are removed, when no admission webhook of the finalizer domain exists anymore and no running pod in the virtual cluster is allowed to update the resource. If 0, stale finalizers are kept")
+	flags.BoolVar(&options.PublishRootCA, "publish-root-ca", false, "If enabled, the syncer maintains the kube-root-ca.crt config map with the server ca certificate of the virtual cluster in every virtual namespace. Use this if the root ca publisher of the virtual controller manager is disabled")
+	flags.BoolVar(&options.OperationsAPI, "operations-api", false, "If enabled, vcluster will serve the operations.vcluster.loft.sh api inside the virtual cluster to resync, garbage collect, pause and inspect synced objects")
+
+	flags.StringVar(&options.UserAnnotation, "user-annotation", "", "If set, workloads created or modified through vcluster are annotated with the virtual user and physical pods get the user stamped onto them. Either plain or hashed")
+	flags.BoolVar(&options.AuditExec, "audit-exec", false, "If enabled, every exec and attach request into a pod is logged with the virtual user, the virtual and physical pod, the container and the command")
+	flags.BoolVar(&options.ExecRequireAnnotation, "exec-require-annotation", false, "If enabled, exec and attach are only allowed into virtual pods with the vcluster.loft.sh/allow-exec: \"true\" annotation, which only users with the allow-exec verb on pods may set. Denied requests are always logged")
+
+	flags.IntVar(&options.GCPercent, "gc-percent", 0, "The garbage collector target percentage of the syncer (GOGC). If 0, the GOGC environment variable or the go default is used")
+	flags.StringVar(&options.MemoryLimit, "memory-limit", "", "The soft memory limit of the syncer (GOMEMLIMIT), e.g. 400Mi. Should be below the memory limit of the syncer container")
+	flags.StringVar(&options.MemoryBallast, "memory-ballast", "", "If set, the syncer allocates an untouched memory ballast of the given size, e.g. 100Mi, to garbage collect less often")
+	flags.BoolVar(&options.CacheStripManagedFields, "cache-strip-managed-fields", false, "If enabled, managed fields are removed from all cached objects to reduce the memory usage of the syncer")
+	flags.StringSliceVar(&options.UncachedResources, "uncached-resources", []string{}, "Kinds that are read directly from the api server instead of a cache, e.g. Secret or Ingress.networking.k8s.io. Only useful for kinds that no enabled syncer watches")
 
 	// Deprecated Flags
 	flags.BoolVar(&options.DeprecatedSyncNodeChanges, "sync-node-changes", false, "If enabled and --fake-nodes is false, the virtual cluster will proxy node updates from the virtual cluster to the host cluster. This is not recommended and should only be used if you know what you are doing.")
@@ -2,6 +2,7 @@ package delegatingauthorizer
 
 import (
 	"context"
+	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	authv1 "k8s.io/api/authorization/v1"
@@ -17,6 +18,7 @@ type GroupVersionResourceVerb struct {
 	Verb        string
 }
 
+// PathVerb is a non resource path and verb, a path ending with * matches all paths with that prefix
 type PathVerb struct {
 	Path string
 	Verb string
@@ -88,7 +90,7 @@ func applies(a authorizer.Attributes, resources []GroupVersionResourceVerb, nonR
 		}
 	} else {
 		for _, p := range nonResources {
-			if p.Path == a.GetPath() && (p.Verb == "*" || p.Verb == a.GetVerb()) {
+			if pathMatches(p.Path, a.GetPath()) && (p.Verb == "*" || p.Verb == a.GetVerb()) {
 				return true
 			}
 		}
@@ -96,3 +98,11 @@ func applies(a authorizer.Attributes, resources []GroupVersionResourceVerb, nonR
 
 	return false
 }
+
+func pathMatches(pattern, path string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(path, strings.TrimSuffix(pattern, "*"))
+	}
+
+	return pattern == path
+}
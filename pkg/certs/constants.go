@@ -114,6 +114,10 @@ const (
 	ControllerManagerKubeConfigFileName = "controller-manager.conf"
 	// SchedulerKubeConfigFileName defines the file name for the scheduler's kubeconfig file
 	SchedulerKubeConfigFileName = "scheduler.conf"
+	// SchedulerClientCertName defines the scheduler client certificate name, extracted from the scheduler's kubeconfig file
+	SchedulerClientCertName = "scheduler-client.crt"
+	// SchedulerClientKeyName defines the scheduler client key name, extracted from the scheduler's kubeconfig file
+	SchedulerClientKeyName = "scheduler-client.key"
 
 	// Some well-known users and groups in the core Kubernetes authorization system
 
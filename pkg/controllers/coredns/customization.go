@@ -0,0 +1,258 @@
+package coredns
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	CustomizationConfigMapName = "coredns-customization"
+	CustomConfigMapName        = "coredns-custom"
+	DeploymentName             = "coredns"
+	CorefileKey                = "Corefile"
+
+	// DefaultUpstreamAnnotation holds the nameservers of the Corefile before they were replaced
+	DefaultUpstreamAnnotation = "vcluster.loft.sh/coredns-default-upstream"
+	// UpstreamAnnotation holds the nameservers the Corefile was changed to
+	UpstreamAnnotation = "vcluster.loft.sh/coredns-upstream"
+	// ConfigHashAnnotation is set on the pod template of the CoreDNS deployment to roll it
+	// whenever the customization changes
+	ConfigHashAnnotation = "vcluster.loft.sh/coredns-config-hash"
+)
+
+// CoreDNSCustomizationReconciler applies the stub domains, upstream nameservers and zone files of the
+// coredns-customization ConfigMap as well as the resolvable host services to CoreDNS
+type CoreDNSCustomizationReconciler struct {
+	client.Client
+	Log      loghelper.Logger
+	Recorder record.EventRecorder
+
+	// HostServices are resolved through the host cluster DNS at HostNameserver, which serves them
+	// below svc.<HostClusterDomain>
+	HostServices      []coredns.HostService
+	HostNameserver    string
+	HostClusterDomain string
+	ClusterDomain     string
+}
+
+func (r *CoreDNSCustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	customization := &coredns.Customization{}
+	customizationConfigMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: Namespace, Name: CustomizationConfigMapName}, customizationConfigMap)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	} else if err == nil {
+		customization, err = coredns.ParseCustomization(customizationConfigMap.Data)
+		if err != nil {
+			// keep the current configuration until the customization is fixed
+			r.Log.Infof("invalid CoreDNS customization: %v", err)
+			r.Recorder.Eventf(customizationConfigMap, corev1.EventTypeWarning, "InvalidCustomization", "Invalid CoreDNS customization, keeping the current configuration: %v", err)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// wait until CoreDNS is deployed
+	corefileConfigMap := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: Namespace, Name: ConfigMapName}, corefileConfigMap)
+	if kerrors.IsNotFound(err) {
+		r.Log.Debugf("%s/%s Configmap not found, CoreDNS is not fully configured", ConfigMapName, Namespace)
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+	deployment := &appsv1.Deployment{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: Namespace, Name: DeploymentName}, deployment)
+	if kerrors.IsNotFound(err) {
+		r.Log.Debugf("%s/%s Deployment not found, CoreDNS is not fully configured", DeploymentName, Namespace)
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// compute the desired configuration
+	newCorefileConfigMap, err := desiredCorefileConfigMap(corefileConfigMap, customization.UpstreamNameservers)
+	if err != nil {
+		r.Log.Infof("cannot apply CoreDNS upstream nameservers: %v", err)
+		if customizationConfigMap.Name != "" {
+			r.Recorder.Eventf(customizationConfigMap, corev1.EventTypeWarning, "InvalidCustomization", "Cannot apply upstream nameservers, keeping the current configuration: %v", err)
+		}
+		return ctrl.Result{}, nil
+	}
+	customConfigMap := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: Namespace, Name: CustomConfigMapName}, customConfigMap)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	customFiles := customization.CustomFiles()
+	if len(r.HostServices) > 0 {
+		customFiles[coredns.CustomKeyPrefix+"hostservices.server"] = coredns.HostServicesServerBlock(r.HostServices, r.ClusterDomain, r.HostClusterDomain, r.HostNameserver)
+	}
+	newCustomConfigMap := desiredCustomConfigMap(customConfigMap, customFiles)
+	configHash := hashCustomization(customFiles, customization.UpstreamNameservers)
+	_, hasConfigHash := deployment.Spec.Template.Annotations[ConfigHashAnnotation]
+	if equality.Semantic.DeepEqual(corefileConfigMap, newCorefileConfigMap) &&
+		equality.Semantic.DeepEqual(customConfigMap.Data, newCustomConfigMap.Data) &&
+		(deployment.Spec.Template.Annotations[ConfigHashAnnotation] == configHash || (!hasConfigHash && len(customFiles) == 0 && len(customization.UpstreamNameservers) == 0)) {
+		return ctrl.Result{}, nil
+	}
+
+	// never change the configuration while a previous rollout is still in progress, so there
+	// are always ready CoreDNS pods with a working configuration
+	if !isRolledOut(deployment) {
+		r.Log.Debugf("wait for CoreDNS rollout to finish before applying the customization")
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	}
+
+	if customConfigMap.ResourceVersion == "" && len(newCustomConfigMap.Data) > 0 {
+		err = r.Client.Create(ctx, newCustomConfigMap)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if customConfigMap.ResourceVersion != "" && !equality.Semantic.DeepEqual(customConfigMap.Data, newCustomConfigMap.Data) {
+		err = r.Client.Patch(ctx, newCustomConfigMap, client.MergeFrom(customConfigMap))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if !equality.Semantic.DeepEqual(corefileConfigMap, newCorefileConfigMap) {
+		err = r.Client.Patch(ctx, newCorefileConfigMap, client.MergeFrom(corefileConfigMap))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// roll the deployment, so all CoreDNS pods pick up the new configuration
+	if deployment.Spec.Template.Annotations[ConfigHashAnnotation] != configHash {
+		r.Log.Infof("roll CoreDNS deployment to apply the customization")
+		newDeployment := deployment.DeepCopy()
+		if newDeployment.Spec.Template.Annotations == nil {
+			newDeployment.Spec.Template.Annotations = map[string]string{}
+		}
+		newDeployment.Spec.Template.Annotations[ConfigHashAnnotation] = configHash
+		err = r.Client.Patch(ctx, newDeployment, client.MergeFrom(deployment))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// desiredCorefileConfigMap returns the Corefile ConfigMap with the given upstream nameservers or the
+// default upstream nameservers if none are given
+func desiredCorefileConfigMap(configMap *corev1.ConfigMap, upstreamNameservers []string) (*corev1.ConfigMap, error) {
+	newConfigMap := configMap.DeepCopy()
+	defaultUpstream, hasDefaultUpstream := newConfigMap.Annotations[DefaultUpstreamAnnotation]
+	if len(upstreamNameservers) == 0 && !hasDefaultUpstream {
+		return newConfigMap, nil
+	}
+
+	corefile := newConfigMap.Data[CorefileKey]
+	current, err := coredns.GetUpstreamNameservers(corefile)
+	if err != nil {
+		return nil, err
+	}
+
+	// the Corefile is reset to the default upstream nameservers when the manifests are applied again
+	if !hasDefaultUpstream || strings.Join(current, " ") != newConfigMap.Annotations[UpstreamAnnotation] {
+		defaultUpstream = strings.Join(current, " ")
+	}
+
+	if len(upstreamNameservers) == 0 {
+		delete(newConfigMap.Annotations, DefaultUpstreamAnnotation)
+		delete(newConfigMap.Annotations, UpstreamAnnotation)
+		newConfigMap.Data[CorefileKey], err = coredns.SetUpstreamNameservers(corefile, strings.Fields(defaultUpstream))
+		return newConfigMap, err
+	}
+
+	if newConfigMap.Annotations == nil {
+		newConfigMap.Annotations = map[string]string{}
+	}
+	newConfigMap.Annotations[DefaultUpstreamAnnotation] = defaultUpstream
+	newConfigMap.Annotations[UpstreamAnnotation] = strings.Join(upstreamNameservers, " ")
+	newConfigMap.Data[CorefileKey], err = coredns.SetUpstreamNameservers(corefile, upstreamNameservers)
+	return newConfigMap, err
+}
+
+// desiredCustomConfigMap returns the coredns-custom ConfigMap with the given files, keys that are
+// not managed by vcluster are kept
+func desiredCustomConfigMap(configMap *corev1.ConfigMap, files map[string]string) *corev1.ConfigMap {
+	newConfigMap := configMap.DeepCopy()
+	if newConfigMap.ResourceVersion == "" {
+		newConfigMap.ObjectMeta = metav1.ObjectMeta{Namespace: Namespace, Name: CustomConfigMapName}
+	}
+
+	data := map[string]string{}
+	for key, value := range newConfigMap.Data {
+		if !strings.HasPrefix(key, coredns.CustomKeyPrefix) {
+			data[key] = value
+		}
+	}
+	for key, value := range files {
+		data[key] = value
+	}
+	newConfigMap.Data = data
+	return newConfigMap
+}
+
+func hashCustomization(files map[string]string, upstreamNameservers []string) string {
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key + "\n" + files[key] + "\n"))
+	}
+	hash.Write([]byte(strings.Join(upstreamNameservers, " ")))
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func isRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *CoreDNSCustomizationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// only receive reconcile requests for the ConfigMaps that make up the CoreDNS configuration
+	p := func(object client.Object) bool {
+		if object.GetNamespace() != Namespace {
+			return false
+		}
+
+		name := object.GetName()
+		return name == CustomizationConfigMapName || name == CustomConfigMapName || name == ConfigMapName
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("coredns_customization").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(p), predicate.ResourceVersionChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,169 @@
+package coredns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCustomizationReconcile(t *testing.T) {
+	corefile := ".:1053 {\n    forward . /etc/resolv.conf\n}\n\nimport /etc/coredns/custom/*.server\n"
+	newCorefileConfigMap := func(corefile string, annotations map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: Namespace, Name: ConfigMapName, Annotations: annotations},
+			Data:       map[string]string{CorefileKey: corefile, NodeHostsKey: ""},
+		}
+	}
+	newCustomizationConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: Namespace, Name: CustomizationConfigMapName},
+			Data:       data,
+		}
+	}
+	newDeployment := func(configHash string, rolledOut bool) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: Namespace, Name: DeploymentName}}
+		if configHash != "" {
+			deployment.Spec.Template.Annotations = map[string]string{ConfigHashAnnotation: configHash}
+		}
+		if rolledOut {
+			deployment.Status = appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1}
+		}
+		return deployment
+	}
+	userCustomConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: Namespace, Name: CustomConfigMapName},
+		Data: map[string]string{
+			"user.server":                 "example.net:1053 {\n}\n",
+			"vcluster-stubdomains.server": "stale",
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		objects            []client.Object
+		hostServices       []coredns.HostService
+		expectedCorefile   string
+		expectedCustomKeys []string
+		expectedRolled     bool
+		expectedRequeue    bool
+	}{
+		{
+			name:             "No customization",
+			objects:          []client.Object{newCorefileConfigMap(corefile, nil), newDeployment("", true)},
+			expectedCorefile: corefile,
+		},
+		{
+			name: "Apply customization",
+			objects: []client.Object{
+				newCorefileConfigMap(corefile, nil),
+				newDeployment("", true),
+				newCustomizationConfigMap(map[string]string{"stubDomains": `{"corp.example.com": ["10.0.0.10"]}`, "upstreamNameservers": `["1.1.1.1"]`}),
+				userCustomConfigMap.DeepCopy(),
+			},
+			expectedCorefile:   ".:1053 {\n    forward . 1.1.1.1\n}\n\nimport /etc/coredns/custom/*.server\n",
+			expectedCustomKeys: []string{"user.server", "vcluster-stubdomains.server"},
+			expectedRolled:     true,
+		},
+		{
+			name: "Keep configuration of invalid customization",
+			objects: []client.Object{
+				newCorefileConfigMap(corefile, nil),
+				newDeployment("", true),
+				newCustomizationConfigMap(map[string]string{"upstreamNameservers": `["dns.example.com"]`}),
+			},
+			expectedCorefile: corefile,
+		},
+		{
+			name: "Wait for rollout",
+			objects: []client.Object{
+				newCorefileConfigMap(corefile, nil),
+				newDeployment("", false),
+				newCustomizationConfigMap(map[string]string{"upstreamNameservers": `["1.1.1.1"]`}),
+			},
+			expectedCorefile: corefile,
+			expectedRequeue:  true,
+		},
+		{
+			name: "Restore default upstream nameservers",
+			objects: []client.Object{
+				newCorefileConfigMap(".:1053 {\n    forward . 1.1.1.1\n}\n\nimport /etc/coredns/custom/*.server\n", map[string]string{DefaultUpstreamAnnotation: "/etc/resolv.conf", UpstreamAnnotation: "1.1.1.1"}),
+				newDeployment("0123456789abcdef", true),
+				userCustomConfigMap.DeepCopy(),
+			},
+			expectedCorefile:   corefile,
+			expectedCustomKeys: []string{"user.server"},
+			expectedRolled:     true,
+		},
+		{
+			name: "Keep new default upstream nameservers",
+			objects: []client.Object{
+				newCorefileConfigMap(".:1053 {\n    forward . 10.96.0.10\n}\n", map[string]string{DefaultUpstreamAnnotation: "/etc/resolv.conf", UpstreamAnnotation: "1.1.1.1"}),
+				newDeployment("0123456789abcdef", true),
+			},
+			expectedCorefile: ".:1053 {\n    forward . 10.96.0.10\n}\n",
+			expectedRolled:   true,
+		},
+		{
+			name:               "Resolve host services",
+			objects:            []client.Object{newCorefileConfigMap(corefile, nil), newDeployment("", true)},
+			hostServices:       []coredns.HostService{{Namespace: "shared", Name: "postgres"}},
+			expectedCorefile:   corefile,
+			expectedCustomKeys: []string{"vcluster-hostservices.server"},
+			expectedRolled:     true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		fakeClient := fake.NewClientBuilder().WithObjects(testCase.objects...).Build()
+		r := &CoreDNSCustomizationReconciler{
+			Client:            fakeClient,
+			Log:               loghelper.New("test"),
+			Recorder:          record.NewFakeRecorder(10),
+			HostServices:      testCase.hostServices,
+			HostNameserver:    "10.96.0.10",
+			HostClusterDomain: "cluster.local",
+			ClusterDomain:     "cluster.local",
+		}
+		result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: Namespace, Name: CustomizationConfigMapName}})
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, result.RequeueAfter > 0, testCase.expectedRequeue, "unexpected requeue in test case %s", testCase.name)
+
+		corefileConfigMap := &corev1.ConfigMap{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: Namespace, Name: ConfigMapName}, corefileConfigMap)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, corefileConfigMap.Data[CorefileKey], testCase.expectedCorefile, "unexpected Corefile in test case %s", testCase.name)
+
+		customConfigMap := &corev1.ConfigMap{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: Namespace, Name: CustomConfigMapName}, customConfigMap)
+		assert.Assert(t, err == nil || len(testCase.expectedCustomKeys) == 0, "unexpected error in test case %s: %v", testCase.name, err)
+		customKeys := []string{}
+		for key := range customConfigMap.Data {
+			customKeys = append(customKeys, key)
+		}
+		assert.Equal(t, len(customKeys), len(testCase.expectedCustomKeys), "unexpected coredns-custom keys in test case %s: %v", testCase.name, customKeys)
+		for _, key := range testCase.expectedCustomKeys {
+			_, ok := customConfigMap.Data[key]
+			assert.Assert(t, ok, "missing coredns-custom key %s in test case %s", key, testCase.name)
+		}
+		if customConfigMap.Data["vcluster-stubdomains.server"] != "" {
+			assert.Assert(t, customConfigMap.Data["vcluster-stubdomains.server"] != "stale", "stale coredns-custom key in test case %s", testCase.name)
+		}
+
+		deployment := &appsv1.Deployment{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: Namespace, Name: DeploymentName}, deployment)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		configHash := deployment.Spec.Template.Annotations[ConfigHashAnnotation]
+		assert.Equal(t, configHash != "" && configHash != "0123456789abcdef", testCase.expectedRolled, "unexpected config hash in test case %s", testCase.name)
+	}
+}
@@ -0,0 +1,262 @@
+package expose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// ExposeAnnotation on a virtual service exposes the service through an ingress in the host cluster.
+	// The value is the name or number of the exposed port, or true for the first port.
+	ExposeAnnotation = "vcluster.loft.sh/expose"
+	// ExposeURLAnnotation is set on the virtual service to the url it is exposed at
+	ExposeURLAnnotation = "vcluster.loft.sh/expose-url"
+	// ExposedByLabel is set on the host ingresses created for exposed services
+	ExposedByLabel = "vcluster.loft.sh/exposed-by"
+)
+
+// ExposeReconciler creates an ingress in the host cluster for every virtual service with the expose
+// annotation, so tenants can make services reachable without permissions in the host cluster
+type ExposeReconciler struct {
+	VirtualClient client.Client
+	LocalClient   client.Client
+	Log           loghelper.Logger
+	Recorder      record.EventRecorder
+
+	// DomainTemplate is the host of the ingress, {name}, {namespace} and {vcluster} are replaced with the
+	// name and namespace of the virtual service and the name of the vcluster
+	DomainTemplate   string
+	IngressClassName string
+	// TLSSecretName is a secret in the host namespace that holds the certificate for the domain
+	TLSSecretName string
+}
+
+func (r *ExposeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	vService := &corev1.Service{}
+	err := r.VirtualClient.Get(ctx, req.NamespacedName, vService)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	_, exposed := vService.Annotations[ExposeAnnotation]
+	if kerrors.IsNotFound(err) || vService.DeletionTimestamp != nil || !exposed {
+		return ctrl.Result{}, r.unexpose(ctx, req.NamespacedName, vService)
+	}
+
+	// the ingress is owned by the host service, so it is deleted together with it
+	pService := &corev1.Service{}
+	err = r.LocalClient.Get(ctx, types.NamespacedName{Namespace: translate.Default.PhysicalNamespace(vService.Namespace), Name: translate.Default.PhysicalName(vService.Name, vService.Namespace)}, pService)
+	if kerrors.IsNotFound(err) {
+		r.Log.Debugf("wait for service %s/%s to be synced before exposing it", vService.Namespace, vService.Name)
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired, err := r.desiredIngress(vService, pService)
+	if err != nil {
+		r.Log.Infof("cannot expose service %s/%s: %v", vService.Namespace, vService.Name, err)
+		r.Recorder.Eventf(vService, corev1.EventTypeWarning, "ExposeFailed", "Cannot expose service: %v", err)
+		return ctrl.Result{}, r.unexpose(ctx, req.NamespacedName, vService)
+	}
+
+	ingress := &networkingv1.Ingress{}
+	err = r.LocalClient.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, ingress)
+	if kerrors.IsNotFound(err) {
+		r.Log.Infof("expose service %s/%s at %s", vService.Namespace, vService.Name, desired.Spec.Rules[0].Host)
+		err = r.LocalClient.Create(ctx, desired)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if err != nil {
+		return ctrl.Result{}, err
+	} else if ingress.Labels[ExposedByLabel] != translate.Suffix {
+		r.Recorder.Eventf(vService, corev1.EventTypeWarning, "ExposeFailed", "Cannot expose service, because ingress %s/%s already exists in the host cluster", desired.Namespace, desired.Name)
+		return ctrl.Result{}, nil
+	} else if !equality.Semantic.DeepEqual(ingress.Spec, desired.Spec) || !equality.Semantic.DeepEqual(ingress.OwnerReferences, desired.OwnerReferences) {
+		r.Log.Infof("update ingress %s/%s of exposed service %s/%s", desired.Namespace, desired.Name, vService.Namespace, vService.Name)
+		newIngress := ingress.DeepCopy()
+		newIngress.Spec = desired.Spec
+		newIngress.OwnerReferences = desired.OwnerReferences
+		err = r.LocalClient.Patch(ctx, newIngress, client.MergeFrom(ingress))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// tell the tenant where the service is reachable
+	url := "http://" + desired.Spec.Rules[0].Host
+	if len(desired.Spec.TLS) > 0 {
+		url = "https://" + desired.Spec.Rules[0].Host
+	}
+	if vService.Annotations[ExposeURLAnnotation] != url {
+		newService := vService.DeepCopy()
+		newService.Annotations[ExposeURLAnnotation] = url
+		err = r.VirtualClient.Patch(ctx, newService, client.MergeFrom(vService))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// unexpose deletes the host ingress of the service and removes the url annotation
+func (r *ExposeReconciler) unexpose(ctx context.Context, name types.NamespacedName, vService *corev1.Service) error {
+	ingress := &networkingv1.Ingress{}
+	err := r.LocalClient.Get(ctx, types.NamespacedName{Namespace: translate.Default.PhysicalNamespace(name.Namespace), Name: IngressName(name.Name, name.Namespace)}, ingress)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	} else if err == nil && ingress.Labels[ExposedByLabel] == translate.Suffix {
+		r.Log.Infof("delete ingress %s/%s of service %s/%s that is no longer exposed", ingress.Namespace, ingress.Name, name.Namespace, name.Name)
+		err = r.LocalClient.Delete(ctx, ingress)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if vService.Name == "" || vService.DeletionTimestamp != nil {
+		return nil
+	} else if _, ok := vService.Annotations[ExposeURLAnnotation]; ok {
+		newService := vService.DeepCopy()
+		delete(newService.Annotations, ExposeURLAnnotation)
+		return r.VirtualClient.Patch(ctx, newService, client.MergeFrom(vService))
+	}
+
+	return nil
+}
+
+// IngressName returns the name of the host ingress of an exposed service. It differs from the
+// translated name of the service, so it doesn't clash with a synced ingress of the same name.
+func IngressName(name, namespace string) string {
+	return translate.SafeConcatName(translate.Default.PhysicalName(name, namespace), "expose")
+}
+
+func (r *ExposeReconciler) desiredIngress(vService, pService *corev1.Service) (*networkingv1.Ingress, error) {
+	port, err := exposedPort(vService)
+	if err != nil {
+		return nil, err
+	}
+
+	host := ExpandDomainTemplate(r.DomainTemplate, vService.Name, vService.Namespace, translate.Suffix)
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid host %s: %s", host, strings.Join(errs, ", "))
+	}
+
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      IngressName(vService.Name, vService.Namespace),
+			Namespace: pService.Namespace,
+			Labels: map[string]string{
+				ExposedByLabel: translate.Suffix,
+			},
+			Annotations: map[string]string{
+				translate.NameAnnotation:      vService.Name,
+				translate.NamespaceAnnotation: vService.Namespace,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "Service",
+					Name:       pService.Name,
+					UID:        pService.UID,
+				},
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: pService.Name,
+											Port: networkingv1.ServiceBackendPort{Number: port},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if r.IngressClassName != "" {
+		ingressClassName := r.IngressClassName
+		ingress.Spec.IngressClassName = &ingressClassName
+	}
+	if r.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: r.TLSSecretName}}
+	}
+
+	return ingress, nil
+}
+
+// exposedPort returns the port of the service the expose annotation refers to
+func exposedPort(vService *corev1.Service) (int32, error) {
+	if vService.Spec.Type == corev1.ServiceTypeExternalName {
+		return 0, fmt.Errorf("services of type %s can't be exposed", corev1.ServiceTypeExternalName)
+	} else if len(vService.Spec.Ports) == 0 {
+		return 0, fmt.Errorf("service has no ports")
+	}
+
+	value := vService.Annotations[ExposeAnnotation]
+	if value == "" || value == "true" {
+		return vService.Spec.Ports[0].Port, nil
+	}
+	for _, port := range vService.Spec.Ports {
+		if port.Name == value || strconv.Itoa(int(port.Port)) == value {
+			return port.Port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("service has no port %s", value)
+}
+
+// ExpandDomainTemplate replaces {name}, {namespace} and {vcluster} in the domain template
+func ExpandDomainTemplate(domainTemplate, name, namespace, vclusterName string) string {
+	return strings.NewReplacer("{name}", name, "{namespace}", namespace, "{vcluster}", vclusterName).Replace(domainTemplate)
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *ExposeReconciler) SetupWithManager(virtualManager, localManager ctrl.Manager) error {
+	// revert changes to the host ingresses
+	return ctrl.NewControllerManagedBy(virtualManager).
+		Named("expose").
+		For(&corev1.Service{}).
+		WatchesRawSource(source.Kind(localManager.GetCache(), &networkingv1.Ingress{}), handler.EnqueueRequestsFromMapFunc(func(_ context.Context, object client.Object) []reconcile.Request {
+			if object == nil || object.GetLabels()[ExposedByLabel] != translate.Suffix {
+				return nil
+			}
+
+			annotations := object.GetAnnotations()
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: annotations[translate.NamespaceAnnotation], Name: annotations[translate.NameAnnotation]}}}
+		})).
+		Complete(r)
+}
@@ -0,0 +1,166 @@
+package expose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcile(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	vService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			Annotations: map[string]string{ExposeAnnotation: "http"},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "metrics", Port: 9090}, {Name: "http", Port: 8080}},
+		},
+	}
+	pService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName("web", "default"),
+			Namespace: "test",
+			UID:       "host-uid",
+		},
+	}
+	unexposedService := vService.DeepCopy()
+	unexposedService.Annotations = map[string]string{ExposeURLAnnotation: "https://web-default.apps.example.com"}
+	invalidPortService := vService.DeepCopy()
+	invalidPortService.Annotations[ExposeAnnotation] = "grpc"
+	existingIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      IngressName("web", "default"),
+			Namespace: "test",
+			Labels:    map[string]string{ExposedByLabel: translate.Suffix},
+		},
+	}
+	syncedIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pService.Name,
+			Namespace: "test",
+			Labels:    map[string]string{translate.MarkerLabel: translate.Suffix},
+		},
+	}
+	foreignIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      IngressName("web", "default"),
+			Namespace: "test",
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		virtualObjects  []client.Object
+		hostObjects     []client.Object
+		expectedHost    string
+		expectedPort    int32
+		expectedURL     string
+		expectedIngress bool
+		expectedEvent   bool
+		expectedSynced  bool
+	}{
+		{
+			name:            "Expose service",
+			virtualObjects:  []client.Object{vService.DeepCopy()},
+			hostObjects:     []client.Object{pService.DeepCopy()},
+			expectedHost:    "web-default.apps.example.com",
+			expectedPort:    8080,
+			expectedURL:     "https://web-default.apps.example.com",
+			expectedIngress: true,
+		},
+		{
+			name:            "Update existing ingress",
+			virtualObjects:  []client.Object{vService.DeepCopy()},
+			hostObjects:     []client.Object{pService.DeepCopy(), existingIngress.DeepCopy()},
+			expectedHost:    "web-default.apps.example.com",
+			expectedPort:    8080,
+			expectedURL:     "https://web-default.apps.example.com",
+			expectedIngress: true,
+		},
+		{
+			name:            "Keep synced ingress with the same name as the service",
+			virtualObjects:  []client.Object{vService.DeepCopy()},
+			hostObjects:     []client.Object{pService.DeepCopy(), syncedIngress.DeepCopy()},
+			expectedHost:    "web-default.apps.example.com",
+			expectedPort:    8080,
+			expectedURL:     "https://web-default.apps.example.com",
+			expectedIngress: true,
+			expectedSynced:  true,
+		},
+		{
+			name:           "Remove ingress of unexposed service",
+			virtualObjects: []client.Object{unexposedService.DeepCopy()},
+			hostObjects:    []client.Object{pService.DeepCopy(), existingIngress.DeepCopy()},
+		},
+		{
+			name:           "Invalid port",
+			virtualObjects: []client.Object{invalidPortService.DeepCopy()},
+			hostObjects:    []client.Object{pService.DeepCopy()},
+			expectedEvent:  true,
+		},
+		{
+			name:            "Keep foreign ingress",
+			virtualObjects:  []client.Object{vService.DeepCopy()},
+			hostObjects:     []client.Object{pService.DeepCopy(), foreignIngress.DeepCopy()},
+			expectedIngress: true,
+			expectedEvent:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		virtualClient := fake.NewClientBuilder().WithObjects(testCase.virtualObjects...).Build()
+		localClient := fake.NewClientBuilder().WithObjects(testCase.hostObjects...).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &ExposeReconciler{
+			VirtualClient:  virtualClient,
+			LocalClient:    localClient,
+			Log:            loghelper.New("test"),
+			Recorder:       recorder,
+			DomainTemplate: "{name}-{namespace}.apps.example.com",
+			TLSSecretName:  "wildcard-tls",
+		}
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}})
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, len(recorder.Events) > 0, testCase.expectedEvent, "unexpected events in test case %s", testCase.name)
+
+		service := &corev1.Service{}
+		err = virtualClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "web"}, service)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, service.Annotations[ExposeURLAnnotation], testCase.expectedURL, "unexpected url in test case %s", testCase.name)
+
+		err = localClient.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: pService.Name}, &networkingv1.Ingress{})
+		assert.Equal(t, err == nil, testCase.expectedSynced, "unexpected ingress with the service name in test case %s", testCase.name)
+
+		ingress := &networkingv1.Ingress{}
+		err = localClient.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: IngressName("web", "default")}, ingress)
+		if !testCase.expectedIngress {
+			assert.Assert(t, kerrors.IsNotFound(err), "expected ingress to be deleted in test case %s", testCase.name)
+			continue
+		}
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		if testCase.expectedHost == "" {
+			continue
+		}
+
+		assert.Equal(t, ingress.Spec.Rules[0].Host, testCase.expectedHost, "unexpected host in test case %s", testCase.name)
+		assert.Equal(t, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name, pService.Name, "unexpected backend in test case %s", testCase.name)
+		assert.Equal(t, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number, testCase.expectedPort, "unexpected port in test case %s", testCase.name)
+		assert.DeepEqual(t, ingress.Spec.TLS, []networkingv1.IngressTLS{{Hosts: []string{testCase.expectedHost}, SecretName: "wildcard-tls"}})
+		assert.Equal(t, string(ingress.OwnerReferences[0].UID), "host-uid", "unexpected owner in test case %s", testCase.name)
+	}
+}
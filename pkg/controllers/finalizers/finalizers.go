@@ -0,0 +1,302 @@
+package finalizers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StaleFinalizerReconciler removes the finalizers of custom resources that are stuck in deletion because
+// the operator that added them was uninstalled from the virtual cluster. A finalizer is considered stale,
+// if the object is terminating for longer than the timeout, no webhook of the finalizer domain exists
+// anymore and no running pod of the virtual cluster uses a service account that is allowed to update the
+// object, which an operator needs to remove its finalizer.
+type StaleFinalizerReconciler struct {
+	client.Client
+
+	// Reader lists the metadata of the custom resources, it is backed by the cache, so only
+	// metadata informers are started and the api server is not listed on every requeue
+	Reader client.Reader
+
+	Recorder record.EventRecorder
+	Timeout  time.Duration
+	Log      loghelper.Logger
+}
+
+func (r *StaleFinalizerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	err := r.Get(ctx, req.NamespacedName, crd)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	version := storageVersion(crd)
+	if version == "" {
+		return ctrl.Result{}, nil
+	}
+
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: crd.Spec.Names.Kind}
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err = r.Reader.List(ctx, list)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	webhooks, err := r.webhookNames(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// objects might get deleted at any time, so check again after the timeout
+	requeueAfter := r.Timeout
+	controllers := &controllerChecker{client: r.Client, resource: crd.Spec.Names.Plural, group: crd.Spec.Group, allowed: map[string]bool{}}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if obj.DeletionTimestamp == nil {
+			continue
+		}
+
+		stale := StaleFinalizers(obj.Finalizers, webhooks)
+		if len(stale) == 0 {
+			continue
+		}
+
+		terminating := time.Since(obj.DeletionTimestamp.Time)
+		if terminating < r.Timeout {
+			if r.Timeout-terminating < requeueAfter {
+				requeueAfter = r.Timeout - terminating
+			}
+			continue
+		}
+
+		// the finalizers are only removed if nothing in the virtual cluster could remove them anymore
+		running, err := controllers.running(ctx, obj.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if running != "" {
+			r.Log.Debugf("keep finalizers %s of %s %s, because pods of service account %s are allowed to remove them", strings.Join(stale, ", "), gvk.Kind, client.ObjectKeyFromObject(obj).String(), running)
+			continue
+		}
+
+		obj.SetGroupVersionKind(gvk)
+		err = r.removeFinalizers(ctx, obj, stale)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		r.Log.Infof("removed stale finalizers %s from %s %s, which was terminating since %s", strings.Join(stale, ", "), gvk.Kind, client.ObjectKeyFromObject(obj).String(), obj.DeletionTimestamp.UTC().Format(time.RFC3339))
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, "StaleFinalizersRemoved", "Removed finalizers %s, because neither a webhook nor a running controller of their owner exists anymore and the object was terminating for longer than %s", strings.Join(stale, ", "), r.Timeout)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *StaleFinalizerReconciler) removeFinalizers(ctx context.Context, obj *metav1.PartialObjectMetadata, stale []string) error {
+	original := obj.DeepCopy()
+	finalizers := []string{}
+	for _, finalizer := range obj.Finalizers {
+		if !contains(stale, finalizer) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+
+	obj.Finalizers = finalizers
+	err := r.Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// controllerChecker checks if a running pod of the virtual cluster uses a service account that is allowed to
+// update the custom resources, the results are remembered for a single reconcile
+type controllerChecker struct {
+	client client.Client
+
+	resource string
+	group    string
+
+	serviceAccounts []string
+	allowed         map[string]bool
+}
+
+// running returns the service account of a running pod that is allowed to update the custom resources in
+// the given namespace or an empty string if there is none
+func (c *controllerChecker) running(ctx context.Context, namespace string) (string, error) {
+	if c.serviceAccounts == nil {
+		podList := &corev1.PodList{}
+		err := c.client.List(ctx, podList)
+		if err != nil {
+			return "", err
+		}
+
+		serviceAccounts := map[string]bool{}
+		c.serviceAccounts = []string{}
+		for _, pod := range podList.Items {
+			if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+				continue
+			}
+
+			serviceAccount := pod.Namespace + "/" + pod.Spec.ServiceAccountName
+			if pod.Spec.ServiceAccountName == "" {
+				serviceAccount = pod.Namespace + "/default"
+			}
+			if !serviceAccounts[serviceAccount] {
+				serviceAccounts[serviceAccount] = true
+				c.serviceAccounts = append(c.serviceAccounts, serviceAccount)
+			}
+		}
+	}
+
+	for _, serviceAccount := range c.serviceAccounts {
+		key := serviceAccount + "@" + namespace
+		allowed, ok := c.allowed[key]
+		if !ok {
+			var err error
+			allowed, err = c.canUpdate(ctx, serviceAccount, namespace)
+			if err != nil {
+				return "", err
+			}
+
+			c.allowed[key] = allowed
+		}
+		if allowed {
+			return serviceAccount, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (c *controllerChecker) canUpdate(ctx context.Context, serviceAccount, namespace string) (bool, error) {
+	serviceAccountNamespace, serviceAccountName, _ := strings.Cut(serviceAccount, "/")
+	accessReview := &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:   serviceaccount.MakeUsername(serviceAccountNamespace, serviceAccountName),
+			Groups: append(serviceaccount.MakeGroupNames(serviceAccountNamespace), user.AllAuthenticated),
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "update",
+				Group:     c.group,
+				Resource:  c.resource,
+			},
+		},
+	}
+	err := c.client.Create(ctx, accessReview)
+	if err != nil {
+		return false, err
+	}
+
+	return accessReview.Status.Allowed && !accessReview.Status.Denied, nil
+}
+
+// webhookNames returns the names of all admission webhooks of the virtual cluster, which are fully
+// qualified and usually share the domain of the finalizers of their operator
+func (r *StaleFinalizerReconciler) webhookNames(ctx context.Context) ([]string, error) {
+	names := []string{}
+	mutatingWebhooks := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	err := r.List(ctx, mutatingWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range mutatingWebhooks.Items {
+		for _, webhook := range configuration.Webhooks {
+			names = append(names, webhook.Name)
+		}
+	}
+
+	validatingWebhooks := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	err = r.List(ctx, validatingWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range validatingWebhooks.Items {
+		for _, webhook := range configuration.Webhooks {
+			names = append(names, webhook.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// StaleFinalizers returns the finalizers that are neither kubernetes nor vcluster finalizers and
+// whose domain is not served by any of the given webhooks
+func StaleFinalizers(finalizers []string, webhooks []string) []string {
+	stale := []string{}
+	for _, finalizer := range finalizers {
+		domain, _, found := strings.Cut(finalizer, "/")
+		if !found || isReservedDomain(domain) {
+			continue
+		}
+
+		owned := false
+		for _, webhook := range webhooks {
+			if webhook == domain || strings.HasSuffix(webhook, "."+domain) {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			stale = append(stale, finalizer)
+		}
+	}
+
+	return stale
+}
+
+func isReservedDomain(domain string) bool {
+	for _, reserved := range []string{"kubernetes.io", "k8s.io", "loft.sh"} {
+		if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *StaleFinalizerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("stale_finalizers").
+		For(&apiextensionsv1.CustomResourceDefinition{}).
+		Complete(r)
+}
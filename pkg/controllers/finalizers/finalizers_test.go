@@ -0,0 +1,101 @@
+package finalizers
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestStaleFinalizers(t *testing.T) {
+	webhooks := []string{"webhook.cert-manager.io", "example.com"}
+	testCases := []struct {
+		name       string
+		finalizers []string
+		expected   []string
+	}{
+		{
+			name:       "Kubernetes finalizers",
+			finalizers: []string{"kubernetes", "foregroundDeletion", "kubernetes.io/pv-protection", "batch.kubernetes.io/job-tracking", "customresourcecleanup.apiextensions.k8s.io"},
+			expected:   []string{},
+		},
+		{
+			name:       "vcluster finalizers",
+			finalizers: []string{"vcluster.loft.sh/cleanup"},
+			expected:   []string{},
+		},
+		{
+			name:       "Finalizers of existing webhooks",
+			finalizers: []string{"cert-manager.io/finalizer", "example.com/finalizer"},
+			expected:   []string{},
+		},
+		{
+			name:       "Finalizers of uninstalled operators",
+			finalizers: []string{"operator.example.org/finalizer", "cert-manager.io/finalizer", "sub.example.com/finalizer"},
+			expected:   []string{"operator.example.org/finalizer", "sub.example.com/finalizer"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Assert(t, cmp.DeepEqual(StaleFinalizers(testCase.finalizers, webhooks), testCase.expected), "unexpected result in test case %s", testCase.name)
+	}
+}
+
+func TestControllerChecker(t *testing.T) {
+	newPod := func(namespace, name, serviceAccount string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.PodSpec{ServiceAccountName: serviceAccount},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	interceptorFuncs := interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			accessReview, ok := obj.(*authv1.SubjectAccessReview)
+			if !ok {
+				return c.Create(ctx, obj, opts...)
+			}
+
+			accessReview.Status.Allowed = accessReview.Spec.User == "system:serviceaccount:operator:manager" && accessReview.Spec.ResourceAttributes.Resource == "widgets"
+			return nil
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		pods     []client.Object
+		expected string
+	}{
+		{
+			name: "Running operator",
+			pods: []client.Object{
+				newPod("default", "app", "", corev1.PodRunning),
+				newPod("operator", "manager", "manager", corev1.PodRunning),
+			},
+			expected: "operator/manager",
+		},
+		{
+			name: "Uninstalled operator",
+			pods: []client.Object{
+				newPod("default", "app", "", corev1.PodRunning),
+				newPod("operator", "manager", "manager", corev1.PodFailed),
+			},
+			expected: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		fakeClient := fake.NewClientBuilder().WithObjects(testCase.pods...).WithInterceptorFuncs(interceptorFuncs).Build()
+		checker := &controllerChecker{client: fakeClient, resource: "widgets", group: "example.com", allowed: map[string]bool{}}
+		running, err := checker.running(context.Background(), "default")
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, running, testCase.expected, "unexpected result in test case %s", testCase.name)
+	}
+}
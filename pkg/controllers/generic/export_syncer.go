@@ -16,6 +16,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	patchesregex "github.com/loft-sh/vcluster/pkg/patches/regex"
 	util "github.com/loft-sh/vcluster/pkg/util/context"
@@ -143,8 +144,8 @@ func (f *exporter) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (c
 		return f.TranslateMetadata(ctx.Context, vObj), nil
 	}, &virtualToHostNameResolver{namespace: vObj.GetNamespace(), targetNamespace: translate.Default.PhysicalNamespace(vObj.GetNamespace())})
 	if err != nil {
-		f.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Error syncing to physical cluster: %v", err)
-		return ctrl.Result{}, fmt.Errorf("error applying patches: %v", err)
+		f.EventRecorder().Eventf(vObj, "Warning", syncerrors.Reason(err), "Error syncing to physical cluster: %v", err)
+		return ctrl.Result{}, fmt.Errorf("error applying patches: %w", err)
 	}
 
 	// wait here for vObj to be created
@@ -215,8 +216,8 @@ func (f *exporter) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj c
 			return ctrl.Result{Requeue: true}, nil
 		}
 
-		f.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Error syncing to virtual cluster: %v", err)
-		return ctrl.Result{}, fmt.Errorf("failed to patch virtual %s %s/%s: %v", f.config.Kind, vObj.GetNamespace(), vObj.GetName(), err)
+		f.EventRecorder().Eventf(vObj, "Warning", syncerrors.Reason(err), "Error syncing to virtual cluster: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to patch virtual %s %s/%s: %w", f.config.Kind, vObj.GetNamespace(), vObj.GetName(), err)
 	} else if result == controllerutil.OperationResultUpdated || result == controllerutil.OperationResultUpdatedStatus || result == controllerutil.OperationResultUpdatedStatusOnly {
 		// a change will trigger reconciliation anyway, and at that point we can make
 		// a more accurate updates(reverse patches) to the virtual resource
@@ -244,8 +245,8 @@ func (f *exporter) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj c
 			return ctrl.Result{}, nil
 		}
 
-		f.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Error syncing to physical cluster: %v", err)
-		return ctrl.Result{}, fmt.Errorf("error applying patches: %v", err)
+		f.EventRecorder().Eventf(vObj, "Warning", syncerrors.Reason(err), "Error syncing to physical cluster: %v", err)
+		return ctrl.Result{}, fmt.Errorf("error applying patches: %w", err)
 	}
 
 	return ctrl.Result{}, nil
@@ -0,0 +1,191 @@
+package hostimport
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ImportedFromAnnotation is set on imported virtual objects and holds the host object they were imported from
+const ImportedFromAnnotation = "vcluster.loft.sh/imported-from"
+
+// Importer mirrors the config maps or secrets of the host namespace that match a label selector
+// into a virtual namespace. The virtual objects are read-only, changes to them are reverted and
+// they are deleted when the host object is deleted or doesn't match the selector anymore.
+type Importer struct {
+	// Obj is either a config map or a secret
+	Obj client.Object
+
+	Selector         labels.Selector
+	HostNamespace    string
+	VirtualNamespace string
+
+	HostClient    client.Client
+	VirtualClient client.Client
+
+	Log loghelper.Logger
+}
+
+func (i *Importer) Register(name string, hostManager, virtualManager ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(virtualManager).
+		Named(name).
+		WatchesRawSource(source.Kind(hostManager.GetCache(), i.Obj.DeepCopyObject().(client.Object)), handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+			if obj.GetNamespace() != i.HostNamespace {
+				return nil
+			}
+
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: i.HostNamespace, Name: obj.GetName()}}}
+		})).
+		Watches(i.Obj.DeepCopyObject().(client.Object), handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+			if obj.GetNamespace() != i.VirtualNamespace {
+				return nil
+			}
+
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: i.HostNamespace, Name: obj.GetName()}}}
+		})).
+		Complete(i)
+}
+
+func (i *Importer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	hostObj, err := i.getHostObject(ctx, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	virtualObj := i.Obj.DeepCopyObject().(client.Object)
+	err = i.VirtualClient.Get(ctx, types.NamespacedName{Namespace: i.VirtualNamespace, Name: req.Name}, virtualObj)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		} else if hostObj == nil {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, i.create(ctx, hostObj)
+	} else if virtualObj.GetLabels()[translate.ControllerLabel] != "vcluster" {
+		// skip as it seems the object was user created
+		if hostObj != nil {
+			i.Log.Infof("skip importing %s, because virtual %s/%s already exists", req.NamespacedName, i.VirtualNamespace, req.Name)
+		}
+		return ctrl.Result{}, nil
+	} else if hostObj == nil {
+		i.Log.Infof("delete imported %s/%s, because the host object is missing or not selected anymore", i.VirtualNamespace, req.Name)
+		err = i.VirtualClient.Delete(ctx, virtualObj)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// secret types are immutable, so we recreate the secret
+	if hostSecret, ok := hostObj.(*corev1.Secret); ok && hostSecret.Type != virtualObj.(*corev1.Secret).Type {
+		i.Log.Infof("recreate imported %s/%s, because the secret type changed", i.VirtualNamespace, req.Name)
+		err = i.VirtualClient.Delete(ctx, virtualObj)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, i.create(ctx, hostObj)
+	}
+
+	updated := virtualObj.DeepCopyObject().(client.Object)
+	i.translate(hostObj, updated)
+	if !equality.Semantic.DeepEqual(updated, virtualObj) {
+		i.Log.Infof("update imported %s/%s, because it differs from the host object", i.VirtualNamespace, req.Name)
+		return ctrl.Result{}, i.VirtualClient.Update(ctx, updated)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// getHostObject returns the host object or nil if it doesn't exist or shouldn't be imported
+func (i *Importer) getHostObject(ctx context.Context, name types.NamespacedName) (client.Object, error) {
+	hostObj := i.Obj.DeepCopyObject().(client.Object)
+	err := i.HostClient.Get(ctx, name, hostObj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	// objects synced by vcluster are never imported
+	if translate.Default.IsManaged(hostObj) || !i.Selector.Matches(labels.Set(hostObj.GetLabels())) {
+		return nil, nil
+	}
+
+	return hostObj, nil
+}
+
+func (i *Importer) create(ctx context.Context, hostObj client.Object) error {
+	err := i.ensureNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	virtualObj := i.Obj.DeepCopyObject().(client.Object)
+	virtualObj.SetName(hostObj.GetName())
+	virtualObj.SetNamespace(i.VirtualNamespace)
+	i.translate(hostObj, virtualObj)
+	if hostSecret, ok := hostObj.(*corev1.Secret); ok {
+		virtualObj.(*corev1.Secret).Type = hostSecret.Type
+	}
+
+	i.Log.Infof("import %s/%s into %s", hostObj.GetNamespace(), hostObj.GetName(), i.VirtualNamespace)
+	return i.VirtualClient.Create(ctx, virtualObj)
+}
+
+func (i *Importer) ensureNamespace(ctx context.Context) error {
+	err := i.VirtualClient.Get(ctx, types.NamespacedName{Name: i.VirtualNamespace}, &corev1.Namespace{})
+	if err == nil || !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	err = i.VirtualClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: i.VirtualNamespace}})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// translate copies the labels, annotations and data of hostObj to virtualObj
+func (i *Importer) translate(hostObj, virtualObj client.Object) {
+	newLabels := map[string]string{}
+	for k, v := range hostObj.GetLabels() {
+		newLabels[k] = v
+	}
+	newLabels[translate.ControllerLabel] = "vcluster"
+	virtualObj.SetLabels(newLabels)
+
+	newAnnotations := map[string]string{}
+	for k, v := range hostObj.GetAnnotations() {
+		newAnnotations[k] = v
+	}
+	newAnnotations[ImportedFromAnnotation] = hostObj.GetNamespace() + "/" + hostObj.GetName()
+	virtualObj.SetAnnotations(newAnnotations)
+
+	switch host := hostObj.(type) {
+	case *corev1.ConfigMap:
+		virtual := virtualObj.(*corev1.ConfigMap)
+		virtual.Data = host.Data
+		virtual.BinaryData = host.BinaryData
+	case *corev1.Secret:
+		virtual := virtualObj.(*corev1.Secret)
+		virtual.Data = host.Data
+	}
+}
@@ -0,0 +1,154 @@
+package hostimport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcile(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	hostConfigMap := func(name string, objLabels map[string]string, data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    objLabels,
+			},
+			Data: map[string]string{"ca.crt": data},
+		}
+	}
+	selected := map[string]string{"trust": "bundle"}
+
+	testCases := []struct {
+		name string
+
+		hostObjs    []runtime.Object
+		virtualObjs []runtime.Object
+		request     string
+		obj         client.Object
+
+		expectedData    map[string]string
+		expectedMissing bool
+	}{
+		{
+			name:         "import selected config map",
+			hostObjs:     []runtime.Object{hostConfigMap("bundle", selected, "a")},
+			request:      "bundle",
+			obj:          &corev1.ConfigMap{},
+			expectedData: map[string]string{"ca.crt": "a"},
+		},
+		{
+			name:            "skip config map that is not selected",
+			hostObjs:        []runtime.Object{hostConfigMap("other", map[string]string{"trust": "other"}, "a")},
+			request:         "other",
+			obj:             &corev1.ConfigMap{},
+			expectedMissing: true,
+		},
+		{
+			name:     "revert changes of imported config map",
+			hostObjs: []runtime.Object{hostConfigMap("bundle", selected, "a")},
+			virtualObjs: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bundle",
+					Namespace: "default",
+					Labels:    map[string]string{translate.ControllerLabel: "vcluster"},
+				},
+				Data: map[string]string{"ca.crt": "changed"},
+			}},
+			request:      "bundle",
+			obj:          &corev1.ConfigMap{},
+			expectedData: map[string]string{"ca.crt": "a"},
+		},
+		{
+			name:     "delete imported config map when host config map is not selected anymore",
+			hostObjs: []runtime.Object{hostConfigMap("bundle", nil, "a")},
+			virtualObjs: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bundle",
+					Namespace: "default",
+					Labels:    map[string]string{translate.ControllerLabel: "vcluster"},
+				},
+			}},
+			request:         "bundle",
+			obj:             &corev1.ConfigMap{},
+			expectedMissing: true,
+		},
+		{
+			name:     "keep user created config map",
+			hostObjs: []runtime.Object{hostConfigMap("bundle", selected, "a")},
+			virtualObjs: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bundle",
+					Namespace: "default",
+				},
+				Data: map[string]string{"ca.crt": "user"},
+			}},
+			request:      "bundle",
+			obj:          &corev1.ConfigMap{},
+			expectedData: map[string]string{"ca.crt": "user"},
+		},
+		{
+			name: "import selected secret",
+			hostObjs: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "endpoint",
+					Namespace: "test",
+					Labels:    selected,
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: map[string][]byte{"url": []byte("https://example.com")},
+			}},
+			request:      "endpoint",
+			obj:          &corev1.Secret{},
+			expectedData: map[string]string{"url": "https://example.com"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		importer := &Importer{
+			Obj:              testCase.obj,
+			Selector:         labels.SelectorFromSet(selected),
+			HostNamespace:    "test",
+			VirtualNamespace: "default",
+			HostClient:       testingutil.NewFakeClient(testingutil.NewScheme(), testCase.hostObjs...),
+			VirtualClient:    testingutil.NewFakeClient(testingutil.NewScheme(), testCase.virtualObjs...),
+			Log:              loghelper.New("test"),
+		}
+
+		_, err := importer.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "test", Name: testCase.request}})
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		virtualObj := testCase.obj.DeepCopyObject().(client.Object)
+		err = importer.VirtualClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: testCase.request}, virtualObj)
+		if testCase.expectedMissing {
+			assert.Assert(t, kerrors.IsNotFound(err), "expected virtual object to be missing in test case %s", testCase.name)
+			continue
+		}
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		data := map[string]string{}
+		switch obj := virtualObj.(type) {
+		case *corev1.ConfigMap:
+			data = obj.Data
+		case *corev1.Secret:
+			for k, v := range obj.Data {
+				data[k] = string(v)
+			}
+		}
+		assert.DeepEqual(t, data, testCase.expectedData)
+	}
+}
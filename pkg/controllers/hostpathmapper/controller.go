@@ -0,0 +1,295 @@
+package hostpathmapper
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	controllercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// StatusAnnotation is set on the vcluster service and holds the health of the hostpath mapper
+	StatusAnnotation = "vcluster.loft.sh/hostpath-mapper-status"
+
+	// NodeNameEnvVar is read by the hostpath mapper to find the node it runs on
+	NodeNameEnvVar = "VCLUSTER_HOSTPATH_MAPPER_CURRENT_NODE_NAME"
+
+	// unhealthyRequeue is the interval the health is checked while not all mappers are ready
+	unhealthyRequeue = 30 * time.Second
+)
+
+// Reconciler deploys the hostpath mapper daemonset next to the syncer, so the hostpath
+// mapper doesn't need to be installed separately
+type Reconciler struct {
+	Client client.Client
+	Log    loghelper.Logger
+
+	Namespace   string
+	ServiceName string
+	Options     *controllercontext.VirtualClusterOptions
+}
+
+// New creates the reconciler, currentNamespaceClient has to be able to access the namespace vcluster runs in
+func New(ctx *controllercontext.ControllerContext, currentNamespaceClient client.Client) *Reconciler {
+	return &Reconciler{
+		Client:      currentNamespaceClient,
+		Log:         loghelper.New("hostpath-mapper-controller"),
+		Namespace:   ctx.CurrentNamespace,
+		ServiceName: ctx.Options.ServiceName,
+		Options:     ctx.Options,
+	}
+}
+
+// DaemonSetName returns the name of the managed hostpath mapper daemonset
+func DaemonSetName(vClusterName string) string {
+	return vClusterName + "-hostpath-mapper"
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	desired, err := r.DaemonSet()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: desired.Namespace, Name: desired.Name}}
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, daemonSet, func() error {
+		daemonSet.Labels = desired.Labels
+		daemonSet.OwnerReferences = desired.OwnerReferences
+		if daemonSet.Spec.Selector == nil {
+			daemonSet.Spec.Selector = desired.Spec.Selector
+		}
+		if !equality.Semantic.DeepDerivative(desired.Spec.Template, daemonSet.Spec.Template) {
+			daemonSet.Spec.Template = desired.Spec.Template
+		}
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("reconcile hostpath mapper daemonset: %w", err)
+	} else if result != controllerutil.OperationResultNone {
+		r.Log.Infof("hostpath mapper daemonset %s", result)
+	}
+
+	status, healthy := Health(daemonSet)
+	err = r.reportStatus(ctx, status)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !healthy {
+		return ctrl.Result{RequeueAfter: unhealthyRequeue}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reportStatus writes the hostpath mapper health to the vcluster service
+func (r *Reconciler) reportStatus(ctx context.Context, status string) error {
+	service := &corev1.Service{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.ServiceName}, service)
+	if err != nil {
+		return fmt.Errorf("get vcluster service: %w", err)
+	} else if service.Annotations[StatusAnnotation] == status {
+		return nil
+	}
+
+	patch := client.MergeFrom(service.DeepCopy())
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[StatusAnnotation] = status
+	return r.Client.Patch(ctx, service, patch)
+}
+
+// Health returns a human readable health of the daemonset and if all mappers are ready
+func Health(daemonSet *appsv1.DaemonSet) (string, bool) {
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return "Progressing", false
+	}
+
+	desired := daemonSet.Status.DesiredNumberScheduled
+	ready := daemonSet.Status.NumberReady
+	if ready < desired || daemonSet.Status.UpdatedNumberScheduled < desired {
+		return fmt.Sprintf("NotReady (%d/%d)", ready, desired), false
+	}
+
+	return fmt.Sprintf("Ready (%d/%d)", ready, desired), true
+}
+
+// vClusterName returns the name the virtual paths of the synced pods are built from
+func (r *Reconciler) vClusterName() string {
+	if r.Options.Name == "" {
+		return r.ServiceName
+	}
+
+	return r.Options.Name
+}
+
+// DaemonSet returns the desired hostpath mapper daemonset
+func (r *Reconciler) DaemonSet() (*appsv1.DaemonSet, error) {
+	if r.Options.HostpathMapperImage == "" {
+		return nil, fmt.Errorf("--hostpath-mapper-image is required to manage the hostpath mapper")
+	}
+
+	nodeSelector, err := parseKeyValues(r.Options.HostpathMapperNodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("parse hostpath mapper node selector: %w", err)
+	}
+
+	limits := corev1.ResourceList{}
+	rawLimits, err := parseKeyValues(r.Options.HostpathMapperLimits)
+	if err != nil {
+		return nil, fmt.Errorf("parse hostpath mapper limits: %w", err)
+	}
+	for name, value := range rawLimits {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("parse hostpath mapper limit %s: %w", name, err)
+		}
+		limits[corev1.ResourceName(name)] = quantity
+	}
+
+	// the volumes mirror the hostpath mapper daemonset of the chart, the virtual paths have to match the
+	// ones the host paths of the synced pods are rewritten to
+	name := r.vClusterName()
+	serviceAccountName := r.Options.HostpathMapperServiceAccount
+	if serviceAccountName == "" {
+		serviceAccountName = "vc-" + name
+	}
+	kubeConfigSecret := r.Options.KubeConfigSecret
+	if kubeConfigSecret == "" {
+		kubeConfigSecret = "vc-" + name
+	}
+	labels := map[string]string{
+		"app":       "vcluster-hostpath-mapper",
+		"component": "hostpath-mapper",
+		"release":   name,
+	}
+	virtualPath := fmt.Sprintf(podtranslate.VirtualPathTemplate, r.Namespace, name)
+	hostPathVolume := func(name, hostPath string) corev1.Volume {
+		return corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: hostPath}}}
+	}
+
+	args := []string{
+		"maphostpaths",
+		"--name=" + name,
+		"--target-namespace=" + r.Namespace,
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: "logs", MountPath: podtranslate.LogHostPath},
+		{Name: "virtual-logs", MountPath: path.Join(virtualPath, "log")},
+		{Name: "pod-logs", MountPath: podtranslate.PodLoggingHostPath},
+		{Name: "virtual-pod-logs", MountPath: path.Join(virtualPath, "log", "pods")},
+		{Name: "kubelet-pods", MountPath: podtranslate.PhysicalKubeletVolumeMountPath},
+		{Name: "virtual-kubelet-pods", MountPath: path.Join(virtualPath, "kubelet", "pods")},
+	}
+	volumes := []corev1.Volume{
+		hostPathVolume("logs", podtranslate.LogHostPath),
+		hostPathVolume("virtual-logs", path.Join(virtualPath, "log")),
+		hostPathVolume("pod-logs", podtranslate.PodLoggingHostPath),
+		hostPathVolume("kubelet-pods", podtranslate.KubeletPodPath),
+		hostPathVolume("virtual-pod-logs", path.Join(virtualPath, "log", "pods")),
+		hostPathVolume("virtual-kubelet-pods", path.Join(virtualPath, "kubelet", "pods")),
+	}
+	if r.Options.MapDockerContainerLogs {
+		args = append(args, "--map-docker-container-logs=true")
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{Name: "docker-containers", MountPath: podtranslate.PhysicalDockerContainersVolumeMountPath},
+			corev1.VolumeMount{Name: "virtual-docker-containers", MountPath: path.Join(virtualPath, "docker", "containers")},
+		)
+		volumes = append(volumes,
+			hostPathVolume("docker-containers", podtranslate.DockerContainersHostPath),
+			hostPathVolume("virtual-docker-containers", path.Join(virtualPath, "docker", "containers")),
+		)
+	}
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "kubeconfig", MountPath: "/data/server/tls"})
+	volumes = append(volumes, corev1.Volume{Name: "kubeconfig", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: kubeConfigSecret}}})
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       r.Namespace,
+			Name:            DaemonSetName(name),
+			Labels:          labels,
+			OwnerReferences: translate.GetOwnerReference(nil),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					NodeSelector:       nodeSelector,
+					Containers: []corev1.Container{
+						{
+							Name:    "hostpath-mapper",
+							Image:   r.Options.DefaultImageRegistry + r.Options.HostpathMapperImage,
+							Command: []string{"/vcluster"},
+							Args:    args,
+							Env: []corev1.EnvVar{
+								{
+									Name:      NodeNameEnvVar,
+									ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
+								},
+							},
+							Resources:    corev1.ResourceRequirements{Limits: limits},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}, nil
+}
+
+func parseKeyValues(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	ret := map[string]string{}
+	for _, value := range values {
+		key, val, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid value %s, expected key=value", value)
+		}
+		ret[key] = val
+	}
+	return ret, nil
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := DaemonSetName(r.vClusterName())
+	pp := func(object client.Object) bool {
+		return object.GetNamespace() == r.Namespace && (object.GetName() == name || object.GetName() == r.ServiceName)
+	}
+
+	// the daemonset and the vcluster service are reconciled as a single request, the service
+	// makes sure the daemonset is created initially
+	eventHandler := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: r.Namespace, Name: name}}}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("hostpath_mapper").
+		Watches(&appsv1.DaemonSet{}, eventHandler, builder.WithPredicates(predicate.NewPredicateFuncs(pp))).
+		Watches(&corev1.Service{}, eventHandler, builder.WithPredicates(predicate.NewPredicateFuncs(pp))).
+		Complete(r)
+}
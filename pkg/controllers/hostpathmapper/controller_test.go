@@ -0,0 +1,173 @@
+package hostpathmapper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	controllercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestDaemonSet(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options controllercontext.VirtualClusterOptions
+
+		expectedErr          bool
+		expectedImage        string
+		expectedNodeSelector map[string]string
+		expectedLimits       corev1.ResourceList
+		expectedAccount      string
+		expectedMounts       int
+	}{
+		{
+			name:        "missing image",
+			options:     controllercontext.VirtualClusterOptions{Name: "test"},
+			expectedErr: true,
+		},
+		{
+			name: "defaults",
+			options: controllercontext.VirtualClusterOptions{
+				Name:                "test",
+				HostpathMapperImage: "ghcr.io/loft-sh/vcluster:0.15.0",
+			},
+			expectedImage:   "ghcr.io/loft-sh/vcluster:0.15.0",
+			expectedLimits:  corev1.ResourceList{},
+			expectedAccount: "vc-test",
+			expectedMounts:  7,
+		},
+		{
+			name: "docker container logs",
+			options: controllercontext.VirtualClusterOptions{
+				Name:                   "test",
+				HostpathMapperImage:    "ghcr.io/loft-sh/vcluster:0.15.0",
+				MapDockerContainerLogs: true,
+			},
+			expectedImage:   "ghcr.io/loft-sh/vcluster:0.15.0",
+			expectedLimits:  corev1.ResourceList{},
+			expectedAccount: "vc-test",
+			expectedMounts:  9,
+		},
+		{
+			name: "node selector and limits",
+			options: controllercontext.VirtualClusterOptions{
+				Name:                         "test",
+				DefaultImageRegistry:         "registry.local/",
+				HostpathMapperImage:          "loft-sh/vcluster:0.15.0",
+				HostpathMapperServiceAccount: "mapper",
+				HostpathMapperNodeSelector:   []string{"kubernetes.io/os=linux"},
+				HostpathMapperLimits:         []string{"cpu=100m", "memory=128Mi"},
+			},
+			expectedImage:        "registry.local/loft-sh/vcluster:0.15.0",
+			expectedNodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			expectedLimits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			expectedAccount: "mapper",
+			expectedMounts:  7,
+		},
+		{
+			name: "invalid limit",
+			options: controllercontext.VirtualClusterOptions{
+				Name:                 "test",
+				HostpathMapperImage:  "loft-sh/vcluster",
+				HostpathMapperLimits: []string{"cpu"},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		r := &Reconciler{Namespace: "vcluster", ServiceName: "test", Options: &testCase.options}
+		daemonSet, err := r.DaemonSet()
+		if testCase.expectedErr {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, daemonSet.Name, "test-hostpath-mapper", "unexpected name in test case %s", testCase.name)
+		podSpec := daemonSet.Spec.Template.Spec
+		assert.Equal(t, podSpec.Containers[0].Image, testCase.expectedImage, "unexpected image in test case %s", testCase.name)
+		assert.Equal(t, podSpec.ServiceAccountName, testCase.expectedAccount, "unexpected service account in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(podSpec.NodeSelector, testCase.expectedNodeSelector), "unexpected node selector in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(podSpec.Containers[0].Resources.Limits, testCase.expectedLimits), "unexpected limits in test case %s", testCase.name)
+		assert.Equal(t, len(podSpec.Containers[0].VolumeMounts), testCase.expectedMounts, "unexpected volume mounts in test case %s", testCase.name)
+		assert.Equal(t, len(podSpec.Volumes), testCase.expectedMounts, "unexpected volumes in test case %s", testCase.name)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status appsv1.DaemonSetStatus
+
+		expectedStatus  string
+		expectedHealthy bool
+	}{
+		{
+			name:           "progressing",
+			status:         appsv1.DaemonSetStatus{ObservedGeneration: 1},
+			expectedStatus: "Progressing",
+		},
+		{
+			name:           "not ready",
+			status:         appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberReady: 1},
+			expectedStatus: "NotReady (1/3)",
+		},
+		{
+			name:            "ready",
+			status:          appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberReady: 3},
+			expectedStatus:  "Ready (3/3)",
+			expectedHealthy: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Generation: 2}, Status: testCase.status}
+		status, healthy := Health(daemonSet)
+		assert.Equal(t, status, testCase.expectedStatus, "unexpected status in test case %s", testCase.name)
+		assert.Equal(t, healthy, testCase.expectedHealthy, "unexpected health in test case %s", testCase.name)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "vcluster", Name: "test"}}
+	pClient := testingutil.NewFakeClient(testingutil.NewScheme(), service)
+	r := &Reconciler{
+		Client:      pClient,
+		Log:         loghelper.New("test"),
+		Namespace:   "vcluster",
+		ServiceName: "test",
+		Options: &controllercontext.VirtualClusterOptions{
+			Name:                "test",
+			HostpathMapperImage: "ghcr.io/loft-sh/vcluster",
+		},
+	}
+
+	// the fake client doesn't schedule any pods, so the empty daemonset is healthy
+	result, err := r.Reconcile(ctx, ctrl.Request{})
+	assert.NilError(t, err)
+	assert.Equal(t, result.RequeueAfter, time.Duration(0))
+
+	daemonSet := &appsv1.DaemonSet{}
+	err = pClient.Get(ctx, types.NamespacedName{Namespace: "vcluster", Name: "test-hostpath-mapper"}, daemonSet)
+	assert.NilError(t, err)
+	assert.Equal(t, daemonSet.Spec.Template.Spec.Containers[0].Image, "ghcr.io/loft-sh/vcluster")
+
+	err = pClient.Get(ctx, types.NamespacedName{Namespace: "vcluster", Name: "test"}, service)
+	assert.NilError(t, err)
+	assert.Equal(t, service.Annotations[StatusAnnotation], "Ready (0/0)")
+}
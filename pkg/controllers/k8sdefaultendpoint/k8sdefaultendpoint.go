@@ -3,13 +3,17 @@ package k8sdefaultendpoint
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	controllercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/services"
+	"github.com/loft-sh/vcluster/pkg/specialservices"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	corev1 "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -24,6 +28,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// driftCheckInterval is the interval the virtual kubernetes service, endpoints and endpoint
+// slice are compared to the physical service
+const driftCheckInterval = time.Minute
+
 type provider interface {
 	createClientObject() client.Object
 	createOrPatch(ctx context.Context, virtualClient client.Client, vEndpoints *corev1.Endpoints) error
@@ -67,7 +75,18 @@ func (e *EndpointController) Reconcile(ctx context.Context, req ctrl.Request) (c
 	if err != nil {
 		return ctrl.Result{RequeueAfter: time.Second}, err
 	}
-	return ctrl.Result{}, nil
+
+	// make sure port changes of the physical service are reflected in the virtual service
+	err = specialservices.SyncKubernetesService(ctx, e.VirtualClient, e.LocalClient, e.ServiceNamespace, e.ServiceName, types.NamespacedName{
+		Name:      specialservices.DefaultKubernetesSVCName,
+		Namespace: specialservices.DefaultKubernetesSVCNamespace,
+	}, services.TranslateServicePorts)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: time.Second}, err
+	}
+
+	// check periodically, so drift is repaired even if we missed an event
+	return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
 }
 
 // SetupWithManager adds the controller to the manager
@@ -87,8 +106,12 @@ func (e *EndpointController) SetupWithManager(mgr ctrl.Manager) error {
 		Named("kubernetes_default_endpoint").
 		For(&corev1.Endpoints{},
 			builder.WithPredicates(pfuncs, predicate.ResourceVersionChangedPredicate{})).
+		Watches(&corev1.Service{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(pfuncs, predicate.ResourceVersionChangedPredicate{})).
 		WatchesRawSource(source.Kind(e.VirtualManagerCache, &corev1.Endpoints{}),
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(vfuncs)).
+		WatchesRawSource(source.Kind(e.VirtualManagerCache, &corev1.Service{}),
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(vfuncs)).
 		WatchesRawSource(source.Kind(e.VirtualManagerCache, e.provider.createClientObject()),
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(vfuncs)).
 		Complete(e)
@@ -121,53 +144,89 @@ func (e *EndpointController) syncKubernetesServiceEndpoints(ctx context.Context,
 			vEndpoints.Labels = map[string]string{}
 		}
 		vEndpoints.Labels[discovery.LabelSkipMirror] = "true"
+		vEndpoints.Subsets = translateSubsets(pEndpoints.Subsets)
+		return nil
+	})
+	if err != nil {
+		return err
+	} else if result != controllerutil.OperationResultNone {
+		e.Log.Infof("%s virtual kubernetes endpoints", result)
+	}
 
-		// build new subsets
-		newSubsets := []corev1.EndpointSubset{}
-		for _, subset := range pEndpoints.Subsets {
-			newPorts := []corev1.EndpointPort{}
-			for _, p := range subset.Ports {
-				if p.Name != "https" {
-					continue
-				}
-
-				newPorts = append(newPorts, p)
-			}
+	// the endpoint slice is always checked, as it might have drifted independently of the endpoints
+	return e.provider.createOrPatch(ctx, virtualClient, vEndpoints)
+}
 
-			newAddresses := []corev1.EndpointAddress{}
-			for _, address := range subset.Addresses {
-				address.Hostname = ""
-				address.NodeName = nil
-				address.TargetRef = nil
-				newAddresses = append(newAddresses, address)
-			}
-			newNotReadyAddresses := []corev1.EndpointAddress{}
-			for _, address := range subset.NotReadyAddresses {
-				address.Hostname = ""
-				address.NodeName = nil
-				address.TargetRef = nil
-				newNotReadyAddresses = append(newNotReadyAddresses, address)
+// translateSubsets translates the subsets of the physical service endpoints. Subsets with the same
+// ports, e.g. of multiple api server replicas, are merged and their addresses are sorted, so
+// the virtual endpoints stay stable while replicas are added or removed.
+func translateSubsets(pSubsets []corev1.EndpointSubset) []corev1.EndpointSubset {
+	newSubsets := []corev1.EndpointSubset{}
+	for _, subset := range pSubsets {
+		newPorts := kubernetesPorts(subset.Ports)
+		newAddresses := translateAddresses(subset.Addresses)
+		newNotReadyAddresses := translateAddresses(subset.NotReadyAddresses)
+
+		merged := false
+		for i := range newSubsets {
+			if equality.Semantic.DeepEqual(newSubsets[i].Ports, newPorts) {
+				newSubsets[i].Addresses = append(newSubsets[i].Addresses, newAddresses...)
+				newSubsets[i].NotReadyAddresses = append(newSubsets[i].NotReadyAddresses, newNotReadyAddresses...)
+				merged = true
+				break
 			}
-
+		}
+		if !merged {
 			newSubsets = append(newSubsets, corev1.EndpointSubset{
 				Addresses:         newAddresses,
 				NotReadyAddresses: newNotReadyAddresses,
 				Ports:             newPorts,
 			})
 		}
+	}
 
-		vEndpoints.Subsets = newSubsets
-		return nil
-	})
-	if err != nil {
-		return nil
+	for i := range newSubsets {
+		sortAddresses(newSubsets[i].Addresses)
+		sortAddresses(newSubsets[i].NotReadyAddresses)
+	}
+
+	return newSubsets
+}
+
+// kubernetesPorts returns the https port of the api server. If the ports are named differently,
+// a single port is used as is.
+func kubernetesPorts(ports []corev1.EndpointPort) []corev1.EndpointPort {
+	newPorts := []corev1.EndpointPort{}
+	for _, p := range ports {
+		if p.Name != "https" {
+			continue
+		}
+
+		newPorts = append(newPorts, p)
+	}
+	if len(newPorts) == 0 && len(ports) == 1 {
+		newPorts = append(newPorts, ports[0])
 	}
 
-	if result == controllerutil.OperationResultCreated || result == controllerutil.OperationResultUpdated {
-		return e.provider.createOrPatch(ctx, virtualClient, vEndpoints)
+	return newPorts
+}
+
+func translateAddresses(addresses []corev1.EndpointAddress) []corev1.EndpointAddress {
+	newAddresses := []corev1.EndpointAddress{}
+	for _, address := range addresses {
+		address.Hostname = ""
+		address.NodeName = nil
+		address.TargetRef = nil
+		newAddresses = append(newAddresses, address)
 	}
 
-	return err
+	return newAddresses
+}
+
+func sortAddresses(addresses []corev1.EndpointAddress) {
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].IP < addresses[j].IP
+	})
 }
 
 // allAddressesIPv6 returns true if all provided addresses are IPv6.
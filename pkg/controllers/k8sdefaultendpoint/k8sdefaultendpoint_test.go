@@ -0,0 +1,113 @@
+package k8sdefaultendpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestTranslateSubsets(t *testing.T) {
+	httpsPorts := []corev1.EndpointPort{{Name: "https", Port: 6443, Protocol: corev1.ProtocolTCP}}
+	newPorts := []corev1.EndpointPort{{Name: "https", Port: 8443, Protocol: corev1.ProtocolTCP}}
+
+	subsets := translateSubsets([]corev1.EndpointSubset{
+		{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.2", Hostname: "vcluster-1"}},
+			Ports:     append(httpsPorts, corev1.EndpointPort{Name: "etcd", Port: 2379}),
+		},
+		{
+			Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1", Hostname: "vcluster-0"}},
+			NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}},
+			Ports:             httpsPorts,
+		},
+		{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.4"}},
+			Ports:     newPorts,
+		},
+	})
+	assert.DeepEqual(t, subsets, []corev1.EndpointSubset{
+		{
+			Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+			NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}},
+			Ports:             httpsPorts,
+		},
+		{
+			Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.4"}},
+			NotReadyAddresses: []corev1.EndpointAddress{},
+			Ports:             newPorts,
+		},
+	})
+
+	// a single port that is named differently is used as is
+	otherPorts := []corev1.EndpointPort{{Name: "api", Port: 443, Protocol: corev1.ProtocolTCP}}
+	subsets = translateSubsets([]corev1.EndpointSubset{{Ports: otherPorts}})
+	assert.DeepEqual(t, subsets[0].Ports, otherPorts)
+}
+
+func TestSyncKubernetesServiceEndpoints(t *testing.T) {
+	ctx := context.Background()
+	pEndpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vcluster",
+			Namespace: "test",
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}, {IP: "10.0.0.1"}},
+				Ports:     []corev1.EndpointPort{{Name: "https", Port: 8443, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+	}
+	driftedSlice := &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubernetes",
+			Namespace: "default",
+		},
+		AddressType: discovery.AddressTypeIPv4,
+		Endpoints:   []discovery.Endpoint{{Addresses: []string{"10.0.0.9"}}},
+	}
+
+	localClient := testingutil.NewFakeClient(testingutil.NewScheme(), pEndpoints)
+	virtualClient := testingutil.NewFakeClient(testingutil.NewScheme(), driftedSlice)
+	e := &EndpointController{
+		ServiceName:      "vcluster",
+		ServiceNamespace: "test",
+		LocalClient:      localClient,
+		VirtualClient:    virtualClient,
+		Log:              loghelper.New("test"),
+		provider:         &v1Provider{},
+	}
+
+	// the endpoint slice is repaired even though the endpoints are created
+	err := e.syncKubernetesServiceEndpoints(ctx, virtualClient, localClient, e.ServiceName, e.ServiceNamespace)
+	assert.NilError(t, err)
+
+	vEndpoints := &corev1.Endpoints{}
+	err = virtualClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "kubernetes"}, vEndpoints)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vEndpoints.Subsets[0].Addresses, []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}})
+
+	vSlice := &discovery.EndpointSlice{}
+	err = virtualClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "kubernetes"}, vSlice)
+	assert.NilError(t, err)
+	assert.Equal(t, len(vSlice.Endpoints), 2)
+	assert.DeepEqual(t, vSlice.Endpoints[0].Addresses, []string{"10.0.0.1"})
+	assert.Equal(t, *vSlice.Ports[0].Port, int32(8443))
+
+	// drift of the endpoint slice is repaired without any change of the endpoints
+	vSlice.Endpoints = nil
+	err = virtualClient.Update(ctx, vSlice)
+	assert.NilError(t, err)
+	err = e.syncKubernetesServiceEndpoints(ctx, virtualClient, localClient, e.ServiceName, e.ServiceNamespace)
+	assert.NilError(t, err)
+	err = virtualClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "kubernetes"}, vSlice)
+	assert.NilError(t, err)
+	assert.Equal(t, len(vSlice.Endpoints), 2)
+}
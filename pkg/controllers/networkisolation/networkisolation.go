@@ -0,0 +1,177 @@
+package networkisolation
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// ControlPlaneLabel selects the pods of the vcluster control plane
+	ControlPlaneLabel = "release"
+
+	// DNSNamespace and DNSLabel select the DNS pods of the host cluster
+	DNSNamespace  = "kube-system"
+	DNSLabel      = "k8s-app"
+	DNSLabelValue = "kube-dns"
+)
+
+// NetworkIsolationReconciler maintains a NetworkPolicy in the host namespace that only allows the synced
+// pods to talk to each other, the vcluster control plane and DNS
+type NetworkIsolationReconciler struct {
+	client.Client
+	Log loghelper.Logger
+
+	// Name of the vcluster, the control plane pods are labeled with release=<name>
+	Name string
+	// Namespace is the host namespace the pods are synced to
+	Namespace string
+	// ControlPlaneNamespace is the host namespace the vcluster control plane runs in
+	ControlPlaneNamespace string
+	// AllowedNamespaces are host namespaces that may connect to the synced pods, e.g. the namespace of
+	// an ingress controller
+	AllowedNamespaces []string
+}
+
+// PolicyName returns the name of the NetworkPolicy for the given vcluster
+func PolicyName(name string) string {
+	return name + "-workload-isolation"
+}
+
+func (r *NetworkIsolationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	desired := r.desiredNetworkPolicy()
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, networkPolicy)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		r.Log.Infof("create network policy %s/%s to isolate the synced pods", desired.Namespace, desired.Name)
+		err = r.Client.Create(ctx, desired)
+		if kerrors.IsAlreadyExists(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// revert manual changes
+	if equality.Semantic.DeepEqual(networkPolicy.Spec, desired.Spec) && networkPolicy.Labels[translate.MarkerLabel] == translate.Suffix {
+		return ctrl.Result{}, nil
+	}
+
+	r.Log.Infof("update network policy %s/%s, because it differs from the isolation policy", desired.Namespace, desired.Name)
+	newNetworkPolicy := networkPolicy.DeepCopy()
+	if newNetworkPolicy.Labels == nil {
+		newNetworkPolicy.Labels = map[string]string{}
+	}
+	newNetworkPolicy.Labels[translate.MarkerLabel] = translate.Suffix
+	newNetworkPolicy.Spec = desired.Spec
+	err = r.Client.Patch(ctx, newNetworkPolicy, client.MergeFrom(networkPolicy))
+	return ctrl.Result{}, err
+}
+
+// DeleteNetworkPolicy removes the network policy of the given vcluster after network isolation was turned off,
+// network policies that were not created by vcluster are kept
+func DeleteNetworkPolicy(ctx context.Context, reader client.Reader, writer client.Writer, namespace, name string) error {
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: PolicyName(name)}, networkPolicy)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	} else if networkPolicy.Labels[translate.MarkerLabel] != translate.Suffix {
+		return nil
+	}
+
+	err = writer.Delete(ctx, networkPolicy)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (r *NetworkIsolationReconciler) desiredNetworkPolicy() *networkingv1.NetworkPolicy {
+	workloads := metav1.LabelSelector{MatchLabels: map[string]string{translate.MarkerLabel: translate.Suffix}}
+	controlPlane := networkingv1.NetworkPolicyPeer{
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{ControlPlaneLabel: r.Name}},
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: r.ControlPlaneNamespace}},
+	}
+	dns := networkingv1.NetworkPolicyPeer{
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{DNSLabel: DNSLabelValue}},
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: DNSNamespace}},
+	}
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	dnsPort := intstr.FromInt(53)
+
+	ingressFrom := []networkingv1.NetworkPolicyPeer{{PodSelector: workloads.DeepCopy()}, controlPlane}
+	for _, namespace := range r.AllowedNamespaces {
+		ingressFrom = append(ingressFrom, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: namespace}},
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PolicyName(r.Name),
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				translate.MarkerLabel: translate.Suffix,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: workloads,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: ingressFrom},
+			},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{To: []networkingv1.NetworkPolicyPeer{{PodSelector: workloads.DeepCopy()}, controlPlane}},
+				// the DNS of the vcluster is a synced pod itself, the host DNS is needed to resolve
+				// host services and for pods in the host network
+				{
+					To: []networkingv1.NetworkPolicyPeer{dns},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &udp, Port: &dnsPort},
+						{Protocol: &tcp, Port: &dnsPort},
+					},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *NetworkIsolationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the network policy doesn't exist on the first start, so we trigger the first reconcile ourselves
+	events := make(chan event.GenericEvent, 1)
+	events <- event.GenericEvent{Object: r.desiredNetworkPolicy()}
+
+	p := func(object client.Object) bool {
+		return object.GetNamespace() == r.Namespace && object.GetName() == PolicyName(r.Name)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("network_isolation").
+		For(&networkingv1.NetworkPolicy{}, builder.WithPredicates(predicate.NewPredicateFuncs(p))).
+		WatchesRawSource(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{}).
+		Complete(r)
+}
@@ -0,0 +1,102 @@
+package networkisolation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcile(t *testing.T) {
+	changedPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PolicyName("vcluster"),
+			Namespace: "host",
+			Labels:    map[string]string{"team": "a"},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		},
+	}
+	testCases := []struct {
+		name    string
+		objects []client.Object
+	}{
+		{
+			name: "Create missing network policy",
+		},
+		{
+			name:    "Revert changed network policy",
+			objects: []client.Object{changedPolicy.DeepCopy()},
+		},
+	}
+
+	for _, testCase := range testCases {
+		fakeClient := fake.NewClientBuilder().WithObjects(testCase.objects...).Build()
+		r := &NetworkIsolationReconciler{
+			Client:                fakeClient,
+			Log:                   loghelper.New("test"),
+			Name:                  "vcluster",
+			Namespace:             "host",
+			ControlPlaneNamespace: "control-plane",
+			AllowedNamespaces:     []string{"ingress-nginx"},
+		}
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "host", Name: PolicyName("vcluster")}})
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		networkPolicy := &networkingv1.NetworkPolicy{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "host", Name: PolicyName("vcluster")}, networkPolicy)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, networkPolicy.Spec, r.desiredNetworkPolicy().Spec)
+		assert.Equal(t, networkPolicy.Labels[translate.MarkerLabel], translate.Suffix, "unexpected labels in test case %s", testCase.name)
+
+		// synced pods only accept connections from each other, the control plane and the allowed namespaces
+		ingressFrom := networkPolicy.Spec.Ingress[0].From
+		assert.Equal(t, len(ingressFrom), 3, "unexpected ingress peers in test case %s", testCase.name)
+		assert.Equal(t, ingressFrom[1].NamespaceSelector.MatchLabels[corev1.LabelMetadataName], "control-plane", "unexpected control plane namespace in test case %s", testCase.name)
+		assert.Equal(t, ingressFrom[2].NamespaceSelector.MatchLabels[corev1.LabelMetadataName], "ingress-nginx", "unexpected allowed namespace in test case %s", testCase.name)
+	}
+}
+
+func TestDeleteNetworkPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		labels   map[string]string
+		expected bool
+	}{
+		{
+			name:     "Delete network policy of vcluster",
+			labels:   map[string]string{translate.MarkerLabel: translate.Suffix},
+			expected: false,
+		},
+		{
+			name:     "Keep foreign network policy",
+			labels:   map[string]string{"team": "a"},
+			expected: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		fakeClient := fake.NewClientBuilder().WithObjects(&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PolicyName("vcluster"),
+				Namespace: "host",
+				Labels:    testCase.labels,
+			},
+		}).Build()
+		err := DeleteNetworkPolicy(context.TODO(), fakeClient, fakeClient, "host", "vcluster")
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "host", Name: PolicyName("vcluster")}, &networkingv1.NetworkPolicy{})
+		assert.Equal(t, err == nil, testCase.expected, "unexpected network policy in test case %s", testCase.name)
+	}
+}
@@ -0,0 +1,161 @@
+package nodelease
+
+import (
+	"context"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Namespace is the namespace the kubelets renew their node leases in
+	Namespace = corev1.NamespaceNodeLease
+
+	// LeaseDurationSeconds is the lease duration the kubelet uses by default
+	LeaseDurationSeconds = 40
+
+	// RenewInterval is the interval the kubelet renews its lease in by default
+	RenewInterval = 10 * time.Second
+)
+
+// NodeLeaseReconciler renews the node leases of the virtual nodes as long as the corresponding host node is
+// ready, like the kubelet does. Fake nodes are always considered ready, as are nodes that are kept for the
+// deletion grace period of a missing host node. This keeps the node lifecycle controller of the virtual cluster
+// from marking healthy nodes as unreachable.
+type NodeLeaseReconciler struct {
+	client.Client
+
+	// PhysicalClient reads the host nodes
+	PhysicalClient client.Client
+
+	Log loghelper.Logger
+}
+
+func (r *NodeLeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	vNode := &corev1.Node{}
+	err := r.Get(ctx, req.NamespacedName, vNode)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	} else if vNode.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	ready, err := r.hostNodeReady(ctx, vNode)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !ready {
+		// let the lease expire, but check again in case the host node recovers
+		return ctrl.Result{RequeueAfter: RenewInterval}, nil
+	}
+
+	lease := &coordinationv1.Lease{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: Namespace, Name: vNode.Name}, lease)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		r.Log.Debugf("create node lease %s", vNode.Name)
+		err = r.Create(ctx, NewLease(vNode, time.Now()))
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{RequeueAfter: RenewInterval}, nil
+	}
+
+	// the node might have changed in between, so only renew if the lease is due
+	if lease.Spec.RenewTime != nil {
+		renewedAgo := time.Since(lease.Spec.RenewTime.Time)
+		if renewedAgo >= 0 && renewedAgo < RenewInterval/2 {
+			return ctrl.Result{RequeueAfter: RenewInterval - renewedAgo}, nil
+		}
+	}
+
+	lease.Spec.HolderIdentity = pointer.String(vNode.Name)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(LeaseDurationSeconds)
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+	lease.OwnerReferences = ownerReferences(vNode)
+	err = r.Update(ctx, lease)
+	if err != nil && !kerrors.IsConflict(err) {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: RenewInterval}, nil
+}
+
+func (r *NodeLeaseReconciler) hostNodeReady(ctx context.Context, vNode *corev1.Node) (bool, error) {
+	if vNode.Labels[nodes.FakeNodeLabel] == "true" {
+		return true, nil
+	}
+
+	pNode := &corev1.Node{}
+	err := r.PhysicalClient.Get(ctx, types.NamespacedName{Name: vNode.Name}, pNode)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nodes.IsHostNodeMissing(vNode), nil
+		}
+		return false, err
+	}
+
+	return IsReady(pNode), nil
+}
+
+// IsReady returns true if the node reports the ready condition
+func IsReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// NewLease returns the lease of the node renewed at the given time
+func NewLease(node *corev1.Node, renewTime time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       Namespace,
+			Name:            node.Name,
+			OwnerReferences: ownerReferences(node),
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       pointer.String(node.Name),
+			LeaseDurationSeconds: pointer.Int32(LeaseDurationSeconds),
+			RenewTime:            &metav1.MicroTime{Time: renewTime},
+		},
+	}
+}
+
+// ownerReferences sets the node as owner of the lease, so the lease is garbage collected with the node
+func ownerReferences(node *corev1.Node) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Node",
+			Name:       node.Name,
+			UID:        node.UID,
+		},
+	}
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *NodeLeaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("node_lease").
+		For(&corev1.Node{}).
+		Complete(r)
+}
@@ -0,0 +1,89 @@
+package nodelease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"gotest.tools/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcile(t *testing.T) {
+	newNode := func(ready corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			},
+		}
+	}
+	missingNode := newNode(corev1.ConditionTrue)
+	missingNode.Status.Conditions = append(missingNode.Status.Conditions, corev1.NodeCondition{Type: nodes.ConditionHostNodeMissing, Status: corev1.ConditionTrue})
+	fakeNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{nodes.FakeNodeLabel: "true"}}}
+	staleTime := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name           string
+		virtualObjects []client.Object
+		hostObjects    []client.Object
+		expectRenewed  bool
+	}{
+		{
+			name:           "Create lease of fake node",
+			virtualObjects: []client.Object{fakeNode.DeepCopy()},
+			expectRenewed:  true,
+		},
+		{
+			name:           "Renew lease of ready host node",
+			virtualObjects: []client.Object{newNode(corev1.ConditionTrue), NewLease(newNode(corev1.ConditionTrue), staleTime)},
+			hostObjects:    []client.Object{newNode(corev1.ConditionTrue)},
+			expectRenewed:  true,
+		},
+		{
+			name:           "Keep lease of not ready host node",
+			virtualObjects: []client.Object{newNode(corev1.ConditionTrue), NewLease(newNode(corev1.ConditionTrue), staleTime)},
+			hostObjects:    []client.Object{newNode(corev1.ConditionUnknown)},
+		},
+		{
+			name:           "Keep lease of deleted host node",
+			virtualObjects: []client.Object{newNode(corev1.ConditionTrue), NewLease(newNode(corev1.ConditionTrue), staleTime)},
+		},
+		{
+			name:           "Renew lease of missing host node within grace period",
+			virtualObjects: []client.Object{missingNode.DeepCopy(), NewLease(missingNode, staleTime)},
+			expectRenewed:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		virtualClient := fake.NewClientBuilder().WithObjects(testCase.virtualObjects...).Build()
+		r := &NodeLeaseReconciler{
+			Client:         virtualClient,
+			PhysicalClient: fake.NewClientBuilder().WithObjects(testCase.hostObjects...).Build(),
+			Log:            loghelper.New("test"),
+		}
+		result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "node-1"}})
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, result.RequeueAfter, RenewInterval, "unexpected requeue in test case %s", testCase.name)
+
+		lease := &coordinationv1.Lease{}
+		err = virtualClient.Get(context.TODO(), types.NamespacedName{Namespace: Namespace, Name: "node-1"}, lease)
+		if kerrors.IsNotFound(err) {
+			assert.Assert(t, !testCase.expectRenewed, "expected lease in test case %s", testCase.name)
+			continue
+		}
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, time.Since(lease.Spec.RenewTime.Time) < RenewInterval, testCase.expectRenewed, "unexpected renew time in test case %s", testCase.name)
+		assert.Equal(t, *lease.Spec.HolderIdentity, "node-1", "unexpected holder in test case %s", testCase.name)
+	}
+}
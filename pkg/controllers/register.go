@@ -10,16 +10,22 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/generic"
+	"github.com/loft-sh/vcluster/pkg/controllers/hostimport"
 	"github.com/loft-sh/vcluster/pkg/controllers/servicesync"
 	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/inventory"
 	"github.com/loft-sh/vcluster/pkg/plugin"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
 	util "github.com/loft-sh/vcluster/pkg/util/context"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/k8sdefaultendpoint"
 	"github.com/loft-sh/vcluster/pkg/controllers/manifests"
@@ -33,11 +39,20 @@ import (
 	"github.com/loft-sh/vcluster/cmd/vcluster/context"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
 	"github.com/loft-sh/vcluster/pkg/controllers/coredns"
+	"github.com/loft-sh/vcluster/pkg/controllers/expose"
+	"github.com/loft-sh/vcluster/pkg/controllers/finalizers"
+	"github.com/loft-sh/vcluster/pkg/controllers/hostpathmapper"
+	"github.com/loft-sh/vcluster/pkg/controllers/networkisolation"
+	"github.com/loft-sh/vcluster/pkg/controllers/nodelease"
 	"github.com/loft-sh/vcluster/pkg/controllers/podsecurity"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/configmaps"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/endpoints"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/endpointslices"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/events"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/gateways"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/istio"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/leases"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/networkpolicies"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumeclaims"
@@ -45,14 +60,18 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/poddisruptionbudgets"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/pods"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/priorityclasses"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/routes"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/runtimeclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/secrets"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/services"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/storageclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshotclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshotcontents"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshots"
+	"github.com/loft-sh/vcluster/pkg/controllers/rootca"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corednsconfig "github.com/loft-sh/vcluster/pkg/coredns"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -61,18 +80,27 @@ import (
 var ResourceControllers = map[string][]func(*synccontext.RegisterContext) (syncer.Object, error){
 	"services":               {services.New},
 	"configmaps":             {configmaps.New},
-	"secrets":                {secrets.New},
+	"secrets,ingresses":      {secrets.New},
 	"endpoints":              {endpoints.New},
+	"endpointslices":         {endpointslices.New},
 	"pods":                   {pods.New},
 	"events":                 {events.New},
 	"persistentvolumeclaims": {persistentvolumeclaims.New},
 	"ingresses":              {ingresses.New},
 	"ingressclasses":         {ingressclasses.New},
+	"gateways":               {gateways.NewGateway},
+	"httproutes":             {gateways.NewHTTPRoute},
+	"grpcroutes":             {gateways.NewGRPCRoute},
+	"virtualservices":        {istio.NewVirtualService},
+	"destinationrules":       {istio.NewDestinationRule},
+	"routes":                 {routes.New},
 	"storageclasses":         {storageclasses.New},
 	"hoststorageclasses":     {storageclasses.NewHostStorageClassSyncer},
 	"priorityclasses":        {priorityclasses.New},
+	"runtimeclasses":         {runtimeclasses.New},
 	"nodes,fake-nodes":       {nodes.New},
 	"poddisruptionbudgets":   {poddisruptionbudgets.New},
+	"leases":                 {leases.New},
 	"networkpolicies":        {networkpolicies.New},
 	"volumesnapshots":        {volumesnapshotclasses.New, volumesnapshots.New, volumesnapshotcontents.New},
 	"serviceaccounts":        {serviceaccounts.New},
@@ -155,6 +183,17 @@ func RegisterControllers(ctx *context.ControllerContext, syncers []syncer.Object
 		return err
 	}
 
+	// make the synced physical state available to backup tooling through the operations api
+	if ctx.Operations != nil {
+		ctx.Operations.SetInventory(&inventory.Snapshotter{
+			PhysicalClient:         ctx.LocalManager.GetAPIReader(),
+			CurrentNamespace:       ctx.CurrentNamespace,
+			CurrentNamespaceClient: ctx.CurrentNamespaceClient,
+			Name:                   ctx.Options.Name,
+			Quiesce:                ctx.Operations.Quiesce,
+		})
+	}
+
 	// register controller that maintains pod security standard check
 	if ctx.Options.EnforcePodSecurityStandard != "" {
 		err := RegisterPodSecurityController(ctx)
@@ -163,12 +202,65 @@ func RegisterControllers(ctx *context.ControllerContext, syncers []syncer.Object
 		}
 	}
 
-	// register controller that keeps CoreDNS NodeHosts config up to date
+	// register controller that exposes virtual services through host ingresses
+	if ctx.Options.ExposeDomainTemplate != "" {
+		err := RegisterExposeController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// register controller that maintains the network isolation policy in the host namespace
+	if ctx.Options.EnforceNetworkIsolation {
+		err := RegisterNetworkIsolationController(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := RegisterNetworkIsolationCleanup(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// register controllers that keep CoreDNS NodeHosts and customization config up to date
 	err = RegisterCoreDNSController(ctx)
 	if err != nil {
 		return err
 	}
 
+	// register controller that removes finalizers of uninstalled operators
+	if ctx.Options.StaleFinalizerTimeout > 0 {
+		err = RegisterStaleFinalizerController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// register controller that renews the leases of the virtual nodes
+	if ctx.Options.SyncNodeLeases {
+		err = RegisterNodeLeaseController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// register controller that publishes the virtual cluster ca into every namespace
+	if ctx.Options.PublishRootCA {
+		err = RegisterRootCAController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// register controller that deploys the hostpath mapper daemonset
+	if ctx.Options.ManageHostpathMapper {
+		err = RegisterHostpathMapperController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	// register init manifests configmap watcher controller
 	err = RegisterInitManifestsController(ctx)
 	if err != nil {
@@ -181,6 +273,14 @@ func RegisterControllers(ctx *context.ControllerContext, syncers []syncer.Object
 		return err
 	}
 
+	// register importers for selected host config maps and secrets
+	if ctx.Options.HostConfigImportSelector != "" {
+		err = RegisterHostImportControllers(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = RegisterGenericSyncController(ctx)
 	if err != nil {
 		return err
@@ -244,34 +344,77 @@ func RegisterGenericSyncController(ctx *context.ControllerContext) error {
 	return nil
 }
 
-func RegisterInitManifestsController(ctx *context.ControllerContext) error {
-	currentNamespaceManager := ctx.LocalManager
-	if ctx.Options.TargetNamespace != ctx.CurrentNamespace {
-		var err error
-		currentNamespaceManager, err = ctrl.NewManager(ctx.LocalManager.GetConfig(), ctrl.Options{
-			Scheme: ctx.LocalManager.GetScheme(),
-			MapperProvider: func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
-				return ctx.LocalManager.GetRESTMapper(), nil
-			},
-			MetricsBindAddress: "0",
-			LeaderElection:     false,
-			Namespace:          ctx.CurrentNamespace,
-			NewClient:          pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
-		})
+func RegisterHostImportControllers(ctx *context.ControllerContext) error {
+	selector, err := labels.Parse(ctx.Options.HostConfigImportSelector)
+	if err != nil {
+		return errors.Wrap(err, "parse host config import selector")
+	}
+
+	hostNamespace := ctx.Options.TargetNamespace
+	if ctx.Options.MultiNamespaceMode {
+		hostNamespace = ctx.CurrentNamespace
+	}
+
+	for name, obj := range map[string]client.Object{
+		"configmap-importer": &corev1.ConfigMap{},
+		"secret-importer":    &corev1.Secret{},
+	} {
+		importer := &hostimport.Importer{
+			Obj:              obj,
+			Selector:         selector,
+			HostNamespace:    hostNamespace,
+			VirtualNamespace: ctx.Options.HostConfigImportNamespace,
+			HostClient:       ctx.LocalManager.GetClient(),
+			VirtualClient:    ctx.VirtualManager.GetClient(),
+			Log:              loghelper.New(name),
+		}
+		err = importer.Register(name, ctx.LocalManager, ctx.VirtualManager)
 		if err != nil {
-			return err
+			return errors.Wrapf(err, "register %s", name)
 		}
+	}
 
-		// start the manager
-		go func() {
-			err := currentNamespaceManager.Start(ctx.Context)
-			if err != nil {
-				panic(err)
-			}
-		}()
+	return nil
+}
 
-		// Wait for caches to be synced
-		currentNamespaceManager.GetCache().WaitForCacheSync(ctx.Context)
+// newCurrentNamespaceManager returns a manager for the namespace vcluster runs in, which is the local
+// manager unless the target namespace differs
+func newCurrentNamespaceManager(ctx *context.ControllerContext) (ctrl.Manager, error) {
+	if ctx.Options.TargetNamespace == ctx.CurrentNamespace {
+		return ctx.LocalManager, nil
+	}
+
+	currentNamespaceManager, err := ctrl.NewManager(ctx.LocalManager.GetConfig(), ctrl.Options{
+		Scheme: ctx.LocalManager.GetScheme(),
+		MapperProvider: func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
+			return ctx.LocalManager.GetRESTMapper(), nil
+		},
+		MetricsBindAddress: "0",
+		LeaderElection:     false,
+		Namespace:          ctx.CurrentNamespace,
+		NewClient:          pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// start the manager
+	go func() {
+		err := currentNamespaceManager.Start(ctx.Context)
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	// Wait for caches to be synced
+	currentNamespaceManager.GetCache().WaitForCacheSync(ctx.Context)
+	return currentNamespaceManager, nil
+}
+
+func RegisterInitManifestsController(ctx *context.ControllerContext) error {
+	currentNamespaceManager, err := newCurrentNamespaceManager(ctx)
+	if err != nil {
+		return err
 	}
 
 	vconfig, err := plugin.ConvertRestConfigToClientConfig(ctx.VirtualManager.GetConfig())
@@ -442,6 +585,130 @@ func RegisterCoreDNSController(ctx *context.ControllerContext) error {
 	if err != nil {
 		return fmt.Errorf("unable to setup CoreDNS NodeHosts controller: %v", err)
 	}
+
+	hostServices, err := corednsconfig.ParseHostServices(ctx.Options.HostServiceDNS)
+	if err != nil {
+		return err
+	}
+	hostClusterDomain := ctx.Options.HostClusterDomain
+	if hostClusterDomain == "" {
+		hostClusterDomain = corednsconfig.GetHostClusterDomain()
+	}
+	customizationController := &coredns.CoreDNSCustomizationReconciler{
+		Client:            ctx.VirtualManager.GetClient(),
+		Log:               loghelper.New("corednscustomization-controller"),
+		Recorder:          ctx.VirtualManager.GetEventRecorderFor("vcluster-coredns"),
+		HostServices:      hostServices,
+		HostNameserver:    corednsconfig.GetHostNameserver(),
+		HostClusterDomain: hostClusterDomain,
+		ClusterDomain:     ctx.Options.ClusterDomain,
+	}
+	err = customizationController.SetupWithManager(ctx.VirtualManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup CoreDNS customization controller: %v", err)
+	}
+	return nil
+}
+
+func RegisterHostpathMapperController(ctx *context.ControllerContext) error {
+	// the daemonset is deployed next to the syncer, which might not be the target namespace
+	currentNamespaceManager, err := newCurrentNamespaceManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = hostpathmapper.New(ctx, currentNamespaceManager.GetClient()).SetupWithManager(currentNamespaceManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup hostpath mapper controller: %v", err)
+	}
+	return nil
+}
+
+func RegisterStaleFinalizerController(ctx *context.ControllerContext) error {
+	controller := &finalizers.StaleFinalizerReconciler{
+		Client:   ctx.VirtualManager.GetClient(),
+		Reader:   ctx.VirtualManager.GetCache(),
+		Recorder: ctx.VirtualManager.GetEventRecorderFor("vcluster-stale-finalizers"),
+		Timeout:  ctx.Options.StaleFinalizerTimeout,
+		Log:      loghelper.New("stale-finalizer-controller"),
+	}
+	err := controller.SetupWithManager(ctx.VirtualManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup stale finalizer controller: %v", err)
+	}
+	return nil
+}
+
+func RegisterNodeLeaseController(ctx *context.ControllerContext) error {
+	controller := &nodelease.NodeLeaseReconciler{
+		Client:         ctx.VirtualManager.GetClient(),
+		PhysicalClient: ctx.LocalManager.GetClient(),
+		Log:            loghelper.New("node-lease-controller"),
+	}
+	err := controller.SetupWithManager(ctx.VirtualManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup node lease controller: %v", err)
+	}
+	return nil
+}
+
+func RegisterRootCAController(ctx *context.ControllerContext) error {
+	controller := &rootca.RootCAReconciler{
+		Client: ctx.VirtualManager.GetClient(),
+		CAFile: ctx.Options.ServerCaCert,
+		Log:    loghelper.New("root-ca-publisher"),
+	}
+	err := controller.SetupWithManager(ctx.VirtualManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup root ca publisher controller: %v", err)
+	}
+	return nil
+}
+
+func RegisterExposeController(ctx *context.ControllerContext) error {
+	controller := &expose.ExposeReconciler{
+		VirtualClient:    ctx.VirtualManager.GetClient(),
+		LocalClient:      ctx.LocalManager.GetClient(),
+		Log:              loghelper.New("expose-controller"),
+		Recorder:         ctx.VirtualManager.GetEventRecorderFor("vcluster-expose"),
+		DomainTemplate:   ctx.Options.ExposeDomainTemplate,
+		IngressClassName: ctx.Options.ExposeIngressClassName,
+		TLSSecretName:    ctx.Options.ExposeTLSSecret,
+	}
+	err := controller.SetupWithManager(ctx.VirtualManager, ctx.LocalManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup expose controller: %v", err)
+	}
+	return nil
+}
+
+func RegisterNetworkIsolationController(ctx *context.ControllerContext) error {
+	controller := &networkisolation.NetworkIsolationReconciler{
+		Client:                ctx.LocalManager.GetClient(),
+		Log:                   loghelper.New("network-isolation-controller"),
+		Name:                  ctx.Options.Name,
+		Namespace:             ctx.Options.TargetNamespace,
+		ControlPlaneNamespace: ctx.CurrentNamespace,
+		AllowedNamespaces:     ctx.Options.NetworkIsolationAllowedNamespaces,
+	}
+	err := controller.SetupWithManager(ctx.LocalManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup network isolation controller: %v", err)
+	}
+	return nil
+}
+
+// RegisterNetworkIsolationCleanup removes the network isolation policy, in case network isolation was enforced before
+func RegisterNetworkIsolationCleanup(ctx *context.ControllerContext) error {
+	if ctx.Options.MultiNamespaceMode {
+		return nil
+	}
+
+	err := networkisolation.DeleteNetworkPolicy(ctx.Context, ctx.LocalManager.GetAPIReader(), ctx.LocalManager.GetClient(), ctx.Options.TargetNamespace, ctx.Options.Name)
+	if err != nil {
+		// older charts don't allow vcluster to delete the network policy
+		klog.Infof("error removing network isolation policy: %v", err)
+	}
 	return nil
 }
 
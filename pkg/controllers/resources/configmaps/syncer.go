@@ -9,6 +9,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/podhelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -54,10 +55,7 @@ func (s *configMapSyncer) RegisterIndices(ctx *synccontext.RegisterContext) erro
 	}
 
 	// index pods by their used config maps
-	return ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &corev1.Pod{}, constants.IndexByConfigMap, func(rawObj client.Object) []string {
-		pod := rawObj.(*corev1.Pod)
-		return ConfigNamesFromPod(pod)
-	})
+	return ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &corev1.Pod{}, constants.IndexByConfigMap, podhelper.IndexReferencesOfRunningPods(ConfigNamesFromPod))
 }
 
 var _ syncer.ControllerModifier = &configMapSyncer{}
@@ -93,6 +91,11 @@ func (s *configMapSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object,
 		return ctrl.Result{}, nil
 	}
 
+	// immutable config maps can't be updated, so the physical config map is recreated
+	if reason := recreateReason(pObj.(*corev1.ConfigMap), vObj.(*corev1.ConfigMap)); reason != "" {
+		return s.SyncDownRecreate(ctx, vObj, pObj, s.translate(ctx.Context, vObj), reason)
+	}
+
 	newConfigMap := s.translateUpdate(ctx.Context, pObj.(*corev1.ConfigMap), vObj.(*corev1.ConfigMap))
 	if newConfigMap != nil {
 		translator.PrintChanges(pObj, newConfigMap, ctx.Log)
@@ -9,9 +9,11 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 )
 
 func TestSync(t *testing.T) {
@@ -66,6 +68,29 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	exceededQuota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: baseConfigMap.Namespace,
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceConfigMaps: resource.MustParse("1"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceConfigMaps: resource.MustParse("2"),
+			},
+		},
+	}
+
+	immutableConfigMap := updatedConfigMap.DeepCopy()
+	immutableConfigMap.Immutable = pointer.Bool(true)
+	immutableConfigMap.Data = map[string]string{"test": "changed"}
+	immutableSyncedConfigMap := updatedSyncedConfigMap.DeepCopy()
+	immutableSyncedConfigMap.Immutable = pointer.Bool(true)
+	recreatedSyncedConfigMap := immutableSyncedConfigMap.DeepCopy()
+	recreatedSyncedConfigMap.Data = immutableConfigMap.Data
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name: "Unused config map",
@@ -98,6 +123,24 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Used config map in namespace with exceeded quota",
+			InitialVirtualState: []runtime.Object{
+				baseConfigMap,
+				basePod,
+				exceededQuota,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("ConfigMap"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.EnforceVirtualResourceQuotas = true
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				result, err := syncer.(*configMapSyncer).SyncDown(syncCtx, baseConfigMap)
+				assert.NilError(t, err)
+				assert.Assert(t, result.RequeueAfter > 0)
+			},
+		},
 		{
 			Name: "Update used config map",
 			InitialVirtualState: []runtime.Object{
@@ -118,6 +161,26 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Recreate immutable config map",
+			InitialVirtualState: []runtime.Object{
+				immutableConfigMap,
+				basePod,
+			},
+			InitialPhysicalState: []runtime.Object{
+				immutableSyncedConfigMap,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("ConfigMap"): {
+					recreatedSyncedConfigMap,
+				},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*configMapSyncer).Sync(syncCtx, immutableSyncedConfigMap.DeepCopy(), immutableConfigMap)
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name: "Remove unused config map",
 			InitialVirtualState: []runtime.Object{
@@ -36,5 +36,25 @@ func (s *configMapSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev
 		updated.BinaryData = vObj.BinaryData
 	}
 
+	// check immutable
+	if !equality.Semantic.DeepEqual(vObj.Immutable, pObj.Immutable) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Immutable = vObj.Immutable
+	}
+
 	return updated
 }
+
+// recreateReason returns why the physical config map needs to be recreated instead of updated, which is
+// the case if the physical config map is immutable and the virtual config map was recreated with other contents
+func recreateReason(pObj, vObj *corev1.ConfigMap) string {
+	if pObj.Immutable == nil || !*pObj.Immutable {
+		return ""
+	} else if vObj.Immutable == nil || !*vObj.Immutable {
+		return "virtual config map is not immutable anymore"
+	} else if !equality.Semantic.DeepEqual(vObj.Data, pObj.Data) || !equality.Semantic.DeepEqual(vObj.BinaryData, pObj.BinaryData) {
+		return "data of immutable virtual config map has changed"
+	}
+
+	return ""
+}
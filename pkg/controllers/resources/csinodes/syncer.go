@@ -1,6 +1,8 @@
 package csinodes
 
 import (
+	"context"
+
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
@@ -9,7 +11,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
@@ -24,6 +29,22 @@ type csinodeSyncer struct {
 	virtualClient client.Client
 }
 
+var _ syncer.ControllerModifier = &csinodeSyncer{}
+
+func (s *csinodeSyncer) ModifyController(ctx *synccontext.RegisterContext, builder *builder.Builder) (*builder.Builder, error) {
+	// nodes are synced lazily, so the csinode has to be synced as soon as its node shows up in the virtual cluster
+	return builder.Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(mapNodes)), nil
+}
+
+func mapNodes(_ context.Context, obj client.Object) []reconcile.Request {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: node.Name}}}
+}
+
 var _ syncer.UpSyncer = &csinodeSyncer{}
 var _ syncer.Syncer = &csinodeSyncer{}
 
@@ -1,6 +1,7 @@
 package csinodes
 
 import (
+	"context"
 	"testing"
 
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
@@ -10,6 +11,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 )
@@ -159,6 +162,14 @@ func TestSync(t *testing.T) {
 	})
 }
 
+func TestMapNodes(t *testing.T) {
+	requests := mapNodes(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}})
+	assert.DeepEqual(t, requests, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "test-node"}}})
+
+	requests = mapNodes(context.Background(), &storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}})
+	assert.Equal(t, len(requests), 0)
+}
+
 func intRef(i int32) *int32 {
 	return &i
 }
@@ -23,6 +23,8 @@ func (s *csistoragecapacitySyncer) fetchVirtualStorageClass(ctx *synccontext.Syn
 		err := clienthelper.GetByIndex(ctx.Context, ctx.VirtualClient, sc, constants.IndexByPhysicalName, physName)
 		if errors.IsNotFound(err) {
 			return "", true, nil
+		} else if err != nil {
+			return "", false, err
 		}
 		return sc.Name, false, nil
 	}
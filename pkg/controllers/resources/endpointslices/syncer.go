@@ -0,0 +1,66 @@
+package endpointslices
+
+import (
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagedBy is the value of the managed by label of synced endpoint slices, so the endpoint
+// slice controllers of the host cluster leave them alone
+const ManagedBy = "vcluster.loft.sh"
+
+// kubernetesManagers are the controllers that maintain the endpoint slices of services with a selector
+// and of endpoints. As services and endpoints are synced themselves, the host creates these slices.
+var kubernetesManagers = map[string]bool{
+	"endpointslice-controller.k8s.io":          true,
+	"endpointslicemirroring-controller.k8s.io": true,
+}
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &endpointSliceSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "endpointslice", &discoveryv1.EndpointSlice{}),
+	}, nil
+}
+
+type endpointSliceSyncer struct {
+	translator.NamespacedTranslator
+}
+
+var _ syncer.Syncer = &endpointSliceSyncer{}
+
+func (s *endpointSliceSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	if !shouldSync(vObj.(*discoveryv1.EndpointSlice)) {
+		return ctrl.Result{}, nil
+	}
+
+	newEndpointSlice, err := s.translate(ctx, vObj.(*discoveryv1.EndpointSlice))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return s.SyncDownCreate(ctx, vObj, newEndpointSlice)
+}
+
+func (s *endpointSliceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	if !shouldSync(vObj.(*discoveryv1.EndpointSlice)) {
+		return syncer.DeleteObject(ctx, pObj, "endpoint slice is managed by kubernetes")
+	}
+
+	newEndpointSlice, err := s.translateUpdate(ctx, pObj.(*discoveryv1.EndpointSlice), vObj.(*discoveryv1.EndpointSlice))
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if newEndpointSlice != nil {
+		translator.PrintChanges(pObj, newEndpointSlice, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, newEndpointSlice)
+}
+
+// shouldSync checks if the endpoint slice is maintained manually or by a custom controller for a service
+func shouldSync(endpointSlice *discoveryv1.EndpointSlice) bool {
+	return endpointSlice.Labels[discoveryv1.LabelServiceName] != "" && !kubernetesManagers[endpointSlice.Labels[discoveryv1.LabelManagedBy]]
+}
@@ -0,0 +1,188 @@
+package endpointslices
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+func TestSync(t *testing.T) {
+	vEndpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-abc",
+			Namespace: "test",
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: "external",
+				discoveryv1.LabelManagedBy:   "external-controller",
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses: []string{"10.0.0.1"},
+			},
+			{
+				Addresses: []string{"10.0.0.2"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "test", Name: "pod", UID: "123"},
+			},
+		},
+		Ports: []discoveryv1.EndpointPort{{Name: pointer.String("http"), Port: pointer.Int32(80)}},
+	}
+	pEndpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName(vEndpointSlice.Name, vEndpointSlice.Namespace),
+			Namespace: "test",
+			Annotations: map[string]string{
+				translate.NameAnnotation:      vEndpointSlice.Name,
+				translate.NamespaceAnnotation: vEndpointSlice.Namespace,
+				translate.UIDAnnotation:       "",
+			},
+			Labels: map[string]string{
+				translate.NamespaceLabel: vEndpointSlice.Namespace,
+				translate.Default.ConvertLabelKey(discoveryv1.LabelServiceName): "external",
+				translate.Default.ConvertLabelKey(discoveryv1.LabelManagedBy):   "external-controller",
+				discoveryv1.LabelServiceName:                                    translate.Default.PhysicalName("external", "test"),
+				discoveryv1.LabelManagedBy:                                      ManagedBy,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses: []string{"10.0.0.1"},
+			},
+			{
+				Addresses: []string{"10.0.0.2"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "test", Name: translate.Default.PhysicalName("pod", "test")},
+			},
+		},
+		Ports: vEndpointSlice.Ports,
+	}
+
+	vUpdatedEndpointSlice := vEndpointSlice.DeepCopy()
+	vUpdatedEndpointSlice.Endpoints = vUpdatedEndpointSlice.Endpoints[:1]
+	pUpdatedEndpointSlice := pEndpointSlice.DeepCopy()
+	pUpdatedEndpointSlice.Endpoints = pUpdatedEndpointSlice.Endpoints[:1]
+
+	vManagedEndpointSlice := vEndpointSlice.DeepCopy()
+	vManagedEndpointSlice.Labels[discoveryv1.LabelManagedBy] = "endpointslice-controller.k8s.io"
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                "Forward create",
+			InitialVirtualState: []runtime.Object{vEndpointSlice},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"): {pEndpointSlice},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*endpointSliceSyncer).SyncDown(syncCtx, vEndpointSlice)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Forward update",
+			InitialVirtualState:  []runtime.Object{vUpdatedEndpointSlice},
+			InitialPhysicalState: []runtime.Object{pEndpointSlice},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"): {pUpdatedEndpointSlice},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*endpointSliceSyncer).Sync(syncCtx, pEndpointSlice, vUpdatedEndpointSlice)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Don't sync endpoint slices managed by kubernetes",
+			InitialVirtualState: []runtime.Object{vManagedEndpointSlice},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*endpointSliceSyncer).SyncDown(syncCtx, vManagedEndpointSlice)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Delete endpoint slices that became managed by kubernetes",
+			InitialVirtualState:  []runtime.Object{vManagedEndpointSlice},
+			InitialPhysicalState: []runtime.Object{pEndpointSlice},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*endpointSliceSyncer).Sync(syncCtx, pEndpointSlice, vManagedEndpointSlice)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
+
+func TestTranslateEndpointsZones(t *testing.T) {
+	endpoint := func(nodeName, zone string, hints ...string) discoveryv1.Endpoint {
+		endpoint := discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}
+		if nodeName != "" {
+			endpoint.NodeName = pointer.String(nodeName)
+		}
+		if zone != "" {
+			endpoint.Zone = pointer.String(zone)
+		}
+		if len(hints) > 0 {
+			endpoint.Hints = &discoveryv1.EndpointHints{}
+			for _, hint := range hints {
+				endpoint.Hints.ForZones = append(endpoint.Hints.ForZones, discoveryv1.ForZone{Name: hint})
+			}
+		}
+		return endpoint
+	}
+
+	testCases := []struct {
+		name string
+
+		endpoints []discoveryv1.Endpoint
+		zones     map[string]string
+
+		expectedEndpoints []discoveryv1.Endpoint
+	}{
+		{
+			name:              "zones and hints match the host",
+			endpoints:         []discoveryv1.Endpoint{endpoint("node-a", "zone-a", "zone-a", "zone-b"), endpoint("node-b", "zone-b")},
+			zones:             map[string]string{"node-a": "zone-a", "node-b": "zone-b"},
+			expectedEndpoints: []discoveryv1.Endpoint{endpoint("node-a", "zone-a", "zone-a", "zone-b"), endpoint("node-b", "zone-b")},
+		},
+		{
+			name:              "zone without hints",
+			endpoints:         []discoveryv1.Endpoint{endpoint("node-a", "")},
+			zones:             map[string]string{"node-a": "zone-a"},
+			expectedEndpoints: []discoveryv1.Endpoint{endpoint("node-a", "zone-a")},
+		},
+		{
+			name:              "hints are recomputed if a zone differs",
+			endpoints:         []discoveryv1.Endpoint{endpoint("node-a", "zone-b", "zone-b"), endpoint("node-b", "zone-b", "zone-b")},
+			zones:             map[string]string{"node-a": "zone-a", "node-b": "zone-b"},
+			expectedEndpoints: []discoveryv1.Endpoint{endpoint("node-a", "zone-a", "zone-a"), endpoint("node-b", "zone-b", "zone-b")},
+		},
+		{
+			name:              "hints are removed if an endpoint has no zone",
+			endpoints:         []discoveryv1.Endpoint{endpoint("node-a", "zone-b", "zone-b"), endpoint("", "", "zone-b")},
+			zones:             map[string]string{"node-a": "zone-a"},
+			expectedEndpoints: []discoveryv1.Endpoint{endpoint("node-a", "zone-a"), endpoint("", "")},
+		},
+	}
+
+	for _, testCase := range testCases {
+		endpoints := translateEndpoints("test", testCase.endpoints, testCase.zones)
+		assert.DeepEqual(t, endpoints, testCase.expectedEndpoints)
+	}
+}
@@ -0,0 +1,145 @@
+package endpointslices
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (s *endpointSliceSyncer) translate(ctx *synccontext.SyncContext, vEndpointSlice *discoveryv1.EndpointSlice) (*discoveryv1.EndpointSlice, error) {
+	zones, err := nodeZones(ctx, vEndpointSlice.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointSlice := s.TranslateMetadata(ctx.Context, vEndpointSlice).(*discoveryv1.EndpointSlice)
+	endpointSlice.Labels = translateLabels(endpointSlice.Labels, vEndpointSlice)
+	endpointSlice.Endpoints = translateEndpoints(vEndpointSlice.Namespace, vEndpointSlice.Endpoints, zones)
+	return endpointSlice, nil
+}
+
+func (s *endpointSliceSyncer) translateUpdate(ctx *synccontext.SyncContext, pObj, vObj *discoveryv1.EndpointSlice) (*discoveryv1.EndpointSlice, error) {
+	var updated *discoveryv1.EndpointSlice
+
+	zones, err := nodeZones(ctx, vObj.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	translatedEndpoints := translateEndpoints(vObj.Namespace, vObj.Endpoints, zones)
+	if !equality.Semantic.DeepEqual(translatedEndpoints, pObj.Endpoints) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Endpoints = translatedEndpoints
+	}
+
+	if !equality.Semantic.DeepEqual(vObj.Ports, pObj.Ports) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Ports = vObj.Ports
+	}
+
+	_, annotations, labels := s.TranslateMetadataUpdate(ctx.Context, vObj, pObj)
+	labels = translateLabels(labels, vObj)
+	if !equality.Semantic.DeepEqual(annotations, pObj.Annotations) || !equality.Semantic.DeepEqual(labels, pObj.Labels) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Annotations = annotations
+		updated.Labels = labels
+	}
+
+	return updated, nil
+}
+
+// translateLabels points the endpoint slice to the physical service, kube-proxy and the dns of
+// the host cluster find the endpoint slices of a service by these labels
+func translateLabels(labels map[string]string, vEndpointSlice *discoveryv1.EndpointSlice) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[discoveryv1.LabelServiceName] = translate.Default.PhysicalName(vEndpointSlice.Labels[discoveryv1.LabelServiceName], vEndpointSlice.Namespace)
+	labels[discoveryv1.LabelManagedBy] = ManagedBy
+	return labels
+}
+
+// nodeZones returns the zones of the nodes of the endpoints. Virtual nodes carry the zone of the
+// host node, either synced from the host node or set on fake nodes with --fake-node-topology.
+func nodeZones(ctx *synccontext.SyncContext, vEndpoints []discoveryv1.Endpoint) (map[string]string, error) {
+	zones := map[string]string{}
+	for _, vEndpoint := range vEndpoints {
+		if vEndpoint.NodeName == nil || *vEndpoint.NodeName == "" {
+			continue
+		} else if _, ok := zones[*vEndpoint.NodeName]; ok {
+			continue
+		}
+
+		node := &corev1.Node{}
+		err := ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Name: *vEndpoint.NodeName}, node)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		zones[*vEndpoint.NodeName] = node.Labels[corev1.LabelTopologyZone]
+	}
+
+	return zones, nil
+}
+
+// translateEndpoints rewrites the target pods of the endpoints and sets the zone of the host node
+// on every endpoint, as kube-proxy of the host routes by these zones. If a zone differs from the
+// one the tenant specified, the topology hints of the slice don't match the host anymore and are
+// recomputed to route every endpoint within its own zone, or removed if not every endpoint has
+// a zone, as kube-proxy ignores the hints then anyway.
+func translateEndpoints(namespace string, vEndpoints []discoveryv1.Endpoint, zones map[string]string) []discoveryv1.Endpoint {
+	endpoints := []discoveryv1.Endpoint{}
+	recomputeHints := false
+	for _, vEndpoint := range vEndpoints {
+		endpoint := *vEndpoint.DeepCopy()
+		if endpoint.NodeName != nil && zones[*endpoint.NodeName] != "" {
+			zone := zones[*endpoint.NodeName]
+			if endpoint.Zone == nil || *endpoint.Zone != zone {
+				endpoint.Zone = &zone
+				recomputeHints = true
+			}
+		}
+		if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+			targetNamespace := endpoint.TargetRef.Namespace
+			if targetNamespace == "" {
+				targetNamespace = namespace
+			}
+
+			endpoint.TargetRef.Name = translate.Default.PhysicalName(endpoint.TargetRef.Name, targetNamespace)
+			endpoint.TargetRef.Namespace = translate.Default.PhysicalNamespace(targetNamespace)
+			endpoint.TargetRef.UID = ""
+			endpoint.TargetRef.ResourceVersion = ""
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+	if recomputeHints {
+		recomputeEndpointHints(endpoints)
+	}
+
+	return endpoints
+}
+
+func recomputeEndpointHints(endpoints []discoveryv1.Endpoint) {
+	hasHints, allZones := false, true
+	for _, endpoint := range endpoints {
+		hasHints = hasHints || endpoint.Hints != nil
+		allZones = allZones && endpoint.Zone != nil && *endpoint.Zone != ""
+	}
+	if !hasHints {
+		return
+	}
+
+	for i := range endpoints {
+		endpoints[i].Hints = nil
+		if allZones {
+			endpoints[i].Hints = &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: *endpoints[i].Zone}}}
+		}
+	}
+}
@@ -22,22 +22,35 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var AcceptedKinds = map[schema.GroupVersionKind]bool{
-	corev1.SchemeGroupVersion.WithKind("Pod"):       true,
-	corev1.SchemeGroupVersion.WithKind("Service"):   true,
-	corev1.SchemeGroupVersion.WithKind("Endpoint"):  true,
-	corev1.SchemeGroupVersion.WithKind("Secret"):    true,
-	corev1.SchemeGroupVersion.WithKind("ConfigMap"): true,
+// AcceptedKinds maps the kinds of involved objects whose host events are synced to the
+// controller that syncs these objects
+var AcceptedKinds = map[schema.GroupVersionKind]string{
+	corev1.SchemeGroupVersion.WithKind("Pod"):                   "pods",
+	corev1.SchemeGroupVersion.WithKind("Service"):               "services",
+	corev1.SchemeGroupVersion.WithKind("Endpoints"):             "endpoints",
+	corev1.SchemeGroupVersion.WithKind("Secret"):                "secrets",
+	corev1.SchemeGroupVersion.WithKind("ConfigMap"):             "configmaps",
+	corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): "persistentvolumeclaims",
 }
 
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	// only objects of enabled controllers are translated and indexed by their physical name
+	acceptedKinds := map[schema.GroupVersionKind]bool{}
+	for gvk, controller := range AcceptedKinds {
+		if ctx.Controllers.Has(controller) {
+			acceptedKinds[gvk] = true
+		}
+	}
+
 	return &eventSyncer{
 		virtualClient: ctx.VirtualManager.GetClient(),
+		acceptedKinds: acceptedKinds,
 	}, nil
 }
 
 type eventSyncer struct {
 	virtualClient client.Client
+	acceptedKinds map[schema.GroupVersionKind]bool
 }
 
 func (s *eventSyncer) Resource() client.Object {
@@ -87,7 +100,7 @@ func (s *eventSyncer) reconcile(ctx *synccontext.SyncContext, req ctrl.Request)
 
 	// check if the involved object is accepted
 	gvk := pEvent.InvolvedObject.GroupVersionKind()
-	if !AcceptedKinds[gvk] {
+	if !s.acceptedKinds[gvk] {
 		return nil
 	}
 
@@ -96,24 +109,8 @@ func (s *eventSyncer) reconcile(ctx *synccontext.SyncContext, req ctrl.Request)
 		return err
 	}
 
-	index := ""
-	switch pEvent.InvolvedObject.Kind {
-	case "Pod":
-		index = constants.IndexByPhysicalName
-	case "Service":
-		index = constants.IndexByPhysicalName
-	case "Endpoint":
-		index = constants.IndexByPhysicalName
-	case "Secret":
-		index = constants.IndexByPhysicalName
-	case "ConfigMap":
-		index = constants.IndexByPhysicalName
-	default:
-		return nil
-	}
-
 	// get involved object
-	err = clienthelper.GetByIndex(ctx.Context, ctx.VirtualClient, vInvolvedObj, index, pEvent.Namespace+"/"+pEvent.InvolvedObject.Name)
+	err = clienthelper.GetByIndex(ctx.Context, ctx.VirtualClient, vInvolvedObj, constants.IndexByPhysicalName, pEvent.Namespace+"/"+pEvent.InvolvedObject.Name)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			return nil
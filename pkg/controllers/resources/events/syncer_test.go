@@ -18,10 +18,12 @@ import (
 
 func newFakeSyncer(t *testing.T, ctx *synccontext.RegisterContext) (*synccontext.SyncContext, *eventSyncer) {
 	// we need that index here as well otherwise we wouldn't find the related pod
-	err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &corev1.Pod{}, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
-		return []string{translate.Default.PhysicalNamespace(rawObj.GetNamespace()) + "/" + translate.Default.PhysicalName(rawObj.GetName(), rawObj.GetNamespace())}
-	})
-	assert.NilError(t, err)
+	for _, obj := range []client.Object{&corev1.Pod{}, &corev1.PersistentVolumeClaim{}} {
+		err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, obj, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
+			return []string{translate.Default.PhysicalNamespace(rawObj.GetNamespace()) + "/" + translate.Default.PhysicalName(rawObj.GetName(), rawObj.GetNamespace())}
+		})
+		assert.NilError(t, err)
+	}
 
 	syncContext, object := generictesting.FakeStartSyncer(t, ctx, New)
 	return syncContext, object.(*eventSyncer)
@@ -83,6 +85,48 @@ func TestSync(t *testing.T) {
 		Count:          pEventUpdated.Count,
 		InvolvedObject: vEvent.InvolvedObject,
 	}
+	vPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pvc",
+			Namespace: "test",
+		},
+	}
+	pPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName(vPVC.Name, vPVC.Namespace),
+			Namespace: generictesting.DefaultTestTargetNamespace,
+		},
+	}
+	pPVCEvent := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pPVC.Name + ".17a9e3c2",
+			Namespace: generictesting.DefaultTestTargetNamespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      corev1.SchemeGroupVersion.String(),
+			Kind:            "PersistentVolumeClaim",
+			Name:            pPVC.Name,
+			Namespace:       pPVC.Namespace,
+			ResourceVersion: generictesting.FakeClientResourceVersion,
+		},
+		Reason:  "ProvisioningFailed",
+		Message: "storageclass.storage.k8s.io \"fast\" not found for " + pPVC.Name,
+	}
+	vPVCEvent := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vPVC.Name + ".17a9e3c2",
+			Namespace: vPVC.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      corev1.SchemeGroupVersion.String(),
+			Kind:            "PersistentVolumeClaim",
+			Name:            vPVC.Name,
+			Namespace:       vPVC.Namespace,
+			ResourceVersion: generictesting.FakeClientResourceVersion,
+		},
+		Reason:  "ProvisioningFailed",
+		Message: "storageclass.storage.k8s.io \"fast\" not found for " + vPVC.Name,
+	}
 
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
@@ -134,5 +178,52 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Create new persistent volume claim event",
+			InitialVirtualState: []runtime.Object{
+				vNamespace,
+				vPVC,
+			},
+			InitialPhysicalState: []runtime.Object{
+				pPVC,
+				pPVCEvent,
+			},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Event"): {
+					vPVCEvent,
+				},
+			},
+			Sync: func(registerContext *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, registerContext)
+				_, err := syncer.ReconcileStart(syncContext, ctrl.Request{NamespacedName: types.NamespacedName{
+					Namespace: pPVCEvent.Namespace,
+					Name:      pPVCEvent.Name,
+				}})
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Ignore event of disabled controller",
+			InitialVirtualState: []runtime.Object{
+				vNamespace,
+				vPVC,
+			},
+			InitialPhysicalState: []runtime.Object{
+				pPVC,
+				pPVCEvent,
+			},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Event"): {},
+			},
+			Sync: func(registerContext *synccontext.RegisterContext) {
+				registerContext.Controllers.Delete("persistentvolumeclaims")
+				syncContext, syncer := newFakeSyncer(t, registerContext)
+				_, err := syncer.ReconcileStart(syncContext, ctrl.Request{NamespacedName: types.NamespacedName{
+					Namespace: pPVCEvent.Namespace,
+					Name:      pPVCEvent.Name,
+				}})
+				assert.NilError(t, err)
+			},
+		},
 	})
 }
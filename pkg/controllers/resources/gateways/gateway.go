@@ -0,0 +1,89 @@
+package gateways
+
+import (
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	GatewayGVK   = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "Gateway"}
+	HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "HTTPRoute"}
+	GRPCRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "GRPCRoute"}
+)
+
+func NewGateway(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &gatewaySyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "gateway", newObject(GatewayGVK)),
+	}, nil
+}
+
+type gatewaySyncer struct {
+	translator.NamespacedTranslator
+}
+
+var _ syncer.Initializer = &gatewaySyncer{}
+
+func (s *gatewaySyncer) Init(ctx *synccontext.RegisterContext) error {
+	return ensureCRD(ctx, GatewayGVK)
+}
+
+var _ syncer.Syncer = &gatewaySyncer{}
+
+func (s *gatewaySyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*unstructured.Unstructured)))
+}
+
+func (s *gatewaySyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	vGateway := vObj.(*unstructured.Unstructured)
+	pGateway := pObj.(*unstructured.Unstructured)
+
+	// the host gateway controller reports addresses and listener conditions
+	if !equality.Semantic.DeepEqual(vGateway.Object["status"], pGateway.Object["status"]) {
+		return syncStatus(ctx, vGateway, pGateway.Object["status"])
+	}
+
+	newGateway := s.translateUpdate(ctx.Context, pGateway, vGateway)
+	if newGateway != nil {
+		translator.PrintChanges(pObj, newGateway, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, newGateway)
+}
+
+// SecretNamesFromGateway returns the secrets of the gateway listener certificates in the form namespace/name
+func SecretNamesFromGateway(gateway *unstructured.Unstructured) []string {
+	secrets := []string{}
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	for _, l := range listeners {
+		listener, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		refs, _, _ := unstructured.NestedSlice(listener, "tls", "certificateRefs")
+		for _, r := range refs {
+			ref, ok := r.(map[string]interface{})
+			if !ok || refKind(ref, secretKind) != secretKind {
+				continue
+			}
+
+			name, _ := ref["name"].(string)
+			namespace, _ := ref["namespace"].(string)
+			if namespace == "" {
+				namespace = gateway.GetNamespace()
+			}
+			if name != "" {
+				secrets = append(secrets, namespace+"/"+name)
+			}
+		}
+	}
+
+	return translate.UniqueSlice(secrets)
+}
@@ -0,0 +1,65 @@
+package gateways
+
+import (
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func NewHTTPRoute(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return newRouteSyncer(ctx, HTTPRouteGVK), nil
+}
+
+func NewGRPCRoute(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return newRouteSyncer(ctx, GRPCRouteGVK), nil
+}
+
+func newRouteSyncer(ctx *synccontext.RegisterContext, gvk schema.GroupVersionKind) *routeSyncer {
+	return &routeSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, strings.ToLower(gvk.Kind), newObject(gvk)),
+		gvk:                  gvk,
+	}
+}
+
+// routeSyncer syncs HTTPRoutes and GRPCRoutes, which only differ in their matches
+type routeSyncer struct {
+	translator.NamespacedTranslator
+	gvk schema.GroupVersionKind
+}
+
+var _ syncer.Initializer = &routeSyncer{}
+
+func (s *routeSyncer) Init(ctx *synccontext.RegisterContext) error {
+	return ensureCRD(ctx, s.gvk)
+}
+
+var _ syncer.Syncer = &routeSyncer{}
+
+func (s *routeSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*unstructured.Unstructured)))
+}
+
+func (s *routeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	vRoute := vObj.(*unstructured.Unstructured)
+	pRoute := pObj.(*unstructured.Unstructured)
+
+	// the host gateway controller reports which parents accepted the route
+	status := translateRouteStatusBackwards(vRoute, pRoute)
+	if !equality.Semantic.DeepEqual(vRoute.Object["status"], status) {
+		return syncStatus(ctx, vRoute, status)
+	}
+
+	newRoute := s.translateUpdate(ctx.Context, pRoute, vRoute)
+	if newRoute != nil {
+		translator.PrintChanges(pObj, newRoute, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, newRoute)
+}
@@ -0,0 +1,290 @@
+package gateways
+
+import (
+	"context"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// groupKind identifies the kind of object a gateway api reference points to
+type groupKind struct {
+	group string
+	kind  string
+}
+
+var (
+	secretKind  = groupKind{group: "", kind: "Secret"}
+	serviceKind = groupKind{group: "", kind: "Service"}
+	gatewayKind = groupKind{group: GatewayGVK.Group, kind: GatewayGVK.Kind}
+)
+
+func newObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+func ensureCRD(ctx *synccontext.RegisterContext, gvk schema.GroupVersionKind) error {
+	_, _, err := translate.EnsureCRDFromPhysicalCluster(ctx.Context, ctx.PhysicalManager.GetConfig(), ctx.VirtualManager.GetConfig(), gvk)
+	return err
+}
+
+func syncStatus(ctx *synccontext.SyncContext, vObj *unstructured.Unstructured, status interface{}) (ctrl.Result, error) {
+	newObj := vObj.DeepCopy()
+	if status == nil {
+		delete(newObj.Object, "status")
+	} else {
+		newObj.Object["status"] = runtime.DeepCopyJSONValue(status)
+	}
+
+	translator.PrintChanges(vObj, newObj, ctx.Log)
+	return ctrl.Result{}, ctx.VirtualClient.Status().Update(ctx.Context, newObj)
+}
+
+func (s *gatewaySyncer) translate(ctx context.Context, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	pObj := s.TranslateMetadata(ctx, vObj).(*unstructured.Unstructured)
+	pObj.Object["spec"] = translateGatewaySpec(vObj)
+	delete(pObj.Object, "status")
+	return pObj
+}
+
+func (s *gatewaySyncer) translateUpdate(ctx context.Context, pObj, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	return translateUpdate(ctx, s.NamespacedTranslator, pObj, vObj, translateGatewaySpec(vObj))
+}
+
+func (s *routeSyncer) translate(ctx context.Context, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	pObj := s.TranslateMetadata(ctx, vObj).(*unstructured.Unstructured)
+	pObj.Object["spec"] = translateRouteSpec(vObj)
+	delete(pObj.Object, "status")
+	return pObj
+}
+
+func (s *routeSyncer) translateUpdate(ctx context.Context, pObj, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	return translateUpdate(ctx, s.NamespacedTranslator, pObj, vObj, translateRouteSpec(vObj))
+}
+
+func translateUpdate(ctx context.Context, t translator.NamespacedTranslator, pObj, vObj *unstructured.Unstructured, translatedSpec map[string]interface{}) *unstructured.Unstructured {
+	var updated *unstructured.Unstructured
+
+	if !equality.Semantic.DeepEqual(translatedSpec, pObj.Object["spec"]) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Object["spec"] = translatedSpec
+	}
+
+	changed, translatedAnnotations, translatedLabels := t.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.SetAnnotations(translatedAnnotations)
+		updated.SetLabels(translatedLabels)
+	}
+
+	return updated
+}
+
+func translateGatewaySpec(vGateway *unstructured.Unstructured) map[string]interface{} {
+	spec := copySpec(vGateway)
+	listeners, _ := spec["listeners"].([]interface{})
+	for _, l := range listeners {
+		listener, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if tls, ok := listener["tls"].(map[string]interface{}); ok {
+			translateRefs(tls["certificateRefs"], vGateway.GetNamespace(), secretKind, secretKind)
+		}
+		if namespaces, _, _ := unstructured.NestedMap(listener, "allowedRoutes", "namespaces"); namespaces != nil {
+			listener["allowedRoutes"].(map[string]interface{})["namespaces"] = translateAllowedNamespaces(namespaces)
+		}
+	}
+
+	return spec
+}
+
+// translateAllowedNamespaces restricts the routes a gateway accepts to routes of this vcluster
+func translateAllowedNamespaces(namespaces map[string]interface{}) map[string]interface{} {
+	from, _ := namespaces["from"].(string)
+	if from != "All" && from != "Selector" {
+		return namespaces
+	}
+
+	// all routes are synced into the same host namespace, so the gateway namespace
+	// is the only one that could contain routes of this vcluster
+	namespaceTranslator, ok := translate.Default.(translate.NamespaceSelectorTranslator)
+	if translate.Default.SingleNamespaceTarget() || !ok {
+		return map[string]interface{}{"from": "Same"}
+	}
+
+	selector := &metav1.LabelSelector{}
+	if from == "Selector" {
+		vSelector, _ := namespaces["selector"].(map[string]interface{})
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(vSelector, selector); err != nil {
+			// never fall back to all namespaces of the host
+			return map[string]interface{}{"from": "Same"}
+		}
+	}
+
+	pSelector, err := runtime.DefaultUnstructuredConverter.ToUnstructured(namespaceTranslator.TranslateNamespaceSelector(selector))
+	if err != nil {
+		return map[string]interface{}{"from": "Same"}
+	}
+
+	return map[string]interface{}{"from": "Selector", "selector": pSelector}
+}
+
+func translateRouteSpec(vRoute *unstructured.Unstructured) map[string]interface{} {
+	spec := copySpec(vRoute)
+	translateRefs(spec["parentRefs"], vRoute.GetNamespace(), gatewayKind, gatewayKind, serviceKind)
+
+	rules, _ := spec["rules"].([]interface{})
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		translateFilters(rule["filters"], vRoute.GetNamespace())
+		backendRefs, _ := rule["backendRefs"].([]interface{})
+		for _, b := range backendRefs {
+			backendRef, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			translateRef(backendRef, vRoute.GetNamespace(), serviceKind, serviceKind)
+			translateFilters(backendRef["filters"], vRoute.GetNamespace())
+		}
+	}
+
+	return spec
+}
+
+func translateFilters(filters interface{}, namespace string) {
+	filterList, _ := filters.([]interface{})
+	for _, f := range filterList {
+		filter, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if backendRef, _, _ := unstructured.NestedMap(filter, "requestMirror", "backendRef"); backendRef != nil {
+			translateRef(backendRef, namespace, serviceKind, serviceKind)
+			filter["requestMirror"].(map[string]interface{})["backendRef"] = backendRef
+		}
+	}
+}
+
+// translateRouteStatusBackwards returns the status of the physical route with the parent references
+// pointing to the virtual objects again
+func translateRouteStatusBackwards(vRoute, pRoute *unstructured.Unstructured) interface{} {
+	status, ok := runtime.DeepCopyJSONValue(pRoute.Object["status"]).(map[string]interface{})
+	if !ok {
+		return pRoute.Object["status"]
+	}
+
+	// remember which physical parent belongs to which virtual parent
+	vParents := map[string]map[string]interface{}{}
+	vParentRefs, _, _ := unstructured.NestedSlice(vRoute.Object, "spec", "parentRefs")
+	for _, r := range vParentRefs {
+		vRef, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		pRef := runtime.DeepCopyJSONValue(vRef).(map[string]interface{})
+		translateRef(pRef, vRoute.GetNamespace(), gatewayKind, gatewayKind, serviceKind)
+		vParents[refKey(pRef)] = vRef
+	}
+
+	parents, _ := status["parents"].([]interface{})
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		pRef, ok := parent["parentRef"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if vRef, ok := vParents[refKey(pRef)]; ok {
+			parent["parentRef"] = runtime.DeepCopyJSONValue(vRef)
+		}
+	}
+
+	return status
+}
+
+func refKey(ref map[string]interface{}) string {
+	name, _ := ref["name"].(string)
+	namespace, _ := ref["namespace"].(string)
+	sectionName, _ := ref["sectionName"].(string)
+	return namespace + "/" + name + "/" + sectionName
+}
+
+func translateRefs(refs interface{}, namespace string, defaultKind groupKind, syncedKinds ...groupKind) {
+	refList, _ := refs.([]interface{})
+	for _, r := range refList {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		translateRef(ref, namespace, defaultKind, syncedKinds...)
+	}
+}
+
+// translateRef rewrites the name and namespace of a reference to one of the synced kinds to
+// the physical object, references to other kinds are left as they are
+func translateRef(ref map[string]interface{}, namespace string, defaultKind groupKind, syncedKinds ...groupKind) {
+	kind := refKind(ref, defaultKind)
+	synced := false
+	for _, syncedKind := range syncedKinds {
+		if kind == syncedKind {
+			synced = true
+			break
+		}
+	}
+
+	name, _ := ref["name"].(string)
+	if !synced || name == "" {
+		return
+	}
+
+	refNamespace, _ := ref["namespace"].(string)
+	if refNamespace != "" {
+		ref["namespace"] = translate.Default.PhysicalNamespace(refNamespace)
+	} else {
+		refNamespace = namespace
+	}
+	ref["name"] = translate.Default.PhysicalName(name, refNamespace)
+}
+
+func refKind(ref map[string]interface{}, defaultKind groupKind) groupKind {
+	retKind := defaultKind
+	if group, ok := ref["group"].(string); ok {
+		retKind.group = group
+	}
+	if kind, ok := ref["kind"].(string); ok && kind != "" {
+		retKind.kind = kind
+	}
+
+	return retKind
+}
+
+func copySpec(obj *unstructured.Unstructured) map[string]interface{} {
+	spec, ok := obj.Object["spec"].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	return runtime.DeepCopyJSONValue(spec).(map[string]interface{})
+}
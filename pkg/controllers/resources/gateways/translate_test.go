@@ -0,0 +1,129 @@
+package gateways
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTranslateRouteSpec(t *testing.T) {
+	vRoute := newObject(HTTPRouteGVK)
+	vRoute.SetNamespace("test")
+	vRoute.SetName("route")
+	vRoute.Object["spec"] = map[string]interface{}{
+		"parentRefs": []interface{}{
+			map[string]interface{}{"name": "gateway"},
+			map[string]interface{}{"name": "gateway", "namespace": "other", "sectionName": "https"},
+			map[string]interface{}{"name": "unknown", "group": "example.com", "kind": "Gateway"},
+		},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": "service", "port": int64(80)},
+					map[string]interface{}{"name": "bucket", "group": "example.com", "kind": "Bucket"},
+				},
+				"filters": []interface{}{
+					map[string]interface{}{
+						"type":          "RequestMirror",
+						"requestMirror": map[string]interface{}{"backendRef": map[string]interface{}{"name": "mirror", "port": int64(80)}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"parentRefs": []interface{}{
+			map[string]interface{}{"name": translate.Default.PhysicalName("gateway", "test")},
+			map[string]interface{}{"name": translate.Default.PhysicalName("gateway", "other"), "namespace": translate.Default.PhysicalNamespace("other"), "sectionName": "https"},
+			map[string]interface{}{"name": "unknown", "group": "example.com", "kind": "Gateway"},
+		},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": translate.Default.PhysicalName("service", "test"), "port": int64(80)},
+					map[string]interface{}{"name": "bucket", "group": "example.com", "kind": "Bucket"},
+				},
+				"filters": []interface{}{
+					map[string]interface{}{
+						"type":          "RequestMirror",
+						"requestMirror": map[string]interface{}{"backendRef": map[string]interface{}{"name": translate.Default.PhysicalName("mirror", "test"), "port": int64(80)}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.DeepEqual(t, translateRouteSpec(vRoute), expected)
+
+	// the status refers to the physical parents again
+	pRoute := newObject(HTTPRouteGVK)
+	pRoute.Object["status"] = map[string]interface{}{
+		"parents": []interface{}{
+			map[string]interface{}{
+				"controllerName": "example.com/gateway",
+				"parentRef":      expected["parentRefs"].([]interface{})[1],
+			},
+		},
+	}
+	assert.DeepEqual(t, translateRouteStatusBackwards(vRoute, pRoute), map[string]interface{}{
+		"parents": []interface{}{
+			map[string]interface{}{
+				"controllerName": "example.com/gateway",
+				"parentRef":      map[string]interface{}{"name": "gateway", "namespace": "other", "sectionName": "https"},
+			},
+		},
+	})
+}
+
+func TestTranslateGatewaySpec(t *testing.T) {
+	testCases := []struct {
+		name          string
+		allowedRoutes map[string]interface{}
+		expected      map[string]interface{}
+	}{
+		{
+			name:          "Same namespace",
+			allowedRoutes: map[string]interface{}{"from": "Same"},
+			expected:      map[string]interface{}{"from": "Same"},
+		},
+		{
+			name:          "All namespaces",
+			allowedRoutes: map[string]interface{}{"from": "All"},
+			expected:      map[string]interface{}{"from": "Same"},
+		},
+		{
+			name: "Selected namespaces",
+			allowedRoutes: map[string]interface{}{
+				"from":     "Selector",
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"team": "a"}},
+			},
+			expected: map[string]interface{}{"from": "Same"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		vGateway := newObject(GatewayGVK)
+		vGateway.SetNamespace("test")
+		vGateway.Object["spec"] = map[string]interface{}{
+			"gatewayClassName": "example",
+			"listeners": []interface{}{
+				map[string]interface{}{
+					"name":          "https",
+					"tls":           map[string]interface{}{"certificateRefs": []interface{}{map[string]interface{}{"name": "cert"}}},
+					"allowedRoutes": map[string]interface{}{"namespaces": testCase.allowedRoutes},
+				},
+			},
+		}
+
+		spec := translateGatewaySpec(vGateway)
+		listener := spec["listeners"].([]interface{})[0].(map[string]interface{})
+		assert.DeepEqual(t, listener["allowedRoutes"], map[string]interface{}{"namespaces": testCase.expected})
+		assert.DeepEqual(t, listener["tls"], map[string]interface{}{"certificateRefs": []interface{}{map[string]interface{}{"name": translate.Default.PhysicalName("cert", "test")}}})
+		assert.DeepEqual(t, SecretNamesFromGateway(vGateway), []string{"test/cert"})
+		_, found, _ := unstructured.NestedFieldNoCopy(vGateway.Object, "spec", "listeners")
+		assert.Assert(t, found, "virtual gateway must not be changed in test case %s", testCase.name)
+	}
+}
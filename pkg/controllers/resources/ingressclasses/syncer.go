@@ -1,20 +1,65 @@
 package ingressclasses
 
 import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses/util"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	classMapping, err := util.ParseIngressClassMapping(ctx.Options.IngressClassMapping)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ingressClassSyncer{
-		Translator: translator.NewMirrorPhysicalTranslator("ingressclass", &networkingv1.IngressClass{}),
+		Translator: &classTranslator{
+			Translator:   translator.NewMirrorPhysicalTranslator("ingressclass", &networkingv1.IngressClass{}),
+			classMapping: classMapping,
+		},
 	}, nil
 }
 
+// classTranslator mirrors the host ingress classes under the names of the ingress class mapping, so the
+// virtual ingresses of a mapped class find it in the virtual cluster
+type classTranslator struct {
+	translator.Translator
+
+	classMapping util.IngressClassMapping
+}
+
+func (c *classTranslator) TranslateMetadata(ctx context.Context, pObj client.Object) client.Object {
+	vObj := c.Translator.TranslateMetadata(ctx, pObj)
+	vObj.SetName(c.classMapping.ToVirtual(pObj.GetName()))
+	return vObj
+}
+
+// IsManaged skips host ingress classes whose name is mapped to another host class, as the virtual class of
+// that name mirrors the other host class
+func (c *classTranslator) IsManaged(ctx context.Context, pObj client.Object) (bool, error) {
+	name := pObj.GetName()
+	if c.classMapping.ToVirtual(name) == name && c.classMapping.ToHost(name) != name {
+		return false, nil
+	}
+
+	return c.Translator.IsManaged(ctx, pObj)
+}
+
+func (c *classTranslator) VirtualToPhysical(_ context.Context, req types.NamespacedName, _ client.Object) types.NamespacedName {
+	return types.NamespacedName{Name: c.classMapping.ToHost(req.Name)}
+}
+
+func (c *classTranslator) PhysicalToVirtual(_ context.Context, pObj client.Object) types.NamespacedName {
+	return types.NamespacedName{Name: c.classMapping.ToVirtual(pObj.GetName())}
+}
+
 type ingressClassSyncer struct {
 	translator.Translator
 }
@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 )
@@ -79,6 +80,11 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	pObjMapped := pObj.DeepCopy()
+	pObjMapped.Name = "host-ingc"
+	pObjShadowed := pObj.DeepCopy()
+	pObjShadowed.Spec.Controller = "other-controller"
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name:                 "Sync Up",
@@ -96,6 +102,28 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Sync Up mapped class",
+			InitialVirtualState:  []runtime.Object{},
+			InitialPhysicalState: []runtime.Object{pObjMapped, pObjShadowed},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("IngressClass"): {vObj},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("IngressClass"): {pObjMapped, pObjShadowed},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.IngressClassMapping = []string{"test-ingc=host-ingc"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				managed, err := syncer.(*ingressClassSyncer).IsManaged(syncCtx.Context, pObjShadowed)
+				assert.NilError(t, err)
+				assert.Assert(t, !managed)
+				assert.Equal(t, syncer.(*ingressClassSyncer).VirtualToPhysical(syncCtx.Context, types.NamespacedName{Name: vObj.Name}, vObj).Name, pObjMapped.Name)
+
+				_, err = syncer.(*ingressClassSyncer).SyncUp(syncCtx, pObjMapped)
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                  "Sync Down",
 			InitialVirtualState:   []runtime.Object{vObj},
@@ -1,6 +1,9 @@
 package legacy
 
 import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses/util"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
@@ -12,13 +15,20 @@ import (
 )
 
 func NewSyncer(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	classMapping, err := util.ParseIngressClassMapping(ctx.Options.IngressClassMapping)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of the ingress-class-mapping flag: %v", err)
+	}
+
 	return &ingressSyncer{
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "ingress", &networkingv1beta1.Ingress{}),
+		classMapping:         classMapping,
 	}, nil
 }
 
 type ingressSyncer struct {
 	translator.NamespacedTranslator
+	classMapping util.IngressClassMapping
 }
 
 var _ syncer.Syncer = &ingressSyncer{}
@@ -8,6 +8,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -22,6 +23,8 @@ func (s *ingressSyncer) TranslateMetadataUpdate(ctx context.Context, vObj client
 func (s *ingressSyncer) translate(ctx context.Context, vIngress *networkingv1beta1.Ingress) *networkingv1beta1.Ingress {
 	newIngress := s.TranslateMetadata(ctx, vIngress).(*networkingv1beta1.Ingress)
 	newIngress.Spec = *translateSpec(vIngress.Namespace, &vIngress.Spec)
+	newIngress.Spec.IngressClassName = s.classMapping.TranslateClassName(vIngress.Spec.IngressClassName)
+	newIngress.Annotations = s.classMapping.TranslateClassAnnotation(newIngress.Annotations)
 	return newIngress
 }
 
@@ -29,13 +32,15 @@ func (s *ingressSyncer) translateUpdate(ctx context.Context, pObj, vObj *network
 	var updated *networkingv1beta1.Ingress
 
 	translatedSpec := *translateSpec(vObj.Namespace, &vObj.Spec)
+	translatedSpec.IngressClassName = s.classMapping.TranslateClassName(vObj.Spec.IngressClassName)
 	if !equality.Semantic.DeepEqual(translatedSpec, pObj.Spec) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Spec = translatedSpec
 	}
 
-	changed, translatedAnnotations, translatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
-	if changed {
+	_, translatedAnnotations, translatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	translatedAnnotations = s.classMapping.TranslateClassAnnotation(translatedAnnotations)
+	if !equality.Semantic.DeepEqual(translatedAnnotations, pObj.GetAnnotations()) || !equality.Semantic.DeepEqual(translatedLabels, pObj.GetLabels()) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Annotations = translatedAnnotations
 		updated.Labels = translatedLabels
@@ -49,7 +54,7 @@ func (s *ingressSyncer) translateUpdateBackwards(pObj, vObj *networkingv1beta1.I
 
 	if vObj.Spec.IngressClassName == nil && pObj.Spec.IngressClassName != nil {
 		updated = translator.NewIfNil(updated, vObj)
-		updated.Spec.IngressClassName = pObj.Spec.IngressClassName
+		updated.Spec.IngressClassName = pointer.String(s.classMapping.ToVirtual(*pObj.Spec.IngressClassName))
 	}
 
 	return updated
@@ -1,8 +1,10 @@
 package ingresses
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses/util"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
@@ -14,13 +16,20 @@ import (
 )
 
 func NewSyncer(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	classMapping, err := util.ParseIngressClassMapping(ctx.Options.IngressClassMapping)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of the ingress-class-mapping flag: %v", err)
+	}
+
 	return &ingressSyncer{
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "ingress", &networkingv1.Ingress{}),
+		classMapping:         classMapping,
 	}, nil
 }
 
 type ingressSyncer struct {
 	translator.NamespacedTranslator
+	classMapping util.IngressClassMapping
 }
 
 var _ syncer.Syncer = &ingressSyncer{}
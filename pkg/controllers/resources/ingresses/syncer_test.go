@@ -172,6 +172,28 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Create forward with ingress class mapping",
+			InitialVirtualState: []runtime.Object{&networkingv1.Ingress{
+				ObjectMeta: vObjectMeta,
+				Spec:       networkingv1.IngressSpec{IngressClassName: stringPointer("nginx")},
+			}},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("Ingress"): {&networkingv1.Ingress{
+					ObjectMeta: pObjectMeta,
+					Spec:       networkingv1.IngressSpec{IngressClassName: stringPointer("nginx-tenant-a")},
+				}},
+			},
+			Sync: func(registerContext *synccontext.RegisterContext) {
+				registerContext.Options.IngressClassMapping = []string{"nginx=nginx-tenant-a"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, registerContext, NewSyncer)
+				_, err := syncer.(*ingressSyncer).SyncDown(syncCtx, &networkingv1.Ingress{
+					ObjectMeta: vObjectMeta,
+					Spec:       networkingv1.IngressSpec{IngressClassName: stringPointer("nginx")},
+				})
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name: "Update forward",
 			InitialVirtualState: []runtime.Object{&networkingv1.Ingress{
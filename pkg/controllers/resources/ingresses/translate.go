@@ -8,13 +8,16 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (s *ingressSyncer) translate(ctx context.Context, vIngress *networkingv1.Ingress) *networkingv1.Ingress {
 	newIngress := s.TranslateMetadata(ctx, vIngress).(*networkingv1.Ingress)
 	newIngress.Spec = *translateSpec(vIngress.Namespace, &vIngress.Spec)
+	newIngress.Spec.IngressClassName = s.classMapping.TranslateClassName(vIngress.Spec.IngressClassName)
 	newIngress.Annotations, _ = translateIngressAnnotations(newIngress.Annotations, vIngress.Namespace)
+	newIngress.Annotations = s.classMapping.TranslateClassAnnotation(newIngress.Annotations)
 	return newIngress
 }
 
@@ -30,6 +33,7 @@ func (s *ingressSyncer) translateUpdate(ctx context.Context, pObj, vObj *network
 	var updated *networkingv1.Ingress
 
 	translatedSpec := *translateSpec(vObj.Namespace, &vObj.Spec)
+	translatedSpec.IngressClassName = s.classMapping.TranslateClassName(vObj.Spec.IngressClassName)
 	if !equality.Semantic.DeepEqual(translatedSpec, pObj.Spec) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Spec = translatedSpec
@@ -37,6 +41,7 @@ func (s *ingressSyncer) translateUpdate(ctx context.Context, pObj, vObj *network
 
 	_, translatedAnnotations, translatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
 	translatedAnnotations, _ = translateIngressAnnotations(translatedAnnotations, vObj.Namespace)
+	translatedAnnotations = s.classMapping.TranslateClassAnnotation(translatedAnnotations)
 	if !equality.Semantic.DeepEqual(translatedAnnotations, pObj.GetAnnotations()) || !equality.Semantic.DeepEqual(translatedLabels, pObj.GetLabels()) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Annotations = translatedAnnotations
@@ -51,7 +56,7 @@ func (s *ingressSyncer) translateUpdateBackwards(pObj, vObj *networkingv1.Ingres
 
 	if vObj.Spec.IngressClassName == nil && pObj.Spec.IngressClassName != nil {
 		updated = translator.NewIfNil(updated, vObj)
-		updated.Spec.IngressClassName = pObj.Spec.IngressClassName
+		updated.Spec.IngressClassName = pointer.String(s.classMapping.ToVirtual(*pObj.Spec.IngressClassName))
 	}
 
 	return updated
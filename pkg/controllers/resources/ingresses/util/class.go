@@ -0,0 +1,63 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/util/namemapping"
+	"k8s.io/utils/pointer"
+)
+
+// IngressClassAnnotation is the deprecated way of setting the ingress class of an ingress
+const IngressClassAnnotation = "kubernetes.io/ingress.class"
+
+// IngressClassMapping maps virtual ingress class names to host ingress class names
+type IngressClassMapping map[string]string
+
+// ParseIngressClassMapping parses mappings in the form virtualClass=hostClass
+func ParseIngressClassMapping(mappings []string) (IngressClassMapping, error) {
+	// ingress classes are also mapped back, so a host class may only be mapped from one virtual class
+	out, err := namemapping.Parse(mappings, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingress class mapping: %w", err)
+	}
+
+	return out, nil
+}
+
+// ToHost returns the host ingress class of the virtual ingress class
+func (m IngressClassMapping) ToHost(virtualClass string) string {
+	if hostClass, ok := m[virtualClass]; ok {
+		return hostClass
+	}
+
+	return virtualClass
+}
+
+// ToVirtual returns the virtual ingress class of the host ingress class
+func (m IngressClassMapping) ToVirtual(hostClass string) string {
+	for virtualClass, mapped := range m {
+		if mapped == hostClass {
+			return virtualClass
+		}
+	}
+
+	return hostClass
+}
+
+// TranslateClassName translates the ingress class name of a virtual ingress
+func (m IngressClassMapping) TranslateClassName(virtualClass *string) *string {
+	if virtualClass == nil {
+		return nil
+	}
+
+	return pointer.String(m.ToHost(*virtualClass))
+}
+
+// TranslateClassAnnotation translates the ingress class annotation of a virtual ingress
+func (m IngressClassMapping) TranslateClassAnnotation(annotations map[string]string) map[string]string {
+	if virtualClass, ok := annotations[IngressClassAnnotation]; ok {
+		annotations[IngressClassAnnotation] = m.ToHost(virtualClass)
+	}
+
+	return annotations
+}
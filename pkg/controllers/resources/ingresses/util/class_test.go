@@ -0,0 +1,67 @@
+package util
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseIngressClassMapping(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mappings []string
+
+		expectedMapping IngressClassMapping
+		expectedErr     bool
+	}{
+		{
+			name:            "no mappings",
+			expectedMapping: IngressClassMapping{},
+		},
+		{
+			name:            "mappings",
+			mappings:        []string{"nginx=nginx-tenant-a", "traefik=traefik-internal"},
+			expectedMapping: IngressClassMapping{"nginx": "nginx-tenant-a", "traefik": "traefik-internal"},
+		},
+		{
+			name:        "missing host class",
+			mappings:    []string{"nginx="},
+			expectedErr: true,
+		},
+		{
+			name:        "missing separator",
+			mappings:    []string{"nginx"},
+			expectedErr: true,
+		},
+		{
+			name:        "ambiguous host class",
+			mappings:    []string{"nginx=shared", "traefik=shared"},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mapping, err := ParseIngressClassMapping(testCase.mappings)
+		if testCase.expectedErr {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, mapping, testCase.expectedMapping)
+	}
+}
+
+func TestIngressClassMapping(t *testing.T) {
+	mapping := IngressClassMapping{"nginx": "nginx-tenant-a"}
+
+	assert.Equal(t, mapping.ToHost("nginx"), "nginx-tenant-a")
+	assert.Equal(t, mapping.ToHost("traefik"), "traefik")
+	assert.Equal(t, mapping.ToVirtual("nginx-tenant-a"), "nginx")
+	assert.Equal(t, mapping.ToVirtual("traefik"), "traefik")
+
+	assert.Assert(t, mapping.TranslateClassName(nil) == nil)
+	assert.Equal(t, *mapping.TranslateClassName(&[]string{"nginx"}[0]), "nginx-tenant-a")
+	assert.DeepEqual(t, mapping.TranslateClassAnnotation(map[string]string{IngressClassAnnotation: "nginx"}), map[string]string{IngressClassAnnotation: "nginx-tenant-a"})
+}
@@ -0,0 +1,102 @@
+package istio
+
+import (
+	"context"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	VirtualServiceGVK  = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}
+	DestinationRuleGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "DestinationRule"}
+)
+
+func NewVirtualService(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return newSyncer(ctx, VirtualServiceGVK, translateVirtualServiceSpec), nil
+}
+
+func NewDestinationRule(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return newSyncer(ctx, DestinationRuleGVK, translateDestinationRuleSpec), nil
+}
+
+// specTranslator translates the spec of a virtual istio object into the spec of the host object
+type specTranslator func(vObj *unstructured.Unstructured, clusterDomain string) map[string]interface{}
+
+func newSyncer(ctx *synccontext.RegisterContext, gvk schema.GroupVersionKind, translateSpec specTranslator) *istioSyncer {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+
+	return &istioSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, strings.ToLower(gvk.Kind), obj),
+
+		gvk:           gvk,
+		clusterDomain: ctx.Options.ClusterDomain,
+		translateSpec: translateSpec,
+	}
+}
+
+// istioSyncer syncs VirtualServices and DestinationRules, which only differ in their spec
+type istioSyncer struct {
+	translator.NamespacedTranslator
+
+	gvk           schema.GroupVersionKind
+	clusterDomain string
+	translateSpec specTranslator
+}
+
+var _ syncer.Initializer = &istioSyncer{}
+
+func (s *istioSyncer) Init(ctx *synccontext.RegisterContext) error {
+	_, _, err := translate.EnsureCRDFromPhysicalCluster(ctx.Context, ctx.PhysicalManager.GetConfig(), ctx.VirtualManager.GetConfig(), s.gvk)
+	return err
+}
+
+var _ syncer.Syncer = &istioSyncer{}
+
+func (s *istioSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*unstructured.Unstructured)))
+}
+
+func (s *istioSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	newObj := s.translateUpdate(ctx.Context, pObj.(*unstructured.Unstructured), vObj.(*unstructured.Unstructured))
+	if newObj != nil {
+		translator.PrintChanges(pObj, newObj, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, newObj)
+}
+
+func (s *istioSyncer) translate(ctx context.Context, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	pObj := s.TranslateMetadata(ctx, vObj).(*unstructured.Unstructured)
+	pObj.Object["spec"] = s.translateSpec(vObj, s.clusterDomain)
+	delete(pObj.Object, "status")
+	return pObj
+}
+
+func (s *istioSyncer) translateUpdate(ctx context.Context, pObj, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	var updated *unstructured.Unstructured
+
+	translatedSpec := s.translateSpec(vObj, s.clusterDomain)
+	if !equality.Semantic.DeepEqual(translatedSpec, pObj.Object["spec"]) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Object["spec"] = translatedSpec
+	}
+
+	changed, translatedAnnotations, translatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.SetAnnotations(translatedAnnotations)
+		updated.SetLabels(translatedLabels)
+	}
+
+	return updated
+}
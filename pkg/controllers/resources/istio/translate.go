@@ -0,0 +1,125 @@
+package istio
+
+import (
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func translateVirtualServiceSpec(vVirtualService *unstructured.Unstructured, clusterDomain string) map[string]interface{} {
+	spec := copySpec(vVirtualService)
+	namespace := vVirtualService.GetNamespace()
+	if hosts, ok := spec["hosts"].([]interface{}); ok {
+		for i, h := range hosts {
+			if host, ok := h.(string); ok {
+				hosts[i] = translateHost(host, namespace, clusterDomain)
+			}
+		}
+	}
+
+	for _, routeType := range []string{"http", "tcp", "tls"} {
+		rules, _ := spec[routeType].([]interface{})
+		for _, r := range rules {
+			rule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			translateDestinations(rule["route"], namespace, clusterDomain)
+			translateDestinations(rule["mirrors"], namespace, clusterDomain)
+			if mirror, ok := rule["mirror"].(map[string]interface{}); ok {
+				translateDestination(mirror, namespace, clusterDomain)
+			}
+		}
+	}
+
+	return spec
+}
+
+func translateDestinationRuleSpec(vDestinationRule *unstructured.Unstructured, clusterDomain string) map[string]interface{} {
+	spec := copySpec(vDestinationRule)
+	translateDestination(spec, vDestinationRule.GetNamespace(), clusterDomain)
+
+	// subsets and the workload selector select pods, so they have to select the translated pod labels
+	subsets, _ := spec["subsets"].([]interface{})
+	for _, s := range subsets {
+		if subset, ok := s.(map[string]interface{}); ok {
+			translateLabels(subset, "labels", vDestinationRule.GetNamespace())
+		}
+	}
+	if workloadSelector, ok := spec["workloadSelector"].(map[string]interface{}); ok {
+		translateLabels(workloadSelector, "matchLabels", vDestinationRule.GetNamespace())
+	}
+
+	return spec
+}
+
+// translateLabels translates the pod labels in the given field of obj
+func translateLabels(obj map[string]interface{}, field, namespace string) {
+	labels, _, _ := unstructured.NestedStringMap(obj, field)
+	if labels == nil {
+		return
+	}
+
+	pLabels := map[string]interface{}{}
+	for k, v := range translate.Default.TranslateLabels(labels, namespace, nil) {
+		pLabels[k] = v
+	}
+	obj[field] = pLabels
+}
+
+// translateDestinations translates the hosts of a list of route or mirror destinations
+func translateDestinations(destinations interface{}, namespace, clusterDomain string) {
+	destinationList, _ := destinations.([]interface{})
+	for _, d := range destinationList {
+		destination, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if inner, ok := destination["destination"].(map[string]interface{}); ok {
+			translateDestination(inner, namespace, clusterDomain)
+		}
+	}
+}
+
+func translateDestination(destination map[string]interface{}, namespace, clusterDomain string) {
+	if host, ok := destination["host"].(string); ok {
+		destination["host"] = translateHost(host, namespace, clusterDomain)
+	}
+}
+
+// translateHost rewrites a host that refers to a virtual service, either by its short name or by
+// its cluster local name, to the cluster local name of the host service. Other hosts, e.g. external
+// hosts or wildcards, are returned as they are.
+func translateHost(host, namespace, clusterDomain string) string {
+	name := host
+	if strings.Contains(host, ".") {
+		suffix := ".svc." + clusterDomain
+		if !strings.HasSuffix(host, suffix) {
+			return host
+		}
+
+		parts := strings.Split(strings.TrimSuffix(host, suffix), ".")
+		if len(parts) != 2 {
+			return host
+		}
+		name, namespace = parts[0], parts[1]
+	}
+	if name == "" || namespace == "" || strings.Contains(name, "*") {
+		return host
+	}
+
+	return translate.Default.PhysicalName(name, namespace) + "." + translate.Default.PhysicalNamespace(namespace) + ".svc." + clusterDomain
+}
+
+func copySpec(obj *unstructured.Unstructured) map[string]interface{} {
+	spec, ok := obj.Object["spec"].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	return runtime.DeepCopyJSONValue(spec).(map[string]interface{})
+}
@@ -0,0 +1,122 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTranslateHost(t *testing.T) {
+	testCases := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{
+			name:     "Short name",
+			host:     "reviews",
+			expected: translate.Default.PhysicalName("reviews", "test") + "." + translate.Default.PhysicalNamespace("test") + ".svc.cluster.local",
+		},
+		{
+			name:     "Cluster local name",
+			host:     "ratings.other.svc.cluster.local",
+			expected: translate.Default.PhysicalName("ratings", "other") + "." + translate.Default.PhysicalNamespace("other") + ".svc.cluster.local",
+		},
+		{
+			name:     "External host",
+			host:     "api.example.com",
+			expected: "api.example.com",
+		},
+		{
+			name:     "Wildcard",
+			host:     "*.other.svc.cluster.local",
+			expected: "*.other.svc.cluster.local",
+		},
+		{
+			name:     "Other cluster domain",
+			host:     "reviews.test.svc.example.local",
+			expected: "reviews.test.svc.example.local",
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, translateHost(testCase.host, "test", "cluster.local"), testCase.expected, "unexpected host in test case %s", testCase.name)
+	}
+}
+
+func TestTranslateVirtualServiceSpec(t *testing.T) {
+	pReviews := translate.Default.PhysicalName("reviews", "test") + "." + translate.Default.PhysicalNamespace("test") + ".svc.cluster.local"
+	vVirtualService := &unstructured.Unstructured{}
+	vVirtualService.SetGroupVersionKind(VirtualServiceGVK)
+	vVirtualService.SetNamespace("test")
+	vVirtualService.Object["spec"] = map[string]interface{}{
+		"hosts":    []interface{}{"reviews", "api.example.com"},
+		"gateways": []interface{}{"mesh"},
+		"http": []interface{}{
+			map[string]interface{}{
+				"route": []interface{}{
+					map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v1"}, "weight": int64(90)},
+					map[string]interface{}{"destination": map[string]interface{}{"host": "api.example.com"}, "weight": int64(10)},
+				},
+				"mirror": map[string]interface{}{"host": "reviews.test.svc.cluster.local"},
+			},
+		},
+		"tcp": []interface{}{
+			map[string]interface{}{
+				"route": []interface{}{
+					map[string]interface{}{"destination": map[string]interface{}{"host": "reviews"}},
+				},
+			},
+		},
+	}
+
+	assert.DeepEqual(t, translateVirtualServiceSpec(vVirtualService, "cluster.local"), map[string]interface{}{
+		"hosts":    []interface{}{pReviews, "api.example.com"},
+		"gateways": []interface{}{"mesh"},
+		"http": []interface{}{
+			map[string]interface{}{
+				"route": []interface{}{
+					map[string]interface{}{"destination": map[string]interface{}{"host": pReviews, "subset": "v1"}, "weight": int64(90)},
+					map[string]interface{}{"destination": map[string]interface{}{"host": "api.example.com"}, "weight": int64(10)},
+				},
+				"mirror": map[string]interface{}{"host": pReviews},
+			},
+		},
+		"tcp": []interface{}{
+			map[string]interface{}{
+				"route": []interface{}{
+					map[string]interface{}{"destination": map[string]interface{}{"host": pReviews}},
+				},
+			},
+		},
+	})
+
+	hosts, _, _ := unstructured.NestedStringSlice(vVirtualService.Object, "spec", "hosts")
+	assert.DeepEqual(t, hosts, []string{"reviews", "api.example.com"})
+}
+
+func TestTranslateDestinationRuleSpec(t *testing.T) {
+	vDestinationRule := &unstructured.Unstructured{}
+	vDestinationRule.SetGroupVersionKind(DestinationRuleGVK)
+	vDestinationRule.SetNamespace("test")
+	vDestinationRule.Object["spec"] = map[string]interface{}{
+		"host":             "reviews",
+		"subsets":          []interface{}{map[string]interface{}{"name": "v1", "labels": map[string]interface{}{"version": "v1"}}},
+		"workloadSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "productpage"}},
+	}
+
+	pLabels := func(labels map[string]string) map[string]interface{} {
+		ret := map[string]interface{}{}
+		for k, v := range translate.Default.TranslateLabels(labels, "test", nil) {
+			ret[k] = v
+		}
+		return ret
+	}
+	assert.DeepEqual(t, translateDestinationRuleSpec(vDestinationRule, "cluster.local"), map[string]interface{}{
+		"host":             translate.Default.PhysicalName("reviews", "test") + "." + translate.Default.PhysicalNamespace("test") + ".svc.cluster.local",
+		"subsets":          []interface{}{map[string]interface{}{"name": "v1", "labels": pLabels(map[string]string{"version": "v1"})}},
+		"workloadSelector": map[string]interface{}{"matchLabels": pLabels(map[string]string{"app": "productpage"})},
+	})
+}
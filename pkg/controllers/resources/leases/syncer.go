@@ -0,0 +1,36 @@
+package leases
+
+import (
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// New creates a syncer that mirrors the leases of the virtual cluster, e.g. leader election leases
+// of controllers, into the host namespace. The host leases are never written back, so they only
+// allow host side tooling to observe leadership and staleness.
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &leaseSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "lease", &coordinationv1.Lease{}),
+	}, nil
+}
+
+type leaseSyncer struct {
+	translator.NamespacedTranslator
+}
+
+func (s *leaseSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*coordinationv1.Lease)))
+}
+
+func (s *leaseSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	newLease := s.translateUpdate(ctx.Context, pObj.(*coordinationv1.Lease), vObj.(*coordinationv1.Lease))
+	if newLease != nil {
+		translator.PrintChanges(pObj, newLease, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, newLease)
+}
@@ -0,0 +1,101 @@
+package leases
+
+import (
+	"testing"
+	"time"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+func TestSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+	vObjectMeta := metav1.ObjectMeta{
+		Name:            "my-controller",
+		Namespace:       "default",
+		ResourceVersion: generictesting.FakeClientResourceVersion,
+	}
+	pObjectMeta := metav1.ObjectMeta{
+		Name:      translate.Default.PhysicalName("my-controller", vObjectMeta.Namespace),
+		Namespace: "test",
+		Annotations: map[string]string{
+			translate.NameAnnotation:      vObjectMeta.Name,
+			translate.NamespaceAnnotation: vObjectMeta.Namespace,
+			translate.UIDAnnotation:       "",
+		},
+		Labels: map[string]string{
+			translate.NamespaceLabel: vObjectMeta.Namespace,
+			translate.MarkerLabel:    translate.Suffix,
+		},
+		ResourceVersion: generictesting.FakeClientResourceVersion,
+	}
+
+	renewTime := metav1.NewMicroTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	vLease := &coordinationv1.Lease{
+		ObjectMeta: vObjectMeta,
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       pointer.String("my-controller-0"),
+			LeaseDurationSeconds: pointer.Int32(15),
+			RenewTime:            &renewTime,
+		},
+	}
+	pLease := &coordinationv1.Lease{
+		ObjectMeta: pObjectMeta,
+		Spec:       vLease.Spec,
+	}
+
+	newRenewTime := metav1.NewMicroTime(renewTime.Add(10 * time.Second))
+	vRenewedLease := vLease.DeepCopy()
+	vRenewedLease.Spec.HolderIdentity = pointer.String("my-controller-1")
+	vRenewedLease.Spec.RenewTime = &newRenewTime
+	vRenewedLease.Spec.LeaseTransitions = pointer.Int32(1)
+	pRenewedLease := pLease.DeepCopy()
+	pRenewedLease.Spec = vRenewedLease.Spec
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name: "Create Host Cluster Lease",
+			InitialVirtualState: []runtime.Object{
+				vLease.DeepCopy(),
+			},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				coordinationv1.SchemeGroupVersion.WithKind("Lease"): {vLease.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				coordinationv1.SchemeGroupVersion.WithKind("Lease"): {pLease.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*leaseSyncer).SyncDown(syncCtx, vLease.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Update Host Cluster Lease on renewal",
+			InitialVirtualState: []runtime.Object{
+				vRenewedLease.DeepCopy(),
+			},
+			InitialPhysicalState: []runtime.Object{
+				pLease.DeepCopy(),
+			},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				coordinationv1.SchemeGroupVersion.WithKind("Lease"): {vRenewedLease.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				coordinationv1.SchemeGroupVersion.WithKind("Lease"): {pRenewedLease.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*leaseSyncer).Sync(syncCtx, pLease.DeepCopy(), vRenewedLease.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package leases
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func (s *leaseSyncer) translate(ctx context.Context, vObj *coordinationv1.Lease) *coordinationv1.Lease {
+	newLease := s.TranslateMetadata(ctx, vObj).(*coordinationv1.Lease)
+	newLease.Spec = *vObj.Spec.DeepCopy()
+	return newLease
+}
+
+func (s *leaseSyncer) translateUpdate(ctx context.Context, pObj, vObj *coordinationv1.Lease) *coordinationv1.Lease {
+	var updated *coordinationv1.Lease
+
+	// the holder and renew time are mirrored as is, so staleness is visible in the host cluster
+	if !equality.Semantic.DeepEqual(vObj.Spec, pObj.Spec) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Spec = *vObj.Spec.DeepCopy()
+	}
+
+	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Annotations = updatedAnnotations
+		updated.Labels = updatedLabels
+	}
+
+	return updated
+}
@@ -0,0 +1,227 @@
+package namespaces
+
+import (
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DeletionPolicyDelete deletes the physical namespace as soon as the virtual namespace is deleted
+	DeletionPolicyDelete = "delete"
+	// DeletionPolicyGrace deletes the physical namespace after the grace period. Objects in the
+	// namespace are still deleted together with their virtual objects.
+	DeletionPolicyGrace = "grace"
+	// DeletionPolicyRetain keeps the physical namespace and all objects in it for the grace period
+	DeletionPolicyRetain = "retain"
+	// DeletionPolicyOrphan keeps the physical namespace and all objects in it until they are deleted
+	// manually and labels the namespace, so it can be found for forensic purposes
+	DeletionPolicyOrphan = "orphan"
+)
+
+var _ syncer.OptionsProvider = &namespaceSyncer{}
+
+func (s *namespaceSyncer) WithOptions() *syncer.Options {
+	// a recreated virtual namespace has a different uid, but should undelete the physical namespace
+	return &syncer.Options{DisableUIDDeletion: s.deletionPolicy != DeletionPolicyDelete}
+}
+
+var _ syncer.UpSyncer = &namespaceSyncer{}
+
+// SyncUp applies the deletion policy to a physical namespace whose virtual namespace was deleted
+func (s *namespaceSyncer) SyncUp(ctx *synccontext.SyncContext, pObj client.Object) (ctrl.Result, error) {
+	if pObj.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	} else if s.deletionPolicy == DeletionPolicyOrphan {
+		return s.orphan(ctx, pObj.(*corev1.Namespace))
+	} else if s.deletionPolicy == DeletionPolicyDelete || s.deletionGracePeriod <= 0 {
+		s.eventRecorder.Eventf(virtualNamespace(pObj), corev1.EventTypeNormal, "DeletingHostNamespace", "Deleting host namespace %s and all objects in it", pObj.GetName())
+		return syncer.DeleteObject(ctx, pObj, "virtual object was deleted")
+	}
+
+	deleteAfter, err := time.Parse(time.RFC3339, pObj.GetAnnotations()[translate.DeleteAfterAnnotation])
+	if err != nil {
+		return s.startGracePeriod(ctx, pObj.(*corev1.Namespace))
+	}
+
+	remaining := time.Until(deleteAfter)
+	if remaining <= 0 {
+		s.eventRecorder.Eventf(virtualNamespace(pObj), corev1.EventTypeNormal, "DeletingHostNamespace", "Grace period expired, deleting host namespace %s and all objects in it", pObj.GetName())
+		return syncer.DeleteObject(ctx, pObj, "grace period of the deleted virtual object expired")
+	}
+
+	return ctrl.Result{RequeueAfter: remaining}, nil
+}
+
+// startGracePeriod marks the physical namespace for deletion after the grace period
+func (s *namespaceSyncer) startGracePeriod(ctx *synccontext.SyncContext, pNamespace *corev1.Namespace) (ctrl.Result, error) {
+	deleteAfter := time.Now().Add(s.deletionGracePeriod).UTC().Format(time.RFC3339)
+
+	updated := pNamespace.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[translate.DeleteAfterAnnotation] = deleteAfter
+	if s.deletionPolicy == DeletionPolicyRetain {
+		updated.Annotations[translate.RetainAnnotation] = "true"
+	}
+
+	ctx.Log.Infof("delete physical namespace %s after %s, because virtual namespace was deleted", pNamespace.Name, deleteAfter)
+	err := ctx.PhysicalClient.Update(ctx.Context, updated)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if s.deletionPolicy == DeletionPolicyRetain {
+		s.eventRecorder.Eventf(virtualNamespace(pNamespace), corev1.EventTypeNormal, "RetainingHostNamespace", "Retaining host namespace %s and all objects in it until %s", pNamespace.Name, deleteAfter)
+	} else {
+		s.eventRecorder.Eventf(virtualNamespace(pNamespace), corev1.EventTypeNormal, "DeletingHostNamespace", "Deleting host namespace %s after %s", pNamespace.Name, deleteAfter)
+	}
+	return ctrl.Result{RequeueAfter: s.deletionGracePeriod}, nil
+}
+
+// orphan keeps the physical namespace and all objects in it and labels the namespace as orphaned
+func (s *namespaceSyncer) orphan(ctx *synccontext.SyncContext, pNamespace *corev1.Namespace) (ctrl.Result, error) {
+	if pNamespace.Labels[translate.OrphanedLabel] == "true" {
+		return ctrl.Result{}, nil
+	}
+
+	updated := pNamespace.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	updated.Annotations[translate.RetainAnnotation] = "true"
+	updated.Labels[translate.OrphanedLabel] = "true"
+
+	ctx.Log.Infof("orphan physical namespace %s, because virtual namespace was deleted", pNamespace.Name)
+	err := ctx.PhysicalClient.Update(ctx.Context, updated)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	s.eventRecorder.Eventf(virtualNamespace(pNamespace), corev1.EventTypeNormal, "OrphaningHostNamespace", "Keeping host namespace %s and all objects in it, labeled with %s=true", pNamespace.Name, translate.OrphanedLabel)
+	return ctrl.Result{}, nil
+}
+
+// retention returns how long the objects of a deleted virtual namespace are still retained in the physical
+// namespace, a negative duration if they are retained until the retain annotation is removed manually and
+// zero if they are not retained
+func retention(pObj client.Object) time.Duration {
+	if pObj.GetAnnotations()[translate.RetainAnnotation] != "true" {
+		return 0
+	} else if pObj.GetLabels()[translate.OrphanedLabel] == "true" {
+		return -1
+	}
+
+	deleteAfter, err := time.Parse(time.RFC3339, pObj.GetAnnotations()[translate.DeleteAfterAnnotation])
+	if err != nil {
+		return 0
+	}
+
+	remaining := time.Until(deleteAfter)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// restoredKinds are the retained objects that are restored into a recreated virtual namespace. Other retained
+// objects, e.g. pods, are removed once the retention ends, as the virtual objects that created them are gone.
+var restoredKinds = []func() client.ObjectList{
+	func() client.ObjectList { return &corev1.ConfigMapList{} },
+	func() client.ObjectList { return &corev1.SecretList{} },
+	func() client.ObjectList { return &corev1.PersistentVolumeClaimList{} },
+}
+
+// undelete restores the retained objects of the physical namespace into the recreated virtual namespace and
+// adopts the physical objects, so they are not deleted because of the different uid of the virtual objects.
+// Virtual objects that exist already are adopted as they are.
+func (s *namespaceSyncer) undelete(ctx *synccontext.SyncContext, pNamespace *corev1.Namespace, vNamespace *corev1.Namespace) (int, error) {
+	restored := 0
+	for _, newList := range restoredKinds {
+		list := newList()
+		err := ctx.PhysicalClient.List(ctx.Context, list, client.InNamespace(pNamespace.Name))
+		if err != nil {
+			return restored, err
+		}
+
+		err = meta.EachListItem(list, func(obj runtime.Object) error {
+			pObj, ok := obj.(client.Object)
+			if !ok || !translate.Default.IsManaged(pObj) {
+				return nil
+			}
+
+			vObj := restoredObject(pObj, vNamespace.Name)
+			err := ctx.VirtualClient.Create(ctx.Context, vObj)
+			if kerrors.IsAlreadyExists(err) {
+				err = ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Namespace: vObj.GetNamespace(), Name: vObj.GetName()}, vObj)
+			} else if err == nil {
+				ctx.Log.Infof("restore virtual %T %s/%s from retained physical namespace %s", pObj, vObj.GetNamespace(), vObj.GetName(), pNamespace.Name)
+				restored++
+			}
+			if err != nil {
+				return err
+			} else if pObj.GetAnnotations()[translate.UIDAnnotation] == string(vObj.GetUID()) {
+				return nil
+			}
+
+			adopted := pObj.DeepCopyObject().(client.Object)
+			annotations := adopted.GetAnnotations()
+			annotations[translate.UIDAnnotation] = string(vObj.GetUID())
+			adopted.SetAnnotations(annotations)
+			return ctx.PhysicalClient.Update(ctx.Context, adopted)
+		})
+		if err != nil {
+			return restored, err
+		}
+	}
+
+	return restored, nil
+}
+
+// restoredObject returns the virtual object for a retained physical object
+func restoredObject(pObj client.Object, vNamespace string) client.Object {
+	vObj := pObj.DeepCopyObject().(client.Object)
+	translate.ResetObjectMetadata(vObj)
+	vObj.SetNamespace(vNamespace)
+	vObj.SetName(pObj.GetAnnotations()[translate.NameAnnotation])
+
+	annotations := vObj.GetAnnotations()
+	for _, k := range []string{translate.NameAnnotation, translate.NamespaceAnnotation, translate.UIDAnnotation, translate.ManagedAnnotationsAnnotation, translate.ManagedLabelsAnnotation} {
+		delete(annotations, k)
+	}
+	vObj.SetAnnotations(annotations)
+
+	// the virtual claim is bound to the virtual volume of the physical volume by the syncer
+	pvc, ok := vObj.(*corev1.PersistentVolumeClaim)
+	if ok {
+		pvc.Spec.VolumeName = ""
+		pvc.Status = corev1.PersistentVolumeClaimStatus{}
+	}
+
+	return vObj
+}
+
+// virtualNamespace returns a reference to the deleted virtual namespace of the physical namespace to record events on
+func virtualNamespace(pObj client.Object) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pObj.GetAnnotations()[translate.NameAnnotation],
+			UID:  types.UID(pObj.GetAnnotations()[translate.UIDAnnotation]),
+		},
+	}
+}
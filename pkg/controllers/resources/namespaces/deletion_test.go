@@ -0,0 +1,208 @@
+package namespaces
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSyncUp(t *testing.T) {
+	expired := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	pending := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	testCases := []struct {
+		name string
+
+		policy      string
+		annotations map[string]string
+		labels      map[string]string
+
+		expectedDeleted  bool
+		expectedRequeue  bool
+		expectedRetained bool
+		expectedOrphaned bool
+		expectedEvent    string
+	}{
+		{
+			name:            "delete immediately",
+			policy:          DeletionPolicyDelete,
+			expectedDeleted: true,
+			expectedEvent:   "DeletingHostNamespace",
+		},
+		{
+			name:            "start grace period",
+			policy:          DeletionPolicyGrace,
+			expectedRequeue: true,
+			expectedEvent:   "DeletingHostNamespace",
+		},
+		{
+			name:             "start retention",
+			policy:           DeletionPolicyRetain,
+			expectedRequeue:  true,
+			expectedRetained: true,
+			expectedEvent:    "RetainingHostNamespace",
+		},
+		{
+			name:             "orphan",
+			policy:           DeletionPolicyOrphan,
+			expectedRetained: true,
+			expectedOrphaned: true,
+			expectedEvent:    "OrphaningHostNamespace",
+		},
+		{
+			name:             "already orphaned",
+			policy:           DeletionPolicyOrphan,
+			annotations:      map[string]string{translate.RetainAnnotation: "true"},
+			labels:           map[string]string{translate.OrphanedLabel: "true"},
+			expectedRetained: true,
+			expectedOrphaned: true,
+		},
+		{
+			name:            "grace period pending",
+			policy:          DeletionPolicyGrace,
+			annotations:     map[string]string{translate.DeleteAfterAnnotation: pending},
+			expectedRequeue: true,
+		},
+		{
+			name:            "grace period expired",
+			policy:          DeletionPolicyRetain,
+			annotations:     map[string]string{translate.DeleteAfterAnnotation: expired, translate.RetainAnnotation: "true"},
+			expectedDeleted: true,
+			expectedEvent:   "DeletingHostNamespace",
+		},
+	}
+
+	for _, testCase := range testCases {
+		pNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: testCase.annotations, Labels: testCase.labels}}
+		ctx := &synccontext.SyncContext{
+			Context:        context.Background(),
+			Log:            loghelper.New("test"),
+			PhysicalClient: testingutil.NewFakeClient(testingutil.NewScheme(), pNamespace.DeepCopy()),
+		}
+
+		recorder := record.NewFakeRecorder(10)
+		s := &namespaceSyncer{deletionPolicy: testCase.policy, deletionGracePeriod: time.Hour, eventRecorder: recorder}
+		result, err := s.SyncUp(ctx, pNamespace)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, result.RequeueAfter > 0, testCase.expectedRequeue, "unexpected requeue in test case %s", testCase.name)
+		if testCase.expectedEvent != "" {
+			assert.Assert(t, len(recorder.Events) == 1 && strings.Contains(<-recorder.Events, testCase.expectedEvent), "expected event %s in test case %s", testCase.expectedEvent, testCase.name)
+		} else {
+			assert.Equal(t, len(recorder.Events), 0, "unexpected event in test case %s", testCase.name)
+		}
+
+		namespace := &corev1.Namespace{}
+		err = ctx.PhysicalClient.Get(ctx.Context, types.NamespacedName{Name: "test"}, namespace)
+		if testCase.expectedDeleted {
+			assert.Assert(t, kerrors.IsNotFound(err), "expected namespace to be deleted in test case %s", testCase.name)
+			continue
+		}
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, namespace.Annotations[translate.DeleteAfterAnnotation] != "", !testCase.expectedOrphaned, "unexpected delete after annotation in test case %s", testCase.name)
+		assert.Equal(t, namespace.Annotations[translate.RetainAnnotation] == "true", testCase.expectedRetained, "unexpected retention in test case %s", testCase.name)
+		assert.Equal(t, namespace.Labels[translate.OrphanedLabel] == "true", testCase.expectedOrphaned, "unexpected orphaned label in test case %s", testCase.name)
+	}
+}
+
+func TestSyncRecreated(t *testing.T) {
+	defer func() { translate.Default = translate.NewSingleNamespaceTranslator("test") }()
+
+	expired := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	pending := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	pNamespaceName := translate.NewMultiNamespaceTranslator("test").PhysicalNamespace("test")
+
+	testCases := []struct {
+		name string
+
+		annotations map[string]string
+		labels      map[string]string
+
+		expectedRestored bool
+		expectedEvent    bool
+	}{
+		{
+			name:        "undelete namespace within grace period",
+			annotations: map[string]string{translate.DeleteAfterAnnotation: pending},
+		},
+		{
+			name:             "restore retained objects",
+			annotations:      map[string]string{translate.DeleteAfterAnnotation: pending, translate.RetainAnnotation: "true"},
+			expectedRestored: true,
+			expectedEvent:    true,
+		},
+		{
+			name:        "retention expired",
+			annotations: map[string]string{translate.DeleteAfterAnnotation: expired, translate.RetainAnnotation: "true"},
+		},
+		{
+			name:             "restore orphaned objects",
+			annotations:      map[string]string{translate.RetainAnnotation: "true"},
+			labels:           map[string]string{translate.OrphanedLabel: "true"},
+			expectedRestored: true,
+			expectedEvent:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		vNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+		pNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: pNamespaceName, Annotations: testCase.annotations, Labels: testCase.labels}}
+		pConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "config",
+				Namespace: pNamespaceName,
+				Labels:    map[string]string{"app": "test"},
+				Annotations: map[string]string{
+					translate.NameAnnotation:      "config",
+					translate.NamespaceAnnotation: "test",
+					translate.UIDAnnotation:       "deleted",
+				},
+			},
+			Data: map[string]string{"key": "value"},
+		}
+		registerContext := generictesting.NewFakeRegisterContext(testingutil.NewFakeClient(testingutil.NewScheme(), pNamespace.DeepCopy(), pConfigMap.DeepCopy()), testingutil.NewFakeClient(testingutil.NewScheme(), vNamespace.DeepCopy()))
+		registerContext.Options.NamespaceDeletionPolicy = DeletionPolicyRetain
+		ctx, s := generictesting.FakeStartSyncer(t, registerContext, New)
+		translate.Default = translate.NewMultiNamespaceTranslator("test")
+		recorder := record.NewFakeRecorder(10)
+		s.(*namespaceSyncer).eventRecorder = recorder
+
+		_, err := s.(*namespaceSyncer).Sync(ctx, pNamespace, vNamespace)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, len(recorder.Events) > 0, testCase.expectedEvent, "unexpected events in test case %s", testCase.name)
+
+		namespace := &corev1.Namespace{}
+		err = ctx.PhysicalClient.Get(ctx.Context, types.NamespacedName{Name: pNamespaceName}, namespace)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, namespace.Annotations[translate.RetainAnnotation], "", "unexpected retention in test case %s", testCase.name)
+		assert.Equal(t, namespace.Annotations[translate.DeleteAfterAnnotation], "", "unexpected delete after annotation in test case %s", testCase.name)
+		assert.Equal(t, namespace.Labels[translate.OrphanedLabel], "", "unexpected orphaned label in test case %s", testCase.name)
+
+		vConfigMap := &corev1.ConfigMap{}
+		err = ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Namespace: "test", Name: "config"}, vConfigMap)
+		if !testCase.expectedRestored {
+			assert.Assert(t, kerrors.IsNotFound(err), "unexpected restored configmap in test case %s", testCase.name)
+			continue
+		}
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, vConfigMap.Data, pConfigMap.Data)
+		assert.DeepEqual(t, vConfigMap.Labels, pConfigMap.Labels)
+		assert.Equal(t, vConfigMap.Annotations[translate.UIDAnnotation], "", "unexpected uid annotation in test case %s", testCase.name)
+
+		err = ctx.PhysicalClient.Get(ctx.Context, types.NamespacedName{Namespace: pNamespaceName, Name: "config"}, pConfigMap)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, pConfigMap.Annotations[translate.UIDAnnotation], string(vConfigMap.UID), "physical configmap not adopted in test case %s", testCase.name)
+	}
+}
@@ -3,6 +3,7 @@ package namespaces
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
@@ -13,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -23,6 +25,8 @@ import (
 var excludedAnnotations = []string{
 	"scheduler.alpha.kubernetes.io/node-selector",
 	"scheduler.alpha.kubernetes.io/defaultTolerations",
+	translate.DeleteAfterAnnotation,
+	translate.RetainAnnotation,
 }
 
 const (
@@ -43,6 +47,10 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		Translator:                 translator.NewClusterTranslator(ctx, "namespace", &corev1.Namespace{}, NamespaceNameTranslator, excludedAnnotations...),
 		workloadServiceAccountName: ctx.Options.ServiceAccount,
 		namespaceLabels:            namespaceLabels,
+		syncedNamespaceLabels:      ctx.Options.SyncNamespaceLabels,
+		deletionPolicy:             ctx.Options.NamespaceDeletionPolicy,
+		deletionGracePeriod:        ctx.Options.NamespaceDeletionGracePeriod,
+		eventRecorder:              ctx.VirtualManager.GetEventRecorderFor("namespace-syncer"),
 	}, nil
 }
 
@@ -50,6 +58,11 @@ type namespaceSyncer struct {
 	translator.Translator
 	workloadServiceAccountName string
 	namespaceLabels            map[string]string
+	syncedNamespaceLabels      []string
+
+	deletionPolicy      string
+	deletionGracePeriod time.Duration
+	eventRecorder       record.EventRecorder
 }
 
 var _ syncer.IndicesRegisterer = &namespaceSyncer{}
@@ -75,6 +88,23 @@ func (s *namespaceSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Obj
 }
 
 func (s *namespaceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	// wait until the physical namespace is gone, if it was deleted before the virtual namespace was recreated
+	if pObj.GetDeletionTimestamp() != nil {
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	// restore the retained objects before the retention ends, afterwards they would be removed
+	restored := -1
+	if retention(pObj) != 0 {
+		var err error
+		restored, err = s.undelete(ctx, pObj.(*corev1.Namespace), vObj.(*corev1.Namespace))
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("restore retained objects: %w", err)
+		}
+	} else if pObj.GetAnnotations()[translate.DeleteAfterAnnotation] != "" || pObj.GetLabels()[translate.OrphanedLabel] == "true" {
+		ctx.Log.Infof("undelete physical namespace %s, because virtual namespace was recreated", pObj.GetName())
+	}
+
 	updated := s.translateUpdate(ctx.Context, pObj.(*corev1.Namespace), vObj.(*corev1.Namespace))
 	if updated != nil {
 		ctx.Log.Infof("updating physical namespace %s, because virtual namespace has changed", updated.Name)
@@ -84,6 +114,9 @@ func (s *namespaceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object,
 			return ctrl.Result{}, err
 		}
 	}
+	if restored >= 0 {
+		s.eventRecorder.Eventf(vObj, corev1.EventTypeNormal, "UndeletedHostNamespace", "Undeleted host namespace %s and restored %d retained objects", pObj.GetName(), restored)
+	}
 
 	return ctrl.Result{}, s.EnsureWorkloadServiceAccount(ctx, pObj.GetName())
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -12,6 +13,9 @@ import (
 func (s *namespaceSyncer) translate(ctx context.Context, vObj client.Object) *corev1.Namespace {
 	newNamespace := s.TranslateMetadata(ctx, vObj).(*corev1.Namespace)
 
+	// add synced labels of the virtual namespace
+	s.addSyncedNamespaceLabels(vObj.GetLabels(), newNamespace.Labels)
+
 	// add user defined namespace labels
 	for k, v := range s.namespaceLabels {
 		newNamespace.Labels[k] = v
@@ -24,12 +28,18 @@ func (s *namespaceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev
 	var updated *corev1.Namespace
 
 	_, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	// add synced labels of the virtual namespace
+	s.addSyncedNamespaceLabels(vObj.Labels, updatedLabels)
 	// add user defined namespace labels
 	for k, v := range s.namespaceLabels {
 		updatedLabels[k] = v
 	}
 	// set the kubernetes.io/metadata.name label
 	updatedLabels[corev1.LabelMetadataName] = pObj.Name
+	// undelete the physical namespace if the virtual namespace was recreated within the grace period
+	delete(updatedAnnotations, translate.DeleteAfterAnnotation)
+	delete(updatedAnnotations, translate.RetainAnnotation)
+	delete(updatedLabels, translate.OrphanedLabel)
 	// check if any labels or annotations changed
 	if !equality.Semantic.DeepEqual(updatedAnnotations, pObj.GetAnnotations()) || !equality.Semantic.DeepEqual(updatedLabels, pObj.GetLabels()) {
 		updated = translator.NewIfNil(updated, pObj)
@@ -39,3 +49,11 @@ func (s *namespaceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev
 
 	return updated
 }
+
+func (s *namespaceSyncer) addSyncedNamespaceLabels(vLabels, labels map[string]string) {
+	for _, k := range s.syncedNamespaceLabels {
+		if v, ok := vLabels[k]; ok {
+			labels[k] = v
+		}
+	}
+}
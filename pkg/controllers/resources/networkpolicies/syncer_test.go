@@ -391,3 +391,48 @@ func TestSync(t *testing.T) {
 		},
 	})
 }
+
+func TestTranslateSpecMultiNamespace(t *testing.T) {
+	defer func() { translate.Default = translate.NewSingleNamespaceTranslator("test") }()
+	translate.Default = translate.NewMultiNamespaceTranslator("test")
+
+	podSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	ipBlock := &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}
+	vSpec := &networkingv1.NetworkPolicySpec{
+		PodSelector: *podSelector,
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{
+				{PodSelector: podSelector},
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+				{NamespaceSelector: &metav1.LabelSelector{}, PodSelector: podSelector},
+				{IPBlock: ipBlock},
+			},
+		}},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	}
+
+	marker := translate.SafeConcatName("test", "x", translate.Suffix)
+	pSpec := translateSpec(vSpec, "test")
+	assert.DeepEqual(t, pSpec.PodSelector, *podSelector)
+	assert.DeepEqual(t, pSpec.PolicyTypes, vSpec.PolicyTypes)
+
+	peers := pSpec.Ingress[0].From
+	assert.Equal(t, len(peers), 4)
+
+	// pods of the same namespace
+	assert.DeepEqual(t, peers[0], networkingv1.NetworkPolicyPeer{PodSelector: podSelector})
+
+	// namespaces with translated labels, restricted to namespaces of this vcluster
+	assert.Assert(t, peers[1].PodSelector == nil)
+	assert.Equal(t, len(peers[1].NamespaceSelector.MatchLabels), 2)
+	assert.Equal(t, peers[1].NamespaceSelector.MatchLabels[translate.MarkerLabel], marker)
+	assert.Equal(t, peers[1].NamespaceSelector.MatchLabels["team"], "")
+
+	// all namespaces of this vcluster
+	assert.DeepEqual(t, peers[2], networkingv1.NetworkPolicyPeer{
+		PodSelector:       podSelector,
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{translate.MarkerLabel: marker}},
+	})
+
+	assert.DeepEqual(t, peers[3], networkingv1.NetworkPolicyPeer{IPBlock: ipBlock})
+}
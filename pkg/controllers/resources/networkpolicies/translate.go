@@ -60,9 +60,12 @@ func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *netw
 		})
 	}
 
-	// TODO(Multi-Namespace): add support for multi-namespace translation
+	// pods keep their labels in the multi-namespace mode and the physical namespace only contains
+	// pods of the virtual namespace, so the pod selector can be used as is
 	if !translate.Default.SingleNamespaceTarget() {
-		panic("Multi-Namespace Mode not supported for network policies yet!")
+		outSpec.PodSelector = *spec.PodSelector.DeepCopy()
+		outSpec.PolicyTypes = spec.PolicyTypes
+		return outSpec
 	}
 
 	outSpec.PodSelector = *translate.Default.TranslateLabelSelector(&spec.PodSelector)
@@ -81,7 +84,10 @@ func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *netw
 func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespace string) []networkingv1.NetworkPolicyPeer {
 	if peers == nil {
 		return nil
+	} else if !translate.Default.SingleNamespaceTarget() {
+		return translateNetworkPolicyPeersMultiNamespace(peers)
 	}
+
 	out := []networkingv1.NetworkPolicyPeer{}
 	for _, peer := range peers {
 		newPeer := networkingv1.NetworkPolicyPeer{
@@ -107,3 +113,18 @@ func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespa
 	}
 	return out
 }
+
+func translateNetworkPolicyPeersMultiNamespace(peers []networkingv1.NetworkPolicyPeer) []networkingv1.NetworkPolicyPeer {
+	namespaceTranslator, _ := translate.Default.(translate.NamespaceSelectorTranslator)
+	out := []networkingv1.NetworkPolicyPeer{}
+	for _, peer := range peers {
+		newPeer := *peer.DeepCopy()
+		// select the physical namespaces of the selected virtual namespaces, which never
+		// includes host namespaces that were not synced by this vcluster
+		if namespaceTranslator != nil {
+			newPeer.NamespaceSelector = namespaceTranslator.TranslateNamespaceSelector(peer.NamespaceSelector)
+		}
+		out = append(out, newPeer)
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package nodes
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// allocatableFactors scales the allocatable resources of synced nodes, so the virtual scheduler
+// keeps headroom on nodes that are shared with other tenants
+type allocatableFactors struct {
+	// all applies to every resource without a specific factor, 0 means unset
+	all float64
+
+	resources map[corev1.ResourceName]float64
+}
+
+// parseAllocatableFactors parses factors in the form [resource=]factor. Returns nil if there are
+// no factors.
+func parseAllocatableFactors(factors []string) (*allocatableFactors, error) {
+	if len(factors) == 0 {
+		return nil, nil
+	}
+
+	a := &allocatableFactors{resources: map[corev1.ResourceName]float64{}}
+	for _, f := range factors {
+		name, value, found := strings.Cut(f, "=")
+		if !found {
+			name, value = "", name
+		}
+
+		factor, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || factor <= 0 || math.IsInf(factor, 0) {
+			return nil, fmt.Errorf("invalid node allocatable factor %s: expected a positive number", f)
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			a.all = factor
+		} else {
+			a.resources[corev1.ResourceName(name)] = factor
+		}
+	}
+
+	return a, nil
+}
+
+// scale returns a copy of the resources with the factors applied, rounded down
+func (a *allocatableFactors) scale(resources corev1.ResourceList) corev1.ResourceList {
+	if a == nil || resources == nil {
+		return resources
+	}
+
+	scaled := corev1.ResourceList{}
+	for name, quantity := range resources {
+		factor, ok := a.resources[name]
+		if !ok {
+			factor = a.all
+		}
+		if factor == 0 || factor == 1 {
+			scaled[name] = quantity
+			continue
+		}
+
+		if name == corev1.ResourceCPU {
+			scaled[name] = *resource.NewMilliQuantity(int64(float64(quantity.MilliValue())*factor), quantity.Format)
+		} else {
+			scaled[name] = *resource.NewQuantity(int64(float64(quantity.Value())*factor), quantity.Format)
+		}
+	}
+
+	return scaled
+}
@@ -0,0 +1,72 @@
+package nodes
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestAllocatableFactors(t *testing.T) {
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("3"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+
+	testCases := []struct {
+		name          string
+		factors       []string
+		expected      corev1.ResourceList
+		expectedError bool
+	}{
+		{
+			name:     "No factors",
+			expected: allocatable,
+		},
+		{
+			name:    "Global factor",
+			factors: []string{"0.5"},
+			expected: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1500m"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("55"),
+			},
+		},
+		{
+			name:    "Resource factor takes precedence",
+			factors: []string{"cpu=2", "0.5", "pods=1"},
+			expected: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("6"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+		{
+			name:          "Negative factor",
+			factors:       []string{"memory=-1"},
+			expectedError: true,
+		},
+		{
+			name:          "Invalid factor",
+			factors:       []string{"cpu=half"},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		factors, err := parseAllocatableFactors(testCase.factors)
+		if testCase.expectedError {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		scaled := factors.scale(allocatable)
+		assert.Equal(t, len(scaled), len(testCase.expected), "unexpected resources in test case %s", testCase.name)
+		for name, quantity := range testCase.expected {
+			assert.Assert(t, quantity.Equal(scaled[name]), "unexpected %s %s in test case %s", name, scaled.Name(name, resource.DecimalSI).String(), testCase.name)
+		}
+	}
+}
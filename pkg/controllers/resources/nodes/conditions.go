@@ -0,0 +1,73 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// conditionFilter decides which host node conditions are visible in the vcluster and rewrites
+// messages that reveal host internals. If allowed types are set, only those are synced. Denied
+// types are never synced. The Ready condition is always synced, as the scheduler and the node
+// lifecycle controller of the vcluster depend on it.
+type conditionFilter struct {
+	allowed []string
+	denied  []string
+
+	rewrites []conditionMessageRewrite
+}
+
+// conditionMessageRewrite replaces the message of conditions that match the type. A type ending
+// with * matches all types with that prefix.
+type conditionMessageRewrite struct {
+	conditionType string
+	message       string
+}
+
+// parseConditionFilter parses the condition types and message rewrites in the form type=message
+func parseConditionFilter(allowed []string, denied []string, rewrites []string) (*conditionFilter, error) {
+	filter := &conditionFilter{allowed: allowed, denied: denied}
+	for _, r := range rewrites {
+		conditionType, message, found := strings.Cut(r, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid node condition message rewrite %s: expected format type=message", r)
+		} else if conditionType == "" {
+			return nil, fmt.Errorf("invalid node condition message rewrite %s: type is empty", r)
+		}
+
+		filter.rewrites = append(filter.rewrites, conditionMessageRewrite{conditionType: conditionType, message: message})
+	}
+
+	return filter, nil
+}
+
+func (f *conditionFilter) filter(conditions []corev1.NodeCondition) []corev1.NodeCondition {
+	if f == nil || conditions == nil || (len(f.allowed) == 0 && len(f.denied) == 0 && len(f.rewrites) == 0) {
+		return conditions
+	}
+
+	filtered := []corev1.NodeCondition{}
+	for _, condition := range conditions {
+		conditionType := string(condition.Type)
+		if condition.Type != corev1.NodeReady {
+			if len(f.allowed) > 0 && !translate.MatchesKey(f.allowed, conditionType) {
+				continue
+			} else if translate.MatchesKey(f.denied, conditionType) {
+				continue
+			}
+		}
+
+		for _, rewrite := range f.rewrites {
+			if translate.MatchesPattern(rewrite.conditionType, conditionType) {
+				condition.Message = rewrite.message
+				break
+			}
+		}
+
+		filtered = append(filtered, condition)
+	}
+
+	return filtered
+}
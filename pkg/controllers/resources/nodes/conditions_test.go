@@ -0,0 +1,79 @@
+package nodes
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConditionFilter(t *testing.T) {
+	conditions := []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Message: "kubelet is posting ready status"},
+		{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse, Message: "kubelet has sufficient memory available"},
+		{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse, Message: "kubelet has no disk pressure"},
+		{Type: "KernelDeadlock", Status: corev1.ConditionFalse, Message: "kernel has no deadlock on ip-10-0-1-23"},
+	}
+	testCases := []struct {
+		name          string
+		allowed       []string
+		denied        []string
+		rewrites      []string
+		expectedTypes []corev1.NodeConditionType
+		expectedErr   string
+	}{
+		{
+			name:          "No filter",
+			expectedTypes: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodeMemoryPressure, corev1.NodeDiskPressure, "KernelDeadlock"},
+		},
+		{
+			name:          "Allowed conditions",
+			allowed:       []string{"MemoryPressure"},
+			expectedTypes: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodeMemoryPressure},
+		},
+		{
+			name:          "Denied conditions",
+			denied:        []string{"Disk*", "Ready"},
+			expectedTypes: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodeMemoryPressure, "KernelDeadlock"},
+		},
+		{
+			name:          "Denied takes precedence",
+			allowed:       []string{"MemoryPressure", "DiskPressure"},
+			denied:        []string{"DiskPressure"},
+			expectedTypes: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodeMemoryPressure},
+		},
+		{
+			name:          "Rewritten message",
+			rewrites:      []string{"Kernel*=kernel has no deadlock"},
+			expectedTypes: []corev1.NodeConditionType{corev1.NodeReady, corev1.NodeMemoryPressure, corev1.NodeDiskPressure, "KernelDeadlock"},
+		},
+		{
+			name:        "Invalid rewrite",
+			rewrites:    []string{"KernelDeadlock"},
+			expectedErr: "invalid node condition message rewrite KernelDeadlock: expected format type=message",
+		},
+	}
+
+	for _, testCase := range testCases {
+		filter, err := parseConditionFilter(testCase.allowed, testCase.denied, testCase.rewrites)
+		if testCase.expectedErr != "" {
+			assert.Error(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+			continue
+		}
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		filtered := filter.filter(conditions)
+		types := []corev1.NodeConditionType{}
+		for _, condition := range filtered {
+			types = append(types, condition.Type)
+		}
+		assert.DeepEqual(t, types, testCase.expectedTypes)
+
+		if len(testCase.rewrites) > 0 {
+			assert.Equal(t, filtered[3].Message, "kernel has no deadlock", "unexpected message in test case %s", testCase.name)
+			assert.Equal(t, filtered[0].Message, conditions[0].Message, "unexpected message in test case %s", testCase.name)
+		}
+	}
+
+	assert.Equal(t, conditions[3].Message, "kernel has no deadlock on ip-10-0-1-23", "host conditions are not modified")
+}
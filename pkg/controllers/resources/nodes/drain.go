@@ -0,0 +1,72 @@
+package nodes
+
+import (
+	"github.com/loft-sh/vcluster/pkg/constants"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReasonHostNodeCordoned is the event reason for virtual pods on a cordoned host node
+	ReasonHostNodeCordoned = "HostNodeCordoned"
+
+	// ReasonHostNodeDraining is the event reason for virtual pods on a host node that is about to be drained
+	ReasonHostNodeDraining = "HostNodeDraining"
+)
+
+// drainTaints are set by node autoscalers before they drain and remove a node, often without
+// cordoning it
+var drainTaints = []string{
+	"ToBeDeletedByClusterAutoscaler",
+	"karpenter.sh/disruption",
+	corev1.TaintNodeOutOfService,
+}
+
+// isDraining returns true if the node carries a taint of a node autoscaler that is about to drain it
+func isDraining(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		for _, drainTaint := range drainTaints {
+			if taint.Key == drainTaint && taint.Effect != corev1.TaintEffectPreferNoSchedule {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// unschedulableReason returns the event reason if the host node is interrupted, cordoned or draining
+func unschedulableReason(pNode *corev1.Node, interruption *interruptionSignals) string {
+	if interruption.interrupted(pNode) {
+		return ReasonHostNodeInterruption
+	} else if isDraining(pNode) {
+		return ReasonHostNodeDraining
+	} else if pNode.Spec.Unschedulable {
+		return ReasonHostNodeCordoned
+	}
+
+	return ""
+}
+
+// recordUnschedulable emits an event on every virtual pod of the node, so tenants can move their
+// workloads before the pods get evicted
+func (s *nodeSyncer) recordUnschedulable(ctx *synccontext.SyncContext, pNode *corev1.Node, reason string) error {
+	podList := &corev1.PodList{}
+	err := ctx.VirtualClient.List(ctx.Context, podList, client.MatchingFields{constants.IndexByAssigned: pNode.Name})
+	if err != nil {
+		return err
+	}
+
+	for i := range podList.Items {
+		if reason == ReasonHostNodeInterruption {
+			s.eventRecorder.Eventf(&podList.Items[i], corev1.EventTypeWarning, reason, "Host node %s is about to be reclaimed by the cloud provider, the pod will be terminated", pNode.Name)
+		} else if reason == ReasonHostNodeDraining {
+			s.eventRecorder.Eventf(&podList.Items[i], corev1.EventTypeWarning, reason, "Host node %s is about to be drained, the pod will be evicted", pNode.Name)
+		} else {
+			s.eventRecorder.Eventf(&podList.Items[i], corev1.EventTypeWarning, reason, "Host node %s was cordoned, the pod might be evicted", pNode.Name)
+		}
+	}
+
+	return nil
+}
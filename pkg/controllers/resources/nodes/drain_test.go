@@ -0,0 +1,44 @@
+package nodes
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestUnschedulableReason(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     corev1.NodeSpec
+		expected string
+	}{
+		{
+			name: "Schedulable node",
+		},
+		{
+			name:     "Cordoned node",
+			spec:     corev1.NodeSpec{Unschedulable: true},
+			expected: ReasonHostNodeCordoned,
+		},
+		{
+			name:     "Cluster autoscaler scale down",
+			spec:     corev1.NodeSpec{Unschedulable: true, Taints: []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}}},
+			expected: ReasonHostNodeDraining,
+		},
+		{
+			name:     "Karpenter disruption",
+			spec:     corev1.NodeSpec{Taints: []corev1.Taint{{Key: "karpenter.sh/disruption", Value: "disrupting", Effect: corev1.TaintEffectNoSchedule}}},
+			expected: ReasonHostNodeDraining,
+		},
+		{
+			name: "Scale down candidate",
+			spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectPreferNoSchedule}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		reason := unschedulableReason(&corev1.Node{Spec: testCase.spec}, nil)
+		assert.Equal(t, reason, testCase.expected, "unexpected reason in test case %s", testCase.name)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/pkg/errors"
@@ -26,21 +27,49 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// FakeNodeLabel marks the virtual nodes that are fake nodes
+const FakeNodeLabel = "vcluster.loft.sh/fake-node"
+
 var (
 	// FakeNodesVersion is the default version that will be used for fake nodes
 	FakeNodesVersion = "v1.19.1"
 )
 
 func NewFakeSyncer(ctx *synccontext.RegisterContext, nodeService nodeservice.NodeServiceProvider) (syncer.Object, error) {
-	return &fakeNodeSyncer{
+	template, err := parseFakeNodeTemplate(ctx.Options.FakeNodeResources, ctx.Options.FakeNodeLabels)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse fake node template")
+	}
+
+	podCIDRs, err := newPodCIDRAllocator(ctx.Options.NodePodCIDR, ctx.Options.NodePodCIDRMaskSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node pod cidr")
+	}
+
+	fakeSyncer := &fakeNodeSyncer{
 		nodeServiceProvider: nodeService,
 		fakeKubeletIPs:      ctx.Options.FakeKubeletIPs,
-	}, nil
+		fakeNodeTopology:    ctx.Options.FakeNodeTopology,
+		template:            template,
+		podCIDRs:            podCIDRs,
+	}
+	if ctx.Options.FakeNodeTopology {
+		// host nodes are read without the cache, as the vcluster usually can't watch them
+		fakeSyncer.hostNodeReader = ctx.PhysicalManager.GetAPIReader()
+	}
+
+	return fakeSyncer, nil
 }
 
 type fakeNodeSyncer struct {
 	nodeServiceProvider nodeservice.NodeServiceProvider
 	fakeKubeletIPs      bool
+	fakeNodeTopology    bool
+	template            *fakeNodeTemplate
+	podCIDRs            *podCIDRAllocator
+
+	hostNodeReader     client.Reader
+	hostNodesForbidden atomic.Bool
 }
 
 func (r *fakeNodeSyncer) Resource() client.Object {
@@ -54,12 +83,23 @@ func (r *fakeNodeSyncer) Name() string {
 var _ syncer.IndicesRegisterer = &fakeNodeSyncer{}
 
 func (r *fakeNodeSyncer) RegisterIndices(ctx *synccontext.RegisterContext) error {
+	if r.fakeNodeTopology {
+		err := registerTopologyIndices(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	return registerIndices(ctx)
 }
 
 var _ syncer.ControllerModifier = &fakeNodeSyncer{}
 
 func (r *fakeNodeSyncer) ModifyController(ctx *synccontext.RegisterContext, builder *builder.Builder) (*builder.Builder, error) {
+	if r.fakeNodeTopology {
+		builder = modifyTopologyController(ctx, builder)
+	}
+
 	return modifyController(ctx, r.nodeServiceProvider, builder)
 }
 
@@ -74,7 +114,7 @@ func (r *fakeNodeSyncer) FakeSyncUp(ctx *synccontext.SyncContext, name types.Nam
 	}
 
 	ctx.Log.Infof("Create fake node %s", name.Name)
-	return ctrl.Result{}, CreateFakeNode(ctx.Context, r.fakeKubeletIPs, r.nodeServiceProvider, ctx.VirtualClient, name.Name)
+	return ctrl.Result{}, CreateFakeNode(ctx.Context, r.fakeKubeletIPs, r.template, r.podCIDRs, r.nodeServiceProvider, ctx.VirtualClient, name.Name)
 }
 
 func (r *fakeNodeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
@@ -91,6 +131,11 @@ func (r *fakeNodeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj client.Obje
 		return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
 	}
 
+	// fake nodes created before the pod cidr range was configured get a podCIDR as well
+	if r.podCIDRs != nil && node.Spec.PodCIDR == "" {
+		return ctrl.Result{}, assignPodCIDR(ctx, r.podCIDRs, node)
+	}
+
 	// check if we need to update node ips
 	updated := r.updateIfNeeded(ctx, node, node.Name)
 	if updated != nil {
@@ -101,6 +146,20 @@ func (r *fakeNodeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj client.Obje
 		}
 	}
 
+	// check if we need to update the node resources and labels of the template
+	err = r.syncTemplate(ctx, node)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "update node from template")
+	}
+
+	// check if we need to update the node topology
+	if r.fakeNodeTopology {
+		err := r.syncTopology(ctx, node)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "update node topology")
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -134,6 +193,23 @@ func (r *fakeNodeSyncer) updateIfNeeded(ctx *synccontext.SyncContext, node *core
 	return updated
 }
 
+func (r *fakeNodeSyncer) syncTemplate(ctx *synccontext.SyncContext, node *corev1.Node) error {
+	updated := node.DeepCopy()
+	if !r.template.apply(updated) {
+		return nil
+	}
+
+	ctx.Log.Infof("Update fake node %s from template", node.Name)
+	if !equality.Semantic.DeepEqual(updated.Labels, node.Labels) {
+		err := ctx.VirtualClient.Patch(ctx.Context, updated.DeepCopy(), client.MergeFrom(node))
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctx.VirtualClient.Status().Patch(ctx.Context, updated, client.MergeFrom(node))
+}
+
 func (r *fakeNodeSyncer) nodeNeeded(ctx *synccontext.SyncContext, nodeName string) (bool, error) {
 	return isNodeNeededByPod(ctx.Context, ctx.VirtualClient, ctx.PhysicalClient, nodeName)
 }
@@ -145,6 +221,8 @@ func newGUID() string {
 
 func CreateFakeNode(ctx context.Context,
 	fakeKubeletIPs bool,
+	template *fakeNodeTemplate,
+	podCIDRs *podCIDRAllocator,
 	nodeServiceProvider nodeservice.NodeServiceProvider,
 	virtualClient client.Client,
 	name string) error {
@@ -156,12 +234,12 @@ func CreateFakeNode(ctx context.Context,
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 			Labels: map[string]string{
-				"vcluster.loft.sh/fake-node": "true",
-				"beta.kubernetes.io/arch":    "amd64",
-				"beta.kubernetes.io/os":      "linux",
-				"kubernetes.io/arch":         "amd64",
-				"kubernetes.io/hostname":     translate.SafeConcatName("fake", name),
-				"kubernetes.io/os":           "linux",
+				FakeNodeLabel:             "true",
+				"beta.kubernetes.io/arch": "amd64",
+				"beta.kubernetes.io/os":   "linux",
+				"kubernetes.io/arch":      "amd64",
+				"kubernetes.io/hostname":  translate.SafeConcatName("fake", name),
+				"kubernetes.io/os":        "linux",
 			},
 			Annotations: map[string]string{
 				"node.alpha.kubernetes.io/ttl":                           "0",
@@ -169,6 +247,17 @@ func CreateFakeNode(ctx context.Context,
 			},
 		},
 	}
+	template.apply(node)
+
+	if podCIDRs != nil {
+		podCIDR, err := podCIDRs.allocate(ctx, virtualClient, name)
+		if err != nil {
+			return errors.Wrap(err, "allocate podCIDR")
+		}
+
+		node.Spec.PodCIDR = podCIDR
+		node.Spec.PodCIDRs = []string{podCIDR}
+	}
 
 	err := virtualClient.Create(ctx, node)
 	if err != nil {
@@ -252,6 +341,7 @@ func CreateFakeNode(ctx context.Context,
 		},
 		Images: []corev1.ContainerImage{},
 	}
+	template.apply(node)
 
 	if fakeKubeletIPs {
 		nodeIP, err := nodeServiceProvider.GetNodeIP(ctx, name)
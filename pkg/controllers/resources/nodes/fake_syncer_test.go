@@ -141,6 +141,17 @@ func TestFakeSync(t *testing.T) {
 		},
 	}
 
+	templateNode := baseNode.DeepCopy()
+	templateNode.Labels["node.kubernetes.io/instance-type"] = "m5.large"
+	templateNode.Status.Capacity[corev1.ResourceCPU] = resource.MustParse("8")
+	templateNode.Status.Allocatable[corev1.ResourceCPU] = resource.MustParse("7500m")
+	templateNode.Status.Capacity["nvidia.com/gpu"] = resource.MustParse("4")
+	templateNode.Status.Allocatable["nvidia.com/gpu"] = resource.MustParse("4")
+
+	podCIDRNode := baseNode.DeepCopy()
+	podCIDRNode.Spec.PodCIDR = "10.244.0.0/24"
+	podCIDRNode.Spec.PodCIDRs = []string{"10.244.0.0/24"}
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name:                "Create",
@@ -192,5 +203,37 @@ func TestFakeSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Update from template",
+			InitialVirtualState: []runtime.Object{baseNode.DeepCopy(), basePod.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {templateNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.FakeNodeResources = []string{"cpu=16", baseName.Name + ":cpu=8/7500m", baseName.Name + ":nvidia.com/gpu=4"}
+				ctx.Options.FakeNodeLabels = []string{"node.kubernetes.io/instance-type=m5.large"}
+				syncContext, syncer := newFakeFakeSyncer(t, ctx)
+
+				_, err := syncer.FakeSync(syncContext, baseNode.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Assign podCIDR",
+			InitialVirtualState: []runtime.Object{baseNode.DeepCopy(), basePod.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {podCIDRNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodePodCIDR = "10.244.0.0/24"
+				ctx.Options.NodePodCIDRMaskSize = 24
+				syncContext, syncer := newFakeFakeSyncer(t, ctx)
+
+				_, err := syncer.FakeSync(syncContext, baseNode.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 	})
 }
@@ -0,0 +1,149 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// fakeNodeTemplate holds the resources and labels of fake nodes. Entries are stored by node name, where
+// the empty name holds the entries that apply to all fake nodes.
+type fakeNodeTemplate struct {
+	capacity    map[string]corev1.ResourceList
+	allocatable map[string]corev1.ResourceList
+	labels      map[string]map[string]string
+}
+
+// parseFakeNodeTemplate parses resources in the form [node:]resource=capacity[/allocatable] and labels in the
+// form [node:]key=value. Entries without a node apply to all fake nodes, entries with a node override those.
+func parseFakeNodeTemplate(resources []string, labels []string) (*fakeNodeTemplate, error) {
+	template := &fakeNodeTemplate{
+		capacity:    map[string]corev1.ResourceList{},
+		allocatable: map[string]corev1.ResourceList{},
+		labels:      map[string]map[string]string{},
+	}
+
+	for _, r := range resources {
+		nodeName, name, value, err := splitTemplateEntry(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fake node resource %s: %w", r, err)
+		}
+
+		capacityValue, allocatableValue, found := strings.Cut(value, "/")
+		if !found {
+			allocatableValue = capacityValue
+		}
+		capacity, err := resource.ParseQuantity(capacityValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fake node resource %s: %w", r, err)
+		}
+		allocatable, err := resource.ParseQuantity(allocatableValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fake node resource %s: %w", r, err)
+		} else if allocatable.Cmp(capacity) > 0 {
+			return nil, fmt.Errorf("invalid fake node resource %s: allocatable is greater than capacity", r)
+		}
+
+		if template.capacity[nodeName] == nil {
+			template.capacity[nodeName] = corev1.ResourceList{}
+			template.allocatable[nodeName] = corev1.ResourceList{}
+		}
+		template.capacity[nodeName][corev1.ResourceName(name)] = capacity
+		template.allocatable[nodeName][corev1.ResourceName(name)] = allocatable
+	}
+
+	for _, l := range labels {
+		nodeName, key, value, err := splitTemplateEntry(l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fake node label %s: %w", l, err)
+		} else if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid fake node label %s: %s", l, strings.Join(errs, ", "))
+		} else if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid fake node label %s: %s", l, strings.Join(errs, ", "))
+		}
+
+		if template.labels[nodeName] == nil {
+			template.labels[nodeName] = map[string]string{}
+		}
+		template.labels[nodeName][key] = value
+	}
+
+	return template, nil
+}
+
+func splitTemplateEntry(entry string) (string, string, string, error) {
+	key, value, found := strings.Cut(entry, "=")
+	if !found {
+		return "", "", "", fmt.Errorf("expected format [node:]key=value")
+	}
+
+	nodeName := ""
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		nodeName, key = key[:i], key[i+1:]
+		if nodeName == "" {
+			return "", "", "", fmt.Errorf("node name is empty")
+		}
+	}
+	if key == "" {
+		return "", "", "", fmt.Errorf("key is empty")
+	}
+
+	return nodeName, key, value, nil
+}
+
+// apply sets the resources and labels of the template on the fake node and returns true if the node changed
+func (t *fakeNodeTemplate) apply(node *corev1.Node) bool {
+	if t == nil {
+		return false
+	}
+
+	// entries of the node override the entries of all nodes
+	capacity, allocatable, labels := corev1.ResourceList{}, corev1.ResourceList{}, map[string]string{}
+	for _, nodeName := range []string{"", node.Name} {
+		for name, quantity := range t.capacity[nodeName] {
+			capacity[name] = quantity
+		}
+		for name, quantity := range t.allocatable[nodeName] {
+			allocatable[name] = quantity
+		}
+		for k, v := range t.labels[nodeName] {
+			labels[k] = v
+		}
+	}
+
+	changed := applyResources(&node.Status.Capacity, capacity)
+	if applyResources(&node.Status.Allocatable, allocatable) {
+		changed = true
+	}
+	for k, v := range labels {
+		if existing, ok := node.Labels[k]; ok && existing == v {
+			continue
+		}
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[k] = v
+		changed = true
+	}
+
+	return changed
+}
+
+func applyResources(target *corev1.ResourceList, resources corev1.ResourceList) bool {
+	changed := false
+	for name, quantity := range resources {
+		if existing, ok := (*target)[name]; ok && existing.Cmp(quantity) == 0 {
+			continue
+		}
+		if *target == nil {
+			*target = corev1.ResourceList{}
+		}
+		(*target)[name] = quantity.DeepCopy()
+		changed = true
+	}
+
+	return changed
+}
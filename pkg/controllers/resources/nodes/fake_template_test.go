@@ -0,0 +1,52 @@
+package nodes
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseFakeNodeTemplate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		resources     []string
+		labels        []string
+		expectedError bool
+	}{
+		{
+			name:      "Valid template",
+			resources: []string{"cpu=8", "memory=32Gi/30Gi", "node-1:nvidia.com/gpu=4"},
+			labels:    []string{"node.kubernetes.io/instance-type=m5.large", "node-1:gpu=true"},
+		},
+		{
+			name:          "Missing value",
+			resources:     []string{"cpu"},
+			expectedError: true,
+		},
+		{
+			name:          "Invalid quantity",
+			resources:     []string{"memory=a lot"},
+			expectedError: true,
+		},
+		{
+			name:          "Allocatable greater than capacity",
+			resources:     []string{"cpu=4/8"},
+			expectedError: true,
+		},
+		{
+			name:          "Empty node name",
+			labels:        []string{":gpu=true"},
+			expectedError: true,
+		},
+		{
+			name:          "Invalid label value",
+			labels:        []string{"team=a b"},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err := parseFakeNodeTemplate(testCase.resources, testCase.labels)
+		assert.Equal(t, err != nil, testCase.expectedError, "unexpected error %v in test case %s", err, testCase.name)
+	}
+}
@@ -0,0 +1,114 @@
+package nodes
+
+import (
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReasonHostNodeInterruption is the event reason for virtual pods on a spot or preemptible host node
+	// that is about to be reclaimed
+	ReasonHostNodeInterruption = "HostNodeInterruption"
+
+	// evictionRetryInterval is the interval evictions that are blocked by a pod disruption budget are retried in
+	evictionRetryInterval = 5 * time.Second
+)
+
+// defaultInterruptionTaints are set by cloud providers and their node termination handlers when a spot
+// or preemptible node receives an interruption notice
+var defaultInterruptionTaints = []string{
+	"aws-node-termination-handler/spot-itn",
+	"aws-node-termination-handler/asg-lifecycle-termination",
+	"aws-node-termination-handler/scheduled-maintenance",
+	"cloud.google.com/impending-node-termination",
+}
+
+// interruptionSignals detect host nodes that are about to be reclaimed by the cloud provider, either
+// through a taint or a node condition, e.g. set by the node problem detector
+type interruptionSignals struct {
+	taints     []string
+	conditions []corev1.NodeConditionType
+}
+
+func newInterruptionSignals(taints []string, conditions []string) *interruptionSignals {
+	signals := &interruptionSignals{
+		taints: append(append([]string{}, defaultInterruptionTaints...), taints...),
+	}
+	for _, condition := range conditions {
+		signals.conditions = append(signals.conditions, corev1.NodeConditionType(condition))
+	}
+
+	return signals
+}
+
+// interrupted returns true if the node carries an interruption taint or condition
+func (i *interruptionSignals) interrupted(node *corev1.Node) bool {
+	if i == nil {
+		return false
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectPreferNoSchedule && translate.MatchesKey(i.taints, taint.Key) {
+			return true
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		for _, conditionType := range i.conditions {
+			if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// evictPods evicts the virtual pods of an interrupted node through the eviction api, so pod disruption
+// budgets of the vcluster are respected. Evictions blocked by a budget are retried.
+func (s *nodeSyncer) evictPods(ctx *synccontext.SyncContext, pNode *corev1.Node) (ctrl.Result, error) {
+	podList := &corev1.PodList{}
+	err := ctx.VirtualClient.List(ctx.Context, podList, client.MatchingFields{constants.IndexByAssigned: pNode.Name})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	retry := false
+	for _, pod := range filterOutVirtualDaemonSets(podList) {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		ctx.Log.Infof("evict virtual pod %s/%s, because host node %s is about to be reclaimed", pod.Namespace, pod.Name, pNode.Name)
+		err = ctx.VirtualClient.SubResource("eviction").Create(ctx.Context, &pod, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+			},
+		})
+		if kerrors.IsTooManyRequests(err) {
+			retry = true
+			continue
+		} else if kerrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		s.eventRecorder.Eventf(&pod, corev1.EventTypeWarning, ReasonHostNodeInterruption, "Evicted pod, because host node %s is about to be reclaimed", pNode.Name)
+	}
+
+	if retry {
+		return ctrl.Result{RequeueAfter: evictionRetryInterval}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,58 @@
+package nodes
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestInterrupted(t *testing.T) {
+	testCases := []struct {
+		name       string
+		taints     []string
+		conditions []string
+		node       corev1.Node
+		expected   bool
+	}{
+		{
+			name: "Regular node",
+		},
+		{
+			name:     "AWS spot interruption",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "aws-node-termination-handler/spot-itn", Effect: corev1.TaintEffectNoSchedule}}}},
+			expected: true,
+		},
+		{
+			name:     "GKE preemption",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "cloud.google.com/impending-node-termination", Effect: corev1.TaintEffectNoSchedule}}}},
+			expected: true,
+		},
+		{
+			name: "Prefer no schedule is ignored",
+			node: corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "aws-node-termination-handler/spot-itn", Effect: corev1.TaintEffectPreferNoSchedule}}}},
+		},
+		{
+			name:     "Additional taint prefix",
+			taints:   []string{"example.com/preempt*"},
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "example.com/preemption", Effect: corev1.TaintEffectNoExecute}}}},
+			expected: true,
+		},
+		{
+			name:       "Interruption condition",
+			conditions: []string{"PreemptionScheduled"},
+			node:       corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: "PreemptionScheduled", Status: corev1.ConditionTrue}}}},
+			expected:   true,
+		},
+		{
+			name:       "Interruption condition not true",
+			conditions: []string{"PreemptionScheduled"},
+			node:       corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: "PreemptionScheduled", Status: corev1.ConditionFalse}}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		interrupted := newInterruptionSignals(testCase.taints, testCase.conditions).interrupted(&testCase.node)
+		assert.Equal(t, interrupted, testCase.expected, "unexpected result in test case %s", testCase.name)
+	}
+}
@@ -0,0 +1,50 @@
+package nodes
+
+import (
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// labelFilter decides which host node labels are visible in the vcluster. If allowed
+// labels are set, only those are synced. Denied labels are never synced. Topology labels
+// are always synced, as topology spread constraints and volume topology depend on them.
+type labelFilter struct {
+	allowed []string
+	denied  []string
+}
+
+func (f *labelFilter) filter(labels map[string]string) map[string]string {
+	if f == nil || labels == nil || (len(f.allowed) == 0 && len(f.denied) == 0) {
+		return labels
+	}
+
+	filtered := map[string]string{}
+	for k, v := range labels {
+		if isTopologyLabel(k) {
+			filtered[k] = v
+			continue
+		} else if len(f.allowed) > 0 && !translate.MatchesKey(f.allowed, k) {
+			continue
+		} else if translate.MatchesKey(f.denied, k) {
+			continue
+		}
+
+		filtered[k] = v
+	}
+
+	return filtered
+}
+
+// isTopologyLabel returns true for the well known zone and region labels and the topology labels
+// of csi drivers, which use the topology.<driver>/ prefix by convention
+func isTopologyLabel(key string) bool {
+	switch key {
+	case corev1.LabelTopologyZone, corev1.LabelTopologyRegion, corev1.LabelFailureDomainBetaZone, corev1.LabelFailureDomainBetaRegion:
+		return true
+	}
+
+	prefix, _, found := strings.Cut(key, "/")
+	return found && strings.HasPrefix(prefix, "topology.")
+}
@@ -0,0 +1,75 @@
+package nodes
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+)
+
+func TestLabelFilter(t *testing.T) {
+	labels := map[string]string{
+		"kubernetes.io/hostname":            "node-1",
+		"topology.kubernetes.io/zone":       "eu-west-1a",
+		"topology.kubernetes.io/region":     "eu-west-1",
+		"topology.ebs.csi.aws.com/zone":     "eu-west-1a",
+		"eks.amazonaws.com/nodegroup":       "default",
+		"eks.amazonaws.com/nodegroup-image": "ami-123",
+		"example.com/account":               "123456789",
+	}
+	testCases := []struct {
+		name     string
+		filter   *labelFilter
+		expected map[string]string
+	}{
+		{
+			name:     "No filter",
+			filter:   &labelFilter{},
+			expected: labels,
+		},
+		{
+			name:   "Allowed labels",
+			filter: &labelFilter{allowed: []string{"kubernetes.io/hostname", "topology.kubernetes.io/*"}},
+			expected: map[string]string{
+				"kubernetes.io/hostname":        "node-1",
+				"topology.kubernetes.io/zone":   "eu-west-1a",
+				"topology.kubernetes.io/region": "eu-west-1",
+				"topology.ebs.csi.aws.com/zone": "eu-west-1a",
+			},
+		},
+		{
+			name:   "Denied labels",
+			filter: &labelFilter{denied: []string{"eks.amazonaws.com/*", "example.com/account"}},
+			expected: map[string]string{
+				"kubernetes.io/hostname":        "node-1",
+				"topology.kubernetes.io/zone":   "eu-west-1a",
+				"topology.kubernetes.io/region": "eu-west-1",
+				"topology.ebs.csi.aws.com/zone": "eu-west-1a",
+			},
+		},
+		{
+			name:   "Denied labels take precedence",
+			filter: &labelFilter{allowed: []string{"eks.amazonaws.com/*"}, denied: []string{"eks.amazonaws.com/nodegroup-image"}},
+			expected: map[string]string{
+				"eks.amazonaws.com/nodegroup":   "default",
+				"topology.kubernetes.io/zone":   "eu-west-1a",
+				"topology.kubernetes.io/region": "eu-west-1",
+				"topology.ebs.csi.aws.com/zone": "eu-west-1a",
+			},
+		},
+		{
+			name:   "Topology labels are always synced",
+			filter: &labelFilter{allowed: []string{"kubernetes.io/hostname"}, denied: []string{"topology.*"}},
+			expected: map[string]string{
+				"kubernetes.io/hostname":        "node-1",
+				"topology.kubernetes.io/zone":   "eu-west-1a",
+				"topology.kubernetes.io/region": "eu-west-1",
+				"topology.ebs.csi.aws.com/zone": "eu-west-1a",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Assert(t, cmp.DeepEqual(testCase.filter.filter(labels), testCase.expected), "unexpected labels in test case %s", testCase.name)
+	}
+}
@@ -0,0 +1,124 @@
+package nodes
+
+import (
+	"fmt"
+	"time"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionHostNodeMissing is the condition of a virtual node whose host node is gone
+	ConditionHostNodeMissing corev1.NodeConditionType = "HostNodeMissing"
+
+	// ReasonHostNodeMissing is the reason of the HostNodeMissing condition and event
+	ReasonHostNodeMissing = "HostNodeMissing"
+
+	// missingHeartbeatInterval is how often the heartbeat of a node with a missing host node is renewed. It matches
+	// the status update frequency of the kubelet, so even the default node-monitor-grace-period of 40s is kept.
+	missingHeartbeatInterval = 10 * time.Second
+)
+
+// IsHostNodeMissing returns true if the host node of the virtual node is gone, but the virtual node is still
+// kept for the deletion grace period
+func IsHostNodeMissing(vNode *corev1.Node) bool {
+	missing := findNodeCondition(vNode.Status.Conditions, ConditionHostNodeMissing)
+	return missing != nil && missing.Status == corev1.ConditionTrue
+}
+
+// markHostNodeMissing cordons the virtual node while its host node is missing and returns when to check the node
+// again, which is not positive once the grace period is over. The last conditions of the host node are kept and
+// the HostNodeMissing condition is added.
+//
+// The node lifecycle controller of the virtual cluster would mark the node unreachable once its heartbeats stop,
+// e.g. after the --node-monitor-grace-period=180s of the k8s distro, and the taint manager would then evict all
+// pods of the node after their unreachable toleration of 300s, long before the grace period is over. So the
+// heartbeat of the Ready condition is renewed until the host node returns or the virtual node is removed. Once
+// the host node returns, its status and spec replace the condition and the cordon.
+func (s *nodeSyncer) markHostNodeMissing(ctx *synccontext.SyncContext, vNode *corev1.Node) (time.Duration, error) {
+	now := metav1.Now()
+	missingSince := now
+	missing := findNodeCondition(vNode.Status.Conditions, ConditionHostNodeMissing)
+	if missing != nil && missing.Status == corev1.ConditionTrue {
+		missingSince = missing.LastTransitionTime
+	} else {
+		missing = nil
+	}
+
+	remaining := s.deletionGracePeriod - now.Sub(missingSince.Time)
+	if remaining <= 0 {
+		return remaining, nil
+	}
+
+	// renew the heartbeat only when it is due
+	if missing != nil {
+		heartbeatAgo := now.Sub(missing.LastHeartbeatTime.Time)
+		if heartbeatAgo >= 0 && heartbeatAgo < missingHeartbeatInterval {
+			return minDuration(remaining, missingHeartbeatInterval-heartbeatAgo), nil
+		}
+	}
+
+	// new pods shouldn't be scheduled to the node
+	if !vNode.Spec.Unschedulable {
+		updated := vNode.DeepCopy()
+		updated.Spec.Unschedulable = true
+		ctx.Log.Infof("cordon virtual node %s, because host node is missing", vNode.Name)
+		err := ctx.VirtualClient.Update(ctx.Context, updated)
+		if err != nil {
+			return 0, err
+		}
+
+		vNode = updated
+	}
+
+	updated := vNode.DeepCopy()
+	condition := corev1.NodeCondition{
+		Type:               ConditionHostNodeMissing,
+		Status:             corev1.ConditionTrue,
+		Reason:             ReasonHostNodeMissing,
+		Message:            fmt.Sprintf("Host node is missing, the node is removed if it does not return within %s", s.deletionGracePeriod),
+		LastHeartbeatTime:  now,
+		LastTransitionTime: missingSince,
+	}
+	if existing := findNodeCondition(updated.Status.Conditions, ConditionHostNodeMissing); existing != nil {
+		*existing = condition
+	} else {
+		updated.Status.Conditions = append(updated.Status.Conditions, condition)
+	}
+	if ready := findNodeCondition(updated.Status.Conditions, corev1.NodeReady); ready != nil {
+		ready.LastHeartbeatTime = now
+	}
+
+	if missing == nil {
+		ctx.Log.Infof("mark virtual node %s as host node missing", vNode.Name)
+	}
+	err := ctx.VirtualClient.Status().Update(ctx.Context, updated)
+	if err != nil {
+		return 0, err
+	}
+
+	if missing == nil {
+		s.eventRecorder.Eventf(vNode, corev1.EventTypeWarning, ReasonHostNodeMissing, "Host node is missing, the node is removed if it does not return within %s", s.deletionGracePeriod)
+	}
+	return minDuration(remaining, missingHeartbeatInterval), nil
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func findNodeCondition(conditions []corev1.NodeCondition, conditionType corev1.NodeConditionType) *corev1.NodeCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
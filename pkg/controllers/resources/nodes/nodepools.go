@@ -0,0 +1,93 @@
+package nodes
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/util/nodepools"
+	"github.com/loft-sh/vcluster/pkg/util/taints"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// poolPolicy holds the sync policies of the virtual nodes of a node pool
+type poolPolicy struct {
+	pool *nodepools.Pool
+
+	// taintRules are the rules of the pool followed by the global ones
+	taintRules []taints.Rule
+
+	// allocatableFactors are the factors of the pool, or the global ones if the pool has none
+	allocatableFactors *allocatableFactors
+}
+
+func parsePoolPolicies(pools nodepools.Pools, taintRules []taints.Rule, factors *allocatableFactors) (map[string]*poolPolicy, error) {
+	if len(pools) == 0 {
+		return nil, nil
+	}
+
+	policies := map[string]*poolPolicy{}
+	for _, pool := range pools {
+		poolTaintRules, err := taints.ParseRules(pool.HiddenTaints, nil)
+		if err != nil {
+			return nil, fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
+		poolFactors, err := parseAllocatableFactors(pool.AllocatableFactors)
+		if err != nil {
+			return nil, fmt.Errorf("node pool %s: %w", pool.Name, err)
+		} else if poolFactors == nil {
+			poolFactors = factors
+		}
+
+		policies[pool.Name] = &poolPolicy{
+			pool:               pool,
+			taintRules:         append(poolTaintRules, taintRules...),
+			allocatableFactors: poolFactors,
+		}
+	}
+
+	return policies, nil
+}
+
+// policyFor returns the policy of the node pool the host node belongs to, or nil
+func (s *nodeSyncer) policyFor(pNode *corev1.Node) *poolPolicy {
+	pool := s.nodePools.ForNode(pNode.Labels)
+	if pool == nil {
+		return nil
+	}
+
+	return s.poolPolicies[pool.Name]
+}
+
+// translateLabels returns the filtered labels of the host node together with the labels of its node pool
+func (s *nodeSyncer) translateLabels(pNode *corev1.Node) map[string]string {
+	filtered := s.labelFilter.filter(pNode.Labels)
+	policy := s.policyFor(pNode)
+	if policy == nil {
+		return filtered
+	}
+
+	translated := map[string]string{}
+	for k, v := range filtered {
+		translated[k] = v
+	}
+	for k, v := range policy.pool.VirtualLabels() {
+		translated[k] = v
+	}
+
+	return translated
+}
+
+func (s *nodeSyncer) taintRulesFor(pNode *corev1.Node) []taints.Rule {
+	if policy := s.policyFor(pNode); policy != nil {
+		return policy.taintRules
+	}
+
+	return s.taintRules
+}
+
+func (s *nodeSyncer) allocatableFactorsFor(pNode *corev1.Node) *allocatableFactors {
+	if policy := s.policyFor(pNode); policy != nil {
+		return policy.allocatableFactors
+	}
+
+	return s.allocatableFactors
+}
@@ -0,0 +1,64 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/nodepools"
+	"github.com/loft-sh/vcluster/pkg/util/taints"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodePools(t *testing.T) {
+	pools, err := nodepools.Parse(`
+- name: gpu
+  nodeSelector:
+    example.com/gpu: "true"
+  labels:
+    tier: premium
+  hiddenTaints:
+  - nvidia.com/gpu
+  allocatableFactors:
+  - "0.5"
+`)
+	assert.NilError(t, err)
+	taintRules, err := taints.ParseRules([]string{"dedicated"}, nil)
+	assert.NilError(t, err)
+	factors, err := parseAllocatableFactors([]string{"cpu=0.8"})
+	assert.NilError(t, err)
+	policies, err := parsePoolPolicies(pools, taintRules, factors)
+	assert.NilError(t, err)
+
+	s := &nodeSyncer{
+		labelFilter:        &labelFilter{denied: []string{"example.com/*"}},
+		taintRules:         taintRules,
+		allocatableFactors: factors,
+		nodePools:          pools,
+		poolPolicies:       policies,
+	}
+	hostTaints := []corev1.Taint{
+		{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "other", Effect: corev1.TaintEffectNoSchedule},
+	}
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+
+	gpuNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu", Labels: map[string]string{"example.com/gpu": "true", "kubernetes.io/os": "linux"}}}
+	assert.DeepEqual(t, s.translateLabels(gpuNode), map[string]string{"kubernetes.io/os": "linux", "tier": "premium", nodepools.Label: "gpu"})
+	assert.DeepEqual(t, taints.Translate(hostTaints, s.taintRulesFor(gpuNode)), []corev1.Taint{{Key: "other", Effect: corev1.TaintEffectNoSchedule}})
+	scaled := s.allocatableFactorsFor(gpuNode).scale(allocatable)
+	assert.Equal(t, scaled.Cpu().String(), "2")
+	assert.Equal(t, scaled.Memory().String(), "4Gi")
+
+	otherNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"kubernetes.io/os": "linux"}}}
+	assert.DeepEqual(t, s.translateLabels(otherNode), map[string]string{"kubernetes.io/os": "linux"})
+	assert.DeepEqual(t, taints.Translate(hostTaints, s.taintRulesFor(otherNode)), []corev1.Taint{hostTaints[0], hostTaints[2]})
+	scaled = s.allocatableFactorsFor(otherNode).scale(allocatable)
+	assert.Equal(t, scaled.Cpu().String(), "3200m")
+	assert.Equal(t, scaled.Memory().String(), "8Gi")
+}
@@ -0,0 +1,131 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"net"
+	"sync"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxPodCIDRSubnets limits how many podCIDRs can be carved out of the configured range
+const maxPodCIDRSubnets = 1 << 16
+
+// podCIDRAllocator assigns podCIDRs of a configured range to virtual nodes. The preferred subnet
+// of a node is derived from its name, so a node that is recreated usually gets the same podCIDR again.
+type podCIDRAllocator struct {
+	m sync.Mutex
+
+	clusterCIDR *net.IPNet
+	maskSize    int
+	subnets     int
+
+	// reserved are the podCIDRs that were allocated, but might not be visible in the cache yet
+	reserved map[string]string
+}
+
+func newPodCIDRAllocator(cidr string, maskSize int) (*podCIDRAllocator, error) {
+	if cidr == "" {
+		return nil, nil
+	}
+
+	_, clusterCIDR, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := clusterCIDR.Mask.Size()
+	if maskSize < ones || maskSize > bits {
+		return nil, fmt.Errorf("mask size %d has to be between %d and %d", maskSize, ones, bits)
+	} else if maskSize-ones > 16 {
+		return nil, fmt.Errorf("%s can't be split into more than %d podCIDRs", clusterCIDR.String(), maxPodCIDRSubnets)
+	}
+
+	return &podCIDRAllocator{
+		clusterCIDR: clusterCIDR,
+		maskSize:    maskSize,
+		subnets:     1 << (maskSize - ones),
+		reserved:    map[string]string{},
+	}, nil
+}
+
+// allocate returns the podCIDR of the virtual node, or a free podCIDR if the node has none yet
+func (a *podCIDRAllocator) allocate(ctx context.Context, virtualClient client.Client, nodeName string) (string, error) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	nodeList := &corev1.NodeList{}
+	err := virtualClient.List(ctx, nodeList)
+	if err != nil {
+		return "", err
+	}
+
+	used := map[string]bool{}
+	for _, node := range nodeList.Items {
+		if node.Spec.PodCIDR == "" {
+			continue
+		} else if node.Name == nodeName {
+			return node.Spec.PodCIDR, nil
+		}
+
+		used[node.Spec.PodCIDR] = true
+		delete(a.reserved, node.Name)
+	}
+	if cidr, ok := a.reserved[nodeName]; ok {
+		return cidr, nil
+	}
+	for _, cidr := range a.reserved {
+		used[cidr] = true
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(nodeName))
+	start := int(hash.Sum32() % uint32(a.subnets))
+	for i := 0; i < a.subnets; i++ {
+		cidr := a.subnet((start + i) % a.subnets)
+		if !used[cidr] {
+			a.reserved[nodeName] = cidr
+			return cidr, nil
+		}
+	}
+
+	return "", fmt.Errorf("no free podCIDR left in %s", a.clusterCIDR.String())
+}
+
+// subnet returns the podCIDR with the given index within the configured range
+func (a *podCIDRAllocator) subnet(index int) string {
+	ip := a.clusterCIDR.IP.To4()
+	if ip == nil {
+		ip = a.clusterCIDR.IP.To16()
+	}
+
+	_, bits := a.clusterCIDR.Mask.Size()
+	offset := new(big.Int).Lsh(big.NewInt(int64(index)), uint(bits-a.maskSize))
+	base := new(big.Int).SetBytes(ip)
+	subnetIP := new(big.Int).Add(base, offset).FillBytes(make([]byte, len(ip)))
+	return (&net.IPNet{IP: subnetIP, Mask: net.CIDRMask(a.maskSize, bits)}).String()
+}
+
+// assignPodCIDR assigns a podCIDR to the virtual node if it has none yet. The podCIDR of a node
+// is immutable, so nodes that already have one keep it.
+func assignPodCIDR(ctx *synccontext.SyncContext, allocator *podCIDRAllocator, vNode *corev1.Node) error {
+	if allocator == nil || vNode.Spec.PodCIDR != "" {
+		return nil
+	}
+
+	cidr, err := allocator.allocate(ctx.Context, ctx.VirtualClient, vNode.Name)
+	if err != nil {
+		return fmt.Errorf("allocate podCIDR: %w", err)
+	}
+
+	ctx.Log.Infof("assign podCIDR %s to virtual node %s", cidr, vNode.Name)
+	updated := vNode.DeepCopy()
+	updated.Spec.PodCIDR = cidr
+	updated.Spec.PodCIDRs = []string{cidr}
+	return ctx.VirtualClient.Patch(ctx.Context, updated, client.MergeFrom(vNode))
+}
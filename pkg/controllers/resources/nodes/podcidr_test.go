@@ -0,0 +1,107 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewPodCIDRAllocator(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cidr        string
+		maskSize    int
+		expectedErr string
+	}{
+		{
+			name:     "IPv4",
+			cidr:     "10.244.0.0/16",
+			maskSize: 24,
+		},
+		{
+			name:     "IPv6",
+			cidr:     "fd00:10:244::/56",
+			maskSize: 64,
+		},
+		{
+			name:        "Invalid cidr",
+			cidr:        "10.244.0.0",
+			maskSize:    24,
+			expectedErr: "invalid CIDR address",
+		},
+		{
+			name:        "Mask size too small",
+			cidr:        "10.244.0.0/16",
+			maskSize:    8,
+			expectedErr: "mask size 8 has to be between 16 and 32",
+		},
+		{
+			name:        "Too many subnets",
+			cidr:        "10.0.0.0/8",
+			maskSize:    28,
+			expectedErr: "can't be split into more than 65536 podCIDRs",
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err := newPodCIDRAllocator(testCase.cidr, testCase.maskSize)
+		if testCase.expectedErr != "" {
+			assert.ErrorContains(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+		} else {
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		}
+	}
+
+	allocator, err := newPodCIDRAllocator("", 24)
+	assert.NilError(t, err)
+	assert.Assert(t, allocator == nil)
+}
+
+func TestPodCIDRSubnet(t *testing.T) {
+	allocator, err := newPodCIDRAllocator("10.244.0.0/16", 24)
+	assert.NilError(t, err)
+	assert.Equal(t, allocator.subnets, 256)
+	assert.Equal(t, allocator.subnet(0), "10.244.0.0/24")
+	assert.Equal(t, allocator.subnet(255), "10.244.255.0/24")
+
+	allocator, err = newPodCIDRAllocator("fd00:10:244::/56", 64)
+	assert.NilError(t, err)
+	assert.Equal(t, allocator.subnet(1), "fd00:10:244:1::/64")
+}
+
+func TestPodCIDRAllocate(t *testing.T) {
+	ctx := context.Background()
+	allocator, err := newPodCIDRAllocator("10.244.0.0/30", 31)
+	assert.NilError(t, err)
+
+	existing := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing"},
+		Spec:       corev1.NodeSpec{PodCIDR: "10.244.0.0/31", PodCIDRs: []string{"10.244.0.0/31"}},
+	}
+	virtualClient := fake.NewClientBuilder().WithObjects(existing).Build()
+
+	cidr, err := allocator.allocate(ctx, virtualClient, existing.Name)
+	assert.NilError(t, err)
+	assert.Equal(t, cidr, "10.244.0.0/31", "existing podCIDR")
+
+	cidr, err = allocator.allocate(ctx, virtualClient, "node-1")
+	assert.NilError(t, err)
+	assert.Equal(t, cidr, "10.244.0.2/31", "free podCIDR")
+
+	cidr, err = allocator.allocate(ctx, virtualClient, "node-1")
+	assert.NilError(t, err)
+	assert.Equal(t, cidr, "10.244.0.2/31", "reserved podCIDR")
+
+	_, err = allocator.allocate(ctx, virtualClient, "node-2")
+	assert.ErrorContains(t, err, "no free podCIDR left in 10.244.0.0/30")
+
+	// deleting a node frees its podCIDR
+	assert.NilError(t, virtualClient.Delete(ctx, existing))
+	cidr, err = allocator.allocate(ctx, virtualClient, "node-2")
+	assert.NilError(t, err)
+	assert.Equal(t, cidr, "10.244.0.0/31")
+}
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -11,6 +12,10 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/nodepools"
+	"github.com/loft-sh/vcluster/pkg/util/nodeselector"
+	"github.com/loft-sh/vcluster/pkg/util/resourcenames"
+	"github.com/loft-sh/vcluster/pkg/util/taints"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
@@ -18,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -42,6 +48,50 @@ func NewSyncer(ctx *synccontext.RegisterContext, nodeServiceProvider nodeservice
 		}
 	}
 
+	// parse node selector groups
+	nodeSelectorGroups, err := nodeselector.ParseGroups(ctx.Options.NodeSelectorGroups)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node selector groups")
+	}
+	groupSelectors, err := nodeselector.Selectors(nodeSelectorGroups)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node selector groups")
+	}
+
+	// parse allocatable factors
+	allocatableFactors, err := parseAllocatableFactors(ctx.Options.NodeAllocatableFactors)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node allocatable factors")
+	}
+
+	// parse taint rules
+	taintRules, err := taints.ParseRules(ctx.Options.HideNodeTaints, ctx.Options.NodeTaintRewrites)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node taint rules")
+	}
+
+	// parse node pools
+	nodePools, err := nodepools.Parse(ctx.Options.NodePools)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node pools")
+	}
+	poolPolicies, err := parsePoolPolicies(nodePools, taintRules, allocatableFactors)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node pools")
+	}
+
+	// parse condition filter
+	conditionFilter, err := parseConditionFilter(ctx.Options.SyncNodeConditions, ctx.Options.HideNodeConditions, ctx.Options.NodeConditionRewrites)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node condition filter")
+	}
+
+	// parse pod cidr range
+	podCIDRs, err := newPodCIDRAllocator(ctx.Options.NodePodCIDR, ctx.Options.NodePodCIDRMaskSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node pod cidr")
+	}
+
 	// parse tolerations
 	var tolerations []*corev1.Toleration
 	if len(ctx.Options.Tolerations) > 0 {
@@ -56,16 +106,33 @@ func NewSyncer(ctx *synccontext.RegisterContext, nodeServiceProvider nodeservice
 	return &nodeSyncer{
 		enableScheduler: ctx.Options.EnableScheduler,
 
-		enforceNodeSelector: ctx.Options.EnforceNodeSelector,
+		// pods only prefer the selected nodes with a weight, so other nodes might be needed as well
+		enforceNodeSelector: ctx.Options.EnforceNodeSelector && ctx.Options.NodeSelectorWeight == 0,
 		nodeSelector:        nodeSelector,
+		groupSelectors:      groupSelectors,
 		clearImages:         ctx.Options.ClearNodeImages,
+		imagesLimit:         ctx.Options.NodeImagesLimit,
 		useFakeKubelets:     !ctx.Options.DisableFakeKubelets,
 		fakeKubeletIPs:      ctx.Options.FakeKubeletIPs,
 
+		hideExternalAddresses: ctx.Options.HideNodeExternalAddresses,
+
 		physicalClient:      ctx.PhysicalManager.GetClient(),
 		virtualClient:       ctx.VirtualManager.GetClient(),
 		nodeServiceProvider: nodeServiceProvider,
 		enforcedTolerations: tolerations,
+		taintRules:          taintRules,
+		labelFilter:         &labelFilter{allowed: ctx.Options.SyncNodeLabels, denied: ctx.Options.HideNodeLabels},
+		conditionFilter:     conditionFilter,
+		resourceNames:       ctx.ResourceNames,
+		allocatableFactors:  allocatableFactors,
+		nodePools:           nodePools,
+		poolPolicies:        poolPolicies,
+		podCIDRs:            podCIDRs,
+		deletionGracePeriod: ctx.Options.NodeDeletionGracePeriod,
+		interruption:        newInterruptionSignals(ctx.Options.NodeInterruptionTaints, ctx.Options.NodeInterruptionConditions),
+		evictOnInterruption: ctx.Options.EvictPodsOnNodeInterruption,
+		eventRecorder:       ctx.VirtualManager.GetEventRecorderFor("node-syncer"),
 	}, nil
 }
 
@@ -73,18 +140,34 @@ type nodeSyncer struct {
 	enableScheduler bool
 
 	clearImages bool
+	imagesLimit int
 
 	enforceNodeSelector bool
 	nodeSelector        labels.Selector
+	groupSelectors      []labels.Selector
 	useFakeKubelets     bool
 	fakeKubeletIPs      bool
 
+	hideExternalAddresses bool
+
 	physicalClient client.Client
 	virtualClient  client.Client
 
 	podCache            client.Reader
 	nodeServiceProvider nodeservice.NodeServiceProvider
 	enforcedTolerations []*corev1.Toleration
+	taintRules          []taints.Rule
+	labelFilter         *labelFilter
+	conditionFilter     *conditionFilter
+	resourceNames       *resourcenames.Mapping
+	allocatableFactors  *allocatableFactors
+	nodePools           nodepools.Pools
+	poolPolicies        map[string]*poolPolicy
+	podCIDRs            *podCIDRAllocator
+	deletionGracePeriod time.Duration
+	interruption        *interruptionSignals
+	evictOnInterruption bool
+	eventRecorder       record.EventRecorder
 }
 
 func (s *nodeSyncer) Resource() client.Object {
@@ -219,6 +302,17 @@ var _ syncer.Syncer = &nodeSyncer{}
 
 func (s *nodeSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
 	vNode := vObj.(*corev1.Node)
+
+	// host nodes might only be gone for a moment, e.g. during upgrades
+	if s.deletionGracePeriod > 0 && vNode.DeletionTimestamp == nil {
+		remaining, err := s.markHostNodeMissing(ctx, vNode)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
 	ctx.Log.Infof("delete virtual node %s, because it is not needed anymore", vNode.Name)
 	return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
 }
@@ -249,6 +343,11 @@ func (s *nodeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		return ctrl.Result{}, nil
 	}
 
+	// assign a podCIDR of the virtual range before the spec is synced
+	if s.podCIDRs != nil && vNode.Spec.PodCIDR == "" {
+		return ctrl.Result{}, assignPodCIDR(ctx, s.podCIDRs, vNode)
+	}
+
 	updated := s.translateUpdateBackwards(pNode, vNode)
 	if updated != nil {
 		ctx.Log.Infof("update virtual node %s, because spec has changed", pNode.Name)
@@ -257,6 +356,19 @@ func (s *nodeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+
+		// let the tenants know before their pods get evicted
+		if updated.Spec.Unschedulable && !vNode.Spec.Unschedulable {
+			err = s.recordUnschedulable(ctx, pNode, unschedulableReason(pNode, s.interruption))
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// evict the virtual pods before the host node is reclaimed
+	if s.evictOnInterruption && s.interruption.interrupted(pNode) {
+		return s.evictPods(ctx, pNode)
 	}
 
 	return ctrl.Result{}, nil
@@ -273,14 +385,25 @@ func (s *nodeSyncer) SyncUp(ctx *synccontext.SyncContext, pObj client.Object) (c
 		return ctrl.Result{}, nil
 	}
 
-	ctx.Log.Infof("create virtual node %s, because there is a virtual pod with that node", pNode.Name)
-	err = ctx.VirtualClient.Create(ctx.Context, &corev1.Node{
+	vNode := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        pNode.Name,
-			Labels:      pNode.Labels,
+			Labels:      s.translateLabels(pNode),
 			Annotations: pNode.Annotations,
 		},
-	})
+	}
+	if s.podCIDRs != nil {
+		podCIDR, err := s.podCIDRs.allocate(ctx.Context, ctx.VirtualClient, pNode.Name)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "allocate podCIDR")
+		}
+
+		vNode.Spec.PodCIDR = podCIDR
+		vNode.Spec.PodCIDRs = []string{podCIDR}
+	}
+
+	ctx.Log.Infof("create virtual node %s, because there is a virtual pod with that node", pNode.Name)
+	err = ctx.VirtualClient.Create(ctx.Context, vNode)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -290,13 +413,13 @@ func (s *nodeSyncer) SyncUp(ctx *synccontext.SyncContext, pObj client.Object) (c
 }
 
 func (s *nodeSyncer) shouldSync(ctx context.Context, pObj *corev1.Node) (bool, error) {
-	if s.nodeSelector != nil {
+	if s.nodeSelector != nil || len(s.groupSelectors) > 0 {
 		ls := labels.Set(pObj.Labels)
 		if ls == nil {
 			ls = labels.Set{}
 		}
 
-		matched := s.nodeSelector.Matches(ls)
+		matched := s.matchesNodeSelector(ls)
 		if !matched && !s.enforceNodeSelector {
 			return isNodeNeededByPod(ctx, s.virtualClient, s.physicalClient, pObj.Name)
 		}
@@ -306,6 +429,20 @@ func (s *nodeSyncer) shouldSync(ctx context.Context, pObj *corev1.Node) (bool, e
 	return isNodeNeededByPod(ctx, s.virtualClient, s.physicalClient, pObj.Name)
 }
 
+// matchesNodeSelector returns true if the node labels match the node selector or any of the node selector groups
+func (s *nodeSyncer) matchesNodeSelector(ls labels.Set) bool {
+	if s.nodeSelector != nil && s.nodeSelector.Matches(ls) {
+		return true
+	}
+	for _, selector := range s.groupSelectors {
+		if selector.Matches(ls) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func isNodeNeededByPod(ctx context.Context, virtualClient client.Client, physicalClient client.Client, nodeName string) (bool, error) {
 	// search virtual cache
 	podList := &corev1.PodList{}
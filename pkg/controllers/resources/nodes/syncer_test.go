@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -107,7 +108,110 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	imagesNode := baseNode.DeepCopy()
+	imagesNode.Status.Images = []corev1.ContainerImage{
+		{Names: []string{"large:latest"}, SizeBytes: 3000},
+		{Names: []string{"medium:latest"}, SizeBytes: 2000},
+		{Names: []string{"small:latest"}, SizeBytes: 1000},
+	}
+	limitedImagesVNode := baseVNode.DeepCopy()
+	limitedImagesVNode.Status.Images = imagesNode.Status.Images[:1]
+	annotatedNode := baseNode.DeepCopy()
+	annotatedNode.Annotations = map[string]string{SyncImagesAnnotation: "true"}
+	allImagesVNode := baseVNode.DeepCopy()
+	allImagesVNode.Annotations = annotatedNode.Annotations
+	allImagesVNode.Status.Images = imagesNode.Status.Images
+
+	missingSince := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	readyVNode := baseVNode.DeepCopy()
+	readyVNode.Status.Conditions = []corev1.NodeCondition{
+		{
+			Type:               corev1.NodeReady,
+			Status:             corev1.ConditionTrue,
+			LastHeartbeatTime:  missingSince,
+			LastTransitionTime: missingSince,
+		},
+	}
+	missingVNode := readyVNode.DeepCopy()
+	missingVNode.Spec.Unschedulable = true
+	missingVNode.Status.Conditions = append(missingVNode.Status.Conditions, corev1.NodeCondition{
+		Type:               ConditionHostNodeMissing,
+		Status:             corev1.ConditionTrue,
+		Reason:             ReasonHostNodeMissing,
+		LastHeartbeatTime:  missingSince,
+		LastTransitionTime: missingSince,
+	})
+	heartbeatVNode := missingVNode.DeepCopy()
+	heartbeatVNode.Status.Conditions[1].LastHeartbeatTime = metav1.NewTime(time.Now().Truncate(time.Second))
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                "Mark node of missing host node",
+			InitialVirtualState: []runtime.Object{readyVNode.DeepCopy()},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeDeletionGracePeriod = 5 * time.Minute
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				result, err := syncer.SyncDown(syncCtx, readyVNode.DeepCopy())
+				assert.NilError(t, err)
+				assert.Equal(t, result.RequeueAfter, missingHeartbeatInterval)
+
+				vNode := &corev1.Node{}
+				assert.NilError(t, syncCtx.VirtualClient.Get(syncCtx.Context, baseName, vNode))
+				assert.Assert(t, vNode.Spec.Unschedulable)
+				assert.Assert(t, IsHostNodeMissing(vNode))
+				ready := findNodeCondition(vNode.Status.Conditions, corev1.NodeReady)
+				assert.Assert(t, ready != nil)
+				assert.Equal(t, ready.Status, corev1.ConditionTrue)
+				assert.Assert(t, ready.LastHeartbeatTime.After(missingSince.Time))
+			},
+		},
+		{
+			Name:                "Renew heartbeat of node of missing host node within grace period",
+			InitialVirtualState: []runtime.Object{missingVNode.DeepCopy()},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeDeletionGracePeriod = 5 * time.Minute
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				result, err := syncer.SyncDown(syncCtx, missingVNode.DeepCopy())
+				assert.NilError(t, err)
+				assert.Equal(t, result.RequeueAfter, missingHeartbeatInterval)
+
+				vNode := &corev1.Node{}
+				assert.NilError(t, syncCtx.VirtualClient.Get(syncCtx.Context, baseName, vNode))
+				missing := findNodeCondition(vNode.Status.Conditions, ConditionHostNodeMissing)
+				assert.Assert(t, missing != nil)
+				assert.Assert(t, missing.LastTransitionTime.Equal(&missingSince))
+				assert.Assert(t, missing.LastHeartbeatTime.After(missingSince.Time))
+				ready := findNodeCondition(vNode.Status.Conditions, corev1.NodeReady)
+				assert.Assert(t, ready.LastHeartbeatTime.After(missingSince.Time))
+			},
+		},
+		{
+			Name:                "Keep node of missing host node with recent heartbeat",
+			InitialVirtualState: []runtime.Object{heartbeatVNode.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {heartbeatVNode.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeDeletionGracePeriod = 5 * time.Minute
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				result, err := syncer.SyncDown(syncCtx, heartbeatVNode.DeepCopy())
+				assert.NilError(t, err)
+				assert.Assert(t, result.RequeueAfter > 0 && result.RequeueAfter <= missingHeartbeatInterval)
+			},
+		},
+		{
+			Name:                "Delete node of missing host node after grace period",
+			InitialVirtualState: []runtime.Object{missingVNode.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeDeletionGracePeriod = 30 * time.Second
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.SyncDown(syncCtx, missingVNode.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                "Create backward",
 			InitialVirtualState: []runtime.Object{basePod},
@@ -134,6 +238,54 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Hide external addresses",
+			InitialVirtualState: []runtime.Object{basePod, baseNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {baseVNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.HideNodeExternalAddresses = true
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				pNode := baseNode.DeepCopy()
+				pNode.Status.Addresses = []corev1.NodeAddress{
+					{Address: "10.0.0.1", Type: corev1.NodeInternalIP},
+					{Address: "203.0.113.1", Type: corev1.NodeExternalIP},
+					{Address: "node.example.com", Type: corev1.NodeExternalDNS},
+				}
+				_, err := syncer.Sync(syncCtx, pNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Limit node images",
+			InitialVirtualState: []runtime.Object{basePod, baseNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {limitedImagesVNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeImagesLimit = 1
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, imagesNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Sync all node images of annotated node",
+			InitialVirtualState: []runtime.Object{basePod, annotatedNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {allImagesVNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.ClearNodeImages = true
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, imagesNode, annotatedNode)
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                "Update backward",
 			InitialVirtualState: []runtime.Object{basePod, baseNode},
@@ -237,6 +389,17 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	rewrittenNode := baseNode.DeepCopy()
+	rewrittenNode.Spec.Taints = []corev1.Taint{{Key: "example.com/key1", Value: "value1", Effect: corev1.TaintEffectPreferNoSchedule}}
+	drainingNode := baseNode.DeepCopy()
+	drainingNode.Spec.Taints = append(drainingNode.Spec.Taints, corev1.Taint{Key: "ToBeDeletedByClusterAutoscaler", Value: "1700000000", Effect: corev1.TaintEffectNoSchedule})
+	unschedulableNode := drainingNode.DeepCopy()
+	unschedulableNode.Spec.Unschedulable = true
+	interruptedNode := baseNode.DeepCopy()
+	interruptedNode.Spec.Taints = append(interruptedNode.Spec.Taints, corev1.Taint{Key: "aws-node-termination-handler/spot-itn", Value: "1700000000", Effect: corev1.TaintEffectNoSchedule})
+	interruptedVNode := interruptedNode.DeepCopy()
+	interruptedVNode.Spec.Unschedulable = true
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name:                 "Taint matching Enforced Toleration",
@@ -283,6 +446,65 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Hidden taint",
+			InitialPhysicalState: []runtime.Object{basePod, baseNode},
+			InitialVirtualState:  []runtime.Object{basePod, baseNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {editedNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.HideNodeTaints = []string{"key*:NoSchedule"}
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, baseNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Rewritten taint",
+			InitialPhysicalState: []runtime.Object{basePod, baseNode},
+			InitialVirtualState:  []runtime.Object{basePod, baseNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {rewrittenNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeTaintRewrites = []string{"key1=example.com/key1:PreferNoSchedule"}
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, baseNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Draining host node",
+			InitialPhysicalState: []runtime.Object{basePod, drainingNode},
+			InitialVirtualState:  []runtime.Object{basePod, baseNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {unschedulableNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {basePod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, drainingNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Evict pods of interrupted host node",
+			InitialPhysicalState: []runtime.Object{basePod, interruptedNode},
+			InitialVirtualState:  []runtime.Object{basePod, baseNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {interruptedVNode},
+				corev1.SchemeGroupVersion.WithKind("Pod"):  {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				syncer.evictOnInterruption = true
+				_, err := syncer.Sync(syncCtx, interruptedNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
 	})
 
 	baseName = types.NamespacedName{
@@ -423,6 +645,34 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Node selector group matched and enforceNodeSelector true - expect node to be synced",
+			InitialPhysicalState: []runtime.Object{baseNode},
+			InitialVirtualState:  []runtime.Object{baseVNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Node"): {editedNode},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeSelectorGroups = []string{"100:test=false", "10:test=true"}
+				ctx.Options.EnforceNodeSelector = true
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, baseNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Node selector group not matched and enforceNodeSelector true - expect node not to be synced",
+			InitialPhysicalState: []runtime.Object{basePod, baseNode},
+			InitialVirtualState:  []runtime.Object{baseVNode},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodeSelectorGroups = []string{"100:test=false"}
+				ctx.Options.EnforceNodeSelector = true
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, baseNode, baseNode)
+				assert.NilError(t, err)
+			},
+		},
 	})
 
 	baseName = types.NamespacedName{
@@ -0,0 +1,132 @@
+package nodes
+
+import (
+	"context"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const indexEndpointSliceByNode = "IndexEndpointSliceByNode"
+
+// endpointSliceNodes returns the nodes of the endpoints with a zone in the host EndpointSlice
+func endpointSliceNodes(rawObj client.Object) []string {
+	endpointSlice := rawObj.(*discoveryv1.EndpointSlice)
+	nodes := []string{}
+	for _, endpoint := range endpointSlice.Endpoints {
+		if endpoint.NodeName != nil && *endpoint.NodeName != "" && endpoint.Zone != nil && *endpoint.Zone != "" {
+			nodes = append(nodes, *endpoint.NodeName)
+		}
+	}
+
+	return nodes
+}
+
+func registerTopologyIndices(ctx *synccontext.RegisterContext) error {
+	return ctx.PhysicalManager.GetFieldIndexer().IndexField(ctx.Context, &discoveryv1.EndpointSlice{}, indexEndpointSliceByNode, endpointSliceNodes)
+}
+
+// modifyTopologyController requeues the fake nodes of the endpoints of a changed host EndpointSlice
+func modifyTopologyController(ctx *synccontext.RegisterContext, bld *builder.Builder) *builder.Builder {
+	return bld.WatchesRawSource(source.Kind(ctx.PhysicalManager.GetCache(), &discoveryv1.EndpointSlice{}), handler.EnqueueRequestsFromMapFunc(func(_ context.Context, object client.Object) []reconcile.Request {
+		requests := []reconcile.Request{}
+		for _, nodeName := range endpointSliceNodes(object) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: nodeName}})
+		}
+		return requests
+	}))
+}
+
+// hostNodeZone returns the zone of the host node with the given name. Fake nodes have no access
+// to the host nodes, however the host EndpointSlice controller records the zone of every endpoint,
+// so the zone is read from the EndpointSlices in the host cache instead, which are indexed by
+// node. If no endpoint on the node is found, an empty zone is returned.
+func hostNodeZone(ctx context.Context, physicalClient client.Client, nodeName string) (string, error) {
+	endpointSlices := &discoveryv1.EndpointSliceList{}
+	err := physicalClient.List(ctx, endpointSlices, client.MatchingFields{indexEndpointSliceByNode: nodeName})
+	if err != nil {
+		return "", err
+	}
+
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.NodeName != nil && *endpoint.NodeName == nodeName && endpoint.Zone != nil && *endpoint.Zone != "" {
+				return *endpoint.Zone, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// hostNodeTopology returns the topology labels of the host node with the given name. Fake nodes
+// usually have no access to the host nodes, if the host node can't be read only its zone is
+// returned, which is read from the host EndpointSlices.
+func (r *fakeNodeSyncer) hostNodeTopology(ctx *synccontext.SyncContext, nodeName string) (map[string]string, error) {
+	if r.hostNodeReader != nil && !r.hostNodesForbidden.Load() {
+		hostNode := &corev1.Node{}
+		err := r.hostNodeReader.Get(ctx.Context, types.NamespacedName{Name: nodeName}, hostNode)
+		if err == nil {
+			topology := map[string]string{}
+			for k, v := range hostNode.Labels {
+				if isTopologyLabel(k) {
+					topology[k] = v
+				}
+			}
+			return topology, nil
+		} else if kerrors.IsForbidden(err) {
+			ctx.Log.Debugf("host nodes are not readable, only the zone of fake nodes is synced")
+			r.hostNodesForbidden.Store(true)
+		} else if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	zone, err := hostNodeZone(ctx.Context, ctx.PhysicalClient, nodeName)
+	if err != nil {
+		return nil, err
+	} else if zone == "" {
+		return nil, nil
+	}
+
+	return map[string]string{corev1.LabelTopologyZone: zone}, nil
+}
+
+// syncTopology sets the topology labels of the host node, which are the zone, region and csi
+// topology labels, on the fake node. This makes sure the virtual EndpointSlice controller computes
+// the zones and hints of virtual services from the host zones and volume topology works inside the
+// vcluster. Known topology labels are kept if the host node currently has no endpoints.
+func (r *fakeNodeSyncer) syncTopology(ctx *synccontext.SyncContext, node *corev1.Node) error {
+	topology, err := r.hostNodeTopology(ctx, node.Name)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	patch := client.MergeFrom(node.DeepCopy())
+	for k, v := range topology {
+		if v == "" || node.Labels[k] == v {
+			continue
+		}
+
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[k] = v
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	ctx.Log.Infof("Update topology of fake node %s", node.Name)
+	return ctx.VirtualClient.Patch(ctx.Context, node, patch)
+}
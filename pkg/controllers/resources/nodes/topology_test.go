@@ -0,0 +1,99 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSyncTopology(t *testing.T) {
+	endpointSlice := func(nodeName, zone string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Namespace: "test", Name: "slice-" + nodeName + "-" + zone},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, NodeName: &nodeName, Zone: &zone},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name string
+
+		labels         map[string]string
+		endpointSlices []runtime.Object
+		hostNode       *corev1.Node
+
+		expectedLabels map[string]string
+	}{
+		{
+			name: "no endpoints",
+		},
+		{
+			name:           "zone of host node",
+			endpointSlices: []runtime.Object{endpointSlice("mynode", "zone-a"), endpointSlice("other", "zone-b")},
+			expectedLabels: map[string]string{corev1.LabelTopologyZone: "zone-a"},
+		},
+		{
+			name:           "changed zone",
+			labels:         map[string]string{corev1.LabelTopologyZone: "zone-b"},
+			endpointSlices: []runtime.Object{endpointSlice("mynode", "zone-a")},
+			expectedLabels: map[string]string{corev1.LabelTopologyZone: "zone-a"},
+		},
+		{
+			name:           "zone is kept without endpoints",
+			labels:         map[string]string{corev1.LabelTopologyZone: "zone-b"},
+			expectedLabels: map[string]string{corev1.LabelTopologyZone: "zone-b"},
+		},
+		{
+			name: "topology labels of readable host node",
+			hostNode: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "mynode", Labels: map[string]string{
+				corev1.LabelTopologyZone:           "zone-a",
+				corev1.LabelTopologyRegion:         "region-a",
+				"topology.ebs.csi.aws.com/zone":    "zone-a",
+				"node.kubernetes.io/instance-type": "m5.large",
+			}}},
+			endpointSlices: []runtime.Object{endpointSlice("mynode", "zone-b")},
+			expectedLabels: map[string]string{
+				corev1.LabelTopologyZone:        "zone-a",
+				corev1.LabelTopologyRegion:      "region-a",
+				"topology.ebs.csi.aws.com/zone": "zone-a",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "mynode", Labels: testCase.labels}}
+		physicalObjects := testCase.endpointSlices
+		if testCase.hostNode != nil {
+			physicalObjects = append(physicalObjects, testCase.hostNode)
+		}
+		physicalClient := testingutil.NewFakeClient(testingutil.NewScheme(), physicalObjects...)
+		err := physicalClient.IndexField(context.Background(), &discoveryv1.EndpointSlice{}, indexEndpointSliceByNode, endpointSliceNodes)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		ctx := &synccontext.SyncContext{
+			Context:        context.Background(),
+			Log:            loghelper.New("test"),
+			PhysicalClient: physicalClient,
+			VirtualClient:  testingutil.NewFakeClient(testingutil.NewScheme(), node.DeepCopy()),
+		}
+
+		err = (&fakeNodeSyncer{fakeNodeTopology: true, hostNodeReader: physicalClient}).syncTopology(ctx, node)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		vNode := &corev1.Node{}
+		err = ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Name: "mynode"}, vNode)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, vNode.Labels, testCase.expectedLabels)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/loft-sh/vcluster/pkg/util/stringutil"
+	"github.com/loft-sh/vcluster/pkg/util/taints"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -20,6 +21,10 @@ import (
 
 var (
 	TaintsAnnotation = "vcluster.loft.sh/original-taints"
+
+	// SyncImagesAnnotation on a virtual node syncs the full images list of the host node, even if
+	// images are cleared or limited
+	SyncImagesAnnotation = "vcluster.loft.sh/sync-node-images"
 )
 
 func (s *nodeSyncer) translateUpdateBackwards(pNode *corev1.Node, vNode *corev1.Node) *corev1.Node {
@@ -29,9 +34,13 @@ func (s *nodeSyncer) translateUpdateBackwards(pNode *corev1.Node, vNode *corev1.
 		annotations    map[string]string
 		labels         map[string]string
 		translatedSpec = pNode.Spec.DeepCopy()
+		pLabels        = s.translateLabels(pNode)
 	)
+
+	// hide and rewrite host taints first, so they are treated as if they were set on the host node
+	translatedSpec.Taints = taints.Translate(translatedSpec.Taints, s.taintRulesFor(pNode))
 	if s.enableScheduler {
-		labels, annotations = translate.ApplyMetadata(pNode.Annotations, vNode.Annotations, pNode.Labels, vNode.Labels, TaintsAnnotation)
+		labels, annotations = translate.ApplyMetadata(pNode.Annotations, vNode.Annotations, pLabels, vNode.Labels, TaintsAnnotation)
 
 		// merge taints together
 		oldPhysical := []string{}
@@ -44,7 +53,7 @@ func (s *nodeSyncer) translateUpdateBackwards(pNode *corev1.Node, vNode *corev1.
 
 		// convert physical taints
 		physical := []string{}
-		for _, p := range pNode.Spec.Taints {
+		for _, p := range translatedSpec.Taints {
 			out, err := json.Marshal(p)
 			if err != nil {
 				klog.Errorf("error encoding taint: %v", err)
@@ -89,7 +98,7 @@ func (s *nodeSyncer) translateUpdateBackwards(pNode *corev1.Node, vNode *corev1.
 			annotations[TaintsAnnotation] = string(out)
 		}
 	} else {
-		labels, annotations = translate.ApplyMetadata(pNode.Annotations, vNode.Annotations, pNode.Labels, vNode.Labels)
+		labels, annotations = translate.ApplyMetadata(pNode.Annotations, vNode.Annotations, pLabels, vNode.Labels)
 	}
 
 	// Omit those taints for which the vcluster has enforced tolerations defined
@@ -97,6 +106,18 @@ func (s *nodeSyncer) translateUpdateBackwards(pNode *corev1.Node, vNode *corev1.
 		translatedSpec.Taints = s.filterOutTaintsMatchingTolerations(translatedSpec.Taints)
 	}
 
+	// podCIDRs of the virtual range replace the ones of the host node
+	if s.podCIDRs != nil {
+		translatedSpec.PodCIDR = vNode.Spec.PodCIDR
+		translatedSpec.PodCIDRs = vNode.Spec.PodCIDRs
+	}
+
+	// node autoscalers often drain nodes without cordoning them first, and spot nodes are reclaimed
+	// without being cordoned at all
+	if isDraining(pNode) || s.interruption.interrupted(pNode) {
+		translatedSpec.Unschedulable = true
+	}
+
 	if !equality.Semantic.DeepEqual(vNode.Spec, *translatedSpec) {
 		updated = translator.NewIfNil(updated, vNode)
 		updated.Spec = *translatedSpec
@@ -124,6 +145,9 @@ func (s *nodeSyncer) translateUpdateBackwards(pNode *corev1.Node, vNode *corev1.
 func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *corev1.Node, vNode *corev1.Node) (*corev1.Node, error) {
 	// translate node status first
 	translatedStatus := pNode.Status.DeepCopy()
+	translatedStatus.Capacity = s.resourceNames.ToVirtual(translatedStatus.Capacity)
+	translatedStatus.Allocatable = s.allocatableFactorsFor(pNode).scale(s.resourceNames.ToVirtual(translatedStatus.Allocatable))
+	translatedStatus.Conditions = s.conditionFilter.filter(translatedStatus.Conditions)
 	if s.useFakeKubelets {
 		translatedStatus.DaemonEndpoints = corev1.NodeDaemonEndpoints{
 			KubeletEndpoint: corev1.DaemonEndpoint{
@@ -155,6 +179,8 @@ func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *
 		for _, oldAddress := range translatedStatus.Addresses {
 			if oldAddress.Type == corev1.NodeInternalIP || oldAddress.Type == corev1.NodeInternalDNS || oldAddress.Type == corev1.NodeHostName {
 				continue
+			} else if s.hideExternalAddresses && (oldAddress.Type == corev1.NodeExternalIP || oldAddress.Type == corev1.NodeExternalDNS) {
+				continue
 			}
 
 			newAddresses = append(newAddresses, oldAddress)
@@ -242,8 +268,14 @@ func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *
 		}
 	}
 
-	if s.clearImages {
-		translatedStatus.Images = make([]corev1.ContainerImage, 0)
+	// tenants can opt back in to the full images list per node, e.g. for image locality scoring
+	if vNode.Annotations[SyncImagesAnnotation] != "true" {
+		if s.clearImages {
+			translatedStatus.Images = make([]corev1.ContainerImage, 0)
+		} else if s.imagesLimit > 0 && len(translatedStatus.Images) > s.imagesLimit {
+			// the kubelet reports the largest images first
+			translatedStatus.Images = translatedStatus.Images[:s.imagesLimit]
+		}
 	}
 
 	// check if the status has changed
@@ -0,0 +1,32 @@
+package persistentvolumeclaims
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/util/namemapping"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StorageClassMapping maps virtual storage class names to host storage class names
+type StorageClassMapping map[string]string
+
+// ParseStorageClassMapping parses mappings in the form virtualClass=hostClass
+func ParseStorageClassMapping(mappings []string) (StorageClassMapping, error) {
+	out, err := namemapping.Parse(mappings, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage class mapping: %w", err)
+	}
+
+	return out, nil
+}
+
+// storageClassName returns the storage class of the pvc, either from the spec or the deprecated annotation
+func storageClassName(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		return *pvc.Spec.StorageClassName
+	} else if pvc.Annotations != nil && pvc.Annotations[deprecatedStorageClassAnnotation] != "" {
+		return pvc.Annotations[deprecatedStorageClassAnnotation]
+	}
+
+	return ""
+}
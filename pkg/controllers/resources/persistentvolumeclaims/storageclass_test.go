@@ -0,0 +1,54 @@
+package persistentvolumeclaims
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseStorageClassMapping(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mappings []string
+
+		expectedMapping StorageClassMapping
+		expectedErr     bool
+	}{
+		{
+			name:            "no mappings",
+			expectedMapping: StorageClassMapping{},
+		},
+		{
+			name:            "mappings",
+			mappings:        []string{"fast=ssd-provisioner-a", "standard=ssd-provisioner-a"},
+			expectedMapping: StorageClassMapping{"fast": "ssd-provisioner-a", "standard": "ssd-provisioner-a"},
+		},
+		{
+			name:        "missing host class",
+			mappings:    []string{"fast="},
+			expectedErr: true,
+		},
+		{
+			name:        "missing separator",
+			mappings:    []string{"fast"},
+			expectedErr: true,
+		},
+		{
+			name:        "ambiguous virtual class",
+			mappings:    []string{"fast=ssd", "fast=hdd"},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mapping, err := ParseStorageClassMapping(testCase.mappings)
+		if testCase.expectedErr {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, mapping, testCase.expectedMapping)
+	}
+}
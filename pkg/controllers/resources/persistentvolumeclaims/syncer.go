@@ -5,6 +5,7 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
@@ -35,12 +36,18 @@ const (
 )
 
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	storageClassMapping, err := ParseStorageClassMapping(ctx.Options.StorageClassMapping)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid value of the storage-class-mapping flag")
+	}
+
 	storageClassesEnabled := ctx.Controllers.Has("storageclasses")
 	excludedAnnotations := []string{bindCompletedAnnotation, boundByControllerAnnotation, storageProvisionerAnnotation}
 	return &persistentVolumeClaimSyncer{
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "persistent-volume-claim", &corev1.PersistentVolumeClaim{}, excludedAnnotations...),
 
 		storageClassesEnabled:    storageClassesEnabled,
+		storageClassMapping:      storageClassMapping,
 		schedulerEnabled:         ctx.Options.EnableScheduler,
 		useFakePersistentVolumes: !ctx.Controllers.Has("persistentvolumes"),
 	}, nil
@@ -50,6 +57,7 @@ type persistentVolumeClaimSyncer struct {
 	translator.NamespacedTranslator
 
 	storageClassesEnabled    bool
+	storageClassMapping      StorageClassMapping
 	schedulerEnabled         bool
 	useFakePersistentVolumes bool
 }
@@ -76,9 +84,17 @@ func (s *persistentVolumeClaimSyncer) SyncDown(ctx *synccontext.SyncContext, vOb
 		return ctrl.Result{}, err
 	}
 
+	// tell the tenant that the storage class is not one of the friendly names
+	if className := storageClassName(vPvc); len(s.storageClassMapping) > 0 && className != "" {
+		if _, ok := s.storageClassMapping[className]; !ok {
+			s.EventRecorder().Eventf(vPvc, corev1.EventTypeWarning, "StorageClassNotMapped", "storage class %s is not mapped to a host storage class, the storage class name is used as is", className)
+		}
+	}
+
 	newPvc, err := s.translate(ctx, vPvc)
 	if err != nil {
-		s.EventRecorder().Event(vPvc, "Warning", "SyncError", err.Error())
+		err = syncerrors.NewTranslationError(err)
+		s.EventRecorder().Event(vPvc, "Warning", syncerrors.Reason(err), err.Error())
 		return ctrl.Result{}, err
 	}
 
@@ -158,7 +174,7 @@ func (s *persistentVolumeClaimSyncer) Sync(ctx *synccontext.SyncContext, pObj cl
 	// forward update
 	newPvc, err := s.translateUpdate(ctx.Context, pPvc, vPvc)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, syncerrors.NewTranslationError(err)
 	} else if newPvc != nil {
 		translator.PrintChanges(pPvc, newPvc, ctx.Log)
 	}
@@ -129,6 +129,20 @@ func TestSync(t *testing.T) {
 		Spec:       backwardUpdateStatusPvc.Spec,
 		Status:     backwardUpdateStatusPvc.Status,
 	}
+	mappedStorageClassName := "fast"
+	mappedHostStorageClassName := "ssd-provisioner-a"
+	mappedStorageClassPvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &mappedStorageClassName,
+		},
+	}
+	mappedStorageClassCreatedPvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: pObjectMeta,
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &mappedHostStorageClassName,
+		},
+	}
 
 	generictesting.RunTestsWithContext(t, func(pClient *testingutil.FakeIndexClient, vClient *testingutil.FakeIndexClient) *synccontext.RegisterContext {
 		ctx := generictesting.NewFakeRegisterContext(pClient, vClient)
@@ -150,6 +164,22 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Create forward with mapped storage class",
+			InitialVirtualState: []runtime.Object{mappedStorageClassPvc},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): {mappedStorageClassPvc},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): {mappedStorageClassCreatedPvc},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.StorageClassMapping = []string{mappedStorageClassName + "=" + mappedHostStorageClassName}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*persistentVolumeClaimSyncer).SyncDown(syncCtx, mappedStorageClassPvc)
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Delete forward with create function",
 			InitialVirtualState:  []runtime.Object{basePvc},
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -42,15 +43,18 @@ func (s *persistentVolumeClaimSyncer) translate(ctx *synccontext.SyncContext, vP
 func (s *persistentVolumeClaimSyncer) translateSelector(ctx *synccontext.SyncContext, vPvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
 	vPvc = vPvc.DeepCopy()
 
-	storageClassName := ""
-	if vPvc.Spec.StorageClassName != nil && *vPvc.Spec.StorageClassName != "" {
-		storageClassName = *vPvc.Spec.StorageClassName
-	} else if vPvc.Annotations != nil && vPvc.Annotations[deprecatedStorageClassAnnotation] != "" {
-		storageClassName = vPvc.Annotations[deprecatedStorageClassAnnotation]
+	storageClassName := storageClassName(vPvc)
+
+	// mapped storage classes are used as they are
+	hostStorageClassName, mapped := s.storageClassMapping[storageClassName]
+	if mapped {
+		delete(vPvc.Annotations, deprecatedStorageClassAnnotation)
+		vPvc.Spec.StorageClassName = &hostStorageClassName
+		storageClassName = ""
 	}
 
 	// translate storage class if we manage those in vcluster
-	if s.storageClassesEnabled {
+	if s.storageClassesEnabled && !mapped {
 		if storageClassName == "" && vPvc.Spec.Selector == nil && vPvc.Spec.VolumeName == "" {
 			return nil, fmt.Errorf("no storage class defined for pvc %s/%s", vPvc.Namespace, vPvc.Name)
 		}
@@ -70,7 +74,12 @@ func (s *persistentVolumeClaimSyncer) translateSelector(ctx *synccontext.SyncCon
 				vPvc.Spec.Selector = translate.Default.TranslateLabelSelectorCluster(vPvc.Spec.Selector)
 			}
 			if vPvc.Spec.VolumeName != "" {
-				vPvc.Spec.VolumeName = translate.Default.PhysicalNameClusterScoped(vPvc.Spec.VolumeName)
+				volumeName, err := translateVolumeName(ctx, vPvc.Spec.VolumeName)
+				if err != nil {
+					return nil, err
+				}
+
+				vPvc.Spec.VolumeName = volumeName
 			}
 			// check if the storage class exists in the physical cluster
 			if !s.storageClassesEnabled && storageClassName != "" {
@@ -93,16 +102,32 @@ func (s *persistentVolumeClaimSyncer) translateSelector(ctx *synccontext.SyncCon
 	return vPvc, nil
 }
 
+// translateVolumeName returns the host persistent volume name. Persistent volumes that were synced up from the
+// host cluster keep their host name.
+func translateVolumeName(ctx *synccontext.SyncContext, vVolumeName string) (string, error) {
+	vPv := &corev1.PersistentVolume{}
+	err := ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Name: vVolumeName}, vPv)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return "", err
+	} else if err == nil && vPv.Annotations[persistentvolumes.HostClusterPersistentVolumeAnnotation] != "" {
+		return vPv.Annotations[persistentvolumes.HostClusterPersistentVolumeAnnotation], nil
+	}
+
+	return translate.Default.PhysicalNameClusterScoped(vVolumeName), nil
+}
+
 func (s *persistentVolumeClaimSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
 	var updated *corev1.PersistentVolumeClaim
 
-	// allow storage size to be increased
-	if pObj.Spec.Resources.Requests["storage"] != vObj.Spec.Resources.Requests["storage"] {
+	// allow storage size to be increased, the host persistent volume claim status is synced back to show the progress
+	if storageRequestChanged(pObj, vObj) {
+		pStorage, vStorage := pObj.Spec.Resources.Requests[corev1.ResourceStorage], vObj.Spec.Resources.Requests[corev1.ResourceStorage]
+		s.EventRecorder().Eventf(vObj, corev1.EventTypeNormal, "Resizing", "Resizing host persistent volume claim from %s to %s", pStorage.String(), vStorage.String())
 		updated = translator.NewIfNil(updated, pObj)
 		if updated.Spec.Resources.Requests == nil {
 			updated.Spec.Resources.Requests = make(map[corev1.ResourceName]resource.Quantity)
 		}
-		updated.Spec.Resources.Requests["storage"] = vObj.Spec.Resources.Requests["storage"]
+		updated.Spec.Resources.Requests[corev1.ResourceStorage] = vStorage
 	}
 
 	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
@@ -115,6 +140,13 @@ func (s *persistentVolumeClaimSyncer) translateUpdate(ctx context.Context, pObj,
 	return updated, nil
 }
 
+// storageRequestChanged compares the storage requests by value, as equal quantities can have different formats
+func storageRequestChanged(pObj, vObj *corev1.PersistentVolumeClaim) bool {
+	pStorage, pOk := pObj.Spec.Resources.Requests[corev1.ResourceStorage]
+	vStorage, vOk := vObj.Spec.Resources.Requests[corev1.ResourceStorage]
+	return pOk != vOk || pStorage.Cmp(vStorage) != 0
+}
+
 func (s *persistentVolumeClaimSyncer) translateUpdateBackwards(pObj, vObj *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
 	var updated *corev1.PersistentVolumeClaim
 
@@ -94,7 +94,7 @@ func (r *fakePersistentVolumeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj
 	if err != nil {
 		return ctrl.Result{}, err
 	} else if needed {
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.updateCapacity(ctx, persistentVolume)
 	}
 
 	ctx.Log.Infof("Delete fake persistent volume %s", vObj.GetName())
@@ -121,6 +121,28 @@ func (r *fakePersistentVolumeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj
 	return ctrl.Result{}, nil
 }
 
+// updateCapacity sets the capacity of the fake persistent volume to the capacity of its expanded claim
+func (r *fakePersistentVolumeSyncer) updateCapacity(ctx *synccontext.SyncContext, persistentVolume *corev1.PersistentVolume) error {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	err := ctx.VirtualClient.List(ctx.Context, pvcList, client.MatchingFields{constants.IndexByAssigned: persistentVolume.Name})
+	if err != nil || len(pvcList.Items) == 0 {
+		return err
+	}
+
+	capacity, ok := pvcList.Items[0].Status.Capacity[corev1.ResourceStorage]
+	if !ok || capacity.Cmp(persistentVolume.Spec.Capacity[corev1.ResourceStorage]) == 0 {
+		return nil
+	}
+
+	ctx.Log.Infof("update capacity of fake persistent volume %s to %s", persistentVolume.Name, capacity.String())
+	orig := persistentVolume.DeepCopy()
+	if persistentVolume.Spec.Capacity == nil {
+		persistentVolume.Spec.Capacity = corev1.ResourceList{}
+	}
+	persistentVolume.Spec.Capacity[corev1.ResourceStorage] = capacity
+	return ctx.VirtualClient.Patch(ctx.Context, persistentVolume, client.MergeFrom(orig))
+}
+
 func (r *fakePersistentVolumeSyncer) pvNeeded(ctx *synccontext.SyncContext, pvName string) (bool, error) {
 	pvcList := &corev1.PersistentVolumeClaimList{}
 	err := ctx.VirtualClient.List(ctx.Context, pvcList, client.MatchingFields{constants.IndexByAssigned: pvName})
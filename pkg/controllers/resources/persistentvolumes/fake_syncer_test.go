@@ -10,6 +10,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -81,6 +82,10 @@ func TestFakeSync(t *testing.T) {
 	}
 	pvWithFinalizers := basePv.DeepCopy()
 	pvWithFinalizers.Finalizers = []string{"myfinalizer"}
+	expandedPvc := basePvc.DeepCopy()
+	expandedPvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+	expandedPv := basePv.DeepCopy()
+	expandedPv.Spec.Capacity = expandedPvc.Status.Capacity
 
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
@@ -122,6 +127,19 @@ func TestFakeSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Update capacity of expanded claim",
+			InitialVirtualState: []runtime.Object{basePv.DeepCopy(), expandedPvc.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolume"):      {expandedPv},
+				corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): {expandedPvc},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeFakeSyncer(t, ctx)
+				_, err := syncer.FakeSync(syncContext, basePv.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                "Delete not existent pv",
 			InitialVirtualState: []runtime.Object{},
@@ -34,6 +34,7 @@ func NewSyncer(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		Translator: translator.NewClusterTranslator(ctx, "persistentvolume", &corev1.PersistentVolume{}, NewPersistentVolumeTranslator(), HostClusterPersistentVolumeAnnotation),
 
 		virtualClient: ctx.VirtualManager.GetClient(),
+		syncUpOnly:    ctx.Options.SyncPersistentVolumesUpOnly,
 	}, nil
 }
 
@@ -66,6 +67,9 @@ type persistentVolumeSyncer struct {
 	translator.Translator
 
 	virtualClient client.Client
+
+	// syncUpOnly is true if only host persistent volumes are synced into the virtual cluster
+	syncUpOnly bool
 }
 
 var _ syncer.IndicesRegisterer = &persistentVolumeSyncer{}
@@ -98,6 +102,11 @@ func (s *persistentVolumeSyncer) SyncDown(ctx *synccontext.SyncContext, vObj cli
 		return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vPv)
 	}
 
+	if s.syncUpOnly {
+		ctx.Log.Debugf("skip sync of virtual persistent volume %s, because only host persistent volumes are synced", vPv.Name)
+		return ctrl.Result{}, nil
+	}
+
 	pPv := s.translate(ctx.Context, vPv)
 	ctx.Log.Infof("create physical persistent volume %s, because there is a virtual persistent volume", pPv.Name)
 	err := ctx.PhysicalClient.Create(ctx.Context, pPv)
@@ -149,6 +158,16 @@ func (s *persistentVolumeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.
 		return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
 	}
 
+	// check if the virtual persistent volume was bound to another claim
+	rebindPv, err := s.translateRebind(ctx, vPersistentVolume, pPersistentVolume)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if rebindPv != nil {
+		ctx.Log.Infof("rebind physical persistent volume %s to claim %s/%s", rebindPv.Name, rebindPv.Spec.ClaimRef.Namespace, rebindPv.Spec.ClaimRef.Name)
+		translator.PrintChanges(pPersistentVolume, rebindPv, ctx.Log)
+		return ctrl.Result{}, ctx.PhysicalClient.Update(ctx.Context, rebindPv)
+	}
+
 	// check if there is a corresponding virtual pvc
 	updatedObj := s.translateUpdateBackwards(vPersistentVolume, pPersistentVolume, vPvc)
 	if updatedObj != nil {
@@ -179,7 +198,7 @@ func (s *persistentVolumeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.
 	}
 
 	// update the physical persistent volume if the virtual has changed
-	if vPersistentVolume.Annotations == nil || vPersistentVolume.Annotations[HostClusterPersistentVolumeAnnotation] == "" {
+	if !s.syncUpOnly && (vPersistentVolume.Annotations == nil || vPersistentVolume.Annotations[HostClusterPersistentVolumeAnnotation] == "") {
 		if vPersistentVolume.DeletionTimestamp != nil {
 			if pPersistentVolume.DeletionTimestamp != nil {
 				return ctrl.Result{}, nil
@@ -194,6 +194,31 @@ func TestSync(t *testing.T) {
 			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
 		},
 	}
+	rebindVPv := &corev1.PersistentVolume{
+		ObjectMeta: basePvObjectMeta,
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			ClaimRef: &corev1.ObjectReference{
+				Name:      basePvc.Name,
+				Namespace: basePvc.Namespace,
+			},
+		},
+		Status: corev1.PersistentVolumeStatus{
+			Phase: corev1.VolumeReleased,
+		},
+	}
+	reboundPPv := backwardRetainPPv.DeepCopy()
+	reboundPPv.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Name:       basePPvcReference.Name,
+		Namespace:  basePPvcReference.Namespace,
+	}
+	virtualOnlyVPv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "virtualpv",
+		},
+	}
 
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
@@ -400,6 +425,39 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Rebind released PV to another claim",
+			InitialVirtualState:  []runtime.Object{basePvc, rebindVPv},
+			InitialPhysicalState: []runtime.Object{backwardRetainPPv},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolume"):      {rebindVPv},
+				corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): {basePvc},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolume"): {reboundPPv},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncContext, backwardRetainPPv.DeepCopy(), rebindVPv.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Don't create physical PV when only syncing up",
+			InitialVirtualState: []runtime.Object{virtualOnlyVPv},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolume"): {virtualOnlyVPv},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("PersistentVolume"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.SyncPersistentVolumesUpOnly = true
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.SyncDown(syncContext, virtualOnlyVPv.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Delete PV when reclaim policy is Delete",
 			InitialVirtualState:  []runtime.Object{backwardDeleteVPv},
@@ -3,10 +3,13 @@ package persistentvolumes
 import (
 	"context"
 
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func (s *persistentVolumeSyncer) translate(ctx context.Context, vPv *corev1.PersistentVolume) *corev1.PersistentVolume {
@@ -99,6 +102,47 @@ func (s *persistentVolumeSyncer) translateUpdateBackwards(vPv *corev1.Persistent
 	return updated
 }
 
+// translateRebind rewrites the claim ref of a released or available host persistent volume, if the claim ref
+// of its virtual persistent volume was changed to another virtual persistent volume claim
+func (s *persistentVolumeSyncer) translateRebind(ctx *synccontext.SyncContext, vPv *corev1.PersistentVolume, pPv *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	if vPv.Annotations == nil || vPv.Annotations[HostClusterPersistentVolumeAnnotation] == "" || vPv.Spec.ClaimRef == nil {
+		return nil, nil
+	} else if pPv.Status.Phase != corev1.VolumeReleased && pPv.Status.Phase != corev1.VolumeAvailable {
+		return nil, nil
+	}
+
+	vPvc := &corev1.PersistentVolumeClaim{}
+	err := ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Namespace: vPv.Spec.ClaimRef.Namespace, Name: vPv.Spec.ClaimRef.Name}, vPvc)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	// a claim ref with the uid of a deleted claim is not a rebind
+	if vPv.Spec.ClaimRef.UID != "" && vPv.Spec.ClaimRef.UID != vPvc.UID {
+		return nil, nil
+	}
+
+	pPvcName := translate.Default.PhysicalName(vPvc.Name, vPvc.Namespace)
+	pPvcNamespace := translate.Default.PhysicalNamespace(vPvc.Namespace)
+	if pPv.Spec.ClaimRef != nil && pPv.Spec.ClaimRef.Name == pPvcName && pPv.Spec.ClaimRef.Namespace == pPvcNamespace && pPv.Spec.ClaimRef.UID == "" {
+		return nil, nil
+	}
+
+	// the host persistent volume controller binds the volume as soon as the claim exists
+	updated := pPv.DeepCopy()
+	updated.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Namespace:  pPvcNamespace,
+		Name:       pPvcName,
+	}
+	return updated, nil
+}
+
 func (s *persistentVolumeSyncer) translateUpdate(ctx context.Context, vPv *corev1.PersistentVolume, pPv *corev1.PersistentVolume) *corev1.PersistentVolume {
 	var updated *corev1.PersistentVolume
 
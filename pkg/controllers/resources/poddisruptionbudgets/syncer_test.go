@@ -74,10 +74,30 @@ func TestSync(t *testing.T) {
 		ObjectMeta: hostClusterSyncedPDB.ObjectMeta,
 		Spec: policyv1.PodDisruptionBudgetSpec{
 			MaxUnavailable: vclusterUpdatedSelectorPDB.Spec.MaxUnavailable,
-			Selector:       translate.Default.TranslateLabelSelector(vclusterUpdatedSelectorPDB.Spec.Selector),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					translate.Default.ConvertLabelKey("app"): "nginx",
+					translate.NamespaceLabel:                 vObjectMeta.Namespace,
+					translate.MarkerLabel:                    translate.Suffix,
+				},
+			},
+		},
+	}
+
+	unhealthyPodEvictionPolicy := policyv1.AlwaysAllow
+	vclusterUpdatedEvictionPolicyPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: vclusterPDB.ObjectMeta,
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:               vclusterPDB.Spec.MinAvailable,
+			UnhealthyPodEvictionPolicy: &unhealthyPodEvictionPolicy,
 		},
 	}
 
+	hostClusterSyncedUpdatedEvictionPolicyPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: hostClusterSyncedPDB.ObjectMeta,
+		Spec:       vclusterUpdatedEvictionPolicyPDB.Spec,
+	}
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name: "Create Host Cluster PodDisruptionBudget",
@@ -136,5 +156,25 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Update Host Cluster PodDisruptionBudget's UnhealthyPodEvictionPolicy",
+			InitialVirtualState: []runtime.Object{
+				vclusterUpdatedEvictionPolicyPDB.DeepCopy(),
+			},
+			InitialPhysicalState: []runtime.Object{
+				hostClusterSyncedPDB.DeepCopy(),
+			},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"): {vclusterUpdatedEvictionPolicyPDB.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"): {hostClusterSyncedUpdatedEvictionPolicyPDB.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*pdbSyncer).Sync(syncCtx, hostClusterSyncedPDB, vclusterUpdatedEvictionPolicyPDB)
+				assert.NilError(t, err)
+			},
+		},
 	})
 }
@@ -7,13 +7,12 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (pdb *pdbSyncer) translate(ctx context.Context, vObj *policyv1.PodDisruptionBudget) *policyv1.PodDisruptionBudget {
 	newPDB := pdb.TranslateMetadata(ctx, vObj).(*policyv1.PodDisruptionBudget)
-	if newPDB.Spec.Selector != nil {
-		newPDB.Spec.Selector = translate.Default.TranslateLabelSelector(newPDB.Spec.Selector)
-	}
+	newPDB.Spec.Selector = translateSelector(vObj.Spec.Selector, vObj.Namespace)
 	return newPDB
 }
 
@@ -28,6 +27,12 @@ func (pdb *pdbSyncer) translateUpdate(ctx context.Context, pObj, vObj *policyv1.
 		updated.Spec.MinAvailable = vObj.Spec.MinAvailable
 	}
 
+	// check unhealthy pod eviction policy
+	if !equality.Semantic.DeepEqual(vObj.Spec.UnhealthyPodEvictionPolicy, pObj.Spec.UnhealthyPodEvictionPolicy) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Spec.UnhealthyPodEvictionPolicy = vObj.Spec.UnhealthyPodEvictionPolicy
+	}
+
 	// check annotations
 	changed, updatedAnnotations, updatedLabels := pdb.TranslateMetadataUpdate(ctx, vObj, pObj)
 	if changed {
@@ -37,7 +42,7 @@ func (pdb *pdbSyncer) translateUpdate(ctx context.Context, pObj, vObj *policyv1.
 	}
 
 	// check LabelSelector
-	vObjLabelSelector := translate.Default.TranslateLabelSelector(vObj.Spec.Selector)
+	vObjLabelSelector := translateSelector(vObj.Spec.Selector, vObj.Namespace)
 	if !equality.Semantic.DeepEqual(vObjLabelSelector, pObj.Spec.Selector) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Spec.Selector = vObjLabelSelector
@@ -45,3 +50,21 @@ func (pdb *pdbSyncer) translateUpdate(ctx context.Context, pObj, vObj *policyv1.
 
 	return updated
 }
+
+// translateSelector translates the pod selector of a virtual pod disruption budget. In the single
+// namespace mode pods of all virtual namespaces share a host namespace, so the selector is scoped
+// to the virtual namespace and to the pods of this vcluster instance.
+func translateSelector(selector *metav1.LabelSelector, namespace string) *metav1.LabelSelector {
+	// a nil selector selects no pods at all
+	if selector == nil || !translate.Default.SingleNamespaceTarget() {
+		return selector.DeepCopy()
+	}
+
+	newSelector := translate.Default.TranslateLabelSelector(selector)
+	if newSelector.MatchLabels == nil {
+		newSelector.MatchLabels = map[string]string{}
+	}
+	newSelector.MatchLabels[translate.NamespaceLabel] = namespace
+	newSelector.MatchLabels[translate.MarkerLabel] = translate.Suffix
+	return newSelector
+}
@@ -0,0 +1,187 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/scheduler"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+)
+
+const (
+	// PodConditionHostCapacity is set on virtual pods that are held back until a host node has free capacity for them
+	PodConditionHostCapacity corev1.PodConditionType = "vcluster.loft.sh/HostCapacity"
+
+	// ReasonInsufficientHostCapacity is the reason of the host capacity condition and event
+	ReasonInsufficientHostCapacity = "InsufficientHostCapacity"
+)
+
+// hasHostCapacity returns true if at least one host node the pod could be placed on has enough
+// free capacity for the requests of the pod. Otherwise, a message that explains why is returned.
+func (s *podSyncer) hasHostCapacity(ctx context.Context, pPod *corev1.Pod) (bool, string, error) {
+	nodes := []corev1.Node{}
+	if pPod.Spec.NodeName != "" {
+		pNode := corev1.Node{}
+		err := s.hostCache.Get(ctx, types.NamespacedName{Name: pPod.Spec.NodeName}, &pNode)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				// fake nodes have no host node, so we leave it to the host cluster
+				return true, "", nil
+			}
+			return false, "", err
+		}
+
+		nodes = append(nodes, pNode)
+	} else {
+		nodeList := &corev1.NodeList{}
+		err := s.hostCache.List(ctx, nodeList)
+		if err != nil {
+			return false, "", err
+		}
+
+		nodes = nodeList.Items
+	}
+
+	podRequests, _ := resourcehelper.PodRequestsAndLimits(pPod)
+	podRequests[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+
+	candidates := 0
+	for i := range nodes {
+		if !isCandidateNode(&nodes[i], pPod) {
+			continue
+		}
+		candidates++
+
+		requested, err := scheduler.RequestedOnNode(ctx, s.hostCache, nodes[i].Name)
+		if err != nil {
+			return false, "", err
+		}
+		if scheduler.Fits(nodes[i].Status.Allocatable, requested, podRequests) {
+			return true, "", nil
+		}
+	}
+
+	if candidates == 0 {
+		return false, "No ready host node matches the node selector, affinity and tolerations of the pod", nil
+	}
+
+	return false, fmt.Sprintf("None of the %d matching host nodes has enough free capacity for the requests of the pod", candidates), nil
+}
+
+// setHostCapacityCondition sets the host capacity condition on the virtual pod, so tenants can see why
+// the pod stays pending. Returns true if the condition has changed.
+func setHostCapacityCondition(ctx *synccontext.SyncContext, vPod *corev1.Pod, message string) (bool, error) {
+	for _, condition := range vPod.Status.Conditions {
+		if condition.Type == PodConditionHostCapacity && condition.Status == corev1.ConditionFalse && condition.Message == message {
+			return false, nil
+		}
+	}
+
+	newPod := vPod.DeepCopy()
+	newCondition := corev1.PodCondition{
+		Type:               PodConditionHostCapacity,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInsufficientHostCapacity,
+		Message:            message,
+	}
+	found := false
+	for i := range newPod.Status.Conditions {
+		if newPod.Status.Conditions[i].Type == PodConditionHostCapacity {
+			newPod.Status.Conditions[i] = newCondition
+			found = true
+		}
+	}
+	if !found {
+		newPod.Status.Conditions = append(newPod.Status.Conditions, newCondition)
+	}
+	if newPod.Status.Phase == "" {
+		newPod.Status.Phase = corev1.PodPending
+	}
+
+	ctx.Log.Infof("hold back pod %s/%s, because there is no free capacity in the host cluster: %s", vPod.Namespace, vPod.Name, message)
+	return true, ctx.VirtualClient.Status().Update(ctx.Context, newPod)
+}
+
+// isCandidateNode returns true if the host node is ready and the pod could be scheduled on it
+// regarding node selector, required node affinity and taints
+func isCandidateNode(pNode *corev1.Node, pPod *corev1.Pod) bool {
+	if pNode.Spec.Unschedulable || !isNodeReady(pNode) {
+		return false
+	} else if !labels.SelectorFromSet(pPod.Spec.NodeSelector).Matches(labels.Set(pNode.Labels)) {
+		return false
+	} else if pPod.Spec.Affinity != nil && pPod.Spec.Affinity.NodeAffinity != nil && pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		if !matchesNodeSelectorTerms(pNode, pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+			return false
+		}
+	}
+
+	for i := range pNode.Spec.Taints {
+		if pNode.Spec.Taints[i].Effect != corev1.TaintEffectPreferNoSchedule && !toleratesTaint(pPod.Spec.Tolerations, &pNode.Spec.Taints[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesNodeSelectorTerms returns true if the node matches any of the terms
+func matchesNodeSelectorTerms(pNode *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if matchesNodeSelectorTerm(pNode, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesNodeSelectorTerm(pNode *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+
+	for _, expression := range term.MatchExpressions {
+		if !matchesNodeSelectorRequirement(labels.Set(pNode.Labels), expression) {
+			return false
+		}
+	}
+	for _, field := range term.MatchFields {
+		if field.Key != nodeNameField || !matchesNodeSelectorRequirement(labels.Set{nodeNameField: pNode.Name}, field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func matchesNodeSelectorRequirement(set labels.Set, requirement corev1.NodeSelectorRequirement) bool {
+	operator, ok := nodeSelectorOperators[requirement.Operator]
+	if !ok {
+		return false
+	}
+
+	r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+	if err != nil {
+		return false
+	}
+
+	return r.Matches(set)
+}
@@ -0,0 +1,118 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/scheduler"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHasHostCapacity(t *testing.T) {
+	newNode := func(name string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	newPod := func(name, nodeName, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{
+					{
+						Name: "test",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+						},
+					},
+				},
+			},
+		}
+	}
+	withNodeSelector := func(pod *corev1.Pod, nodeSelector map[string]string) *corev1.Pod {
+		pod.Spec.NodeSelector = nodeSelector
+		return pod
+	}
+	withAffinity := func(pod *corev1.Pod, requirement corev1.NodeSelectorRequirement) *corev1.Pod {
+		pod.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}}},
+				},
+			},
+		}
+		return pod
+	}
+	dedicatedTaint := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+
+	testCases := []struct {
+		name            string
+		hostObjects     []client.Object
+		pod             *corev1.Pod
+		expected        bool
+		expectedMessage string
+	}{
+		{
+			name:        "Free node",
+			hostObjects: []client.Object{newNode("node-1", nil), newPod("other", "node-1", "1")},
+			pod:         newPod("test", "", "1"),
+			expected:    true,
+		},
+		{
+			name:            "Full node",
+			hostObjects:     []client.Object{newNode("node-1", nil), newPod("other", "node-1", "1500m")},
+			pod:             newPod("test", "", "1"),
+			expectedMessage: "None of the 1 matching host nodes has enough free capacity for the requests of the pod",
+		},
+		{
+			name:            "Tainted node",
+			hostObjects:     []client.Object{newNode("node-1", nil, dedicatedTaint)},
+			pod:             newPod("test", "", "1"),
+			expectedMessage: "No ready host node matches the node selector, affinity and tolerations of the pod",
+		},
+		{
+			name:            "Node selector",
+			hostObjects:     []client.Object{newNode("node-1", map[string]string{"pool": "a"}), newNode("node-2", map[string]string{"pool": "b"}), newPod("other", "node-2", "2")},
+			pod:             withNodeSelector(newPod("test", "", "1"), map[string]string{"pool": "b"}),
+			expectedMessage: "None of the 1 matching host nodes has enough free capacity for the requests of the pod",
+		},
+		{
+			name:        "Node affinity",
+			hostObjects: []client.Object{newNode("node-1", map[string]string{"pool": "a"}), newNode("node-2", map[string]string{"pool": "b"}), newPod("other", "node-2", "2")},
+			pod:         withAffinity(newPod("test", "", "1"), corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"a", "b"}}),
+			expected:    true,
+		},
+		{
+			name:        "Fake node",
+			hostObjects: []client.Object{newNode("node-1", nil), newPod("other", "node-1", "2")},
+			pod:         newPod("test", "fake-node", "1"),
+			expected:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		s := &podSyncer{
+			hostCache: fake.NewClientBuilder().WithObjects(testCase.hostObjects...).WithIndex(&corev1.Pod{}, scheduler.IndexPodByNode, scheduler.IndexPod).Build(),
+		}
+
+		hasCapacity, message, err := s.hasHostCapacity(context.TODO(), testCase.pod)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, hasCapacity, testCase.expected, "unexpected result in test case %s", testCase.name)
+		assert.Equal(t, message, testCase.expectedMessage, "unexpected message in test case %s", testCase.name)
+	}
+}
@@ -0,0 +1,119 @@
+package pods
+
+import (
+	"fmt"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// nodeNameField is the field the daemon set controller uses in the node affinity of its pods
+const nodeNameField = "metadata.name"
+
+// bindDaemonSetPod binds the pod of a virtual daemon set directly to the host node it was created
+// for, so the host scheduler can't leave it pending or place it elsewhere. As the host scheduler is
+// bypassed, the pod also tolerates the NoExecute taints of the host node that are not visible on the
+// virtual node. Returns false if the node is not available yet.
+func (s *podSyncer) bindDaemonSetPod(ctx *synccontext.SyncContext, vPod, pPod *corev1.Pod) (bool, error) {
+	nodeName, ok := daemonSetNodeName(vPod)
+	if !ok {
+		return true, nil
+	}
+
+	vNode := &corev1.Node{}
+	err := ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Name: nodeName}, vNode)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, err
+		}
+
+		err = syncerrors.NewTranslationError(fmt.Errorf("node %s of daemon set pod does not exist in virtual cluster", nodeName))
+		s.EventRecorder().Event(vPod, "Warning", syncerrors.Reason(err), err.Error())
+		return false, nil
+	}
+
+	pNode := &corev1.Node{}
+	err = ctx.PhysicalClient.Get(ctx.Context, types.NamespacedName{Name: nodeName}, pNode)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, err
+		}
+
+		err = syncerrors.NewTranslationError(fmt.Errorf("node %s of daemon set pod does not exist in host cluster", nodeName))
+		s.EventRecorder().Event(vPod, "Warning", syncerrors.Reason(err), err.Error())
+		return false, nil
+	}
+
+	pPod.Spec.NodeName = nodeName
+	pPod.Spec.Tolerations = append(pPod.Spec.Tolerations, hiddenTaintTolerations(pNode.Spec.Taints, vNode.Spec.Taints, pPod.Spec.Tolerations)...)
+	return true, nil
+}
+
+// daemonSetNodeName returns the node the pod of a daemon set was created for
+func daemonSetNodeName(pod *corev1.Pod) (string, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.APIVersion != appsv1.SchemeGroupVersion.String() || owner.Kind != "DaemonSet" {
+		return "", false
+	} else if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName, true
+	} else if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil || pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return "", false
+	}
+
+	// the daemon set controller replaces the node affinity with a single term that
+	// selects the node by name
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == nodeNameField && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// hiddenTaintTolerations returns tolerations for the NoExecute taints of the host node that are
+// neither visible on the virtual node nor tolerated already
+func hiddenTaintTolerations(pTaints, vTaints []corev1.Taint, tolerations []corev1.Toleration) []corev1.Toleration {
+	newTolerations := []corev1.Toleration{}
+	for i := range pTaints {
+		if pTaints[i].Effect != corev1.TaintEffectNoExecute || hasTaint(vTaints, &pTaints[i]) || toleratesTaint(tolerations, &pTaints[i]) {
+			continue
+		}
+
+		newTolerations = append(newTolerations, corev1.Toleration{
+			Key:      pTaints[i].Key,
+			Operator: corev1.TolerationOpEqual,
+			Value:    pTaints[i].Value,
+			Effect:   pTaints[i].Effect,
+		})
+	}
+
+	return newTolerations
+}
+
+func hasTaint(taints []corev1.Taint, taint *corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(taint) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+
+	return false
+}
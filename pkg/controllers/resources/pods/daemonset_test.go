@@ -0,0 +1,81 @@
+package pods
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDaemonSetNodeName(t *testing.T) {
+	newPod := func(kind string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "test"}}, appsv1.SchemeGroupVersion.WithKind(kind)),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchFields: []corev1.NodeSelectorRequirement{
+										{
+											Key:      nodeNameField,
+											Operator: corev1.NodeSelectorOpIn,
+											Values:   []string{"node-1"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	nodeName, ok := daemonSetNodeName(newPod("DaemonSet"))
+	assert.Assert(t, ok)
+	assert.Equal(t, nodeName, "node-1")
+
+	_, ok = daemonSetNodeName(newPod("ReplicaSet"))
+	assert.Assert(t, !ok)
+
+	pod := newPod("DaemonSet")
+	pod.Spec.Affinity = nil
+	_, ok = daemonSetNodeName(pod)
+	assert.Assert(t, !ok)
+
+	pod.Spec.NodeName = "node-2"
+	nodeName, ok = daemonSetNodeName(pod)
+	assert.Assert(t, ok)
+	assert.Equal(t, nodeName, "node-2")
+}
+
+func TestHiddenTaintTolerations(t *testing.T) {
+	visible := corev1.Taint{Key: "visible", Value: "true", Effect: corev1.TaintEffectNoExecute}
+	hidden := corev1.Taint{Key: "hidden", Value: "true", Effect: corev1.TaintEffectNoExecute}
+	tolerated := corev1.Taint{Key: "tolerated", Value: "true", Effect: corev1.TaintEffectNoExecute}
+	noSchedule := corev1.Taint{Key: "no-schedule", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+
+	tolerations := hiddenTaintTolerations(
+		[]corev1.Taint{visible, hidden, tolerated, noSchedule},
+		[]corev1.Taint{visible},
+		[]corev1.Toleration{{Key: "tolerated", Operator: corev1.TolerationOpExists}},
+	)
+	assert.DeepEqual(t, tolerations, []corev1.Toleration{
+		{
+			Key:      "hidden",
+			Operator: corev1.TolerationOpEqual,
+			Value:    "true",
+			Effect:   corev1.TaintEffectNoExecute,
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package pods
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HostEvictionReasonAnnotation holds the reason the host cluster evicted or preempted the physical pod
+	HostEvictionReasonAnnotation = "vcluster.loft.sh/host-eviction-reason"
+
+	// ReasonHostEviction is the event reason for virtual pods whose physical pod was evicted by the host cluster
+	ReasonHostEviction = "HostEviction"
+)
+
+// hostEvictionReason returns the reason if the physical pod is terminated, because the host cluster
+// evicted or preempted it
+func hostEvictionReason(pPod *corev1.Pod) (string, bool) {
+	for _, condition := range pPod.Status.Conditions {
+		if condition.Type != corev1.DisruptionTarget || condition.Status != corev1.ConditionTrue {
+			continue
+		} else if condition.Message != "" {
+			return condition.Reason + ": " + condition.Message, true
+		}
+
+		return condition.Reason, true
+	}
+
+	return "", false
+}
+
+// rescheduleEvictedPod handles a virtual pod whose physical pod was evicted by the host cluster. The
+// virtual pod is annotated with the eviction reason first, so its owner and other controllers can
+// react. Afterwards, pods with a controller are deleted immediately, so the controller can create a
+// replacement without waiting for the host pod to terminate. Returns false if the generic delete path
+// should be used.
+func (s *podSyncer) rescheduleEvictedPod(ctx *synccontext.SyncContext, pPod, vPod *corev1.Pod) (bool, error) {
+	reason, evicted := hostEvictionReason(pPod)
+	if !evicted {
+		return false, nil
+	}
+
+	if vPod.Annotations[HostEvictionReasonAnnotation] != reason {
+		newPod := vPod.DeepCopy()
+		if newPod.Annotations == nil {
+			newPod.Annotations = map[string]string{}
+		}
+		newPod.Annotations[HostEvictionReasonAnnotation] = reason
+
+		ctx.Log.Infof("annotate virtual pod %s/%s, because the physical pod was evicted: %s", vPod.Namespace, vPod.Name, reason)
+		s.EventRecorder().Eventf(vPod, "Warning", ReasonHostEviction, "Pod was evicted by the host cluster: %s", reason)
+		return true, ctx.VirtualClient.Update(ctx.Context, newPod)
+	} else if metav1.GetControllerOf(vPod) == nil {
+		return false, nil
+	}
+
+	ctx.Log.Infof("delete virtual pod %s/%s immediately, because the physical pod was evicted", vPod.Namespace, vPod.Name)
+	err := ctx.VirtualClient.Delete(ctx.Context, vPod, &client.DeleteOptions{
+		GracePeriodSeconds: &zero,
+		Preconditions:      metav1.NewUIDPreconditions(string(vPod.UID)),
+	})
+	if kerrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	return true, err
+}
@@ -0,0 +1,118 @@
+package pods
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+func TestHostEvictionReason(t *testing.T) {
+	testCases := []struct {
+		name            string
+		conditions      []corev1.PodCondition
+		expected        bool
+		expectedMessage string
+	}{
+		{
+			name:       "Deleted pod",
+			conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+		{
+			name:            "Preempted pod",
+			conditions:      []corev1.PodCondition{{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: "PreemptionByScheduler", Message: "default-scheduler: preempting to accommodate a higher priority pod"}},
+			expected:        true,
+			expectedMessage: "PreemptionByScheduler: default-scheduler: preempting to accommodate a higher priority pod",
+		},
+		{
+			name:            "Evicted pod",
+			conditions:      []corev1.PodCondition{{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: "EvictionByEvictionAPI"}},
+			expected:        true,
+			expectedMessage: "EvictionByEvictionAPI",
+		},
+	}
+
+	for _, testCase := range testCases {
+		message, evicted := hostEvictionReason(&corev1.Pod{Status: corev1.PodStatus{Conditions: testCase.conditions}})
+		assert.Equal(t, evicted, testCase.expected, "unexpected result in test case %s", testCase.name)
+		assert.Equal(t, message, testCase.expectedMessage, "unexpected message in test case %s", testCase.name)
+	}
+}
+
+func TestRescheduleEvictedPod(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	now := metav1.Now()
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testpod",
+			Namespace: "testns",
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node1",
+		},
+	}
+	vOwnedPod := vPod.DeepCopy()
+	vOwnedPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test", UID: "123", Controller: pointer.Bool(true)}}
+	vAnnotatedPod := vPod.DeepCopy()
+	vAnnotatedPod.Annotations = map[string]string{HostEvictionReasonAnnotation: "PreemptionByScheduler"}
+	pPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:                       translate.Default.PhysicalName(vPod.Name, vPod.Namespace),
+			Namespace:                  generictesting.DefaultTestTargetNamespace,
+			DeletionTimestamp:          &now,
+			DeletionGracePeriodSeconds: pointer.Int64(30),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node1",
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: "PreemptionByScheduler"}},
+		},
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                "Annotate evicted pod",
+			InitialVirtualState: []runtime.Object{vPod.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vAnnotatedPod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.RescheduleHostEvictedPods = true
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).Sync(syncCtx, pPod.DeepCopy(), vPod.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Delete evicted pod with controller immediately",
+			InitialVirtualState: []runtime.Object{vOwnedPod.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.RescheduleHostEvictedPods = true
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).Sync(syncCtx, pPod.DeepCopy(), vOwnedPod.DeepCopy())
+				assert.NilError(t, err)
+
+				// the owner saw the annotation, so the pod is deleted now
+				annotatedPod := &corev1.Pod{}
+				err = syncCtx.VirtualClient.Get(syncCtx.Context, types.NamespacedName{Namespace: vOwnedPod.Namespace, Name: vOwnedPod.Name}, annotatedPod)
+				assert.NilError(t, err)
+				assert.Equal(t, annotatedPod.Annotations[HostEvictionReasonAnnotation], "PreemptionByScheduler")
+				_, err = syncer.(*podSyncer).Sync(syncCtx, pPod.DeepCopy(), annotatedPod)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package pods
+
+import (
+	"fmt"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
+	"github.com/loft-sh/vcluster/pkg/util/nodepools"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// translateNodePool replaces the node pool label in the node selector of the pod with the host node
+// selector of the pool, as the host nodes don't carry that label, and adds the tolerations of the pool.
+// Pods that are bound to a virtual node already get the tolerations of the pool of that node. Returns
+// false if the selected pool does not exist.
+func (s *podSyncer) translateNodePool(ctx *synccontext.SyncContext, vPod, pPod *corev1.Pod) (bool, error) {
+	var pool *nodepools.Pool
+	if poolName, ok := pPod.Spec.NodeSelector[nodepools.Label]; ok {
+		pool = s.nodePools.ByName(poolName)
+		if pool == nil {
+			err := syncerrors.NewTranslationError(fmt.Errorf("node pool %s does not exist in virtual cluster", poolName))
+			s.EventRecorder().Event(vPod, "Warning", syncerrors.Reason(err), err.Error())
+			return false, nil
+		}
+
+		delete(pPod.Spec.NodeSelector, nodepools.Label)
+		for k, v := range pool.NodeSelector {
+			pPod.Spec.NodeSelector[k] = v
+		}
+	} else if pPod.Spec.NodeName != "" {
+		vNode := &corev1.Node{}
+		err := ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Name: pPod.Spec.NodeName}, vNode)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+
+		pool = s.nodePools.ByName(vNode.Labels[nodepools.Label])
+	}
+	if pool == nil {
+		return true, nil
+	}
+
+	for _, tol := range pool.ParsedTolerations() {
+		if !containsToleration(pPod.Spec.Tolerations, tol) {
+			pPod.Spec.Tolerations = append(pPod.Spec.Tolerations, tol)
+		}
+	}
+
+	return true, nil
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(&toleration) {
+			return true
+		}
+	}
+
+	return false
+}
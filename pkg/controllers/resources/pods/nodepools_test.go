@@ -0,0 +1,85 @@
+package pods
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/nodepools"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestTranslateNodePool(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	rawPools := `[{"name":"gpu","nodeSelector":{"example.com/gpu":"true"},"tolerations":["nvidia.com/gpu:NoSchedule"]}]`
+	gpuToleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	vNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "gpu-node",
+			Labels: map[string]string{nodepools.Label: "gpu"},
+		},
+	}
+
+	testCases := []struct {
+		name                 string
+		pod                  *corev1.Pod
+		expectedFound        bool
+		expectedNodeSelector map[string]string
+		expectedTolerations  []corev1.Toleration
+	}{
+		{
+			name:          "Pod without pool",
+			pod:           &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/os": "linux"}}},
+			expectedFound: true,
+			expectedNodeSelector: map[string]string{
+				"kubernetes.io/os": "linux",
+			},
+		},
+		{
+			name:          "Pod selecting pool",
+			pod:           &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{nodepools.Label: "gpu", "kubernetes.io/os": "linux"}}},
+			expectedFound: true,
+			expectedNodeSelector: map[string]string{
+				"example.com/gpu":  "true",
+				"kubernetes.io/os": "linux",
+			},
+			expectedTolerations: []corev1.Toleration{gpuToleration},
+		},
+		{
+			name:                "Pod bound to pool node",
+			pod:                 &corev1.Pod{Spec: corev1.PodSpec{NodeName: vNode.Name, Tolerations: []corev1.Toleration{gpuToleration}}},
+			expectedFound:       true,
+			expectedTolerations: []corev1.Toleration{gpuToleration},
+		},
+		{
+			name:                 "Pod selecting missing pool",
+			pod:                  &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{nodepools.Label: "other"}}},
+			expectedNodeSelector: map[string]string{nodepools.Label: "other"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		generictesting.RunTests(t, []*generictesting.SyncTest{
+			{
+				Name:                testCase.name,
+				InitialVirtualState: []runtime.Object{vNode.DeepCopy()},
+				Sync: func(ctx *synccontext.RegisterContext) {
+					ctx.Options.NodePools = rawPools
+					syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+
+					pPod := testCase.pod.DeepCopy()
+					found, err := syncer.(*podSyncer).translateNodePool(syncCtx, testCase.pod, pPod)
+					assert.NilError(t, err)
+					assert.Equal(t, found, testCase.expectedFound)
+					assert.DeepEqual(t, pPod.Spec.NodeSelector, testCase.expectedNodeSelector)
+					assert.DeepEqual(t, pPod.Spec.Tolerations, testCase.expectedTolerations)
+				},
+			},
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package pods
+
+import (
+	"sort"
+
+	"github.com/loft-sh/vcluster/pkg/util/nodeselector"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// enforceNodeSelector constrains the physical pod to the nodes matching the given selector. Match labels are
+// added to the node selector of the pod, match expressions are added to every required node affinity term,
+// because the terms are ORed and each of them has to select matching nodes only.
+func enforceNodeSelector(pPod *corev1.Pod, nodeSelector *metav1.LabelSelector) {
+	if len(nodeSelector.MatchLabels) > 0 {
+		if pPod.Spec.NodeSelector == nil {
+			pPod.Spec.NodeSelector = map[string]string{}
+		}
+		for k, v := range nodeSelector.MatchLabels {
+			pPod.Spec.NodeSelector[k] = v
+		}
+	}
+	if len(nodeSelector.MatchExpressions) == 0 {
+		return
+	}
+
+	// the match labels are part of the node selector already
+	requirements := nodeSelectorRequirements(&metav1.LabelSelector{MatchExpressions: nodeSelector.MatchExpressions})
+
+	if pPod.Spec.Affinity == nil {
+		pPod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pPod.Spec.Affinity.NodeAffinity == nil {
+		pPod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+
+	required := pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range required.NodeSelectorTerms {
+		for _, requirement := range requirements {
+			if !hasRequirement(required.NodeSelectorTerms[i].MatchExpressions, requirement) {
+				required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, *requirement.DeepCopy())
+			}
+		}
+	}
+}
+
+func hasRequirement(requirements []corev1.NodeSelectorRequirement, requirement corev1.NodeSelectorRequirement) bool {
+	for _, r := range requirements {
+		if r.Key != requirement.Key || r.Operator != requirement.Operator || len(r.Values) != len(requirement.Values) {
+			continue
+		}
+
+		equal := true
+		for i := range r.Values {
+			if r.Values[i] != requirement.Values[i] {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// preferNodeSelector adds the given selector as preferred node affinity term with the weight to the physical
+// pod, so the pod is placed on the selected nodes if possible, but can run on other nodes if they are full.
+func preferNodeSelector(pPod *corev1.Pod, nodeSelector *metav1.LabelSelector, weight int32) {
+	term := corev1.PreferredSchedulingTerm{
+		Weight:     weight,
+		Preference: corev1.NodeSelectorTerm{MatchExpressions: nodeSelectorRequirements(nodeSelector)},
+	}
+
+	if pPod.Spec.Affinity == nil {
+		pPod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pPod.Spec.Affinity.NodeAffinity == nil {
+		pPod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	for _, preferred := range pPod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if equality.Semantic.DeepEqual(preferred, term) {
+			return
+		}
+	}
+
+	pPod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(pPod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, term)
+}
+
+// enforceNodeSelectorGroups constrains the physical pod to the nodes of any of the groups and adds a preferred
+// node affinity term for every weighted group, so the pod is placed on the nodes of the group with the highest
+// weight that has room. If soft is true, the pod is not constrained and only prefers the nodes of the groups.
+func enforceNodeSelectorGroups(pPod *corev1.Pod, groups []nodeselector.Group, soft bool) {
+	for _, group := range groups {
+		if group.Weight > 0 {
+			preferNodeSelector(pPod, group.Selector, group.Weight)
+		}
+	}
+	if soft {
+		return
+	}
+
+	if pPod.Spec.Affinity == nil {
+		pPod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pPod.Spec.Affinity.NodeAffinity == nil {
+		pPod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+
+	// the terms are ORed, so every term of the pod is combined with every group
+	required := pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	terms := make([]corev1.NodeSelectorTerm, 0, len(required.NodeSelectorTerms)*len(groups))
+	for _, podTerm := range required.NodeSelectorTerms {
+		for _, group := range groups {
+			term := *podTerm.DeepCopy()
+			for _, requirement := range nodeSelectorRequirements(group.Selector) {
+				if !hasRequirement(term.MatchExpressions, requirement) {
+					term.MatchExpressions = append(term.MatchExpressions, requirement)
+				}
+			}
+
+			terms = append(terms, term)
+		}
+	}
+	required.NodeSelectorTerms = terms
+}
+
+// nodeSelectorRequirements converts the match labels and expressions of the selector to node selector requirements
+func nodeSelectorRequirements(nodeSelector *metav1.LabelSelector) []corev1.NodeSelectorRequirement {
+	keys := make([]string, 0, len(nodeSelector.MatchLabels))
+	for k := range nodeSelector.MatchLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	requirements := make([]corev1.NodeSelectorRequirement, 0, len(keys)+len(nodeSelector.MatchExpressions))
+	for _, k := range keys {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      k,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{nodeSelector.MatchLabels[k]},
+		})
+	}
+	for _, expression := range nodeSelector.MatchExpressions {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      expression.Key,
+			Operator: corev1.NodeSelectorOperator(expression.Operator),
+			Values:   expression.Values,
+		})
+	}
+
+	return requirements
+}
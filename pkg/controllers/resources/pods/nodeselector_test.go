@@ -0,0 +1,141 @@
+package pods
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/nodeselector"
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnforceNodeSelector(t *testing.T) {
+	nodeSelector, err := metav1.ParseToLabelSelector("tenant=a,pool in (small,large)")
+	assert.NilError(t, err)
+	poolRequirement := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"large", "small"}}
+	zoneRequirement := corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+
+	testCases := []struct {
+		name             string
+		affinity         *corev1.Affinity
+		expectedAffinity *corev1.Affinity
+	}{
+		{
+			name: "Pod without affinity",
+			expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{poolRequirement}}},
+			}}},
+		},
+		{
+			name: "Pod with node affinity terms",
+			affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{poolRequirement}},
+				},
+			}}},
+			expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement, poolRequirement}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{poolRequirement}},
+				},
+			}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		pPod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: testCase.affinity}}
+		enforceNodeSelector(pPod, nodeSelector)
+		assert.Assert(t, cmp.DeepEqual(pPod.Spec.NodeSelector, map[string]string{"tenant": "a"}), "unexpected node selector in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(pPod.Spec.Affinity, testCase.expectedAffinity), "unexpected affinity in test case %s", testCase.name)
+	}
+}
+
+func TestPreferNodeSelector(t *testing.T) {
+	nodeSelector, err := metav1.ParseToLabelSelector("tenant=a,pool in (small,large)")
+	assert.NilError(t, err)
+	zoneTerm := corev1.PreferredSchedulingTerm{Weight: 10, Preference: corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}},
+	}}
+	selectorTerm := corev1.PreferredSchedulingTerm{Weight: 50, Preference: corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "tenant", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+			{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"large", "small"}},
+		},
+	}}
+
+	testCases := []struct {
+		name              string
+		affinity          *corev1.Affinity
+		expectedPreferred []corev1.PreferredSchedulingTerm
+	}{
+		{
+			name:              "Pod without affinity",
+			expectedPreferred: []corev1.PreferredSchedulingTerm{selectorTerm},
+		},
+		{
+			name:              "Pod with preferred node affinity",
+			affinity:          &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{zoneTerm}}},
+			expectedPreferred: []corev1.PreferredSchedulingTerm{zoneTerm, selectorTerm},
+		},
+		{
+			name:              "Pod with preferred node selector",
+			affinity:          &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{selectorTerm}}},
+			expectedPreferred: []corev1.PreferredSchedulingTerm{selectorTerm},
+		},
+	}
+
+	for _, testCase := range testCases {
+		pPod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: testCase.affinity}}
+		preferNodeSelector(pPod, nodeSelector, 50)
+		assert.Assert(t, pPod.Spec.NodeSelector == nil, "unexpected node selector in test case %s", testCase.name)
+		assert.Assert(t, pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil, "unexpected required node affinity in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(pPod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, testCase.expectedPreferred), "unexpected preferred node affinity in test case %s", testCase.name)
+	}
+}
+
+func TestEnforceNodeSelectorGroups(t *testing.T) {
+	groups, err := nodeselector.ParseGroups([]string{"100:pool=a", "pool=b"})
+	assert.NilError(t, err)
+	poolA := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+	poolB := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}
+	zone := corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+	preferred := []corev1.PreferredSchedulingTerm{{Weight: 100, Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{poolA}}}}
+
+	testCases := []struct {
+		name             string
+		affinity         *corev1.Affinity
+		soft             bool
+		expectedRequired *corev1.NodeSelector
+	}{
+		{
+			name: "Pod without affinity",
+			expectedRequired: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{poolA}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{poolB}},
+			}},
+		},
+		{
+			name: "Pod with node affinity",
+			affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}},
+			}}},
+			expectedRequired: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{zone, poolA}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{zone, poolB}},
+			}},
+		},
+		{
+			name: "Soft",
+			soft: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		pPod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: testCase.affinity}}
+		enforceNodeSelectorGroups(pPod, groups, testCase.soft)
+		assert.Assert(t, cmp.DeepEqual(pPod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, testCase.expectedRequired), "unexpected required node affinity in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(pPod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, preferred), "unexpected preferred node affinity in test case %s", testCase.name)
+	}
+}
@@ -0,0 +1,73 @@
+package pods
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// usesVirtualScheduler returns true if the pod is scheduled inside the virtual cluster, either by the
+// virtual scheduler or by a scheduler of the tenant
+func (s *podSyncer) usesVirtualScheduler(pod *corev1.Pod) bool {
+	return s.enableScheduler || s.externalSchedulers[pod.Spec.SchedulerName]
+}
+
+// isWaitingForSync checks if the virtual pod was bound by the virtual scheduler, but was not started yet
+func isWaitingForSync(pod *corev1.Pod) bool {
+	return pod.Spec.NodeName != "" && pod.Status.StartTime == nil && pod.DeletionTimestamp == nil
+}
+
+// nodeReadinessHandler enqueues the pods that were bound to a virtual node as soon as the node becomes
+// ready, so they don't have to wait for the next requeue if the node was missing or not ready yet.
+func nodeReadinessHandler(virtualClient client.Client) handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+			if isNodeReady(e.Object) {
+				enqueueWaitingPods(ctx, virtualClient, e.Object.GetName(), q)
+			}
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			if isNodeReady(e.ObjectNew) && !isNodeReady(e.ObjectOld) {
+				enqueueWaitingPods(ctx, virtualClient, e.ObjectNew.GetName(), q)
+			}
+		},
+	}
+}
+
+func isNodeReady(obj client.Object) bool {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return false
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func enqueueWaitingPods(ctx context.Context, virtualClient client.Client, nodeName string, q workqueue.RateLimitingInterface) {
+	pods := &corev1.PodList{}
+	err := virtualClient.List(ctx, pods, client.MatchingFields{constants.IndexByAssigned: nodeName})
+	if err != nil {
+		loghelper.New("pods-syncer-node-watch-handler").Infof("failed to list pods of node %s: %v", nodeName, err)
+		return
+	}
+
+	for _, pod := range pods.Items {
+		if isWaitingForSync(&pod) {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}})
+		}
+	}
+}
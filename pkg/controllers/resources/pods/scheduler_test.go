@@ -0,0 +1,92 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNodeReadinessHandler(t *testing.T) {
+	now := metav1.Now()
+	pod := func(name, nodeName string, startTime *metav1.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{StartTime: startTime},
+		}
+	}
+	node := func(ready corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}},
+		}
+	}
+
+	ctx := context.Background()
+	virtualClient := testingutil.NewFakeClient(testingutil.NewScheme(), pod("waiting", "node1", nil), pod("started", "node1", &now), pod("other", "node2", nil))
+	err := virtualClient.IndexField(ctx, &corev1.Pod{}, constants.IndexByAssigned, func(rawObj client.Object) []string {
+		return []string{rawObj.(*corev1.Pod).Spec.NodeName}
+	})
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name  string
+		event func(q workqueue.RateLimitingInterface)
+
+		expectedRequests []string
+	}{
+		{
+			name: "ready node created",
+			event: func(q workqueue.RateLimitingInterface) {
+				nodeReadinessHandler(virtualClient).Create(ctx, event.CreateEvent{Object: node(corev1.ConditionTrue)}, q)
+			},
+			expectedRequests: []string{"waiting"},
+		},
+		{
+			name: "not ready node created",
+			event: func(q workqueue.RateLimitingInterface) {
+				nodeReadinessHandler(virtualClient).Create(ctx, event.CreateEvent{Object: node(corev1.ConditionFalse)}, q)
+			},
+		},
+		{
+			name: "node became ready",
+			event: func(q workqueue.RateLimitingInterface) {
+				nodeReadinessHandler(virtualClient).Update(ctx, event.UpdateEvent{ObjectOld: node(corev1.ConditionFalse), ObjectNew: node(corev1.ConditionTrue)}, q)
+			},
+			expectedRequests: []string{"waiting"},
+		},
+		{
+			name: "node stayed ready",
+			event: func(q workqueue.RateLimitingInterface) {
+				nodeReadinessHandler(virtualClient).Update(ctx, event.UpdateEvent{ObjectOld: node(corev1.ConditionTrue), ObjectNew: node(corev1.ConditionTrue)}, q)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		testCase.event(q)
+
+		requests := []string{}
+		for q.Len() > 0 {
+			item, _ := q.Get()
+			requests = append(requests, item.(reconcile.Request).Name)
+			q.Done(item)
+		}
+		q.ShutDown()
+
+		if testCase.expectedRequests == nil {
+			testCase.expectedRequests = []string{}
+		}
+		assert.DeepEqual(t, requests, testCase.expectedRequests)
+	}
+}
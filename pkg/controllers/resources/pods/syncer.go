@@ -2,6 +2,7 @@ package pods
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"time"
 
@@ -10,13 +11,18 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 
 	translatepods "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/nodepools"
+	"github.com/loft-sh/vcluster/pkg/util/nodeselector"
+	"github.com/loft-sh/vcluster/pkg/util/resourcenames"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +43,9 @@ var (
 	zero                              = int64(0)
 )
 
+// systemCriticalPriority is the lowest priority of the system critical priority classes
+const systemCriticalPriority int32 = 2000000000
+
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 	virtualClusterClient, err := kubernetes.NewForConfig(ctx.VirtualManager.GetConfig())
 	if err != nil {
@@ -53,12 +62,25 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		nodeSelector, err = metav1.ParseToLabelSelector(ctx.Options.NodeSelector)
 		if err != nil {
 			return nil, errors.Wrap(err, "parse node selector")
-		} else if len(nodeSelector.MatchExpressions) > 0 {
-			return nil, errors.New("match expressions in the node selector are not supported")
-		} else if len(nodeSelector.MatchLabels) == 0 {
-			return nil, errors.New("at least one label=value pair has to be defined in the label selector")
+		} else if len(nodeSelector.MatchLabels) == 0 && len(nodeSelector.MatchExpressions) == 0 {
+			return nil, errors.New("at least one requirement has to be defined in the label selector")
+		}
+	}
+	// parse node selector groups
+	var nodeSelectorGroups []nodeselector.Group
+	if ctx.Options.EnforceNodeSelector && len(ctx.Options.NodeSelectorGroups) > 0 {
+		if nodeSelector != nil {
+			return nil, errors.New("node selector and node selector groups can't be used together")
+		}
+
+		nodeSelectorGroups, err = nodeselector.ParseGroups(ctx.Options.NodeSelectorGroups)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse node selector groups")
 		}
 	}
+	if ctx.Options.NodeSelectorWeight < 0 || ctx.Options.NodeSelectorWeight > 100 {
+		return nil, errors.Errorf("node selector weight %d has to be between 0 and 100", ctx.Options.NodeSelectorWeight)
+	}
 
 	// parse tolerations
 	var tolerations []*corev1.Toleration
@@ -71,6 +93,18 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		}
 	}
 
+	// schedulers of the tenants
+	externalSchedulers := map[string]bool{}
+	for _, schedulerName := range ctx.Options.ExternalSchedulers {
+		externalSchedulers[schedulerName] = true
+	}
+
+	// parse node pools
+	nodePools, err := nodepools.Parse(ctx.Options.NodePools)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse node pools")
+	}
+
 	// create new namespaced translator
 	namespacedTranslator := translator.NewNamespacedTranslator(ctx, "pod", &corev1.Pod{})
 
@@ -83,15 +117,26 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 	return &podSyncer{
 		NamespacedTranslator: namespacedTranslator,
 
-		serviceName:     ctx.Options.ServiceName,
-		enableScheduler: ctx.Options.EnableScheduler,
+		serviceName:           ctx.Options.ServiceName,
+		dnsServiceIP:          ctx.Options.DNSServiceIP,
+		enableScheduler:       ctx.Options.EnableScheduler,
+		externalSchedulers:    externalSchedulers,
+		runtimeClassesEnabled: ctx.Controllers.Has("runtimeclasses"),
+		limitRangeDefaults:    ctx.Options.HostLimitRangeDefaults,
+		bindDaemonSetPods:     ctx.Options.BindDaemonSetPods,
+		waitForHostCapacity:   ctx.Options.WaitForHostCapacity,
+		rescheduleEvicted:     ctx.Options.RescheduleHostEvictedPods,
+		resourceNames:         ctx.ResourceNames,
 
 		virtualClusterClient:  virtualClusterClient,
 		physicalClusterClient: physicalClusterClient,
 		physicalClusterConfig: ctx.PhysicalManager.GetConfig(),
 		podTranslator:         podTranslator,
 		nodeSelector:          nodeSelector,
+		nodeSelectorWeight:    int32(ctx.Options.NodeSelectorWeight),
+		nodeSelectorGroups:    nodeSelectorGroups,
 		tolerations:           tolerations,
+		nodePools:             nodePools,
 
 		podSecurityStandard: ctx.Options.EnforcePodSecurityStandard,
 	}, nil
@@ -100,15 +145,29 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 type podSyncer struct {
 	translator.NamespacedTranslator
 
-	serviceName     string
-	enableScheduler bool
+	serviceName           string
+	dnsServiceIP          string
+	enableScheduler       bool
+	externalSchedulers    map[string]bool
+	runtimeClassesEnabled bool
+	limitRangeDefaults    bool
+	bindDaemonSetPods     bool
+	waitForHostCapacity   bool
+	rescheduleEvicted     bool
+	resourceNames         *resourcenames.Mapping
 
 	podTranslator         translatepods.Translator
 	virtualClusterClient  kubernetes.Interface
 	physicalClusterClient kubernetes.Interface
 	physicalClusterConfig *rest.Config
 	nodeSelector          *metav1.LabelSelector
+	nodeSelectorWeight    int32
+	nodeSelectorGroups    []nodeselector.Group
 	tolerations           []*corev1.Toleration
+	nodePools             nodepools.Pools
+
+	// hostCache reads the host nodes and the host pods of all namespaces by scheduler.IndexPodByNode
+	hostCache client.Reader
 
 	podSecurityStandard string
 }
@@ -146,7 +205,40 @@ func (s *podSyncer) ModifyController(ctx *synccontext.RegisterContext, builder *
 		},
 	}
 
-	return builder.Watches(&corev1.Namespace{}, eventHandler), nil
+	builder = builder.Watches(&corev1.Namespace{}, eventHandler)
+	if s.enableScheduler || len(s.externalSchedulers) > 0 {
+		builder = builder.Watches(&corev1.Node{}, nodeReadinessHandler(ctx.VirtualManager.GetClient()))
+	}
+
+	if s.waitForHostCapacity {
+		hostCache, err := ctx.HostCache.Reader(ctx.Context)
+		if err != nil {
+			return nil, errors.Wrap(err, "start host cache")
+		}
+		s.hostCache = hostCache
+	}
+
+	return builder, nil
+}
+
+var _ syncer.Prioritizer = &podSyncer{}
+
+// Priority makes sure deletions, system critical pods and pods that were just bound by the
+// virtual scheduler are synced before bulk creations
+func (s *podSyncer) Priority(obj client.Object, deleted bool) int {
+	if deleted || obj.GetDeletionTimestamp() != nil {
+		return syncer.PriorityDeletion
+	}
+
+	pod, ok := obj.(*corev1.Pod)
+	if ok && ((pod.Spec.Priority != nil && *pod.Spec.Priority >= systemCriticalPriority) ||
+		pod.Spec.PriorityClassName == "system-cluster-critical" ||
+		pod.Spec.PriorityClassName == "system-node-critical" ||
+		(s.usesVirtualScheduler(pod) && isWaitingForSync(pod))) {
+		return syncer.PriorityHigh
+	}
+
+	return syncer.PriorityDefault
 }
 
 var _ syncer.Syncer = &podSyncer{}
@@ -181,7 +273,7 @@ func (s *podSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (
 	// translate the pod
 	pPod, err := s.translate(ctx, vPod)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, syncerrors.NewTranslationError(err)
 	}
 
 	// ensure tolerations
@@ -189,18 +281,38 @@ func (s *podSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (
 		pPod.Spec.Tolerations = append(pPod.Spec.Tolerations, *tol)
 	}
 
+	// bind daemon set pods to their node
+	if s.bindDaemonSetPods {
+		bound, err := s.bindDaemonSetPod(ctx, vPod, pPod)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if !bound {
+			return ctrl.Result{RequeueAfter: time.Second * 15}, nil
+		}
+	}
+
+	// translate the node pool the pod selects to the host nodes of the pool
+	if len(s.nodePools) > 0 {
+		found, err := s.translateNodePool(ctx, vPod, pPod)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if !found {
+			return ctrl.Result{RequeueAfter: time.Second * 15}, nil
+		}
+	}
+
 	// ensure node selector
-	if s.nodeSelector != nil {
+	if s.nodeSelector != nil || len(s.nodeSelectorGroups) > 0 {
 		// 2 cases:
 		// 1. Pod already has a nodeName -> then we check if the node exists in the virtual cluster
-		// 2. Pod has no nodeName -> then we set the nodeSelector
-		if pPod.Spec.NodeName == "" {
-			if pPod.Spec.NodeSelector == nil {
-				pPod.Spec.NodeSelector = map[string]string{}
-			}
-			for k, v := range s.nodeSelector.MatchLabels {
-				pPod.Spec.NodeSelector[k] = v
-			}
+		// 2. Pod has no nodeName -> then we set the nodeSelector and node affinity, or only prefer the
+		//    selected nodes if a weight is set
+		if pPod.Spec.NodeName == "" && len(s.nodeSelectorGroups) > 0 {
+			enforceNodeSelectorGroups(pPod, s.nodeSelectorGroups, s.nodeSelectorWeight > 0)
+		} else if pPod.Spec.NodeName == "" && s.nodeSelectorWeight > 0 {
+			preferNodeSelector(pPod, s.nodeSelector, s.nodeSelectorWeight)
+		} else if pPod.Spec.NodeName == "" {
+			enforceNodeSelector(pPod, s.nodeSelector)
 		} else {
 			// make sure the node does exist in the virtual cluster
 			err = ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Name: pPod.Spec.NodeName}, &corev1.Node{})
@@ -215,11 +327,50 @@ func (s *podSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (
 		}
 	}
 
-	// if scheduler is enabled we only sync if the pod has a node name
-	if s.enableScheduler && pPod.Spec.NodeName == "" {
+	// make sure the runtime class does exist in the host cluster, which is mirrored into the virtual cluster
+	if s.runtimeClassesEnabled && pPod.Spec.RuntimeClassName != nil {
+		err = ctx.VirtualClient.Get(ctx.Context, types.NamespacedName{Name: *pPod.Spec.RuntimeClassName}, &nodev1.RuntimeClass{})
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+
+			err = syncerrors.NewTranslationError(fmt.Errorf("given runtimeClassName %s does not exist in host cluster", *pPod.Spec.RuntimeClassName))
+			s.EventRecorder().Event(vPod, "Warning", syncerrors.Reason(err), err.Error())
+			return ctrl.Result{RequeueAfter: time.Second * 15}, nil
+		}
+	}
+
+	// if the pod is scheduled inside the virtual cluster we only sync if the pod has a node name
+	if s.usesVirtualScheduler(vPod) && pPod.Spec.NodeName == "" {
 		return ctrl.Result{}, nil
 	}
 
+	// hold the pod back instead of creating a host pod that stays pending
+	if s.waitForHostCapacity {
+		hasCapacity, message, err := s.hasHostCapacity(ctx.Context, pPod)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if !hasCapacity {
+			changed, err := setHostCapacityCondition(ctx, vPod, message)
+			if err != nil {
+				return ctrl.Result{}, err
+			} else if changed {
+				s.EventRecorder().Event(vPod, "Warning", ReasonInsufficientHostCapacity, message)
+			}
+
+			return ctrl.Result{RequeueAfter: time.Second * 15}, nil
+		}
+	}
+
+	// reflect the limit range defaults of the host namespace in the virtual pod
+	if s.limitRangeDefaults {
+		_, err = s.updateHostResourcesAnnotation(ctx, vPod, pPod)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	return s.SyncDownCreate(ctx, vPod, pPod)
 }
 
@@ -230,6 +381,16 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 	// should pod get deleted?
 	if pPod.DeletionTimestamp != nil {
 		if vPod.DeletionTimestamp == nil {
+			// give the owner of the virtual pod a chance to replace it right away
+			if s.rescheduleEvicted {
+				handled, err := s.rescheduleEvictedPod(ctx, pPod, vPod)
+				if err != nil {
+					return ctrl.Result{}, err
+				} else if handled {
+					return ctrl.Result{}, nil
+				}
+			}
+
 			gracePeriod := minimumGracePeriodInSeconds
 			if vPod.Spec.TerminationGracePeriodSeconds != nil {
 				gracePeriod = *vPod.Spec.TerminationGracePeriodSeconds
@@ -244,6 +405,12 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 			if err := ctx.VirtualClient.Delete(ctx.Context, vPod, &client.DeleteOptions{GracePeriodSeconds: pPod.DeletionGracePeriodSeconds, Preconditions: metav1.NewUIDPreconditions(string(vPod.UID))}); err != nil {
 				return ctrl.Result{}, err
 			}
+		} else {
+			// like the kubelet, keep reporting the status of the terminating pod, so the endpoints
+			// of headless services that publish not ready addresses see the pod stop before it is
+			// replaced, e.g. by a stateful set
+			_, err := s.updateVirtualStatus(ctx, vObj, vPod, stripInjectedSidecarContainers(vPod, pPod, stripHostRewriteContainer(pPod)))
+			return ctrl.Result{}, err
 		}
 
 		return ctrl.Result{}, nil
@@ -278,6 +445,16 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		return ctrl.Result{}, nil
 	}
 
+	// reflect the actual resources of the host pod in the virtual pod
+	if s.limitRangeDefaults {
+		updated, err := s.updateHostResourcesAnnotation(ctx, vPod, pPod)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if updated {
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// has status changed?
 	strippedPod := stripHostRewriteContainer(pPod)
 
@@ -292,20 +469,26 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 	}
 
 	// update status physical -> virtual
-	if !equality.Semantic.DeepEqual(vPod.Status, strippedPod.Status) {
+	updated, err = s.updateVirtualStatus(ctx, vObj, vPod, strippedPod)
+	if err != nil || updated {
+		return ctrl.Result{}, err
+	}
+
+	// update pod deletion cost physical -> virtual
+	if cost, exists, changed := translatepods.HostPodDeletionCost(vPod, pPod); changed {
 		newPod := vPod.DeepCopy()
-		newPod.Status = strippedPod.Status
-		ctx.Log.Infof("update virtual pod %s/%s, because status has changed", vPod.Namespace, vPod.Name)
-		translator.PrintChanges(vPod, newPod, ctx.Log)
-		err := ctx.VirtualClient.Status().Update(ctx.Context, newPod)
-		if err != nil {
-			if !kerrors.IsConflict(err) {
-				s.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Error updating pod: %v", err)
+		if exists {
+			if newPod.Annotations == nil {
+				newPod.Annotations = map[string]string{}
 			}
-
-			return ctrl.Result{}, err
+			newPod.Annotations[translatepods.PodDeletionCostAnnotation] = cost
+		} else {
+			delete(newPod.Annotations, translatepods.PodDeletionCostAnnotation)
 		}
-		return ctrl.Result{}, nil
+
+		ctx.Log.Infof("update virtual pod %s/%s, because pod deletion cost has changed on the host", vPod.Namespace, vPod.Name)
+		translator.PrintChanges(vPod, newPod, ctx.Log)
+		return ctrl.Result{}, ctx.VirtualClient.Update(ctx.Context, newPod)
 	}
 
 	// sync ephemeral containers
@@ -343,7 +526,7 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 	// update the virtual pod if the spec has changed
 	updatedPod, err := s.translateUpdate(ctx.Context, ctx.PhysicalClient, pPod, vPod)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, syncerrors.NewTranslationError(err)
 	} else if updatedPod != nil {
 		translator.PrintChanges(pPod, updatedPod, ctx.Log)
 	}
@@ -420,7 +603,7 @@ func (s *podSyncer) assignNodeToPod(ctx *synccontext.SyncContext, pObj *corev1.P
 		},
 	}, metav1.CreateOptions{})
 	if err != nil {
-		s.EventRecorder().Eventf(vObj, "Warning", "SyncError", "Error binding pod: %v", err)
+		s.EventRecorder().Eventf(vObj, "Warning", syncerrors.Reason(err), "Error binding pod: %v", err)
 		return err
 	}
 
@@ -441,6 +624,28 @@ func (s *podSyncer) assignNodeToPod(ctx *synccontext.SyncContext, pObj *corev1.P
 	return err
 }
 
+// updateVirtualStatus updates the status of the virtual pod to the status of the stripped physical pod
+func (s *podSyncer) updateVirtualStatus(ctx *synccontext.SyncContext, vObj client.Object, vPod, strippedPod *corev1.Pod) (bool, error) {
+	if equality.Semantic.DeepEqual(vPod.Status, strippedPod.Status) {
+		return false, nil
+	}
+
+	newPod := vPod.DeepCopy()
+	newPod.Status = strippedPod.Status
+	ctx.Log.Infof("update virtual pod %s/%s, because status has changed", vPod.Namespace, vPod.Name)
+	translator.PrintChanges(vPod, newPod, ctx.Log)
+	err := ctx.VirtualClient.Status().Update(ctx.Context, newPod)
+	if err != nil {
+		if !kerrors.IsConflict(err) {
+			s.EventRecorder().Eventf(vObj, "Warning", syncerrors.Reason(err), "Error updating pod: %v", err)
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
 func stripHostRewriteContainer(pPod *corev1.Pod) *corev1.Pod {
 	if pPod.Annotations == nil || pPod.Annotations[translatepods.HostsRewrittenAnnotation] != "true" {
 		return pPod
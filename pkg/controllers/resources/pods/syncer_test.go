@@ -5,14 +5,18 @@ import (
 	"testing"
 
 	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/pod-security-admission/api"
 	"k8s.io/utils/pointer"
 )
@@ -142,6 +146,100 @@ func TestSync(t *testing.T) {
 		"otherLabel": "abc",
 	}
 
+	vNamespaceWithLabels := vNamespace.DeepCopy()
+	vNamespaceWithLabels.Labels = map[string]string{
+		"tenant": "team-a",
+		"other":  "value",
+	}
+	vPodWithNamespaceLabels := &corev1.Pod{
+		ObjectMeta: vObjectMeta,
+	}
+	pPodWithNamespaceLabels := pPodBase.DeepCopy()
+	pPodWithNamespaceLabels.Labels = map[string]string{
+		translate.NamespaceLabel: vObjectMeta.Namespace,
+		translate.MarkerLabel:    translate.Suffix,
+		translate.ConvertLabelKeyWithPrefix(podtranslate.NamespaceLabelPrefix, "tenant"): "team-a",
+		translate.ConvertLabelKeyWithPrefix(podtranslate.NamespaceLabelPrefix, "other"):  "value",
+		"tenant": "team-a",
+	}
+
+	pLimitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "limits",
+			Namespace: pObjectMeta.Namespace,
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypeContainer,
+					Default: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+					DefaultRequest: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				},
+			},
+		},
+	}
+	vPodWithoutLimits := &corev1.Pod{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("200m"),
+						},
+					},
+				},
+			},
+		},
+	}
+	vPodWithHostResources := vPodWithoutLimits.DeepCopy()
+	vPodWithHostResources.Annotations = map[string]string{
+		podtranslate.HostResourcesAnnotation: `{"test":{"limits":{"memory":"512Mi"},"requests":{"cpu":"200m","memory":"128Mi"}}}`,
+	}
+	pPodWithLimitRangeDefaults := pPodBase.DeepCopy()
+	pPodWithLimitRangeDefaults.Spec.Containers = []corev1.Container{
+		{
+			Name: "test",
+			Env:  pPodContainerEnv,
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("200m"),
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			},
+		},
+	}
+
+	vPodWithRuntimeClass := &corev1.Pod{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.PodSpec{
+			RuntimeClassName: pointer.String("gvisor"),
+		},
+	}
+	vPodWithExternalScheduler := &corev1.Pod{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.PodSpec{
+			SchedulerName: "tenant-scheduler",
+		},
+	}
+	vRuntimeClass := &nodev1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gvisor",
+		},
+		Handler: "runsc",
+	}
+	pPodWithRuntimeClass := pPodBase.DeepCopy()
+	pPodWithRuntimeClass.Spec.RuntimeClassName = pointer.String("gvisor")
+
 	// pod security standards test objects
 	vPodPSS := &corev1.Pod{
 		ObjectMeta: vObjectMeta,
@@ -396,6 +494,28 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	pDualStackPod := pInjectedPod.DeepCopy()
+	pDualStackPod.Status.PodIP = "10.244.0.5"
+	pDualStackPod.Status.PodIPs = []corev1.PodIP{{IP: "10.244.0.5"}, {IP: "fd00:10:244::5"}}
+	vDualStackPod := vNotInjectedPod.DeepCopy()
+	vDualStackPod.Status.PodIP = pDualStackPod.Status.PodIP
+	vDualStackPod.Status.PodIPs = pDualStackPod.Status.PodIPs
+
+	deletionTimestamp := metav1.Now()
+	gracePeriod := int64(30)
+	vTerminatingPod := vDualStackPod.DeepCopy()
+	vTerminatingPod.DeletionTimestamp = &deletionTimestamp
+	vTerminatingPod.DeletionGracePeriodSeconds = &gracePeriod
+	vTerminatingPod.Finalizers = []string{"test"}
+	vTerminatingPod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	pTerminatingPod := pDualStackPod.DeepCopy()
+	pTerminatingPod.DeletionTimestamp = &deletionTimestamp
+	pTerminatingPod.DeletionGracePeriodSeconds = &gracePeriod
+	pTerminatingPod.Finalizers = []string{"test"}
+	pTerminatingPod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}
+	vTerminatedPod := vTerminatingPod.DeepCopy()
+	vTerminatedPod.Status.Conditions = pTerminatingPod.Status.Conditions
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name:                 "Delete virtual pod",
@@ -435,6 +555,94 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Sync namespace labels",
+			InitialVirtualState:  []runtime.Object{vPodWithNamespaceLabels.DeepCopy(), vNamespaceWithLabels.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vPodWithNamespaceLabels.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {pPodWithNamespaceLabels},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.SyncNamespaceLabels = []string{"tenant"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).SyncDown(syncCtx, vPodWithNamespaceLabels.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Apply host limit range defaults",
+			InitialVirtualState:  []runtime.Object{vPodWithoutLimits.DeepCopy(), vNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy(), pLimitRange.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vPodWithHostResources.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {pPodWithLimitRangeDefaults},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.HostLimitRangeDefaults = true
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				vPod := &corev1.Pod{}
+				assert.NilError(t, syncCtx.VirtualClient.Get(syncCtx.Context, types.NamespacedName{Name: vObjectMeta.Name, Namespace: vObjectMeta.Namespace}, vPod))
+				_, err := syncer.(*podSyncer).SyncDown(syncCtx, vPod)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync with existing runtime class",
+			InitialVirtualState:  []runtime.Object{vPodWithRuntimeClass.DeepCopy(), vRuntimeClass.DeepCopy(), vNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vPodWithRuntimeClass.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {pPodWithRuntimeClass},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Controllers.Insert("runtimeclasses")
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).SyncDown(syncCtx, vPodWithRuntimeClass.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Hold pods of external scheduler until bound",
+			InitialVirtualState:  []runtime.Object{vPodWithExternalScheduler.DeepCopy(), vNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vPodWithExternalScheduler.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.ExternalSchedulers = []string{"tenant-scheduler"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).SyncDown(syncCtx, vPodWithExternalScheduler.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync with missing runtime class",
+			InitialVirtualState:  []runtime.Object{vPodWithRuntimeClass.DeepCopy(), vNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vPodWithRuntimeClass.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Controllers.Insert("runtimeclasses")
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				result, err := syncer.(*podSyncer).SyncDown(syncCtx, vPodWithRuntimeClass.DeepCopy())
+				assert.NilError(t, err)
+				assert.Assert(t, result.RequeueAfter > 0)
+			},
+		},
 		{
 			Name:                 "SyncDown pods without any pod security standards",
 			InitialVirtualState:  []runtime.Object{vPodPSS.DeepCopy(), vNamespace.DeepCopy()},
@@ -515,5 +723,104 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Sync dual-stack pod ips",
+			InitialVirtualState:  []runtime.Object{vNotInjectedPod.DeepCopy(), vInjectedPodNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pDualStackPod.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vDualStackPod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				synccontext, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).Sync(synccontext, pDualStackPod.DeepCopy(), vNotInjectedPod.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync status of terminating pod",
+			InitialVirtualState:  []runtime.Object{vTerminatingPod.DeepCopy(), vInjectedPodNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pTerminatingPod.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vTerminatedPod},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				synccontext, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				vPod := &corev1.Pod{}
+				assert.NilError(t, synccontext.VirtualClient.Get(synccontext.Context, types.NamespacedName{Name: vTerminatingPod.Name, Namespace: vTerminatingPod.Namespace}, vPod))
+				_, err := syncer.(*podSyncer).Sync(synccontext, pTerminatingPod.DeepCopy(), vPod)
+				assert.NilError(t, err)
+			},
+		},
 	})
 }
+
+func TestPriority(t *testing.T) {
+	now := metav1.Now()
+	testCases := []struct {
+		name            string
+		pod             *corev1.Pod
+		deleted         bool
+		enableScheduler bool
+
+		expectedPriority int
+	}{
+		{
+			name:             "regular pod",
+			pod:              &corev1.Pod{},
+			expectedPriority: syncer.PriorityDefault,
+		},
+		{
+			name:             "deleted pod",
+			pod:              &corev1.Pod{},
+			deleted:          true,
+			expectedPriority: syncer.PriorityDeletion,
+		},
+		{
+			name:             "terminating pod",
+			pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			expectedPriority: syncer.PriorityDeletion,
+		},
+		{
+			name:             "system critical priority class",
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{PriorityClassName: "system-node-critical"}},
+			expectedPriority: syncer.PriorityHigh,
+		},
+		{
+			name:             "system critical priority",
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{Priority: pointer.Int32(2000001000)}},
+			expectedPriority: syncer.PriorityHigh,
+		},
+		{
+			name:             "user priority",
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{Priority: pointer.Int32(1000)}},
+			expectedPriority: syncer.PriorityDefault,
+		},
+		{
+			name:             "bound pod without scheduler",
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node1"}},
+			expectedPriority: syncer.PriorityDefault,
+		},
+		{
+			name:             "bound pod with scheduler",
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node1"}},
+			enableScheduler:  true,
+			expectedPriority: syncer.PriorityHigh,
+		},
+		{
+			name:             "bound pod with external scheduler",
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node1", SchedulerName: "tenant-scheduler"}},
+			expectedPriority: syncer.PriorityHigh,
+		},
+		{
+			name:             "started pod with scheduler",
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node1"}, Status: corev1.PodStatus{StartTime: &now}},
+			enableScheduler:  true,
+			expectedPriority: syncer.PriorityDefault,
+		},
+	}
+
+	for _, testCase := range testCases {
+		priority := (&podSyncer{enableScheduler: testCase.enableScheduler, externalSchedulers: map[string]bool{"tenant-scheduler": true}}).Priority(testCase.pod, testCase.deleted)
+		assert.Equal(t, priority, testCase.expectedPriority, "unexpected priority in test case %s", testCase.name)
+	}
+}
@@ -27,6 +27,36 @@ func (s *podSyncer) translate(ctx *synccontext.SyncContext, vPod *corev1.Pod) (*
 	return pPod, err
 }
 
+// updateHostResourcesAnnotation reflects the resources of the containers of pPod that differ from
+// vPod in the host resources annotation of vPod and returns if vPod was changed
+func (s *podSyncer) updateHostResourcesAnnotation(ctx *synccontext.SyncContext, vPod, pPod *corev1.Pod) (bool, error) {
+	// compare the resources with the virtual resource names
+	hostResources, err := podtranslate.HostResources(vPod, s.resourceNames.PodToVirtual(pPod))
+	if err != nil {
+		return false, err
+	} else if hostResources == vPod.Annotations[podtranslate.HostResourcesAnnotation] {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(vPod.DeepCopy())
+	if hostResources == "" {
+		delete(vPod.Annotations, podtranslate.HostResourcesAnnotation)
+	} else {
+		if vPod.Annotations == nil {
+			vPod.Annotations = map[string]string{}
+		}
+		vPod.Annotations[podtranslate.HostResourcesAnnotation] = hostResources
+	}
+
+	ctx.Log.Infof("update host resources annotation of virtual pod %s/%s", vPod.Namespace, vPod.Name)
+	err = ctx.VirtualClient.Patch(ctx.Context, vPod, patch)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (s *podSyncer) getK8sIPDNSIPServiceList(ctx *synccontext.SyncContext, vPod *corev1.Pod) (string, string, []*corev1.Service, error) {
 	kubeIP, err := s.findKubernetesIP(ctx)
 	if err != nil {
@@ -84,6 +114,11 @@ func (s *podSyncer) findKubernetesIP(ctx *synccontext.SyncContext) (string, erro
 }
 
 func (s *podSyncer) findKubernetesDNSIP(ctx *synccontext.SyncContext) (string, error) {
+	if s.dnsServiceIP != "" && specialservices.Default.GetDNSServiceSuffix() == nil {
+		// the host dns service is created with the configured ip, pods don't need to wait for it
+		return s.dnsServiceIP, nil
+	}
+
 	serviceName := specialservices.DefaultKubeDNSServiceName
 	serviceNamespace := specialservices.DefaultKubeDNSServiceNamespace
 
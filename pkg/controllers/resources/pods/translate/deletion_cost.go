@@ -0,0 +1,22 @@
+package translate
+
+import corev1 "k8s.io/api/core/v1"
+
+// HostPodDeletionCost returns the pod deletion cost of the physical pod and if it was changed
+// on the host since it was last synced. Changes within the virtual cluster take precedence,
+// so the cost is only reported as changed if the virtual pod still has the last synced cost.
+// The returned bool tells if the physical pod has a pod deletion cost at all.
+func HostPodDeletionCost(vPod, pPod *corev1.Pod) (string, bool, bool) {
+	pCost, pOk := pPod.Annotations[PodDeletionCostAnnotation]
+	lastCost, lastOk := pPod.Annotations[SyncedPodDeletionCostAnnotation]
+	if pCost == lastCost && pOk == lastOk {
+		return "", false, false
+	}
+
+	vCost, vOk := vPod.Annotations[PodDeletionCostAnnotation]
+	if vCost != lastCost || vOk != lastOk {
+		return "", false, false
+	}
+
+	return pCost, pOk, true
+}
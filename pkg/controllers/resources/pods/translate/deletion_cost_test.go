@@ -0,0 +1,71 @@
+package translate
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHostPodDeletionCost(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		vAnnotations map[string]string
+		pAnnotations map[string]string
+
+		expectedCost    string
+		expectedExists  bool
+		expectedChanged bool
+	}{
+		{
+			name: "no cost",
+		},
+		{
+			name:         "in sync",
+			vAnnotations: map[string]string{PodDeletionCostAnnotation: "10"},
+			pAnnotations: map[string]string{PodDeletionCostAnnotation: "10", SyncedPodDeletionCostAnnotation: "10"},
+		},
+		{
+			name:         "changed in virtual cluster",
+			vAnnotations: map[string]string{PodDeletionCostAnnotation: "20"},
+			pAnnotations: map[string]string{PodDeletionCostAnnotation: "10", SyncedPodDeletionCostAnnotation: "10"},
+		},
+		{
+			name:            "changed on host",
+			vAnnotations:    map[string]string{PodDeletionCostAnnotation: "10"},
+			pAnnotations:    map[string]string{PodDeletionCostAnnotation: "-5", SyncedPodDeletionCostAnnotation: "10"},
+			expectedCost:    "-5",
+			expectedExists:  true,
+			expectedChanged: true,
+		},
+		{
+			name:            "added on host",
+			pAnnotations:    map[string]string{PodDeletionCostAnnotation: "5"},
+			expectedCost:    "5",
+			expectedExists:  true,
+			expectedChanged: true,
+		},
+		{
+			name:            "removed on host",
+			vAnnotations:    map[string]string{PodDeletionCostAnnotation: "10"},
+			pAnnotations:    map[string]string{SyncedPodDeletionCostAnnotation: "10"},
+			expectedChanged: true,
+		},
+		{
+			name:         "changed in both",
+			vAnnotations: map[string]string{PodDeletionCostAnnotation: "20"},
+			pAnnotations: map[string]string{PodDeletionCostAnnotation: "-5", SyncedPodDeletionCostAnnotation: "10"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: testCase.vAnnotations}}
+		pPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: testCase.pAnnotations}}
+		cost, exists, changed := HostPodDeletionCost(vPod, pPod)
+		assert.Equal(t, cost, testCase.expectedCost, "unexpected cost in test case %s", testCase.name)
+		assert.Equal(t, exists, testCase.expectedExists, "unexpected exists in test case %s", testCase.name)
+		assert.Equal(t, changed, testCase.expectedChanged, "unexpected changed in test case %s", testCase.name)
+	}
+}
@@ -12,6 +12,11 @@ const (
 	PodLoggingHostPath = "/var/log/pods"
 	LogHostPath        = "/var/log"
 
+	// DockerContainersHostPath is where the docker runtime (and cri-dockerd)
+	// writes the json log files of the containers it runs. Containerd and cri-o
+	// only write to PodLoggingHostPath
+	DockerContainersHostPath = "/var/lib/docker/containers"
+
 	KubeletPodPath = "/var/lib/kubelet/pods"
 
 	PhysicalVolumeNameSuffix = "vcluster-physical"
@@ -19,6 +24,8 @@ const (
 	PhysicalLogVolumeMountPath     = "/var/vcluster/physical/log"
 	PhysicalPodLogVolumeMountPath  = "/var/vcluster/physical/log/pods"
 	PhysicalKubeletVolumeMountPath = "/var/vcluster/physical/kubelet/pods"
+
+	PhysicalDockerContainersVolumeMountPath = "/var/vcluster/physical/docker/containers"
 )
 
 func (t *translator) rewriteHostPaths(pPod *corev1.Pod) {
@@ -57,6 +64,7 @@ func (t *translator) rewriteHostPaths(pPod *corev1.Pod) {
 		kubeletMountPath := make(map[string]bool)
 		podLogMountPath := make(map[string]bool)
 		logMountPath := make(map[string]bool)
+		dockerContainersMountPath := make(map[string]bool)
 
 		for i, volume := range pPod.Spec.Volumes {
 			if volume.HostPath != nil {
@@ -100,6 +108,20 @@ func (t *translator) rewriteHostPaths(pPod *corev1.Pod) {
 					)
 				}
 
+				if t.mapDockerContainerLogs && volume.HostPath.Path == DockerContainersHostPath &&
+					!strings.HasSuffix(volume.Name, PhysicalVolumeNameSuffix) {
+					t.log.Debugf("rewriting hostPath for docker containers %s", pPod.Name)
+					pPod.Spec.Volumes[i].HostPath.Path = t.virtualDockerContainersPath
+					pPod = t.addPhysicalPathToVolumesAndCorrectContainers(
+						volume.Name,
+						volume.HostPath.Type,
+						DockerContainersHostPath,
+						PhysicalDockerContainersVolumeMountPath,
+						dockerContainersMountPath,
+						pPod,
+					)
+				}
+
 				if volume.HostPath.Path == LogHostPath {
 					pPod.Spec.Volumes[i].HostPath.Path = t.virtualLogsPath
 					pPod = t.addPhysicalPathToVolumesAndCorrectContainers(
@@ -0,0 +1,93 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HostResourcesAnnotation is set on virtual pods and holds the resources of the containers
+// that differ in the host cluster, e.g. because of the defaults of a host namespace limit range
+const HostResourcesAnnotation = "vcluster.loft.sh/host-resources"
+
+// applyLimitRangeDefaults applies the container defaults of the limit ranges in the host namespace
+// to the containers of pPod that don't define the resources themselves
+func (t *translator) applyLimitRangeDefaults(ctx context.Context, pPod *corev1.Pod) error {
+	limitRangeList := &corev1.LimitRangeList{}
+	err := t.pClient.List(ctx, limitRangeList, client.InNamespace(pPod.Namespace))
+	if err != nil {
+		return err
+	}
+
+	for _, limitRange := range limitRangeList.Items {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			for i := range pPod.Spec.InitContainers {
+				applyContainerDefaults(&pPod.Spec.InitContainers[i].Resources, item)
+			}
+			for i := range pPod.Spec.Containers {
+				applyContainerDefaults(&pPod.Spec.Containers[i].Resources, item)
+			}
+		}
+	}
+
+	return nil
+}
+
+func applyContainerDefaults(resources *corev1.ResourceRequirements, item corev1.LimitRangeItem) {
+	for name, value := range item.Default {
+		if _, ok := resources.Limits[name]; !ok {
+			if resources.Limits == nil {
+				resources.Limits = corev1.ResourceList{}
+			}
+			resources.Limits[name] = value.DeepCopy()
+		}
+	}
+	for name, value := range item.DefaultRequest {
+		if _, ok := resources.Requests[name]; !ok {
+			if resources.Requests == nil {
+				resources.Requests = corev1.ResourceList{}
+			}
+			resources.Requests[name] = value.DeepCopy()
+		}
+	}
+}
+
+// HostResources returns the value of the HostResourcesAnnotation for vPod, which holds the
+// resources of all containers that differ in pPod, or an empty string if there are none
+func HostResources(vPod, pPod *corev1.Pod) (string, error) {
+	vResources := map[string]corev1.ResourceRequirements{}
+	for _, containers := range [][]corev1.Container{vPod.Spec.InitContainers, vPod.Spec.Containers} {
+		for _, container := range containers {
+			vResources[container.Name] = container.Resources
+		}
+	}
+
+	hostResources := map[string]corev1.ResourceRequirements{}
+	for _, containers := range [][]corev1.Container{pPod.Spec.InitContainers, pPod.Spec.Containers} {
+		for _, container := range containers {
+			vContainerResources, ok := vResources[container.Name]
+			if !ok || equality.Semantic.DeepEqual(vContainerResources, container.Resources) {
+				continue
+			}
+
+			hostResources[container.Name] = container.Resources
+		}
+	}
+	if len(hostResources) == 0 {
+		return "", nil
+	}
+
+	out, err := json.Marshal(hostResources)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,146 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SCCUIDRangeAnnotation is set by OpenShift on namespaces and holds the user ids pods in the
+	// namespace are allowed to run as with the restricted security context constraints
+	SCCUIDRangeAnnotation = "openshift.io/sa.scc.uid-range"
+	// SCCSupplementalGroupsAnnotation is set by OpenShift on namespaces and holds the groups pods
+	// in the namespace are allowed to use as fsGroup and supplemental groups
+	SCCSupplementalGroupsAnnotation = "openshift.io/sa.scc.supplemental-groups"
+	// SCCAnnotation is set by OpenShift on pods and holds the security context constraints the
+	// pod was admitted with
+	SCCAnnotation = "openshift.io/scc"
+)
+
+// idRange is a range of user or group ids as used in the OpenShift namespace annotations
+type idRange struct {
+	start int64
+	size  int64
+}
+
+func (r idRange) contains(id int64) bool {
+	return id >= r.start && id < r.start+r.size
+}
+
+// parseIDRanges parses a comma separated list of ranges in the form start/size or start-end
+func parseIDRanges(value string) ([]idRange, error) {
+	ranges := []idRange{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		var (
+			start, size int64
+			err         error
+		)
+		if splitted := strings.Split(s, "/"); len(splitted) == 2 {
+			start, err = strconv.ParseInt(splitted[0], 10, 64)
+			if err == nil {
+				size, err = strconv.ParseInt(splitted[1], 10, 64)
+			}
+		} else if splitted := strings.Split(s, "-"); len(splitted) == 2 {
+			var end int64
+			start, err = strconv.ParseInt(splitted[0], 10, 64)
+			if err == nil {
+				end, err = strconv.ParseInt(splitted[1], 10, 64)
+				size = end - start + 1
+			}
+		} else {
+			err = fmt.Errorf("expected start/size or start-end")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid id range %s: %w", s, err)
+		} else if start < 0 || size <= 0 {
+			return nil, fmt.Errorf("invalid id range %s", s)
+		}
+
+		ranges = append(ranges, idRange{start: start, size: size})
+	}
+
+	return ranges, nil
+}
+
+func inRanges(ranges []idRange, id int64) bool {
+	for _, r := range ranges {
+		if r.contains(id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applySCCRanges reads the uid and group ranges OpenShift assigned to the host namespace and
+// changes the security context of pPod, so it is admitted by the restricted security context constraints
+func (t *translator) applySCCRanges(ctx context.Context, pPod *corev1.Pod) error {
+	pNamespace := &corev1.Namespace{}
+	err := t.pAPIReader.Get(ctx, client.ObjectKey{Name: pPod.Namespace}, pNamespace)
+	if err != nil {
+		return err
+	}
+
+	uidRanges, err := parseIDRanges(pNamespace.Annotations[SCCUIDRangeAnnotation])
+	if err != nil {
+		return err
+	}
+	groupRanges, err := parseIDRanges(pNamespace.Annotations[SCCSupplementalGroupsAnnotation])
+	if err != nil {
+		return err
+	}
+	if len(groupRanges) == 0 {
+		// OpenShift falls back to the uid range if there are no supplemental groups
+		groupRanges = uidRanges
+	}
+
+	fitSecurityContext(pPod, uidRanges, groupRanges)
+	return nil
+}
+
+// fitSecurityContext changes user and group ids of the pod that are outside of the given ranges.
+// The pod user is moved to the start of the uid range, container users are cleared so they inherit
+// it, the fsGroup is moved to the start of the group range and other supplemental groups are removed.
+func fitSecurityContext(pPod *corev1.Pod, uidRanges, groupRanges []idRange) {
+	if len(uidRanges) > 0 {
+		if pPod.Spec.SecurityContext != nil && pPod.Spec.SecurityContext.RunAsUser != nil && !inRanges(uidRanges, *pPod.Spec.SecurityContext.RunAsUser) {
+			runAsUser := uidRanges[0].start
+			pPod.Spec.SecurityContext.RunAsUser = &runAsUser
+		}
+
+		for _, containers := range [][]corev1.Container{pPod.Spec.InitContainers, pPod.Spec.Containers} {
+			for i := range containers {
+				if containers[i].SecurityContext != nil && containers[i].SecurityContext.RunAsUser != nil && !inRanges(uidRanges, *containers[i].SecurityContext.RunAsUser) {
+					containers[i].SecurityContext.RunAsUser = nil
+				}
+			}
+		}
+	}
+
+	if len(groupRanges) > 0 && pPod.Spec.SecurityContext != nil {
+		if pPod.Spec.SecurityContext.FSGroup != nil && !inRanges(groupRanges, *pPod.Spec.SecurityContext.FSGroup) {
+			fsGroup := groupRanges[0].start
+			pPod.Spec.SecurityContext.FSGroup = &fsGroup
+		}
+
+		supplementalGroups := []int64{}
+		for _, group := range pPod.Spec.SecurityContext.SupplementalGroups {
+			if inRanges(groupRanges, group) {
+				supplementalGroups = append(supplementalGroups, group)
+			}
+		}
+		if len(supplementalGroups) != len(pPod.Spec.SecurityContext.SupplementalGroups) {
+			pPod.Spec.SecurityContext.SupplementalGroups = supplementalGroups
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package translate
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseIDRanges(t *testing.T) {
+	ranges, err := parseIDRanges("1000680000/10000, 2000-2009")
+	assert.NilError(t, err)
+	assert.Equal(t, len(ranges), 2)
+	assert.Equal(t, ranges[0], idRange{start: 1000680000, size: 10000})
+	assert.Equal(t, ranges[1], idRange{start: 2000, size: 10})
+
+	ranges, err = parseIDRanges("")
+	assert.NilError(t, err)
+	assert.Equal(t, len(ranges), 0)
+
+	for _, invalid := range []string{"1000", "a/10", "1000/0", "2000-1000"} {
+		_, err = parseIDRanges(invalid)
+		assert.Assert(t, err != nil, "expected error for %s", invalid)
+	}
+}
+
+func TestFitSecurityContext(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+	uidRanges := []idRange{{start: 1000680000, size: 10000}}
+	groupRanges := []idRange{{start: 1000680000, size: 10000}}
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser:          int64Ptr(1000),
+				FSGroup:            int64Ptr(2000),
+				SupplementalGroups: []int64{3000, 1000680001},
+			},
+			Containers: []corev1.Container{
+				{Name: "outside", SecurityContext: &corev1.SecurityContext{RunAsUser: int64Ptr(0)}},
+				{Name: "inside", SecurityContext: &corev1.SecurityContext{RunAsUser: int64Ptr(1000680005)}},
+				{Name: "none"},
+			},
+		},
+	}
+	fitSecurityContext(pod, uidRanges, groupRanges)
+	assert.Equal(t, *pod.Spec.SecurityContext.RunAsUser, int64(1000680000))
+	assert.Equal(t, *pod.Spec.SecurityContext.FSGroup, int64(1000680000))
+	assert.DeepEqual(t, pod.Spec.SecurityContext.SupplementalGroups, []int64{1000680001})
+	assert.Assert(t, pod.Spec.Containers[0].SecurityContext.RunAsUser == nil)
+	assert.Equal(t, *pod.Spec.Containers[1].SecurityContext.RunAsUser, int64(1000680005))
+	assert.Assert(t, pod.Spec.Containers[2].SecurityContext == nil)
+
+	// pods without ranges are not changed
+	pod = &corev1.Pod{Spec: corev1.PodSpec{SecurityContext: &corev1.PodSecurityContext{RunAsUser: int64Ptr(1000)}}}
+	fitSecurityContext(pod, nil, nil)
+	assert.Equal(t, *pod.Spec.SecurityContext.RunAsUser, int64(1000))
+}
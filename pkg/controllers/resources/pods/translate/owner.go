@@ -0,0 +1,45 @@
+package translate
+
+import (
+	"encoding/json"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OwnerKindLabel, OwnerNameLabel and OwnerUIDLabel are set on physical pods and hold the
+	// controller owner of the virtual pod, so host side tooling can group pods by workload
+	OwnerKindLabel = "vcluster.loft.sh/owner-kind"
+	OwnerNameLabel = "vcluster.loft.sh/owner-name"
+	OwnerUIDLabel  = "vcluster.loft.sh/owner-uid"
+
+	// OwnerAnnotation holds the full controller owner reference of the virtual pod, as the name
+	// in OwnerNameLabel is shortened if it's not a valid label value
+	OwnerAnnotation = "vcluster.loft.sh/owner"
+)
+
+// translateOwner adds the controller owner of vPod to the labels and annotations of the physical
+// pod or removes it if vPod has no controller owner
+func translateOwner(vPod *corev1.Pod, labels, annotations map[string]string) {
+	delete(labels, OwnerKindLabel)
+	delete(labels, OwnerNameLabel)
+	delete(labels, OwnerUIDLabel)
+	delete(annotations, OwnerAnnotation)
+
+	owner := metav1.GetControllerOf(vPod)
+	if owner == nil {
+		return
+	}
+
+	out, err := json.Marshal(owner)
+	if err != nil {
+		return
+	}
+
+	labels[OwnerKindLabel] = owner.Kind
+	labels[OwnerNameLabel] = translate.SafeConcatName(owner.Name)
+	labels[OwnerUIDLabel] = string(owner.UID)
+	annotations[OwnerAnnotation] = string(out)
+}
@@ -0,0 +1,59 @@
+package translate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+)
+
+func TestTranslateOwner(t *testing.T) {
+	longName := strings.Repeat("a", 70)
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "not-a-controller",
+					UID:        "other",
+				},
+				{
+					APIVersion: "apps/v1",
+					Kind:       "ReplicaSet",
+					Name:       longName,
+					UID:        "1234",
+					Controller: pointer.Bool(true),
+				},
+			},
+		},
+	}
+
+	labels := map[string]string{"app": "test"}
+	annotations := map[string]string{}
+	translateOwner(vPod, labels, annotations)
+	assert.Equal(t, labels[OwnerKindLabel], "ReplicaSet")
+	assert.Equal(t, labels[OwnerUIDLabel], "1234")
+	assert.Equal(t, len(validation.IsValidLabelValue(labels[OwnerNameLabel])), 0)
+
+	owner := &metav1.OwnerReference{}
+	err := json.Unmarshal([]byte(annotations[OwnerAnnotation]), owner)
+	assert.NilError(t, err)
+	assert.Equal(t, owner.APIVersion, "apps/v1")
+	assert.Equal(t, owner.Kind, "ReplicaSet")
+	assert.Equal(t, owner.Name, longName)
+	assert.Equal(t, string(owner.UID), "1234")
+
+	// owners are removed if the pod has no controller anymore
+	vPod.OwnerReferences = vPod.OwnerReferences[:1]
+	translateOwner(vPod, labels, annotations)
+	assert.DeepEqual(t, labels, map[string]string{"app": "test"})
+	assert.DeepEqual(t, annotations, map[string]string{})
+}
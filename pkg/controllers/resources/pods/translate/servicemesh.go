@@ -0,0 +1,31 @@
+package translate
+
+import "strings"
+
+// ServiceMeshLabelPrefixes are the prefixes of the labels service meshes add to host pods when
+// injecting their sidecars, e.g. security.istio.io/tlsMode
+var ServiceMeshLabelPrefixes = []string{
+	"istio.io/",
+	"security.istio.io/",
+	"service.istio.io/",
+	"sidecar.istio.io/",
+	"topology.istio.io/",
+	"linkerd.io/",
+}
+
+// preserveServiceMeshLabels copies the service mesh labels of the host pod to labels, so the
+// syncer doesn't remove the labels the sidecar injector added
+func preserveServiceMeshLabels(pLabels, labels map[string]string) {
+	for k, v := range pLabels {
+		if _, ok := labels[k]; ok {
+			continue
+		}
+
+		for _, prefix := range ServiceMeshLabelPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				labels[k] = v
+				break
+			}
+		}
+	}
+}
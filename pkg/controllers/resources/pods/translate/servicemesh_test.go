@@ -0,0 +1,45 @@
+package translate
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPreserveServiceMeshLabels(t *testing.T) {
+	pLabels := map[string]string{
+		"security.istio.io/tlsMode":          "istio",
+		"service.istio.io/canonical-name":    "reviews",
+		"vcluster.loft.sh/label-test-x-1234": "stale",
+		"app":                                "host",
+	}
+	labels := map[string]string{
+		"service.istio.io/canonical-name": "virtual",
+	}
+
+	preserveServiceMeshLabels(pLabels, labels)
+	assert.DeepEqual(t, labels, map[string]string{
+		"security.istio.io/tlsMode":       "istio",
+		"service.istio.io/canonical-name": "virtual",
+	})
+}
+
+func TestContainerImageDiffKeepsInjectedContainers(t *testing.T) {
+	imageTranslator, err := NewImageTranslator(nil)
+	assert.NilError(t, err)
+
+	pContainers := []corev1.Container{
+		{Name: "app", Image: "app:v1"},
+		{Name: "istio-proxy", Image: "istio/proxyv2"},
+	}
+	vContainers := []corev1.Container{
+		{Name: "app", Image: "app:v2"},
+	}
+
+	assert.DeepEqual(t, calcContainerImageDiff(pContainers, vContainers, imageTranslator, nil), []corev1.Container{
+		{Name: "app", Image: "app:v2"},
+		{Name: "istio-proxy", Image: "istio/proxyv2"},
+	})
+	assert.Assert(t, calcContainerImageDiff(pContainers, pContainers[:1], imageTranslator, nil) == nil)
+}
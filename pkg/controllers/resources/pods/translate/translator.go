@@ -16,6 +16,8 @@ import (
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	"github.com/loft-sh/vcluster/pkg/util/random"
+	"github.com/loft-sh/vcluster/pkg/util/resourcenames"
+	"github.com/loft-sh/vcluster/pkg/util/taints"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -43,13 +45,21 @@ const (
 	ClusterAutoScalerDaemonSetAnnotation = "cluster-autoscaler.kubernetes.io/daemonset-pod"
 	ServiceAccountNameAnnotation         = "vcluster.loft.sh/service-account-name"
 	ServiceAccountTokenAnnotation        = "vcluster.loft.sh/token-"
+	PodDeletionCostAnnotation            = "controller.kubernetes.io/pod-deletion-cost"
+	SyncedPodDeletionCostAnnotation      = "vcluster.loft.sh/synced-pod-deletion-cost"
+)
+
+// limits of the dns config of a pod enforced by the api server
+const (
+	maxDNSNameservers     = 3
+	maxDNSSearchPaths     = 32
+	maxDNSSearchListChars = 2048
 )
 
 var (
 	FieldPathLabelRegEx      = regexp.MustCompile(`^metadata\.labels\['(.+)'\]$`)
 	FieldPathAnnotationRegEx = regexp.MustCompile(`^metadata\.annotations\['(.+)'\]$`)
 	False                    = false
-	maxPriority              = int32(1000000000)
 )
 
 type Translator interface {
@@ -71,33 +81,65 @@ func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventR
 	virtualPath := fmt.Sprintf(VirtualPathTemplate, ctx.CurrentNamespace, name)
 	virtualLogsPath := path.Join(virtualPath, "log")
 	virtualKubeletPath := path.Join(virtualPath, "kubelet")
+	virtualDockerPath := path.Join(virtualPath, "docker")
+
+	hostServiceAccountTokenAudiences := map[string]bool{}
+	for _, audience := range ctx.Options.HostServiceAccountTokenAudiences {
+		hostServiceAccountTokenAudiences[audience] = true
+	}
+
+	externalSchedulers := map[string]bool{}
+	for _, schedulerName := range ctx.Options.ExternalSchedulers {
+		externalSchedulers[schedulerName] = true
+	}
+
+	taintRules, err := taints.ParseRules(ctx.Options.HideNodeTaints, ctx.Options.NodeTaintRewrites)
+	if err != nil {
+		return nil, err
+	}
 
 	return &translator{
 		vClientConfig: ctx.VirtualManager.GetConfig(),
 		vClient:       ctx.VirtualManager.GetClient(),
 
 		pClient:         ctx.PhysicalManager.GetClient(),
+		pAPIReader:      ctx.PhysicalManager.GetAPIReader(),
 		imageTranslator: imageTranslator,
 		eventRecorder:   eventRecorder,
 		log:             loghelper.New("pods-syncer-translator"),
 
 		defaultImageRegistry: ctx.Options.DefaultImageRegistry,
 
-		serviceAccountSecretsEnabled: ctx.Options.ServiceAccountTokenSecrets,
-		clusterDomain:                ctx.Options.ClusterDomain,
-		serviceAccount:               ctx.Options.ServiceAccount,
-		overrideHosts:                ctx.Options.OverrideHosts,
-		overrideHostsImage:           ctx.Options.OverrideHostsContainerImage,
-		serviceAccountsEnabled:       ctx.Controllers.Has("serviceaccounts"),
-		priorityClassesEnabled:       ctx.Controllers.Has("priorityclasses"),
-		enableScheduler:              ctx.Options.EnableScheduler,
-		syncedLabels:                 ctx.Options.SyncLabels,
+		serviceAccountSecretsEnabled:     ctx.Options.ServiceAccountTokenSecrets,
+		hostServiceAccountTokenAudiences: hostServiceAccountTokenAudiences,
+		clusterDomain:                    ctx.Options.ClusterDomain,
+		serviceAccount:                   ctx.Options.ServiceAccount,
+		overrideHosts:                    ctx.Options.OverrideHosts,
+		overrideHostsImage:               ctx.Options.OverrideHostsContainerImage,
+		serviceAccountsEnabled:           ctx.Controllers.Has("serviceaccounts"),
+		priorityClassesEnabled:           ctx.Controllers.Has("priorityclasses"),
+		priorityClassMinValue:            ctx.Options.PriorityClassMinValue,
+		priorityClassMaxValue:            ctx.Options.PriorityClassMaxValue,
+		enableScheduler:                  ctx.Options.EnableScheduler,
+		externalSchedulers:               externalSchedulers,
+		syncedLabels:                     ctx.Options.SyncLabels,
+		syncedNamespaceLabels:            ctx.Options.SyncNamespaceLabels,
+		userAnnotation:                   ctx.Options.UserAnnotation,
+		limitRangeDefaults:               ctx.Options.HostLimitRangeDefaults,
+		serviceMeshMode:                  ctx.Options.ServiceMeshMode,
+		ownerLabels:                      ctx.Options.OwnerLabels,
+		resourceNames:                    ctx.ResourceNames,
+		taintRules:                       taintRules,
+		openshiftMode:                    ctx.Options.OpenshiftMode,
 
 		rewriteVirtualHostPaths: ctx.Options.RewriteHostPaths,
 		virtualLogsPath:         virtualLogsPath,
 		virtualPodLogsPath:      filepath.Join(virtualLogsPath, "pods"),
 		virtualKubeletPodPath:   filepath.Join(virtualKubeletPath, "pods"),
 
+		mapDockerContainerLogs:      ctx.Options.MapDockerContainerLogs,
+		virtualDockerContainersPath: filepath.Join(virtualDockerPath, "containers"),
+
 		projectedVolumeSAToken: make(map[string]string),
 	}, nil
 }
@@ -106,27 +148,52 @@ type translator struct {
 	vClientConfig   *rest.Config
 	vClient         client.Client
 	pClient         client.Client
+	pAPIReader      client.Reader
 	imageTranslator ImageTranslator
 	eventRecorder   record.EventRecorder
 	log             loghelper.Logger
 
 	defaultImageRegistry string
 
+	// resourceNames maps the extended resource names of the containers to the host cluster
+	resourceNames *resourcenames.Mapping
+
+	// taintRules are the rules that rewrite the taints of synced nodes, their tolerations are mapped back
+	taintRules []taints.Rule
+
 	serviceAccountsEnabled       bool
 	serviceAccountSecretsEnabled bool
-	clusterDomain                string
-	serviceAccount               string
-	overrideHosts                bool
-	overrideHostsImage           string
-	priorityClassesEnabled       bool
-	enableScheduler              bool
-	syncedLabels                 []string
+	// hostServiceAccountTokenAudiences are the audiences of projected service account tokens
+	// that are issued by the host cluster for the synced service account
+	hostServiceAccountTokenAudiences map[string]bool
+	clusterDomain                    string
+	serviceAccount                   string
+	overrideHosts                    bool
+	overrideHostsImage               string
+	priorityClassesEnabled           bool
+	priorityClassMinValue            int32
+	priorityClassMaxValue            int32
+	enableScheduler                  bool
+	// externalSchedulers are the names of the schedulers tenants run inside the virtual cluster
+	externalSchedulers    map[string]bool
+	syncedLabels          []string
+	syncedNamespaceLabels []string
+	userAnnotation        string
+	limitRangeDefaults    bool
+	serviceMeshMode       bool
+	ownerLabels           bool
+	// openshiftMode fits the security context of pods to the uid and group ranges of the host namespace
+	openshiftMode bool
 
 	rewriteVirtualHostPaths bool
 	virtualLogsPath         string
 	virtualPodLogsPath      string
 	virtualKubeletPodPath   string
 
+	// mapDockerContainerLogs is only set if the hostpath mapper populates the virtual docker containers path
+	mapDockerContainerLogs      bool
+	virtualDockerContainersPath string
+
 	projectedVolumeSAToken map[string]string
 }
 
@@ -143,6 +210,8 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 
 	// override pod fields
 	pPod.Status = corev1.PodStatus{}
+	t.resourceNames.PodToPhysical(pPod)
+	t.translateTolerations(pPod)
 	pPod.Spec.DeprecatedServiceAccount = ""
 	pPod.Spec.ServiceAccountName = t.serviceAccount
 	if t.serviceAccountsEnabled {
@@ -158,16 +227,7 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 	pPod.Spec.AutomountServiceAccountToken = &False
 	pPod.Spec.EnableServiceLinks = &False
 
-	// check if priority classes are enabled
-	if !t.priorityClassesEnabled {
-		pPod.Spec.PriorityClassName = ""
-		pPod.Spec.Priority = nil
-	} else if pPod.Spec.PriorityClassName != "" {
-		pPod.Spec.PriorityClassName = priorityclasses.NewPriorityClassTranslator()(pPod.Spec.PriorityClassName, nil)
-		if pPod.Spec.Priority != nil && *pPod.Spec.Priority > maxPriority {
-			pPod.Spec.Priority = &maxPriority
-		}
-	}
+	t.translatePriority(pPod)
 
 	// Add an annotation for namespace, name and uid
 	if pPod.Annotations == nil {
@@ -197,6 +257,18 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 	if _, ok := pPod.Annotations[LabelsAnnotation]; !ok {
 		pPod.Annotations[LabelsAnnotation] = translateLabelsAnnotation(vPod)
 	}
+	delete(pPod.Annotations, HostResourcesAnnotation)
+	if cost, ok := vPod.Annotations[PodDeletionCostAnnotation]; ok {
+		pPod.Annotations[SyncedPodDeletionCostAnnotation] = cost
+	}
+	user, err := t.translateUser(ctx, vPod)
+	if err != nil {
+		return nil, err
+	} else if user != "" {
+		pPod.Annotations[UserAnnotation] = user
+	} else {
+		delete(pPod.Annotations, UserAnnotation)
+	}
 	if _, ok := pPod.Annotations[ClusterAutoScalerAnnotation]; !ok {
 		// check if the vPod would be evictable
 		controller := metav1.GetControllerOf(vPod)
@@ -212,8 +284,9 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 	if updatedLabels == nil {
 		updatedLabels = map[string]string{}
 	}
-	for k, v := range vNamespace.GetLabels() {
-		updatedLabels[translate.ConvertLabelKeyWithPrefix(NamespaceLabelPrefix, k)] = v
+	t.translateNamespaceLabels(vNamespace, updatedLabels)
+	if t.ownerLabels {
+		translateOwner(vPod, updatedLabels, pPod.Annotations)
 	}
 	pPod.SetLabels(updatedLabels)
 
@@ -279,6 +352,22 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 		pPod.Spec.EphemeralContainers[i].Image = t.imageTranslator.Translate(pPod.Spec.EphemeralContainers[i].Image)
 	}
 
+	// apply the container defaults of the host namespace limit ranges
+	if t.limitRangeDefaults {
+		err = t.applyLimitRangeDefaults(ctx, pPod)
+		if err != nil {
+			return nil, errors.Wrap(err, "apply limit range defaults")
+		}
+	}
+
+	// fit the security context to the security context constraints of the host namespace
+	if t.openshiftMode {
+		err = t.applySCCRanges(ctx, pPod)
+		if err != nil {
+			return nil, errors.Wrap(err, "apply security context constraints ranges")
+		}
+	}
+
 	// translate image pull secrets
 	for i := range pPod.Spec.ImagePullSecrets {
 		pPod.Spec.ImagePullSecrets[i].Name = translate.Default.PhysicalName(pPod.Spec.ImagePullSecrets[i].Name, vPod.Namespace)
@@ -303,7 +392,7 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 	}
 
 	// translate topology spread constraints
-	if t.enableScheduler {
+	if t.enableScheduler || t.externalSchedulers[vPod.Spec.SchedulerName] {
 		pPod.Spec.TopologySpreadConstraints = nil
 		pPod.Spec.Affinity = nil
 		pPod.Spec.NodeSelector = nil
@@ -439,10 +528,7 @@ func (t *translator) translateProjectedVolume(ctx context.Context, projectedVolu
 			projectedVolume.Sources[i].Secret.Name = translate.Default.PhysicalName(projectedVolume.Sources[i].Secret.Name, vPod.Namespace)
 		}
 		if projectedVolume.Sources[i].ConfigMap != nil {
-			projectedVolume.Sources[i].ConfigMap.Name = translate.Default.PhysicalName(projectedVolume.Sources[i].ConfigMap.Name, vPod.Namespace)
-			if projectedVolume.Sources[i].ConfigMap.Name == "kube-root-ca.crt" {
-				projectedVolume.Sources[i].ConfigMap.Name = translate.SafeConcatName("vcluster", "kube-root-ca.crt", "x", translate.Suffix)
-			}
+			projectedVolume.Sources[i].ConfigMap.Name = configmaps.ConfigMapNameTranslator(types.NamespacedName{Name: projectedVolume.Sources[i].ConfigMap.Name, Namespace: vPod.Namespace}, nil)
 		}
 		if projectedVolume.Sources[i].DownwardAPI != nil {
 			for j := range projectedVolume.Sources[i].DownwardAPI.Items {
@@ -450,6 +536,12 @@ func (t *translator) translateProjectedVolume(ctx context.Context, projectedVolu
 			}
 		}
 		if projectedVolume.Sources[i].ServiceAccountToken != nil {
+			// the host cluster issues the token for the synced service account, which is
+			// required by integrations that trust the host issuer, e.g. IAM roles for service accounts
+			if t.serviceAccountsEnabled && t.hostServiceAccountTokenAudiences[projectedVolume.Sources[i].ServiceAccountToken.Audience] {
+				continue
+			}
+
 			serviceAccountName := "default"
 			if vPod.Spec.ServiceAccountName != "" {
 				serviceAccountName = vPod.Spec.ServiceAccountName
@@ -578,7 +670,7 @@ func TranslateContainerEnv(envVar []corev1.EnvVar, envFrom []corev1.EnvFromSourc
 	for j, env := range envVar {
 		translateDownwardAPI(&envVar[j])
 		if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name != "" {
-			envVar[j].ValueFrom.ConfigMapKeyRef.Name = translate.Default.PhysicalName(envVar[j].ValueFrom.ConfigMapKeyRef.Name, vPod.Namespace)
+			envVar[j].ValueFrom.ConfigMapKeyRef.Name = configmaps.ConfigMapNameTranslator(types.NamespacedName{Name: envVar[j].ValueFrom.ConfigMapKeyRef.Name, Namespace: vPod.Namespace}, nil)
 		}
 		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name != "" {
 			envVar[j].ValueFrom.SecretKeyRef.Name = translate.Default.PhysicalName(envVar[j].ValueFrom.SecretKeyRef.Name, vPod.Namespace)
@@ -588,7 +680,7 @@ func TranslateContainerEnv(envVar []corev1.EnvVar, envFrom []corev1.EnvFromSourc
 	}
 	for j, from := range envFrom {
 		if from.ConfigMapRef != nil && from.ConfigMapRef.Name != "" {
-			envFrom[j].ConfigMapRef.Name = translate.Default.PhysicalName(from.ConfigMapRef.Name, vPod.Namespace)
+			envFrom[j].ConfigMapRef.Name = configmaps.ConfigMapNameTranslator(types.NamespacedName{Name: from.ConfigMapRef.Name, Namespace: vPod.Namespace}, nil)
 		}
 		if from.SecretRef != nil && from.SecretRef.Name != "" {
 			envFrom[j].SecretRef.Name = translate.Default.PhysicalName(from.SecretRef.Name, vPod.Namespace)
@@ -673,12 +765,41 @@ func translateDNSClusterFirstConfig(pPod *corev1.Pod, vPod *corev1.Pod, clusterD
 	if existingDNSConfig != nil {
 		dnsConfig.Nameservers = deleteDuplicates(append(dnsConfig.Nameservers, existingDNSConfig.Nameservers...))
 		dnsConfig.Searches = deleteDuplicates(append(dnsConfig.Searches, existingDNSConfig.Searches...))
+		dnsConfig.Options = mergeDNSOptions(dnsConfig.Options, existingDNSConfig.Options)
+	}
+
+	// the api server rejects pods above these limits, so drop the last entries, which never
+	// include the vcluster dns service and its search paths
+	if len(dnsConfig.Nameservers) > maxDNSNameservers {
+		dnsConfig.Nameservers = dnsConfig.Nameservers[:maxDNSNameservers]
+	}
+	for len(dnsConfig.Searches) > maxDNSSearchPaths || len(strings.Join(dnsConfig.Searches, " ")) > maxDNSSearchListChars {
+		dnsConfig.Searches = dnsConfig.Searches[:len(dnsConfig.Searches)-1]
 	}
 
 	pPod.Spec.DNSPolicy = corev1.DNSNone
 	pPod.Spec.DNSConfig = dnsConfig
 }
 
+// mergeDNSOptions adds the options to the default options, options of the pod take precedence
+func mergeDNSOptions(defaultOptions, options []corev1.PodDNSConfigOption) []corev1.PodDNSConfigOption {
+	merged := []corev1.PodDNSConfigOption{}
+	for _, defaultOption := range defaultOptions {
+		overridden := false
+		for _, option := range options {
+			if option.Name == defaultOption.Name {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, defaultOption)
+		}
+	}
+
+	return append(merged, options...)
+}
+
 func deleteDuplicates(strs []string) []string {
 	strsMap := make(map[string]bool)
 	ret := []string{}
@@ -858,7 +979,11 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 	}
 
 	// check annotations
-	_, updatedAnnotations, updatedLabels := translate.Default.ApplyMetadataUpdate(vPod, pPod, t.syncedLabels, getExcludedAnnotations(pPod)...)
+	excludedAnnotations := getExcludedAnnotations(pPod)
+	if t.openshiftMode {
+		excludedAnnotations = append(excludedAnnotations, SCCAnnotation)
+	}
+	_, updatedAnnotations, updatedLabels := translate.Default.ApplyMetadataUpdate(vPod, pPod, t.syncedLabels, excludedAnnotations...)
 	if updatedAnnotations == nil {
 		updatedAnnotations = map[string]string{}
 	}
@@ -867,6 +992,22 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 	}
 
 	updatedAnnotations[LabelsAnnotation] = translateLabelsAnnotation(vPod)
+	if cost, ok := vPod.Annotations[PodDeletionCostAnnotation]; ok {
+		updatedAnnotations[SyncedPodDeletionCostAnnotation] = cost
+	} else {
+		delete(updatedAnnotations, SyncedPodDeletionCostAnnotation)
+	}
+	user, err := t.translateUser(ctx, vPod)
+	if err != nil {
+		return nil, err
+	} else if user != "" {
+		updatedAnnotations[UserAnnotation] = user
+	} else {
+		delete(updatedAnnotations, UserAnnotation)
+	}
+	if t.ownerLabels {
+		translateOwner(vPod, updatedLabels, updatedAnnotations)
+	}
 	if !equality.Semantic.DeepEqual(updatedAnnotations, pPod.Annotations) {
 		if updatedPod == nil {
 			updatedPod = pPod.DeepCopy()
@@ -875,8 +1016,9 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 	}
 
 	// check pod and namespace labels
-	for k, v := range vNamespace.GetLabels() {
-		updatedLabels[translate.ConvertLabelKeyWithPrefix(NamespaceLabelPrefix, k)] = v
+	t.translateNamespaceLabels(vNamespace, updatedLabels)
+	if t.serviceMeshMode {
+		preserveServiceMeshLabels(pPod.Labels, updatedLabels)
 	}
 	if !equality.Semantic.DeepEqual(updatedLabels, pPod.Labels) {
 		if updatedPod == nil {
@@ -888,8 +1030,23 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 	return updatedPod, nil
 }
 
+// translateNamespaceLabels adds the labels of the virtual namespace to the physical pod labels. Labels
+// that should be synced are also added with their original key, unless the pod has such a label itself
+func (t *translator) translateNamespaceLabels(vNamespace *corev1.Namespace, labels map[string]string) {
+	for k, v := range vNamespace.GetLabels() {
+		labels[translate.ConvertLabelKeyWithPrefix(NamespaceLabelPrefix, k)] = v
+	}
+	for _, k := range t.syncedNamespaceLabels {
+		if v, ok := vNamespace.GetLabels()[k]; ok {
+			if _, exists := labels[k]; !exists {
+				labels[k] = v
+			}
+		}
+	}
+}
+
 func getExcludedAnnotations(pPod *corev1.Pod) []string {
-	annotations := []string{ClusterAutoScalerAnnotation, OwnerSetKind, NamespaceAnnotation, NameAnnotation, UIDAnnotation, ServiceAccountNameAnnotation, HostsRewrittenAnnotation, LabelsAnnotation}
+	annotations := []string{ClusterAutoScalerAnnotation, OwnerSetKind, NamespaceAnnotation, NameAnnotation, UIDAnnotation, ServiceAccountNameAnnotation, HostsRewrittenAnnotation, LabelsAnnotation, SyncedPodDeletionCostAnnotation, UserAnnotation, HostResourcesAnnotation, OwnerAnnotation}
 	if pPod != nil {
 		for _, v := range pPod.Spec.Volumes {
 			if v.Projected != nil {
@@ -976,6 +1133,7 @@ func calcContainerImageDiff(pContainers, vContainers []corev1.Container, transla
 			continue
 		}
 
+		found := false
 		for _, v := range vContainers {
 			if p.Name == v.Name {
 				if p.Image != translateImages.Translate(v.Image) {
@@ -987,9 +1145,16 @@ func calcContainerImageDiff(pContainers, vContainers []corev1.Container, transla
 					newContainers = append(newContainers, p)
 				}
 
+				found = true
 				break
 			}
 		}
+
+		// keep containers injected in the host cluster, e.g. service mesh sidecars,
+		// as containers can't be removed from a pod
+		if !found {
+			newContainers = append(newContainers, p)
+		}
 	}
 
 	if !changed {
@@ -1024,3 +1189,25 @@ func isPodSpecSchedulingGatesDiff(pGates, vGates []corev1.PodSchedulingGate) boo
 	}
 	return true
 }
+
+// translatePriority translates the priority class of the pod to the synced host priority class. The
+// priority admission of the host cluster rejects pods whose priority differs from the value of their
+// priority class, so the priority is clamped the same way as the values of synced priority classes.
+// translateTolerations adds the tolerations for the host taints that the virtual tolerations of the pod
+// tolerate in their rewritten form
+func (t *translator) translateTolerations(pPod *corev1.Pod) {
+	pPod.Spec.Tolerations = append(pPod.Spec.Tolerations, taints.TranslateTolerations(pPod.Spec.Tolerations, t.taintRules)...)
+}
+
+func (t *translator) translatePriority(pPod *corev1.Pod) {
+	if !t.priorityClassesEnabled {
+		pPod.Spec.PriorityClassName = ""
+		pPod.Spec.Priority = nil
+	} else if pPod.Spec.PriorityClassName != "" {
+		pPod.Spec.PriorityClassName = priorityclasses.NewPriorityClassTranslator()(pPod.Spec.PriorityClassName, nil)
+		if pPod.Spec.Priority != nil {
+			priority := priorityclasses.ClampValue(*pPod.Spec.Priority, t.priorityClassMinValue, t.priorityClassMaxValue)
+			pPod.Spec.Priority = &priority
+		}
+	}
+}
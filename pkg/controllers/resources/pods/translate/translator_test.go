@@ -2,15 +2,19 @@ package translate
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/priorityclasses"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/taints"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	"gotest.tools/assert/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -193,27 +197,166 @@ func TestVolumeTranslation(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:             "docker containers host path without docker container logs",
+			rewriteHostPaths: true,
+			vPod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-name",
+					Namespace: "test-ns",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:         "agent",
+							VolumeMounts: []corev1.VolumeMount{{Name: "docker", MountPath: DockerContainersHostPath}},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name:         "docker",
+							VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: DockerContainersHostPath}},
+						},
+					},
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name:         "docker",
+					VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: DockerContainersHostPath}},
+				},
+			},
+			expectedVolumeMounts: []corev1.VolumeMount{
+				{Name: "docker", MountPath: DockerContainersHostPath},
+			},
+		},
+		{
+			name:                   "docker containers host path",
+			rewriteHostPaths:       true,
+			mapDockerContainerLogs: true,
+			vPod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-name",
+					Namespace: "test-ns",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:         "agent",
+							VolumeMounts: []corev1.VolumeMount{{Name: "docker", MountPath: DockerContainersHostPath}},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name:         "docker",
+							VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: DockerContainersHostPath}},
+						},
+					},
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name:         "docker",
+					VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: testVirtualDockerContainersPath}},
+				},
+				{
+					Name:         "docker-" + PhysicalVolumeNameSuffix,
+					VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: DockerContainersHostPath}},
+				},
+			},
+			expectedVolumeMounts: []corev1.VolumeMount{
+				{Name: "docker", MountPath: DockerContainersHostPath},
+				{Name: "docker-" + PhysicalVolumeNameSuffix, MountPath: PhysicalDockerContainersVolumeMountPath},
+			},
+		},
+		{
+			name:                             "service account token of host audience",
+			hostServiceAccountTokenAudiences: []string{"sts.amazonaws.com"},
+			vPod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-name",
+					Namespace: "test-ns",
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: "app",
+					Volumes: []corev1.Volume{
+						{
+							Name: "aws-iam-token",
+							VolumeSource: corev1.VolumeSource{
+								Projected: &corev1.ProjectedVolumeSource{
+									Sources: []corev1.VolumeProjection{
+										{
+											ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+												Audience: "sts.amazonaws.com",
+												Path:     "token",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name: "aws-iam-token",
+					VolumeSource: corev1.VolumeSource{
+						Projected: &corev1.ProjectedVolumeSource{
+							Sources: []corev1.VolumeProjection{
+								{
+									ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+										Audience: "sts.amazonaws.com",
+										Path:     "token",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
 		fakeRecorder := record.NewFakeRecorder(10)
+		hostServiceAccountTokenAudiences := map[string]bool{}
+		for _, audience := range testCase.hostServiceAccountTokenAudiences {
+			hostServiceAccountTokenAudiences[audience] = true
+		}
 		tr := &translator{
 			eventRecorder: fakeRecorder,
 			log:           loghelper.New("pods-syncer-translator-test"),
 			pClient:       fake.NewClientBuilder().Build(),
+
+			rewriteVirtualHostPaths:     testCase.rewriteHostPaths,
+			mapDockerContainerLogs:      testCase.mapDockerContainerLogs,
+			virtualDockerContainersPath: testVirtualDockerContainersPath,
+
+			serviceAccountsEnabled:           testCase.hostServiceAccountTokenAudiences != nil,
+			hostServiceAccountTokenAudiences: hostServiceAccountTokenAudiences,
 		}
 
 		pPod := testCase.vPod.DeepCopy()
 		err := tr.translateVolumes(context.Background(), pPod, &testCase.vPod)
 		assert.NilError(t, err)
 		assert.Assert(t, cmp.DeepEqual(pPod.Spec.Volumes, testCase.expectedVolumes), "Unexpected translation of the Volumes in the '%s' test case", testCase.name)
+		if testCase.expectedVolumeMounts != nil {
+			assert.Assert(t, cmp.DeepEqual(pPod.Spec.Containers[0].VolumeMounts, testCase.expectedVolumeMounts), "Unexpected translation of the VolumeMounts in the '%s' test case", testCase.name)
+		}
 	}
 }
 
+const testVirtualDockerContainersPath = "/tmp/vcluster/test/vcluster/docker/containers"
+
 type translatePodVolumesTestCase struct {
-	name            string
-	vPod            corev1.Pod
-	expectedVolumes []corev1.Volume
+	name                             string
+	rewriteHostPaths                 bool
+	mapDockerContainerLogs           bool
+	hostServiceAccountTokenAudiences []string
+	vPod                             corev1.Pod
+	expectedVolumes                  []corev1.Volume
+	expectedVolumeMounts             []corev1.VolumeMount
 }
 
 func appendToMatchLabels(source *metav1.LabelSelector, k, v string) *metav1.LabelSelector {
@@ -234,3 +377,181 @@ func appendNamespacesToMatchExpressions(source *metav1.LabelSelector, namespaces
 	})
 	return ls
 }
+
+func TestContainerEnvTranslation(t *testing.T) {
+	defaultTranslator := translate.Default
+	translate.Default = translate.NewMultiNamespaceTranslator("vcluster")
+	defer func() { translate.Default = defaultTranslator }()
+
+	vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-name", Namespace: "test-ns"}}
+	rootCAName := translate.SafeConcatName("vcluster", "kube-root-ca.crt", "x", translate.Suffix)
+	envVar, envFrom := TranslateContainerEnv([]corev1.EnvVar{
+		{
+			Name: "CA",
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"},
+					Key:                  "ca.crt",
+				},
+			},
+		},
+	}, []corev1.EnvFromSource{
+		{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"}}},
+		{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}},
+	}, vPod, nil)
+
+	assert.Equal(t, envVar[0].ValueFrom.ConfigMapKeyRef.Name, rootCAName)
+	assert.Equal(t, envFrom[0].ConfigMapRef.Name, rootCAName)
+	assert.Equal(t, envFrom[1].ConfigMapRef.Name, "config")
+}
+
+func TestDNSConfigTranslation(t *testing.T) {
+	defaultSearches := []string{"test-ns.svc.cluster.local", "svc.cluster.local", "cluster.local"}
+	ndots := corev1.PodDNSConfigOption{Name: "ndots", Value: pointer.String("5")}
+	manySearches := []string{}
+	for i := 0; i < 40; i++ {
+		manySearches = append(manySearches, fmt.Sprintf("search-%d.example.com", i))
+	}
+
+	testCases := []struct {
+		name              string
+		dnsPolicy         corev1.DNSPolicy
+		hostNetwork       bool
+		dnsConfig         *corev1.PodDNSConfig
+		expectedDNSPolicy corev1.DNSPolicy
+		expectedDNSConfig *corev1.PodDNSConfig
+	}{
+		{
+			name:              "Cluster first",
+			dnsPolicy:         corev1.DNSClusterFirst,
+			expectedDNSPolicy: corev1.DNSNone,
+			expectedDNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.96.0.10"}, Searches: defaultSearches, Options: []corev1.PodDNSConfigOption{ndots}},
+		},
+		{
+			name:              "Cluster first on host network falls back to default",
+			dnsPolicy:         corev1.DNSClusterFirst,
+			hostNetwork:       true,
+			expectedDNSPolicy: corev1.DNSClusterFirst,
+		},
+		{
+			name:              "Cluster first with host net",
+			dnsPolicy:         corev1.DNSClusterFirstWithHostNet,
+			hostNetwork:       true,
+			expectedDNSPolicy: corev1.DNSNone,
+			expectedDNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.96.0.10"}, Searches: defaultSearches, Options: []corev1.PodDNSConfigOption{ndots}},
+		},
+		{
+			name:        "Keep dns config of the pod",
+			dnsPolicy:   corev1.DNSClusterFirstWithHostNet,
+			hostNetwork: true,
+			dnsConfig: &corev1.PodDNSConfig{
+				Nameservers: []string{"10.0.0.2", "10.0.0.3", "10.0.0.4"},
+				Searches:    []string{"corp.example.com"},
+				Options:     []corev1.PodDNSConfigOption{{Name: "ndots", Value: pointer.String("2")}, {Name: "single-request-reopen"}},
+			},
+			expectedDNSPolicy: corev1.DNSNone,
+			expectedDNSConfig: &corev1.PodDNSConfig{
+				Nameservers: []string{"10.96.0.10", "10.0.0.2", "10.0.0.3"},
+				Searches:    append(append([]string{}, defaultSearches...), "corp.example.com"),
+				Options:     []corev1.PodDNSConfigOption{{Name: "ndots", Value: pointer.String("2")}, {Name: "single-request-reopen"}},
+			},
+		},
+		{
+			name:              "Limit search paths",
+			dnsPolicy:         corev1.DNSClusterFirst,
+			dnsConfig:         &corev1.PodDNSConfig{Searches: manySearches},
+			expectedDNSPolicy: corev1.DNSNone,
+			expectedDNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.96.0.10"}, Searches: append(append([]string{}, defaultSearches...), manySearches[:29]...), Options: []corev1.PodDNSConfigOption{ndots}},
+		},
+		{
+			name:              "None",
+			dnsPolicy:         corev1.DNSNone,
+			dnsConfig:         &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.2"}},
+			expectedDNSPolicy: corev1.DNSNone,
+			expectedDNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.2"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		vPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-name", Namespace: "test-ns"},
+			Spec:       corev1.PodSpec{DNSPolicy: testCase.dnsPolicy, HostNetwork: testCase.hostNetwork, DNSConfig: testCase.dnsConfig},
+		}
+		pPod := vPod.DeepCopy()
+		tr := &translator{clusterDomain: "cluster.local"}
+		tr.translateDNSConfig(pPod, vPod, "10.96.0.10")
+		assert.Equal(t, pPod.Spec.DNSPolicy, testCase.expectedDNSPolicy, "unexpected dns policy in test case %s", testCase.name)
+		assert.DeepEqual(t, pPod.Spec.DNSConfig, testCase.expectedDNSConfig)
+	}
+}
+
+func TestPriorityTranslation(t *testing.T) {
+	hostClassName := priorityclasses.NewPriorityClassTranslator()("high", nil)
+	testCases := []struct {
+		name string
+
+		priorityClassesEnabled bool
+		priority               *int32
+
+		expectedClassName string
+		expectedPriority  *int32
+	}{
+		{
+			name:     "priority classes disabled",
+			priority: pointer.Int32(2000),
+		},
+		{
+			name:                   "priority within bounds",
+			priorityClassesEnabled: true,
+			priority:               pointer.Int32(500),
+			expectedClassName:      hostClassName,
+			expectedPriority:       pointer.Int32(500),
+		},
+		{
+			name:                   "priority of a class clamped to the max value",
+			priorityClassesEnabled: true,
+			priority:               pointer.Int32(2000),
+			expectedClassName:      hostClassName,
+			expectedPriority:       pointer.Int32(1000),
+		},
+		{
+			name:                   "priority of a class clamped to the min value",
+			priorityClassesEnabled: true,
+			priority:               pointer.Int32(-10),
+			expectedClassName:      hostClassName,
+			expectedPriority:       pointer.Int32(0),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tr := &translator{
+			priorityClassesEnabled: testCase.priorityClassesEnabled,
+			priorityClassMinValue:  0,
+			priorityClassMaxValue:  1000,
+		}
+
+		pPod := &corev1.Pod{Spec: corev1.PodSpec{PriorityClassName: "high", Priority: testCase.priority}}
+		tr.translatePriority(pPod)
+		assert.Equal(t, pPod.Spec.PriorityClassName, testCase.expectedClassName, "unexpected priority class in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(pPod.Spec.Priority, testCase.expectedPriority), "unexpected priority in test case %s", testCase.name)
+	}
+}
+
+func TestTolerationTranslation(t *testing.T) {
+	taintRules, err := taints.ParseRules([]string{"hidden"}, []string{"gpu=example.com/gpu", "dedicated:NoSchedule=:PreferNoSchedule"})
+	assert.NilError(t, err)
+	tr := &translator{taintRules: taintRules}
+
+	pPod := &corev1.Pod{Spec: corev1.PodSpec{Tolerations: []corev1.Toleration{
+		{Key: "example.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "tenant-a"},
+		{Key: "hidden", Operator: corev1.TolerationOpExists},
+	}}}
+	tr.translateTolerations(pPod)
+	assert.Assert(t, cmp.DeepEqual(pPod.Spec.Tolerations, []corev1.Toleration{
+		{Key: "example.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "tenant-a"},
+		{Key: "hidden", Operator: corev1.TolerationOpExists},
+		{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	}))
+}
@@ -0,0 +1,91 @@
+package translate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// UserAnnotation holds the virtual user that created or last modified a workload. It is set on
+	// virtual workloads by the vcluster proxy and on physical pods by the pod syncer.
+	UserAnnotation = "vcluster.loft.sh/user"
+
+	// UserAnnotationPlain stamps the virtual user as is onto physical pods
+	UserAnnotationPlain = "plain"
+	// UserAnnotationHashed stamps the sha256 hash of the virtual user onto physical pods
+	UserAnnotationHashed = "hashed"
+
+	// maxOwnerDepth is the number of controller owners that are searched for the virtual user,
+	// which covers pod -> replicaset -> deployment and pod -> job -> cronjob
+	maxOwnerDepth = 2
+)
+
+// userOwnerGroups are the api groups of owners that are searched for the virtual user
+var userOwnerGroups = map[string]bool{
+	"apps":  true,
+	"batch": true,
+}
+
+// TranslateUser returns the user as it should be stamped onto physical pods with the given policy
+func TranslateUser(policy, user string) string {
+	if user == "" {
+		return ""
+	}
+
+	switch policy {
+	case UserAnnotationPlain:
+		return user
+	case UserAnnotationHashed:
+		digest := sha256.Sum256([]byte(user))
+		return "sha256:" + hex.EncodeToString(digest[:])
+	}
+
+	return ""
+}
+
+// translateUser returns the virtual user of the pod as it should be stamped onto the physical pod
+func (t *translator) translateUser(ctx context.Context, vPod client.Object) (string, error) {
+	if t.userAnnotation == "" {
+		return "", nil
+	}
+
+	user, err := findUser(ctx, t.vClient, vPod)
+	if err != nil {
+		return "", err
+	}
+
+	return TranslateUser(t.userAnnotation, user), nil
+}
+
+// findUser returns the virtual user of the object or of its closest apps or batch controller
+func findUser(ctx context.Context, vClient client.Client, obj client.Object) (string, error) {
+	for depth := 0; ; depth++ {
+		if user := obj.GetAnnotations()[UserAnnotation]; user != "" {
+			return user, nil
+		}
+
+		owner := metav1.GetControllerOf(obj)
+		if depth >= maxOwnerDepth || owner == nil {
+			return "", nil
+		}
+
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil || !userOwnerGroups[gv.Group] {
+			return "", nil
+		}
+
+		ownerObj := &metav1.PartialObjectMetadata{}
+		ownerObj.SetGroupVersionKind(gv.WithKind(owner.Kind))
+		err = vClient.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: owner.Name}, ownerObj)
+		if err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+
+		obj = ownerObj
+	}
+}
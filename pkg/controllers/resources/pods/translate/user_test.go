@@ -0,0 +1,74 @@
+package translate
+
+import (
+	"context"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+func TestFindUser(t *testing.T) {
+	controlledBy := func(apiVersion, kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, Controller: pointer.Bool(true)}}
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        "app",
+		Annotations: map[string]string{UserAnnotation: "alice"},
+	}}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "default",
+		Name:            "app-123",
+		OwnerReferences: controlledBy("apps/v1", "Deployment", "app"),
+	}}
+
+	testCases := []struct {
+		name string
+
+		pod     *corev1.Pod
+		objects []runtime.Object
+
+		expectedUser string
+	}{
+		{
+			name:         "pod annotation",
+			pod:          &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Annotations: map[string]string{UserAnnotation: "bob"}}},
+			expectedUser: "bob",
+		},
+		{
+			name:         "deployment annotation",
+			pod:          &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", OwnerReferences: controlledBy("apps/v1", "ReplicaSet", "app-123")}},
+			objects:      []runtime.Object{deployment, replicaSet},
+			expectedUser: "alice",
+		},
+		{
+			name:    "missing owner",
+			pod:     &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", OwnerReferences: controlledBy("apps/v1", "ReplicaSet", "app-123")}},
+			objects: []runtime.Object{deployment},
+		},
+		{
+			name: "other owner group",
+			pod:  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", OwnerReferences: controlledBy("example.com/v1", "App", "app")}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		vClient := testingutil.NewFakeClient(testingutil.NewScheme(), testCase.objects...)
+		user, err := findUser(context.Background(), vClient, testCase.pod)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, user, testCase.expectedUser, "unexpected user in test case %s", testCase.name)
+	}
+}
+
+func TestTranslateUser(t *testing.T) {
+	assert.Equal(t, TranslateUser("", "alice"), "")
+	assert.Equal(t, TranslateUser(UserAnnotationPlain, "alice"), "alice")
+	assert.Equal(t, TranslateUser(UserAnnotationHashed, "alice"), "sha256:2bd806c97f0e00af1a1fc3328fa763a9269723c8db8fac4f93af71db186d6e90")
+	assert.Equal(t, TranslateUser(UserAnnotationHashed, ""), "")
+}
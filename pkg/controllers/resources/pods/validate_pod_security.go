@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -23,7 +24,7 @@ func (s *podSyncer) isPodSecurityStandardsValid(ctx context.Context, pod *corev1
 	} else if result != nil {
 		if !result.Allowed {
 			log.Errorf("%s pod creation not allowed: %s", pod.Name, result.Result.Message)
-			s.EventRecorder().Eventf(pod, "Warning", "SyncError", `Pod %s is forbidden: %s`, pod.Name, result.Result.Message)
+			s.EventRecorder().Eventf(pod, "Warning", string(syncerrors.Forbidden), `Pod %s is forbidden: %s`, pod.Name, result.Result.Message)
 		}
 		return result.Allowed, nil
 	}
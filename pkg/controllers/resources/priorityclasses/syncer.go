@@ -11,14 +11,24 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// MaxValue is the highest value of user defined priority classes, higher values are reserved for system priority classes
+const MaxValue = 1000000000
+
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 	return &priorityClassSyncer{
 		Translator: translator.NewClusterTranslator(ctx, "priorityclass", &schedulingv1.PriorityClass{}, NewPriorityClassTranslator()),
+
+		minValue: ctx.Options.PriorityClassMinValue,
+		maxValue: ctx.Options.PriorityClassMaxValue,
 	}, nil
 }
 
 type priorityClassSyncer struct {
 	translator.Translator
+
+	// minValue and maxValue are the bounds the values of virtual priority classes are clamped to
+	minValue int32
+	maxValue int32
 }
 
 var _ syncer.IndicesRegisterer = &priorityClassSyncer{}
@@ -44,6 +54,11 @@ func (s *priorityClassSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client
 }
 
 func (s *priorityClassSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	// the value of a priority class is immutable, so the physical priority class is recreated
+	if s.translateValue(vObj.(*schedulingv1.PriorityClass).Value) != pObj.(*schedulingv1.PriorityClass).Value {
+		return syncer.DeleteObject(ctx, pObj, "virtual priority class value has changed")
+	}
+
 	// did the priority class change?
 	updated := s.translateUpdate(ctx.Context, pObj.(*schedulingv1.PriorityClass), vObj.(*schedulingv1.PriorityClass))
 	if updated != nil {
@@ -0,0 +1,145 @@
+package priorityclasses
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+)
+
+func TestSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	vObjectMeta := metav1.ObjectMeta{
+		Name: "testpc",
+	}
+	pObjectMeta := metav1.ObjectMeta{
+		Name: translate.Default.PhysicalNameClusterScoped(vObjectMeta.Name),
+		Labels: map[string]string{
+			translate.MarkerLabel: translate.Suffix,
+		},
+		Annotations: map[string]string{
+			translate.NameAnnotation: "testpc",
+			translate.UIDAnnotation:  "",
+		},
+	}
+	vObject := &schedulingv1.PriorityClass{
+		ObjectMeta:    vObjectMeta,
+		Value:         1000,
+		GlobalDefault: true,
+	}
+	pObject := &schedulingv1.PriorityClass{
+		ObjectMeta: pObjectMeta,
+		Value:      1000,
+	}
+	vObjectHigh := &schedulingv1.PriorityClass{
+		ObjectMeta: vObjectMeta,
+		Value:      2000000000,
+	}
+	pObjectHigh := &schedulingv1.PriorityClass{
+		ObjectMeta: pObjectMeta,
+		Value:      MaxValue,
+	}
+	pObjectClamped := &schedulingv1.PriorityClass{
+		ObjectMeta: pObjectMeta,
+		Value:      500,
+	}
+	vObjectUpdated := &schedulingv1.PriorityClass{
+		ObjectMeta:  vObjectMeta,
+		Value:       1000,
+		Description: "test",
+	}
+	pObjectUpdated := &schedulingv1.PriorityClass{
+		ObjectMeta:  pObjectMeta,
+		Value:       1000,
+		Description: "test",
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                "Sync Down",
+			InitialVirtualState: []runtime.Object{vObject},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {vObject},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {pObject},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*priorityClassSyncer).SyncDown(syncCtx, vObject)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Sync Down with value above maximum",
+			InitialVirtualState: []runtime.Object{vObjectHigh},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {vObjectHigh},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {pObjectHigh},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*priorityClassSyncer).SyncDown(syncCtx, vObjectHigh)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Sync Down with value above configured maximum",
+			InitialVirtualState: []runtime.Object{vObject},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {vObject},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {pObjectClamped},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.PriorityClassMaxValue = 500
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*priorityClassSyncer).SyncDown(syncCtx, vObject)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync recreates on value change",
+			InitialVirtualState:  []runtime.Object{vObject},
+			InitialPhysicalState: []runtime.Object{pObjectClamped},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {vObject},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*priorityClassSyncer).Sync(syncCtx, pObjectClamped, vObject)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync",
+			InitialVirtualState:  []runtime.Object{vObjectUpdated},
+			InitialPhysicalState: []runtime.Object{pObject},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {vObjectUpdated},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"): {pObjectUpdated},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*priorityClassSyncer).Sync(syncCtx, pObject, vObjectUpdated)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
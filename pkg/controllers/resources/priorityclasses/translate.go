@@ -13,12 +13,26 @@ func (s *priorityClassSyncer) translate(ctx context.Context, vObj client.Object)
 	// translate the priority class
 	priorityClass := s.TranslateMetadata(ctx, vObj).(*schedulingv1.PriorityClass)
 	priorityClass.GlobalDefault = false
-	if priorityClass.Value > 1000000000 {
-		priorityClass.Value = 1000000000
-	}
+	priorityClass.Value = s.translateValue(priorityClass.Value)
 	return priorityClass
 }
 
+// translateValue clamps the value of a virtual priority class to the configured bounds
+func (s *priorityClassSyncer) translateValue(value int32) int32 {
+	return ClampValue(value, s.minValue, s.maxValue)
+}
+
+// ClampValue clamps a virtual priority to the bounds of the host priority class values
+func ClampValue(value, minValue, maxValue int32) int32 {
+	if value > maxValue {
+		return maxValue
+	} else if value < minValue {
+		return minValue
+	}
+
+	return value
+}
+
 func (s *priorityClassSyncer) translateUpdate(ctx context.Context, pObj, vObj *schedulingv1.PriorityClass) *schedulingv1.PriorityClass {
 	var updated *schedulingv1.PriorityClass
 
@@ -42,15 +56,5 @@ func (s *priorityClassSyncer) translateUpdate(ctx context.Context, pObj, vObj *s
 		updated.Description = vObj.Description
 	}
 
-	// check value
-	translatedValue := vObj.Value
-	if translatedValue > 1000000000 {
-		translatedValue = 1000000000
-	}
-	if translatedValue != pObj.Value {
-		updated = translator.NewIfNil(updated, pObj)
-		updated.Value = translatedValue
-	}
-
 	return updated
 }
@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var RouteGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+const crdPath = "routes/route.openshift.io_routes.yaml"
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(RouteGVK)
+
+	return &routeSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "route", obj),
+	}, nil
+}
+
+type routeSyncer struct {
+	translator.NamespacedTranslator
+}
+
+var _ syncer.Initializer = &routeSyncer{}
+
+func (s *routeSyncer) Init(ctx *synccontext.RegisterContext) error {
+	exists, err := util.KindExists(ctx.PhysicalManager.GetConfig(), RouteGVK)
+	if err != nil {
+		return errors.Wrap(err, "check host cluster kind")
+	} else if !exists {
+		return fmt.Errorf("seems like resource %s is not available in the physical cluster or vcluster has no access to it", RouteGVK.String())
+	}
+
+	// OpenShift serves routes through the openshift-apiserver instead of a crd, so there is no crd
+	// that could be copied from the host and the crd shipped with vcluster is installed instead
+	return util.EnsureCRDFromFile(ctx.Context, ctx.VirtualManager.GetConfig(), path.Join(constants.ContainerManifestsFolder, crdPath), RouteGVK)
+}
+
+var _ syncer.Syncer = &routeSyncer{}
+
+func (s *routeSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*unstructured.Unstructured)))
+}
+
+func (s *routeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	vRoute := vObj.(*unstructured.Unstructured)
+	pRoute := pObj.(*unstructured.Unstructured)
+
+	// the host generates the host name of routes without one
+	vHost, _, _ := unstructured.NestedString(vRoute.Object, "spec", "host")
+	pHost, _, _ := unstructured.NestedString(pRoute.Object, "spec", "host")
+	if vHost == "" && pHost != "" {
+		newRoute := vRoute.DeepCopy()
+		err := unstructured.SetNestedField(newRoute.Object, pHost, "spec", "host")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		ctx.Log.Infof("update virtual route %s/%s, because host name was generated", vRoute.GetNamespace(), vRoute.GetName())
+		translator.PrintChanges(vRoute, newRoute, ctx.Log)
+		return ctrl.Result{}, ctx.VirtualClient.Update(ctx.Context, newRoute)
+	}
+
+	// the host router reports if the route was admitted
+	if !equality.Semantic.DeepEqual(vRoute.Object["status"], pRoute.Object["status"]) {
+		newRoute := vRoute.DeepCopy()
+		if pRoute.Object["status"] == nil {
+			delete(newRoute.Object, "status")
+		} else {
+			newRoute.Object["status"] = runtime.DeepCopyJSONValue(pRoute.Object["status"])
+		}
+
+		translator.PrintChanges(vRoute, newRoute, ctx.Log)
+		return ctrl.Result{}, ctx.VirtualClient.Status().Update(ctx.Context, newRoute)
+	}
+
+	newRoute := s.translateUpdate(ctx.Context, pRoute, vRoute)
+	if newRoute != nil {
+		translator.PrintChanges(pObj, newRoute, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, newRoute)
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const serviceKind = "Service"
+
+func (s *routeSyncer) translate(ctx context.Context, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	pObj := s.TranslateMetadata(ctx, vObj).(*unstructured.Unstructured)
+	pObj.Object["spec"] = translateRouteSpec(vObj)
+	delete(pObj.Object, "status")
+	return pObj
+}
+
+func (s *routeSyncer) translateUpdate(ctx context.Context, pObj, vObj *unstructured.Unstructured) *unstructured.Unstructured {
+	var updated *unstructured.Unstructured
+
+	translatedSpec := translateRouteSpec(vObj)
+	if !equality.Semantic.DeepEqual(translatedSpec, pObj.Object["spec"]) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Object["spec"] = translatedSpec
+	}
+
+	changed, translatedAnnotations, translatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.SetAnnotations(translatedAnnotations)
+		updated.SetLabels(translatedLabels)
+	}
+
+	return updated
+}
+
+// translateRouteSpec rewrites the services the route points to, to the host services
+func translateRouteSpec(vRoute *unstructured.Unstructured) map[string]interface{} {
+	spec, ok := vRoute.Object["spec"].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	spec = runtime.DeepCopyJSONValue(spec).(map[string]interface{})
+	if to, ok := spec["to"].(map[string]interface{}); ok {
+		translateBackend(to, vRoute.GetNamespace())
+	}
+	alternateBackends, _ := spec["alternateBackends"].([]interface{})
+	for _, b := range alternateBackends {
+		if backend, ok := b.(map[string]interface{}); ok {
+			translateBackend(backend, vRoute.GetNamespace())
+		}
+	}
+
+	return spec
+}
+
+func translateBackend(backend map[string]interface{}, namespace string) {
+	kind, _ := backend["kind"].(string)
+	name, _ := backend["name"].(string)
+	if (kind != "" && kind != serviceKind) || name == "" {
+		return
+	}
+
+	backend["name"] = translate.Default.PhysicalName(name, namespace)
+}
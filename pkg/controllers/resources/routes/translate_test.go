@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTranslateRouteSpec(t *testing.T) {
+	vRoute := &unstructured.Unstructured{}
+	vRoute.SetGroupVersionKind(RouteGVK)
+	vRoute.SetNamespace("test")
+	vRoute.SetName("route")
+	vRoute.Object["spec"] = map[string]interface{}{
+		"host": "app.example.com",
+		"to":   map[string]interface{}{"kind": "Service", "name": "app", "weight": int64(80)},
+		"alternateBackends": []interface{}{
+			map[string]interface{}{"name": "canary", "weight": int64(20)},
+			map[string]interface{}{"kind": "Other", "name": "other"},
+		},
+		"port": map[string]interface{}{"targetPort": "http"},
+	}
+
+	expected := map[string]interface{}{
+		"host": "app.example.com",
+		"to":   map[string]interface{}{"kind": "Service", "name": translate.Default.PhysicalName("app", "test"), "weight": int64(80)},
+		"alternateBackends": []interface{}{
+			map[string]interface{}{"name": translate.Default.PhysicalName("canary", "test"), "weight": int64(20)},
+			map[string]interface{}{"kind": "Other", "name": "other"},
+		},
+		"port": map[string]interface{}{"targetPort": "http"},
+	}
+
+	assert.DeepEqual(t, translateRouteSpec(vRoute), expected)
+
+	// the virtual object is not changed
+	name, _, _ := unstructured.NestedString(vRoute.Object, "spec", "to", "name")
+	assert.Equal(t, name, "app")
+}
@@ -0,0 +1,124 @@
+package runtimeclasses
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+)
+
+func TestSync(t *testing.T) {
+	objectMeta := metav1.ObjectMeta{
+		Name: "gvisor",
+		Labels: map[string]string{
+			"runtime": "sandboxed",
+		},
+	}
+
+	pObj := &nodev1.RuntimeClass{
+		ObjectMeta: objectMeta,
+		Handler:    "runsc",
+		Overhead: &nodev1.Overhead{
+			PodFixed: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+	}
+
+	vObj := &nodev1.RuntimeClass{
+		ObjectMeta: objectMeta,
+		Handler:    "runsc",
+		Overhead: &nodev1.Overhead{
+			PodFixed: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+	}
+
+	vObjModified := &nodev1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: objectMeta.Name,
+		},
+		Handler: "runsc",
+		Scheduling: &nodev1.Scheduling{
+			NodeSelector: map[string]string{
+				"sandbox": "true",
+			},
+		},
+	}
+
+	vObjOtherHandler := &nodev1.RuntimeClass{
+		ObjectMeta: objectMeta,
+		Handler:    "kata",
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Sync Up",
+			InitialVirtualState:  []runtime.Object{},
+			InitialPhysicalState: []runtime.Object{pObj},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {vObj},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {pObj},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*runtimeClassSyncer).SyncUp(syncCtx, pObj)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                  "Sync Down",
+			InitialVirtualState:   []runtime.Object{vObj},
+			ExpectedVirtualState:  map[schema.GroupVersionKind][]runtime.Object{},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*runtimeClassSyncer).SyncDown(syncCtx, vObj)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync reverts virtual changes",
+			InitialVirtualState:  []runtime.Object{vObjModified},
+			InitialPhysicalState: []runtime.Object{pObj},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {vObj},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {pObj},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*runtimeClassSyncer).Sync(syncCtx, pObj, vObjModified)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync recreates on handler change",
+			InitialVirtualState:  []runtime.Object{vObjOtherHandler},
+			InitialPhysicalState: []runtime.Object{pObj},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {pObj},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*runtimeClassSyncer).Sync(syncCtx, pObj, vObjOtherHandler)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
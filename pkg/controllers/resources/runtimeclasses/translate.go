@@ -0,0 +1,36 @@
+package runtimeclasses
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func (r *runtimeClassSyncer) translateBackwards(ctx context.Context, pRuntimeClass *nodev1.RuntimeClass) *nodev1.RuntimeClass {
+	return r.TranslateMetadata(ctx, pRuntimeClass).(*nodev1.RuntimeClass)
+}
+
+func (r *runtimeClassSyncer) translateUpdateBackwards(ctx context.Context, pObj, vObj *nodev1.RuntimeClass) *nodev1.RuntimeClass {
+	var updated *nodev1.RuntimeClass
+
+	changed, updatedAnnotations, updatedLabels := r.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Labels = updatedLabels
+		updated.Annotations = updatedAnnotations
+	}
+
+	if !equality.Semantic.DeepEqual(vObj.Overhead, pObj.Overhead) {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Overhead = pObj.Overhead
+	}
+
+	if !equality.Semantic.DeepEqual(vObj.Scheduling, pObj.Scheduling) {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Scheduling = pObj.Scheduling
+	}
+
+	return updated
+}
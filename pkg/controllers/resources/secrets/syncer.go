@@ -10,16 +10,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/gateways"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses/legacy"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/pods"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/podhelper"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,13 +40,30 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 }
 
 func NewSyncer(ctx *synccontext.RegisterContext, useLegacy bool) (syncer.Object, error) {
+	// if secret syncing is disabled, the syncer is only started to sync the tls secrets of ingresses
+	ingressSecretsOnly := !ctx.Controllers.Has("secrets")
+	transforms, err := parseSecretTransforms(ctx.Options.SecretTransforms)
+	if err != nil {
+		return nil, err
+	}
+
+	skipTypes := map[corev1.SecretType]bool{}
+	for _, secretType := range ctx.Options.SkipSecretTypes {
+		skipTypes[corev1.SecretType(secretType)] = true
+	}
+
 	return &secretSyncer{
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "secret", &corev1.Secret{}),
 
-		useLegacyIngress: useLegacy,
-		includeIngresses: ctx.Controllers.Has("ingresses"),
+		useLegacyIngress:   useLegacy,
+		includeIngresses:   ctx.Controllers.Has("ingresses"),
+		includeGateways:    ctx.Controllers.Has("gateways") && !ingressSecretsOnly,
+		ingressSecretsOnly: ingressSecretsOnly,
 
-		syncAllSecrets: ctx.Options.SyncAllSecrets,
+		syncAllSecrets: ctx.Options.SyncAllSecrets && !ingressSecretsOnly,
+
+		skipTypes:  skipTypes,
+		transforms: transforms,
 	}, nil
 }
 
@@ -52,14 +72,24 @@ type secretSyncer struct {
 
 	useLegacyIngress bool
 	includeIngresses bool
+	includeGateways  bool
+
+	// ingressSecretsOnly is set if secret syncing is disabled and only secrets referenced by
+	// ingresses are synced
+	ingressSecretsOnly bool
 
 	syncAllSecrets bool
+
+	// skipTypes are the types of secrets that are never synced to the host cluster
+	skipTypes map[corev1.SecretType]bool
+	// transforms change secrets of a type on the way to the host cluster
+	transforms map[corev1.SecretType]*secretTransform
 }
 
 var _ syncer.IndicesRegisterer = &secretSyncer{}
 
 func (s *secretSyncer) RegisterIndices(ctx *synccontext.RegisterContext) error {
-	if ctx.Controllers.Has("ingresses") {
+	if s.includeIngresses {
 		if s.useLegacyIngress {
 			err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &networkingv1beta1.Ingress{}, constants.IndexByIngressSecret, func(rawObj client.Object) []string {
 				return legacy.SecretNamesFromIngress(rawObj.(*networkingv1beta1.Ingress))
@@ -77,9 +107,20 @@ func (s *secretSyncer) RegisterIndices(ctx *synccontext.RegisterContext) error {
 		}
 	}
 
-	err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &corev1.Pod{}, constants.IndexByPodSecret, func(rawObj client.Object) []string {
-		return pods.SecretNamesFromPod(rawObj.(*corev1.Pod))
-	})
+	if s.includeGateways {
+		err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, newGateway(), constants.IndexByGatewaySecret, func(rawObj client.Object) []string {
+			return gateways.SecretNamesFromGateway(rawObj.(*unstructured.Unstructured))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.ingressSecretsOnly {
+		return s.NamespacedTranslator.RegisterIndices(ctx)
+	}
+
+	err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &corev1.Pod{}, constants.IndexByPodSecret, podhelper.IndexReferencesOfRunningPods(pods.SecretNamesFromPod))
 	if err != nil {
 		return err
 	}
@@ -96,6 +137,12 @@ func (s *secretSyncer) ModifyController(ctx *synccontext.RegisterContext, builde
 			builder = builder.Watches(&networkingv1.Ingress{}, handler.EnqueueRequestsFromMapFunc(mapIngresses))
 		}
 	}
+	if s.includeGateways {
+		builder = builder.Watches(newGateway(), handler.EnqueueRequestsFromMapFunc(mapGateways))
+	}
+	if s.ingressSecretsOnly {
+		return builder, nil
+	}
 
 	return builder.Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(mapPods)), nil
 }
@@ -127,6 +174,11 @@ func (s *secretSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vO
 		return ctrl.Result{}, nil
 	}
 
+	// secret types and immutable secrets can't be updated, so the physical secret is recreated
+	if reason := s.recreateReason(pObj.(*corev1.Secret), vObj.(*corev1.Secret)); reason != "" {
+		return s.SyncDownRecreate(ctx, vObj, pObj, s.translate(ctx.Context, vObj.(*corev1.Secret)), reason)
+	}
+
 	newSecret := s.translateUpdate(ctx.Context, pObj.(*corev1.Secret), vObj.(*corev1.Secret))
 	if newSecret != nil {
 		translator.PrintChanges(pObj, newSecret, ctx.Log)
@@ -139,16 +191,21 @@ func (s *secretSyncer) isSecretUsed(ctx *synccontext.SyncContext, vObj runtime.O
 	secret, ok := vObj.(*corev1.Secret)
 	if !ok || secret == nil {
 		return false, fmt.Errorf("%#v is not a secret", vObj)
-	} else if secret.Annotations != nil && secret.Annotations[constants.SyncResourceAnnotation] == "true" {
-		return true, nil
-	}
+	} else if s.skipTypes[secret.Type] {
+		ctx.Log.Debugf("skip secret %s/%s, because secrets of type %s are not synced", secret.Namespace, secret.Name, secret.Type)
+		return false, nil
+	} else if !s.ingressSecretsOnly {
+		if secret.Annotations != nil && secret.Annotations[constants.SyncResourceAnnotation] == "true" {
+			return true, nil
+		}
 
-	isUsed, err := isSecretUsedByPods(ctx.Context, ctx.VirtualClient, secret.Namespace+"/"+secret.Name)
-	if err != nil {
-		return false, errors.Wrap(err, "is secret used by pods")
-	}
-	if isUsed {
-		return true, nil
+		isUsed, err := isSecretUsedByPods(ctx.Context, ctx.VirtualClient, secret.Namespace+"/"+secret.Name)
+		if err != nil {
+			return false, errors.Wrap(err, "is secret used by pods")
+		}
+		if isUsed {
+			return true, nil
+		}
 	}
 
 	// check if we also sync ingresses
@@ -165,7 +222,23 @@ func (s *secretSyncer) isSecretUsed(ctx *synccontext.SyncContext, vObj runtime.O
 			return false, err
 		}
 
-		return meta.LenList(ingressesList) > 0, nil
+		if meta.LenList(ingressesList) > 0 {
+			return true, nil
+		}
+	}
+
+	// check if we also sync gateways
+	if s.includeGateways {
+		gatewayList := &unstructured.UnstructuredList{}
+		gatewayList.SetGroupVersionKind(gateways.GatewayGVK.GroupVersion().WithKind(gateways.GatewayGVK.Kind + "List"))
+		err := ctx.VirtualClient.List(ctx.Context, gatewayList, client.MatchingFields{constants.IndexByGatewaySecret: secret.Namespace + "/" + secret.Name})
+		if err != nil {
+			return false, err
+		}
+
+		if len(gatewayList.Items) > 0 {
+			return true, nil
+		}
 	}
 
 	if s.syncAllSecrets {
@@ -198,6 +271,35 @@ func mapIngresses(_ context.Context, obj client.Object) []reconcile.Request {
 	return requests
 }
 
+func newGateway() *unstructured.Unstructured {
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gateways.GatewayGVK)
+	return gateway
+}
+
+func mapGateways(_ context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	names := gateways.SecretNamesFromGateway(gateway)
+	for _, name := range names {
+		splitted := strings.Split(name, "/")
+		if len(splitted) == 2 {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: splitted[0],
+					Name:      splitted[1],
+				},
+			})
+		}
+	}
+
+	return requests
+}
+
 func mapIngressesLegacy(_ context.Context, obj client.Object) []reconcile.Request {
 	ingress, ok := obj.(*networkingv1beta1.Ingress)
 	if !ok {
@@ -80,6 +80,22 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	baseIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: baseSecret.Namespace,
+		},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{
+				{
+					SecretName: baseSecret.Name,
+				},
+			},
+		},
+	}
+	terminatedPod := basePod.DeepCopy()
+	terminatedPod.Status.Phase = corev1.PodSucceeded
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name: "Unused secret",
@@ -134,6 +150,74 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Secret used by terminated pod",
+			InitialVirtualState: []runtime.Object{
+				baseSecret,
+				terminatedPod,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncDown(syncContext, baseSecret)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Remove secret of terminated pod",
+			InitialVirtualState: []runtime.Object{
+				updatedSecret,
+				terminatedPod,
+			},
+			InitialPhysicalState: []runtime.Object{
+				syncedSecret,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).Sync(syncContext, syncedSecret, updatedSecret)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Secret used by pod with secret sync disabled",
+			InitialVirtualState: []runtime.Object{
+				baseSecret,
+				basePod,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Controllers.Delete("secrets")
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncDown(syncContext, baseSecret)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Secret used by ingress with secret sync disabled",
+			InitialVirtualState: []runtime.Object{
+				baseSecret,
+				baseIngress,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {
+					syncedSecret,
+				},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Controllers.Delete("secrets")
+				ctx.Options.SyncLabels = []string{testLabel}
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncDown(syncContext, baseSecret)
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name: "Remove unused secret",
 			InitialVirtualState: []runtime.Object{
@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// transformStripKey removes a data key from secrets of a type
+	transformStripKey = "strip-key"
+	// transformType changes the type of secrets of a type
+	transformType = "type"
+)
+
+// secretTransform changes secrets of a type on the way to the host cluster
+type secretTransform struct {
+	// stripKeys are the data keys that are removed. A key ending with * matches all keys with that prefix.
+	stripKeys []string
+	// newType replaces the secret type if set
+	newType corev1.SecretType
+}
+
+// parseSecretTransforms parses transforms in the form secretType:action=value
+func parseSecretTransforms(transforms []string) (map[corev1.SecretType]*secretTransform, error) {
+	parsed := map[corev1.SecretType]*secretTransform{}
+	for _, transform := range transforms {
+		typeAndAction, value, _ := strings.Cut(transform, "=")
+		index := strings.LastIndex(typeAndAction, ":")
+		if index <= 0 || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("invalid secret transform %s, expected secretType:action=value", transform)
+		}
+
+		secretType, action, value := corev1.SecretType(strings.TrimSpace(typeAndAction[:index])), strings.TrimSpace(typeAndAction[index+1:]), strings.TrimSpace(value)
+		if parsed[secretType] == nil {
+			parsed[secretType] = &secretTransform{}
+		}
+		switch action {
+		case transformStripKey:
+			parsed[secretType].stripKeys = append(parsed[secretType].stripKeys, value)
+		case transformType:
+			if parsed[secretType].newType != "" {
+				return nil, fmt.Errorf("invalid secret transform %s, type of %s is changed more than once", transform, secretType)
+			}
+			parsed[secretType].newType = corev1.SecretType(value)
+		default:
+			return nil, fmt.Errorf("invalid secret transform %s, action must be one of %s or %s", transform, transformStripKey, transformType)
+		}
+	}
+
+	return parsed, nil
+}
+
+// translateData returns the data and type of the physical secret of vSecret
+func (s *secretSyncer) translateData(vSecret *corev1.Secret) (map[string][]byte, corev1.SecretType) {
+	data, secretType := vSecret.Data, vSecret.Type
+	if secretType == corev1.SecretTypeServiceAccountToken {
+		secretType = corev1.SecretTypeOpaque
+	}
+
+	transform, ok := s.transforms[vSecret.Type]
+	if !ok {
+		return data, secretType
+	}
+
+	if transform.newType != "" {
+		secretType = transform.newType
+	}
+	if len(transform.stripKeys) > 0 && data != nil {
+		data = map[string][]byte{}
+		for key, value := range vSecret.Data {
+			if !translate.MatchesKey(transform.stripKeys, key) {
+				data[key] = value
+			}
+		}
+	}
+
+	return data, secretType
+}
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSecretTransforms(t *testing.T) {
+	transforms, err := parseSecretTransforms([]string{"kubernetes.io/tls:strip-key=ca.key", "example.com/token:strip-key=private-*", "example.com/token:type=Opaque"})
+	assert.NilError(t, err)
+	s := &secretSyncer{transforms: transforms}
+
+	data, secretType := s.translateData(&corev1.Secret{
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key"), "ca.key": []byte("ca")},
+	})
+	assert.DeepEqual(t, data, map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key")})
+	assert.Equal(t, secretType, corev1.SecretTypeTLS)
+
+	data, secretType = s.translateData(&corev1.Secret{
+		Type: "example.com/token",
+		Data: map[string][]byte{"token": []byte("token"), "private-key": []byte("key")},
+	})
+	assert.DeepEqual(t, data, map[string][]byte{"token": []byte("token")})
+	assert.Equal(t, secretType, corev1.SecretTypeOpaque)
+
+	// secrets without a transform are synced as is
+	data, secretType = s.translateData(&corev1.Secret{
+		Type: corev1.SecretTypeServiceAccountToken,
+		Data: map[string][]byte{"token": []byte("token")},
+	})
+	assert.DeepEqual(t, data, map[string][]byte{"token": []byte("token")})
+	assert.Equal(t, secretType, corev1.SecretTypeOpaque)
+
+	for _, invalid := range []string{"kubernetes.io/tls", "kubernetes.io/tls:strip-key=", "kubernetes.io/tls:rename=a", ":type=Opaque"} {
+		_, err = parseSecretTransforms([]string{invalid})
+		assert.Assert(t, err != nil, "expected error for %s", invalid)
+	}
+
+	_, err = parseSecretTransforms([]string{"example.com/token:type=Opaque", "example.com/token:type=kubernetes.io/basic-auth"})
+	assert.ErrorContains(t, err, "changed more than once")
+}
@@ -10,10 +10,7 @@ import (
 
 func (s *secretSyncer) translate(ctx context.Context, vObj *corev1.Secret) *corev1.Secret {
 	newSecret := s.TranslateMetadata(ctx, vObj).(*corev1.Secret)
-	if newSecret.Type == corev1.SecretTypeServiceAccountToken {
-		newSecret.Type = corev1.SecretTypeOpaque
-	}
-
+	newSecret.Data, newSecret.Type = s.translateData(vObj)
 	return newSecret
 }
 
@@ -21,15 +18,16 @@ func (s *secretSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.S
 	var updated *corev1.Secret
 
 	// check data
-	if !equality.Semantic.DeepEqual(vObj.Data, pObj.Data) {
+	data, _ := s.translateData(vObj)
+	if !equality.Semantic.DeepEqual(data, pObj.Data) {
 		updated = translator.NewIfNil(updated, pObj)
-		updated.Data = vObj.Data
+		updated.Data = data
 	}
 
-	// check secret type
-	if vObj.Type != pObj.Type && vObj.Type != corev1.SecretTypeServiceAccountToken {
+	// check immutable
+	if !equality.Semantic.DeepEqual(vObj.Immutable, pObj.Immutable) {
 		updated = translator.NewIfNil(updated, pObj)
-		updated.Type = vObj.Type
+		updated.Immutable = vObj.Immutable
 	}
 
 	// check annotations
@@ -42,3 +40,20 @@ func (s *secretSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.S
 
 	return updated
 }
+
+// recreateReason returns why the physical secret needs to be recreated instead of updated, which is the case
+// if the secret type changed or if the physical secret is immutable and the virtual secret was recreated with other contents
+func (s *secretSyncer) recreateReason(pObj, vObj *corev1.Secret) string {
+	data, secretType := s.translateData(vObj)
+	if secretType != pObj.Type {
+		return "secret type has changed"
+	} else if pObj.Immutable == nil || !*pObj.Immutable {
+		return ""
+	} else if vObj.Immutable == nil || !*vObj.Immutable {
+		return "virtual secret is not immutable anymore"
+	} else if !equality.Semantic.DeepEqual(data, pObj.Data) {
+		return "data of immutable virtual secret has changed"
+	}
+
+	return ""
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/namemapping"
+)
+
+// ExternalNameMapping maps external names of virtual ExternalName services to host names
+type ExternalNameMapping map[string]string
+
+// ParseExternalNameMapping parses mappings in the form virtualName=hostName
+func ParseExternalNameMapping(mappings []string) (ExternalNameMapping, error) {
+	out, err := namemapping.Parse(mappings, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid external name mapping: %w", err)
+	}
+
+	return out, nil
+}
+
+// ToHost returns the host external name of the virtual external name, external names
+// without a mapping are passed through
+func (m ExternalNameMapping) ToHost(virtualName string) string {
+	if hostName, ok := m[strings.TrimSuffix(virtualName, ".")]; ok {
+		return hostName
+	}
+
+	return virtualName
+}
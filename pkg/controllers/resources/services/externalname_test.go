@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseExternalNameMapping(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mappings []string
+
+		expectedMapping ExternalNameMapping
+		expectedErr     bool
+	}{
+		{
+			name:            "no mappings",
+			expectedMapping: ExternalNameMapping{},
+		},
+		{
+			name:            "mappings",
+			mappings:        []string{"db.example.internal=db.tenant-a.svc.cluster.local", "cache.example.internal=cache.example.com"},
+			expectedMapping: ExternalNameMapping{"db.example.internal": "db.tenant-a.svc.cluster.local", "cache.example.internal": "cache.example.com"},
+		},
+		{
+			name:        "missing host name",
+			mappings:    []string{"db.example.internal="},
+			expectedErr: true,
+		},
+		{
+			name:        "missing separator",
+			mappings:    []string{"db.example.internal"},
+			expectedErr: true,
+		},
+		{
+			name:        "ambiguous virtual name",
+			mappings:    []string{"db.example.internal=a.example.com", "db.example.internal=b.example.com"},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mapping, err := ParseExternalNameMapping(testCase.mappings)
+		if testCase.expectedErr {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, mapping, testCase.expectedMapping)
+	}
+}
+
+func TestExternalNameMappingToHost(t *testing.T) {
+	mapping := ExternalNameMapping{"db.example.internal": "db.tenant-a.svc.cluster.local"}
+
+	assert.Equal(t, mapping.ToHost("db.example.internal"), "db.tenant-a.svc.cluster.local")
+	assert.Equal(t, mapping.ToHost("db.example.internal."), "db.tenant-a.svc.cluster.local")
+	assert.Equal(t, mapping.ToHost("example.com"), "example.com")
+	assert.Equal(t, mapping.ToHost(""), "")
+}
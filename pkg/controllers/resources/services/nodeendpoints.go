@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// NodePortEndpointsAnnotation holds the addresses of the ready host nodes and the node ports allocated in
+// the host cluster of a virtual NodePort service as json, e.g.
+// {"addresses":["192.168.0.10"],"ports":[{"name":"http","protocol":"TCP","port":80,"nodePort":31234}]}
+const NodePortEndpointsAnnotation = "vcluster.loft.sh/node-port-endpoints"
+
+// NodePortEndpoints is the value of the NodePortEndpointsAnnotation
+type NodePortEndpoints struct {
+	// Addresses of the ready host nodes, the external ip of a node if it has one, otherwise its internal ip
+	Addresses []string `json:"addresses"`
+
+	// Ports of the service with the node port allocated in the host cluster
+	Ports []NodePortEndpointPort `json:"ports"`
+}
+
+type NodePortEndpointPort struct {
+	Name     string          `json:"name,omitempty"`
+	Protocol corev1.Protocol `json:"protocol"`
+	Port     int32           `json:"port"`
+	NodePort int32           `json:"nodePort"`
+}
+
+// syncNodePortEndpoints writes the NodePortEndpointsAnnotation to the virtual service and returns true if it was updated
+func (s *serviceSyncer) syncNodePortEndpoints(ctx *synccontext.SyncContext, pService, vService *corev1.Service) (bool, error) {
+	value := ""
+	if vService.Spec.Type == corev1.ServiceTypeNodePort && pService.Spec.Type == corev1.ServiceTypeNodePort {
+		nodeList := &corev1.NodeList{}
+		err := ctx.PhysicalClient.List(ctx.Context, nodeList)
+		if err != nil {
+			return false, err
+		}
+
+		value, err = nodePortEndpoints(pService, nodeList.Items)
+		if err != nil {
+			return false, err
+		}
+	}
+	if vService.Annotations[NodePortEndpointsAnnotation] == value {
+		return false, nil
+	}
+
+	newVService := vService.DeepCopy()
+	if value == "" {
+		delete(newVService.Annotations, NodePortEndpointsAnnotation)
+	} else {
+		if newVService.Annotations == nil {
+			newVService.Annotations = map[string]string{}
+		}
+		newVService.Annotations[NodePortEndpointsAnnotation] = value
+	}
+
+	ctx.Log.Infof("update virtual service %s/%s, because node port endpoints changed", vService.Namespace, vService.Name)
+	err := ctx.VirtualClient.Update(ctx.Context, newVService)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func nodePortEndpoints(pService *corev1.Service, nodes []corev1.Node) (string, error) {
+	endpoints := NodePortEndpoints{
+		Addresses: []string{},
+		Ports:     []NodePortEndpointPort{},
+	}
+	for _, port := range pService.Spec.Ports {
+		if port.NodePort == 0 {
+			continue
+		}
+
+		endpoints.Ports = append(endpoints.Ports, NodePortEndpointPort{
+			Name:     port.Name,
+			Protocol: port.Protocol,
+			Port:     port.Port,
+			NodePort: port.NodePort,
+		})
+	}
+	if len(endpoints.Ports) == 0 {
+		return "", nil
+	}
+
+	addresses := sets.New[string]()
+	for i := range nodes {
+		if !isNodeReady(&nodes[i]) {
+			continue
+		}
+
+		if address := nodeAddress(&nodes[i]); address != "" {
+			addresses.Insert(address)
+		}
+	}
+	endpoints.Addresses = sets.List(addresses)
+
+	out, err := json.Marshal(endpoints)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// nodeAddress returns the external ip of the node or its internal ip if it has none
+func nodeAddress(node *corev1.Node) string {
+	internalIP := ""
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeExternalIP {
+			return address.Address
+		} else if address.Type == corev1.NodeInternalIP && internalIP == "" {
+			internalIP = address.Address
+		}
+	}
+
+	return internalIP
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+var _ syncer.ControllerModifier = &serviceSyncer{}
+
+func (s *serviceSyncer) ModifyController(ctx *synccontext.RegisterContext, bld *builder.Builder) (*builder.Builder, error) {
+	if !s.nodePortEndpoints {
+		return bld, nil
+	}
+
+	// requeue all virtual node port services if a host node changes its addresses or readiness
+	virtualClient := ctx.VirtualManager.GetClient()
+	return bld.WatchesRawSource(source.Kind(ctx.PhysicalManager.GetCache(), &corev1.Node{}), handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+		serviceList := &corev1.ServiceList{}
+		err := virtualClient.List(ctx, serviceList)
+		if err != nil {
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, service := range serviceList.Items {
+			if service.Spec.Type == corev1.ServiceTypeNodePort {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: service.Namespace, Name: service.Name}})
+			}
+		}
+		return requests
+	}), builder.WithPredicates(predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+
+			return isNodeReady(oldNode) != isNodeReady(newNode) || nodeAddress(oldNode) != nodeAddress(newNode)
+		},
+	})), nil
+}
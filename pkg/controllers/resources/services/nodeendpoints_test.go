@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodePortEndpoints(t *testing.T) {
+	readyNode := func(name string, addresses ...corev1.NodeAddress) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Addresses:  addresses,
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	nodes := []corev1.Node{
+		readyNode("node-a", corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.2"}, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.2"}),
+		readyNode("node-b", corev1.NodeAddress{Type: corev1.NodeHostName, Address: "node-b"}, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-c"},
+			Status: corev1.NodeStatus{
+				Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.3"}},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name string
+
+		ports []corev1.ServicePort
+
+		expectedValue string
+	}{
+		{
+			name: "node ports",
+			ports: []corev1.ServicePort{
+				{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 31080},
+				{Name: "dns", Protocol: corev1.ProtocolUDP, Port: 53, NodePort: 31053},
+			},
+			expectedValue: `{"addresses":["10.0.0.1","203.0.113.2"],"ports":[{"name":"http","protocol":"TCP","port":80,"nodePort":31080},{"name":"dns","protocol":"UDP","port":53,"nodePort":31053}]}`,
+		},
+		{
+			name:  "no node ports allocated yet",
+			ports: []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		value, err := nodePortEndpoints(&corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: testCase.ports}}, nodes)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, value, testCase.expectedValue, "unexpected value in test case %s", testCase.name)
+	}
+}
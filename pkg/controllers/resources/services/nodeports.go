@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RemappedNodePortsAnnotation holds the node ports requested in the virtual cluster that were already
+// allocated in the host cluster and were remapped to the node ports of the host service. Format: requested=allocated
+const RemappedNodePortsAnnotation = "vcluster.loft.sh/remapped-node-ports"
+
+// SyncedNodePortsAnnotation is set on the host service and holds the node ports the virtual service was last
+// in sync with, a node port of the virtual service that differs from it was requested by the user.
+// Format: port/protocol=nodePort
+const SyncedNodePortsAnnotation = "vcluster.loft.sh/synced-node-ports"
+
+func nodePortKey(port corev1.ServicePort) string {
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+
+	return fmt.Sprintf("%d/%s", port.Port, protocol)
+}
+
+func hasNodePorts(service *corev1.Service) bool {
+	for _, port := range service.Spec.Ports {
+		if port.NodePort != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatNodePorts returns the value of the SyncedNodePortsAnnotation for the given ports
+func formatNodePorts(ports []corev1.ServicePort) string {
+	nodePorts := []string{}
+	for _, port := range ports {
+		nodePorts = append(nodePorts, fmt.Sprintf("%s=%d", nodePortKey(port), port.NodePort))
+	}
+	sort.Strings(nodePorts)
+
+	return strings.Join(nodePorts, ",")
+}
+
+// syncedNodePorts parses the SyncedNodePortsAnnotation of the host service and returns nil if it is not set
+func syncedNodePorts(pService *corev1.Service) map[string]int32 {
+	value := pService.Annotations[SyncedNodePortsAnnotation]
+	if value == "" {
+		return nil
+	}
+
+	nodePorts := map[string]int32{}
+	for _, nodePort := range strings.Split(value, ",") {
+		key, port, found := strings.Cut(nodePort, "=")
+		if !found {
+			continue
+		}
+
+		parsed, err := strconv.ParseInt(port, 10, 32)
+		if err != nil {
+			continue
+		}
+
+		nodePorts[key] = int32(parsed)
+	}
+
+	return nodePorts
+}
+
+// isRequestedNodePort checks if the node port of the virtual port was requested by the user. Host services
+// without synced node ports were created by an older vcluster, in which case the host node port is kept.
+func isRequestedNodePort(vPort, pPort corev1.ServicePort, synced map[string]int32) bool {
+	if synced == nil || vPort.NodePort == 0 || vPort.NodePort == pPort.NodePort {
+		return false
+	}
+
+	syncedNodePort, ok := synced[nodePortKey(vPort)]
+	return !ok || syncedNodePort != vPort.NodePort
+}
+
+// requestsNodePorts checks if the virtual service requests other node ports than the host service has allocated
+func requestsNodePorts(pService, vService *corev1.Service) bool {
+	if pService.Spec.Type != vService.Spec.Type || !portsEqual(pService, vService) {
+		return false
+	}
+
+	synced := syncedNodePorts(pService)
+	for i := range vService.Spec.Ports {
+		if isRequestedNodePort(vService.Spec.Ports[i], pService.Spec.Ports[i], synced) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syncRequestedNodePorts updates the host service with the requested node ports and returns true if the
+// service should be requeued
+func (s *serviceSyncer) syncRequestedNodePorts(ctx *synccontext.SyncContext, pService, vService *corev1.Service) (bool, error) {
+	newPService := pService.DeepCopy()
+	synced := syncedNodePorts(pService)
+	for i := range vService.Spec.Ports {
+		if isRequestedNodePort(vService.Spec.Ports[i], pService.Spec.Ports[i], synced) {
+			newPService.Spec.Ports[i].NodePort = vService.Spec.Ports[i].NodePort
+		}
+	}
+	if newPService.Annotations == nil {
+		newPService.Annotations = map[string]string{}
+	}
+	newPService.Annotations[SyncedNodePortsAnnotation] = formatNodePorts(newPService.Spec.Ports)
+
+	ctx.Log.Infof("update physical service %s/%s, because virtual service requests other node ports", pService.Namespace, pService.Name)
+	err := ctx.PhysicalClient.Update(ctx.Context, newPService)
+	if err != nil {
+		if kerrors.IsInvalid(err) {
+			return s.handleNodePortConflict(ctx, pService, vService, err)
+		}
+
+		return false, err
+	}
+
+	// the requested node ports are allocated now, so there is nothing remapped anymore
+	if vService.Annotations[RemappedNodePortsAnnotation] != "" {
+		newVService := vService.DeepCopy()
+		delete(newVService.Annotations, RemappedNodePortsAnnotation)
+		err = ctx.VirtualClient.Update(ctx.Context, newVService)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// handleNodePortConflict remaps the requested node ports to the ones of the host service if enabled, otherwise
+// the requested node ports are kept in the virtual service and the rest of the service is synced as usual
+func (s *serviceSyncer) handleNodePortConflict(ctx *synccontext.SyncContext, pService, vService *corev1.Service, conflictErr error) (bool, error) {
+	if !s.remapConflictingNodePorts {
+		s.EventRecorder().Eventf(vService, "Warning", "NodePortConflict", "Requested node ports cannot be allocated in the host cluster: %v", conflictErr)
+		return false, nil
+	}
+
+	// remap the requested node ports to the ones allocated in the host cluster
+	newVService := vService.DeepCopy()
+	remapped := []string{}
+	for i := range newVService.Spec.Ports {
+		if newVService.Spec.Ports[i].NodePort != 0 && newVService.Spec.Ports[i].NodePort != pService.Spec.Ports[i].NodePort {
+			remapped = append(remapped, fmt.Sprintf("%d=%d", newVService.Spec.Ports[i].NodePort, pService.Spec.Ports[i].NodePort))
+			newVService.Spec.Ports[i].NodePort = pService.Spec.Ports[i].NodePort
+		}
+	}
+	if newVService.Annotations == nil {
+		newVService.Annotations = map[string]string{}
+	}
+	newVService.Annotations[RemappedNodePortsAnnotation] = strings.Join(remapped, ",")
+
+	// only report the conflict when the remap changes, not on every retry with the same node ports
+	if vService.Annotations[RemappedNodePortsAnnotation] != newVService.Annotations[RemappedNodePortsAnnotation] {
+		s.EventRecorder().Eventf(vService, "Warning", "NodePortConflict", "Requested node ports cannot be allocated in the host cluster and were remapped to %s: %v", newVService.Annotations[RemappedNodePortsAnnotation], conflictErr)
+	}
+
+	ctx.Log.Infof("remap node ports %s of virtual service %s/%s, because they are already allocated in the host cluster", newVService.Annotations[RemappedNodePortsAnnotation], vService.Namespace, vService.Name)
+	err := ctx.VirtualClient.Update(ctx.Context, newVService)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -2,12 +2,15 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
+	"github.com/pkg/errors"
 
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
@@ -21,20 +24,36 @@ import (
 var ServiceBlockDeletion = "vcluster.loft.sh/block-deletion"
 
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	externalNameMapping, err := ParseExternalNameMapping(ctx.Options.ExternalNameMapping)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid value of the external-name-mapping flag")
+	}
+	if ctx.Options.DNSServiceIP != "" && net.ParseIP(ctx.Options.DNSServiceIP) == nil {
+		return nil, errors.Errorf("invalid value of the dns-service-ip flag: %s is not an ip", ctx.Options.DNSServiceIP)
+	}
+
 	return &serviceSyncer{
 		// exclude "field.cattle.io/publicEndpoints" annotation used by Rancher,
 		// because if it is also installed in the host cluster, it will be
 		// overriding it, which would cause endless updates back and forth.
-		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "service", &corev1.Service{}, "field.cattle.io/publicEndpoints"),
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "service", &corev1.Service{}, "field.cattle.io/publicEndpoints", RemappedNodePortsAnnotation, SyncedNodePortsAnnotation, NodePortEndpointsAnnotation),
 
-		serviceName: ctx.Options.ServiceName,
+		serviceName:               ctx.Options.ServiceName,
+		externalNameMapping:       externalNameMapping,
+		remapConflictingNodePorts: ctx.Options.RemapConflictingNodePorts,
+		dnsServiceIP:              ctx.Options.DNSServiceIP,
+		nodePortEndpoints:         ctx.Options.NodePortEndpoints,
 	}, nil
 }
 
 type serviceSyncer struct {
 	translator.NamespacedTranslator
 
-	serviceName string
+	serviceName               string
+	externalNameMapping       ExternalNameMapping
+	remapConflictingNodePorts bool
+	dnsServiceIP              string
+	nodePortEndpoints         bool
 }
 
 var _ syncer.OptionsProvider = &serviceSyncer{}
@@ -44,7 +63,18 @@ func (s *serviceSyncer) WithOptions() *syncer.Options {
 }
 
 func (s *serviceSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
-	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*corev1.Service)))
+	pService := s.translate(ctx.Context, vObj.(*corev1.Service))
+	result, err := s.SyncDownCreate(ctx, vObj, pService)
+	if kerrors.IsInvalid(err) && hasNodePorts(pService) {
+		// the requested node ports are already allocated in the host cluster, so we let the host cluster
+		// choose the node ports and mark them as requested, the conflict is then handled during sync
+		ctx.Log.Infof("create physical service %s/%s without the requested node ports: %v", pService.Namespace, pService.Name, err)
+		StripNodePorts(pService)
+		pService.Annotations[SyncedNodePortsAnnotation] = formatNodePorts(pService.Spec.Ports)
+		return s.SyncDownCreate(ctx, vObj, pService)
+	}
+
+	return result, err
 }
 
 func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
@@ -56,15 +86,30 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, v
 		return ctrl.Result{RequeueAfter: time.Second * 3}, nil
 	}
 
+	// the cluster ip of a service can't be changed, so the host dns service is recreated
+	if dnsIP := s.requestedClusterIP(vService); dnsIP != "" && pService.Spec.ClusterIP != dnsIP {
+		return syncer.DeleteObject(ctx, pService, fmt.Sprintf("cluster ip %s differs from the configured dns service ip %s", pService.Spec.ClusterIP, dnsIP))
+	}
+
+	// check if node ports were requested in the virtual cluster
+	if requestsNodePorts(pService, vService) {
+		requeue, err := s.syncRequestedNodePorts(ctx, pService, vService)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if requeue {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// check if backwards update is necessary
 	newService := s.translateUpdateBackwards(pService, vService)
 	if newService != nil {
-		if vService.Spec.ClusterIP != pService.Spec.ClusterIP {
-			newService.Spec.ClusterIPs = nil
-			ctx.Log.Infof("recreating virtual service %s/%s, because cluster ip differs %s != %s", vService.Namespace, vService.Name, pService.Spec.ClusterIP, vService.Spec.ClusterIP)
+		if vService.Spec.ClusterIP != pService.Spec.ClusterIP || !equality.Semantic.DeepEqual(vService.Spec.ClusterIPs, newService.Spec.ClusterIPs) {
+			ctx.Log.Infof("recreating virtual service %s/%s, because cluster ips differ %v != %v", vService.Namespace, vService.Name, pService.Spec.ClusterIPs, vService.Spec.ClusterIPs)
 
 			// recreate the new service with the correct cluster ip
-			_, err := recreateService(ctx.Context, ctx.VirtualClient, newService)
+			var err error
+			newService, err = recreateService(ctx.Context, ctx.VirtualClient, newService)
 			if err != nil {
 				ctx.Log.Errorf("error creating virtual service: %s/%s", vService.Namespace, vService.Name)
 				return ctrl.Result{}, err
@@ -77,23 +122,38 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, v
 				return ctrl.Result{}, err
 			}
 		}
-
-		// we will requeue anyways
-		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// check if backwards status update is necessary
-	if !equality.Semantic.DeepEqual(vService.Status, pService.Status) {
-		newService := vService.DeepCopy()
-		newService.Status = pService.Status
+	// check if backwards status update is necessary, this is done right after a spec update, so that
+	// tenants waiting for the load balancer of the host cluster don't have to wait for another reconcile
+	statusService := vService
+	if newService != nil {
+		statusService = newService
+	}
+	if !equality.Semantic.DeepEqual(statusService.Status, pService.Status) {
+		newStatusService := statusService.DeepCopy()
+		newStatusService.Status = pService.Status
 		ctx.Log.Infof("update virtual service %s/%s, because status is out of sync", vService.Namespace, vService.Name)
-		translator.PrintChanges(vService, newService, ctx.Log)
-		err := ctx.VirtualClient.Status().Update(ctx.Context, newService)
+		translator.PrintChanges(statusService, newStatusService, ctx.Log)
+		err := ctx.VirtualClient.Status().Update(ctx.Context, newStatusService)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 
 		return ctrl.Result{Requeue: true}, nil
+	} else if newService != nil {
+		// we will requeue anyways
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// reflect where the node ports can be reached in the host cluster
+	if s.nodePortEndpoints {
+		updated, err := s.syncNodePortEndpoints(ctx, pService, vService)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if updated {
+			return ctrl.Result{Requeue: true}, nil
+		}
 	}
 
 	// forward update
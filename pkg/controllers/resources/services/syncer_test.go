@@ -14,6 +14,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -52,6 +53,7 @@ func TestSync(t *testing.T) {
 	}
 	createdByServerService := createdService.DeepCopy()
 	createdByServerService.Annotations[ServiceBlockDeletion] = "true"
+	internalTrafficPolicyLocal := corev1.ServiceInternalTrafficPolicyLocal
 	updateForwardSpec := corev1.ServiceSpec{
 		Ports: []corev1.ServicePort{
 			{
@@ -68,7 +70,8 @@ func TestSync(t *testing.T) {
 		SessionAffinityConfig: &corev1.SessionAffinityConfig{
 			ClientIP: &corev1.ClientIPConfig{},
 		},
-		HealthCheckNodePort: 112,
+		HealthCheckNodePort:   112,
+		InternalTrafficPolicy: &internalTrafficPolicyLocal,
 	}
 	updateForwardService := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -165,6 +168,37 @@ func TestSync(t *testing.T) {
 		ObjectMeta: vObjectMeta,
 		Status:     updateBackwardStatus,
 	}
+	portError := "PortAllocationFailed"
+	updateBackwardLoadBalancerStatus := corev1.ServiceStatus{
+		LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{
+				{
+					IP: "121:121:121:121",
+					Ports: []corev1.PortStatus{
+						{
+							Port:     80,
+							Protocol: corev1.ProtocolTCP,
+							Error:    &portError,
+						},
+					},
+				},
+			},
+		},
+	}
+	updateBackwardLoadBalancerService := &corev1.Service{
+		ObjectMeta: pObjectMeta,
+		Spec: corev1.ServiceSpec{
+			LoadBalancerIP: "121:121:121:121",
+		},
+		Status: updateBackwardLoadBalancerStatus,
+	}
+	updatedBackwardLoadBalancerService := &corev1.Service{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.ServiceSpec{
+			LoadBalancerIP: "121:121:121:121",
+		},
+		Status: updateBackwardLoadBalancerStatus,
+	}
 	kubernetesWithClusterIPService := &corev1.Service{
 		ObjectMeta: vKubernetesObjectMeta,
 		Spec: corev1.ServiceSpec{
@@ -196,6 +230,44 @@ func TestSync(t *testing.T) {
 			},
 		},
 	}
+	vServicePorts1Remapped := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vObjectMeta.Name,
+			Namespace: vObjectMeta.Namespace,
+			Annotations: map[string]string{
+				RemappedNodePortsAnnotation: "567=456",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "test",
+					Port:       123,
+					NodePort:   456,
+					TargetPort: intstr.FromInt(10),
+				},
+			},
+		},
+	}
+	pServicePorts1Requested := &corev1.Service{
+		ObjectMeta: pObjectMeta,
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "test",
+					Port:       123,
+					NodePort:   567,
+					TargetPort: intstr.FromInt(10),
+				},
+			},
+		},
+	}
+	pServicePorts1Requested.Annotations = map[string]string{
+		translate.NameAnnotation:      vObjectMeta.Name,
+		translate.NamespaceAnnotation: vObjectMeta.Namespace,
+		translate.UIDAnnotation:       "",
+		SyncedNodePortsAnnotation:     "123/TCP=567",
+	}
 	vServicePorts1Synced := &corev1.Service{
 		ObjectMeta: vObjectMeta,
 		Spec: corev1.ServiceSpec{
@@ -222,6 +294,13 @@ func TestSync(t *testing.T) {
 			},
 		},
 	}
+	pServicePorts1Synced := pServicePorts1.DeepCopy()
+	pServicePorts1Synced.Annotations = map[string]string{
+		translate.NameAnnotation:      vObjectMeta.Name,
+		translate.NamespaceAnnotation: vObjectMeta.Namespace,
+		translate.UIDAnnotation:       "",
+		SyncedNodePortsAnnotation:     "123/TCP=456",
+	}
 	pServicePorts2 := &corev1.Service{
 		ObjectMeta: pObjectMeta,
 		Spec: corev1.ServiceSpec{
@@ -275,6 +354,20 @@ func TestSync(t *testing.T) {
 			Ports:        vServiceClusterIPFromExternal.Spec.Ports,
 		},
 	}
+	vServiceExternalMapped := &corev1.Service{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.ServiceSpec{
+			ExternalName: "db.example.internal",
+			Type:         corev1.ServiceTypeExternalName,
+		},
+	}
+	pServiceExternalMapped := &corev1.Service{
+		ObjectMeta: pObjectMeta,
+		Spec: corev1.ServiceSpec{
+			ExternalName: "db.tenant-a.svc.cluster.local",
+			Type:         corev1.ServiceTypeExternalName,
+		},
+	}
 	selectorKey := "test"
 	vServiceNodePortFromExternal := &corev1.Service{
 		ObjectMeta: vObjectMeta,
@@ -301,6 +394,89 @@ func TestSync(t *testing.T) {
 			Ports: vServiceNodePortFromExternal.Spec.Ports,
 		},
 	}
+	singleStack := corev1.IPFamilyPolicySingleStack
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	vServiceRequireDualStack := baseService.DeepCopy()
+	vServiceRequireDualStack.Spec.ClusterIP = "10.96.0.20"
+	vServiceRequireDualStack.Spec.ClusterIPs = []string{"10.96.0.20", "fd00::20"}
+	vServiceRequireDualStack.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	vServiceRequireDualStack.Spec.IPFamilyPolicy = &requireDualStack
+	pServicePreferDualStack := createdService.DeepCopy()
+	pServicePreferDualStack.Spec.IPFamilyPolicy = &preferDualStack
+	pServiceDualStack := pServicePreferDualStack.DeepCopy()
+	pServiceDualStack.Spec.ClusterIP = "10.96.0.20"
+	pServiceDualStack.Spec.ClusterIPs = []string{"10.96.0.20", "fd00::10"}
+	pServiceDualStack.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	vServiceDualStackSynced := vServiceRequireDualStack.DeepCopy()
+	vServiceDualStackSynced.Spec.ClusterIPs = pServiceDualStack.Spec.ClusterIPs
+	pServiceSingleStack := createdService.DeepCopy()
+	pServiceSingleStack.Spec.ClusterIP = "10.96.0.20"
+	pServiceSingleStack.Spec.ClusterIPs = []string{"10.96.0.20"}
+	pServiceSingleStack.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+	pServiceSingleStack.Spec.IPFamilyPolicy = &singleStack
+	vServicePreferDualStack := baseService.DeepCopy()
+	vServicePreferDualStack.Spec.ClusterIP = "10.96.0.20"
+	vServicePreferDualStack.Spec.ClusterIPs = []string{"10.96.0.20"}
+	vServicePreferDualStack.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+	vServicePreferDualStack.Spec.IPFamilyPolicy = &preferDualStack
+	pServiceSingleStackUpgraded := pServiceSingleStack.DeepCopy()
+	pServiceSingleStackUpgraded.Spec.IPFamilyPolicy = &preferDualStack
+	localTrafficPolicy := corev1.ServiceInternalTrafficPolicyLocal
+	pServiceDefaulted := createdService.DeepCopy()
+	pServiceDefaulted.Spec.InternalTrafficPolicy = &localTrafficPolicy
+	vServiceDefaulted := baseService.DeepCopy()
+	vServiceDefaulted.Spec.InternalTrafficPolicy = &localTrafficPolicy
+
+	vDNSService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      specialservices.DefaultKubeDNSServiceName,
+			Namespace: specialservices.DefaultKubeDNSServiceNamespace,
+		},
+	}
+	pDNSService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName(vDNSService.Name, vDNSService.Namespace),
+			Namespace: "test",
+			Annotations: map[string]string{
+				translate.NameAnnotation:      vDNSService.Name,
+				translate.NamespaceAnnotation: vDNSService.Namespace,
+				translate.UIDAnnotation:       "",
+			},
+			Labels: map[string]string{
+				translate.NamespaceLabel: vDNSService.Namespace,
+				translate.MarkerLabel:    translate.Suffix,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.96.0.10",
+		},
+	}
+	pDNSServiceOtherIP := pDNSService.DeepCopy()
+	pDNSServiceOtherIP.Spec.ClusterIP = "10.96.23.42"
+
+	vNodePortService := &corev1.Service{
+		ObjectMeta: vObjectMeta,
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 31080}},
+		},
+	}
+	pNodePortService := &corev1.Service{
+		ObjectMeta: pObjectMeta,
+		Spec:       vNodePortService.Spec,
+	}
+	vNodePortServiceEndpoints := vNodePortService.DeepCopy()
+	vNodePortServiceEndpoints.Annotations = map[string]string{
+		NodePortEndpointsAnnotation: `{"addresses":["10.0.0.1"],"ports":[{"name":"http","protocol":"TCP","port":80,"nodePort":31080}]}`,
+	}
+	pNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: corev1.NodeStatus{
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
 
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
@@ -318,6 +494,89 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Create Forward kube-dns service with configured ip",
+			InitialVirtualState: []runtime.Object{vDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vDNSService.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pDNSService.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.DNSServiceIP = "10.96.0.10"
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).SyncDown(syncCtx, vDNSService.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Recreate kube-dns service with other ip than configured",
+			InitialVirtualState:  []runtime.Object{vDNSService.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pDNSServiceOtherIP.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vDNSService.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.DNSServiceIP = "10.96.0.10"
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, pDNSServiceOtherIP.DeepCopy(), vDNSService.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync node port endpoints physical -> virtual",
+			InitialVirtualState:  []runtime.Object{vNodePortService.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pNodePortService.DeepCopy(), pNode.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vNodePortServiceEndpoints.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pNodePortService.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.NodePortEndpoints = true
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, pNodePortService.DeepCopy(), vNodePortService.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Create Forward with mapped external name",
+			InitialVirtualState: []runtime.Object{vServiceExternalMapped.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServiceExternalMapped.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServiceExternalMapped.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.ExternalNameMapping = []string{"db.example.internal=db.tenant-a.svc.cluster.local"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).SyncDown(syncCtx, vServiceExternalMapped.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update forward with mapped external name",
+			InitialVirtualState:  []runtime.Object{vServiceExternalMapped.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pServiceExternal.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServiceExternalMapped.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServiceExternalMapped.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.ExternalNameMapping = []string{"db.example.internal=db.tenant-a.svc.cluster.local"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, pServiceExternal.DeepCopy(), vServiceExternalMapped.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Sync node ports physical -> virtual",
 			InitialVirtualState:  []runtime.Object{vServicePorts1.DeepCopy()},
@@ -334,6 +593,120 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Sync requested node ports virtual -> physical",
+			InitialVirtualState:  []runtime.Object{vServicePorts1.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pServicePorts1Synced.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServicePorts1.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServicePorts1Requested.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, pServicePorts1Synced.DeepCopy(), vServicePorts1.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Create forward with requested node ports",
+			InitialVirtualState: []runtime.Object{vServicePorts1.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServicePorts1.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServicePorts1Requested.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).SyncDown(syncCtx, vServicePorts1.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Remap conflicting node ports",
+			InitialVirtualState:  []runtime.Object{vServicePorts1.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pServicePorts1.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServicePorts1Remapped.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServicePorts1.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.RemapConflictingNodePorts = true
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				conflictErr := kerrors.NewInvalid(corev1.SchemeGroupVersion.WithKind("Service").GroupKind(), pServicePorts1.Name, nil)
+				_, err := syncer.(*serviceSyncer).handleNodePortConflict(syncCtx, pServicePorts1.DeepCopy(), vServicePorts1.DeepCopy(), conflictErr)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Keep conflicting node ports",
+			InitialVirtualState:  []runtime.Object{vServicePorts1.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pServicePorts1.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServicePorts1.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServicePorts1.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				conflictErr := kerrors.NewInvalid(corev1.SchemeGroupVersion.WithKind("Service").GroupKind(), pServicePorts1.Name, nil)
+				requeue, err := syncer.(*serviceSyncer).handleNodePortConflict(syncCtx, pServicePorts1.DeepCopy(), vServicePorts1.DeepCopy(), conflictErr)
+				assert.NilError(t, err)
+				assert.Assert(t, !requeue)
+			},
+		},
+		{
+			Name:                "Create Forward dual-stack",
+			InitialVirtualState: []runtime.Object{vServiceRequireDualStack.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServiceRequireDualStack.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServicePreferDualStack.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).SyncDown(syncCtx, vServiceRequireDualStack.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync dual-stack cluster ips physical -> virtual",
+			InitialVirtualState:  []runtime.Object{vServiceRequireDualStack.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pServiceDualStack.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServiceDualStackSynced.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServiceDualStack.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, pServiceDualStack.DeepCopy(), vServiceRequireDualStack.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync ip family policy virtual -> physical",
+			InitialVirtualState:  []runtime.Object{vServicePreferDualStack.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pServiceSingleStack.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServicePreferDualStack.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServiceSingleStackUpgraded.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, pServiceSingleStack.DeepCopy(), vServicePreferDualStack.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Sync ports virtual -> physical",
 			InitialVirtualState:  []runtime.Object{vServicePorts1.DeepCopy()},
@@ -350,6 +723,22 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Sync fields defaulted by the host physical -> virtual",
+			InitialVirtualState:  []runtime.Object{baseService.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pServiceDefaulted.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {vServiceDefaulted.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {pServiceDefaulted.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, pServiceDefaulted.DeepCopy(), baseService.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Update forward",
 			InitialVirtualState:  []runtime.Object{updateForwardService.DeepCopy()},
@@ -454,6 +843,22 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Update backward spec and load balancer status",
+			InitialVirtualState:  []runtime.Object{baseService.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{updateBackwardLoadBalancerService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {updatedBackwardLoadBalancerService.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {updateBackwardLoadBalancerService.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, updateBackwardLoadBalancerService.DeepCopy(), baseService.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Update backward not needed",
 			InitialVirtualState:  []runtime.Object{baseService.DeepCopy()},
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/specialservices"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -13,7 +14,7 @@ func (s *serviceSyncer) translate(ctx context.Context, vObj *corev1.Service) *co
 	newService := s.TranslateMetadata(ctx, vObj).(*corev1.Service)
 	newService.Spec.Selector = translate.Default.TranslateLabels(vObj.Spec.Selector, vObj.Namespace, nil)
 	if newService.Spec.ClusterIP != "None" {
-		newService.Spec.ClusterIP = ""
+		newService.Spec.ClusterIP = s.requestedClusterIP(vObj)
 	}
 	newService.Spec.ClusterIPs = nil
 
@@ -21,16 +22,68 @@ func (s *serviceSyncer) translate(ctx context.Context, vObj *corev1.Service) *co
 	// cluster directly circumventing the vcluster proxy, this needs to
 	// be done as creating a service purely inside the
 	// virtual cluster can cause a RequireDualStack ipFamily that
-	// might not be supported in the host cluster, so we let the
-	// host cluster decide for itself what ip family and policy
-	// to use here.
+	// might not be supported in the host cluster, so we only pass
+	// through if the service should be dual-stack and let the
+	// host cluster decide for itself what ip families to use here.
+	// The ip families are synced back to the virtual service afterwards.
 	newService.Spec.IPFamilies = nil
-	newService.Spec.IPFamilyPolicy = nil
+	newService.Spec.IPFamilyPolicy = TranslateIPFamilyPolicy(vObj)
 
-	StripNodePorts(newService)
+	// the external name is passed through, unless it is mapped to a host name
+	newService.Spec.ExternalName = s.externalNameMapping.ToHost(vObj.Spec.ExternalName)
+
+	// requested node ports are passed through, if they are already allocated in the host cluster
+	// the service is created without them
+	if hasNodePorts(newService) {
+		if newService.Annotations == nil {
+			newService.Annotations = map[string]string{}
+		}
+		newService.Annotations[SyncedNodePortsAnnotation] = formatNodePorts(newService.Spec.Ports)
+	}
 	return newService
 }
 
+// requestedClusterIP returns the cluster ip the host service of vObj is created with, which
+// is empty for all services except the kube-dns service if a dns service ip is configured
+func (s *serviceSyncer) requestedClusterIP(vObj *corev1.Service) string {
+	if s.dnsServiceIP == "" || specialservices.Default.GetDNSServiceSuffix() != nil {
+		return ""
+	}
+	if vObj.Namespace != specialservices.DefaultKubeDNSServiceNamespace || vObj.Name != specialservices.DefaultKubeDNSServiceName {
+		return ""
+	}
+
+	return s.dnsServiceIP
+}
+
+// TranslateIPFamilyPolicy returns the ip family policy of the host service, RequireDualStack
+// is relaxed to PreferDualStack, so the host cluster never rejects the service
+func TranslateIPFamilyPolicy(vObj *corev1.Service) *corev1.IPFamilyPolicy {
+	if vObj.Spec.Type == corev1.ServiceTypeExternalName || vObj.Spec.IPFamilyPolicy == nil {
+		return nil
+	}
+
+	policy := *vObj.Spec.IPFamilyPolicy
+	if policy == corev1.IPFamilyPolicyRequireDualStack {
+		policy = corev1.IPFamilyPolicyPreferDualStack
+	}
+	return &policy
+}
+
+// TranslateIPFamilyPolicyBackwards returns the ip family policy of the virtual service for the ip families
+// the host cluster chose, RequireDualStack is kept as long as the host service is dual-stack
+func TranslateIPFamilyPolicyBackwards(pObj, vObj *corev1.Service) *corev1.IPFamilyPolicy {
+	if vObj.Spec.IPFamilyPolicy == nil || *vObj.Spec.IPFamilyPolicy != corev1.IPFamilyPolicyRequireDualStack || len(pObj.Spec.IPFamilies) < 2 {
+		return pObj.Spec.IPFamilyPolicy
+	}
+
+	return vObj.Spec.IPFamilyPolicy
+}
+
+func isDualStack(policy *corev1.IPFamilyPolicy) bool {
+	return policy != nil && *policy != corev1.IPFamilyPolicySingleStack
+}
+
 func StripNodePorts(vObj *corev1.Service) {
 	for i := range vObj.Spec.Ports {
 		vObj.Spec.Ports[i].NodePort = 0
@@ -52,9 +105,14 @@ func portsEqual(pObj, vObj *corev1.Service) bool {
 func (s *serviceSyncer) translateUpdateBackwards(pObj, vObj *corev1.Service) *corev1.Service {
 	var updated *corev1.Service
 
-	if vObj.Spec.ClusterIP != pObj.Spec.ClusterIP {
+	// the host cluster decides which ip families a dual-stack service gets, so we take over
+	// the cluster ips as soon as both services agree on being dual-stack or not
+	if vObj.Spec.ClusterIP != pObj.Spec.ClusterIP || (isDualStack(vObj.Spec.IPFamilyPolicy) == isDualStack(pObj.Spec.IPFamilyPolicy) && !equality.Semantic.DeepEqual(vObj.Spec.ClusterIPs, pObj.Spec.ClusterIPs)) {
 		updated = translator.NewIfNil(updated, vObj)
 		updated.Spec.ClusterIP = pObj.Spec.ClusterIP
+		updated.Spec.ClusterIPs = pObj.Spec.ClusterIPs
+		updated.Spec.IPFamilies = pObj.Spec.IPFamilies
+		updated.Spec.IPFamilyPolicy = TranslateIPFamilyPolicyBackwards(pObj, vObj)
 	}
 
 	if !equality.Semantic.DeepEqual(vObj.Spec.ExternalIPs, pObj.Spec.ExternalIPs) {
@@ -67,10 +125,38 @@ func (s *serviceSyncer) translateUpdateBackwards(pObj, vObj *corev1.Service) *co
 		updated.Spec.LoadBalancerIP = pObj.Spec.LoadBalancerIP
 	}
 
-	// check if we need to sync node ports from host to virtual
-	if pObj.Spec.Type == vObj.Spec.Type && portsEqual(pObj, vObj) && !equality.Semantic.DeepEqual(vObj.Spec.Ports, pObj.Spec.Ports) {
+	// take over fields the host cluster defaulted, otherwise they would be reset on every forward update
+	if vObj.Spec.InternalTrafficPolicy == nil && pObj.Spec.InternalTrafficPolicy != nil {
 		updated = translator.NewIfNil(updated, vObj)
-		updated.Spec.Ports = pObj.Spec.Ports
+		updated.Spec.InternalTrafficPolicy = pObj.Spec.InternalTrafficPolicy
+	}
+	if vObj.Spec.SessionAffinity == pObj.Spec.SessionAffinity && vObj.Spec.SessionAffinityConfig == nil && pObj.Spec.SessionAffinityConfig != nil {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Spec.SessionAffinityConfig = pObj.Spec.SessionAffinityConfig
+	}
+	if vObj.Spec.Type == pObj.Spec.Type && vObj.Spec.ExternalTrafficPolicy == "" && pObj.Spec.ExternalTrafficPolicy != "" {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Spec.ExternalTrafficPolicy = pObj.Spec.ExternalTrafficPolicy
+	}
+	if vObj.Spec.Type == pObj.Spec.Type && vObj.Spec.AllocateLoadBalancerNodePorts == nil && pObj.Spec.AllocateLoadBalancerNodePorts != nil {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Spec.AllocateLoadBalancerNodePorts = pObj.Spec.AllocateLoadBalancerNodePorts
+	}
+
+	// check if we need to sync node ports from host to virtual, node ports requested by the user are kept
+	if pObj.Spec.Type == vObj.Spec.Type && portsEqual(pObj, vObj) && !equality.Semantic.DeepEqual(vObj.Spec.Ports, pObj.Spec.Ports) {
+		ports := make([]corev1.ServicePort, len(pObj.Spec.Ports))
+		synced := syncedNodePorts(pObj)
+		for i := range pObj.Spec.Ports {
+			ports[i] = pObj.Spec.Ports[i]
+			if isRequestedNodePort(vObj.Spec.Ports[i], pObj.Spec.Ports[i], synced) {
+				ports[i].NodePort = vObj.Spec.Ports[i].NodePort
+			}
+		}
+		if !equality.Semantic.DeepEqual(vObj.Spec.Ports, ports) {
+			updated = translator.NewIfNil(updated, vObj)
+			updated.Spec.Ports = ports
+		}
 	}
 
 	return updated
@@ -85,14 +171,18 @@ func (s *serviceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.
 	if vObj.Spec.ClusterIP == pObj.Spec.ClusterIP {
 		delete(updatedAnnotations, ServiceBlockDeletion)
 	}
+	// remember the node ports both services agree on
+	if vObj.Spec.Type == pObj.Spec.Type && hasNodePorts(pObj) && equality.Semantic.DeepEqual(vObj.Spec.Ports, pObj.Spec.Ports) {
+		updatedAnnotations[SyncedNodePortsAnnotation] = formatNodePorts(pObj.Spec.Ports)
+	}
 	if !equality.Semantic.DeepEqual(updatedAnnotations, pObj.Annotations) || !equality.Semantic.DeepEqual(updatedLabels, pObj.Labels) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Annotations = updatedAnnotations
 		updated.Labels = updatedLabels
 	}
 
-	// check ports
-	if !equality.Semantic.DeepEqual(vObj.Spec.Ports, pObj.Spec.Ports) {
+	// check ports, differing node ports of the same type are handled by the requested node ports sync
+	if !portsEqual(pObj, vObj) || (vObj.Spec.Type != pObj.Spec.Type && !equality.Semantic.DeepEqual(vObj.Spec.Ports, pObj.Spec.Ports)) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Spec.Ports = vObj.Spec.Ports
 
@@ -113,9 +203,10 @@ func (s *serviceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.
 	}
 
 	// external name
-	if vObj.Spec.ExternalName != pObj.Spec.ExternalName {
+	externalName := s.externalNameMapping.ToHost(vObj.Spec.ExternalName)
+	if externalName != pObj.Spec.ExternalName {
 		updated = translator.NewIfNil(updated, pObj)
-		updated.Spec.ExternalName = vObj.Spec.ExternalName
+		updated.Spec.ExternalName = externalName
 	}
 
 	// externalTrafficPolicy
@@ -124,6 +215,22 @@ func (s *serviceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.
 		updated.Spec.ExternalTrafficPolicy = vObj.Spec.ExternalTrafficPolicy
 	}
 
+	// internalTrafficPolicy
+	if !equality.Semantic.DeepEqual(vObj.Spec.InternalTrafficPolicy, pObj.Spec.InternalTrafficPolicy) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Spec.InternalTrafficPolicy = vObj.Spec.InternalTrafficPolicy
+	}
+
+	// ip family policy, the ip families are chosen by the host cluster
+	if vObj.Spec.Type != corev1.ServiceTypeExternalName && isDualStack(vObj.Spec.IPFamilyPolicy) != isDualStack(pObj.Spec.IPFamilyPolicy) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Spec.IPFamilyPolicy = TranslateIPFamilyPolicy(vObj)
+		if updated.Spec.IPFamilyPolicy == nil {
+			policy := corev1.IPFamilyPolicySingleStack
+			updated.Spec.IPFamilyPolicy = &policy
+		}
+	}
+
 	// session affinity
 	if vObj.Spec.SessionAffinity != pObj.Spec.SessionAffinity {
 		updated = translator.NewIfNil(updated, pObj)
@@ -136,6 +243,12 @@ func (s *serviceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.
 		updated.Spec.SessionAffinityConfig = vObj.Spec.SessionAffinityConfig
 	}
 
+	// allocateLoadBalancerNodePorts
+	if !equality.Semantic.DeepEqual(vObj.Spec.AllocateLoadBalancerNodePorts, pObj.Spec.AllocateLoadBalancerNodePorts) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Spec.AllocateLoadBalancerNodePorts = vObj.Spec.AllocateLoadBalancerNodePorts
+	}
+
 	// load balancer source ranges
 	if !equality.Semantic.DeepEqual(vObj.Spec.LoadBalancerSourceRanges, pObj.Spec.LoadBalancerSourceRanges) {
 		updated = translator.NewIfNil(updated, pObj)
@@ -4,24 +4,44 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/pkg/errors"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func NewHostStorageClassSyncer(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	selector := labels.Everything()
+	if ctx.Options.HostStorageClassSelector != "" {
+		var err error
+		selector, err = labels.Parse(ctx.Options.HostStorageClassSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse host storage class selector")
+		}
+	}
+
 	return &hostStorageClassSyncer{
 		Translator: translator.NewMirrorPhysicalTranslator("host-storageclass", &storagev1.StorageClass{}),
+
+		selector: selector,
 	}, nil
 }
 
 type hostStorageClassSyncer struct {
 	translator.Translator
+
+	// selector selects the host storage classes that are synced into the virtual cluster
+	selector labels.Selector
 }
 
 var _ syncer.UpSyncer = &hostStorageClassSyncer{}
 
 func (s *hostStorageClassSyncer) SyncUp(ctx *synccontext.SyncContext, pObj client.Object) (ctrl.Result, error) {
+	if !s.selector.Matches(labels.Set(pObj.GetLabels())) {
+		return ctrl.Result{}, nil
+	}
+
 	vObj := s.translateBackwards(ctx.Context, pObj.(*storagev1.StorageClass))
 	ctx.Log.Infof("create storage class %s, because it does not exist in virtual cluster", vObj.Name)
 	return ctrl.Result{}, ctx.VirtualClient.Create(ctx.Context, vObj)
@@ -30,6 +50,11 @@ func (s *hostStorageClassSyncer) SyncUp(ctx *synccontext.SyncContext, pObj clien
 var _ syncer.Syncer = &hostStorageClassSyncer{}
 
 func (s *hostStorageClassSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	if !s.selector.Matches(labels.Set(pObj.GetLabels())) {
+		ctx.Log.Infof("delete virtual storage class %s, because physical object does not match the host storage class selector", vObj.GetName())
+		return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
+	}
+
 	// check if there is a change
 	updated := s.translateUpdateBackwards(ctx.Context, pObj.(*storagev1.StorageClass), vObj.(*storagev1.StorageClass))
 	if updated != nil {
@@ -94,3 +94,79 @@ func TestSync(t *testing.T) {
 		},
 	})
 }
+
+func TestHostStorageClassSync(t *testing.T) {
+	selectedObject := &v1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-a",
+			Labels: map[string]string{
+				"tenant": "a",
+			},
+			Annotations: map[string]string{
+				"storageclass.kubernetes.io/is-default-class": "true",
+			},
+		},
+		Provisioner: "my-provisioner",
+	}
+	otherObject := &v1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-b",
+			Labels: map[string]string{
+				"tenant": "b",
+			},
+		},
+		Provisioner: "my-provisioner",
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Sync Up selected storage class",
+			InitialPhysicalState: []runtime.Object{selectedObject},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("StorageClass"): {selectedObject},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("StorageClass"): {selectedObject},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.HostStorageClassSelector = "tenant=a"
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewHostStorageClassSyncer)
+				_, err := syncer.(*hostStorageClassSyncer).SyncUp(syncCtx, selectedObject)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Don't sync up other storage class",
+			InitialPhysicalState: []runtime.Object{otherObject},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("StorageClass"): {},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("StorageClass"): {otherObject},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.HostStorageClassSelector = "tenant=a"
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewHostStorageClassSyncer)
+				_, err := syncer.(*hostStorageClassSyncer).SyncUp(syncCtx, otherObject)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Delete storage class that is not selected anymore",
+			InitialVirtualState:  []runtime.Object{otherObject},
+			InitialPhysicalState: []runtime.Object{otherObject},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("StorageClass"): {},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				v1.SchemeGroupVersion.WithKind("StorageClass"): {otherObject},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Options.HostStorageClassSelector = "tenant=a"
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, NewHostStorageClassSyncer)
+				_, err := syncer.(*hostStorageClassSyncer).Sync(syncCtx, otherObject, otherObject)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
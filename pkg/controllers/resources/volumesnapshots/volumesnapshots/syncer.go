@@ -7,6 +7,7 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/loft-sh/vcluster/pkg/util"
 
@@ -65,7 +66,7 @@ func (s *volumeSnapshotSyncer) SyncDown(ctx *synccontext.SyncContext, vObj clien
 
 	pObj, err := s.translate(ctx, vVS)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, syncerrors.NewTranslationError(err)
 	}
 
 	return s.SyncDownCreate(ctx, vObj, pObj)
@@ -107,9 +108,13 @@ func (s *volumeSnapshotSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Ob
 				return ctrl.Result{}, err
 			}
 		}
-		if !equality.Semantic.DeepEqual(vVS.Status, pVS.Status) {
+		status, err := s.translateStatusBackwards(ctx, pVS)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !equality.Semantic.DeepEqual(vVS.Status, status) {
 			updated := vVS.DeepCopy()
-			updated.Status = pVS.Status.DeepCopy()
+			updated.Status = status
 			ctx.Log.Infof("update virtual VolumeSnapshot %s, because status has changed", vVS.Name)
 			translator.PrintChanges(vObj, updated, ctx.Log)
 			err := ctx.VirtualClient.Status().Update(ctx.Context, updated)
@@ -144,9 +149,13 @@ func (s *volumeSnapshotSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Ob
 	}
 
 	// check backwards status
-	if !equality.Semantic.DeepEqual(vVS.Status, pVS.Status) {
+	status, err := s.translateStatusBackwards(ctx, pVS)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !equality.Semantic.DeepEqual(vVS.Status, status) {
 		updated := vVS.DeepCopy()
-		updated.Status = pVS.Status.DeepCopy()
+		updated.Status = status
 		ctx.Log.Infof("update virtual volume snapshot %s/%s, because the status has changed", vVS.Namespace, vVS.Name)
 		translator.PrintChanges(vObj, updated, ctx.Log)
 		err := ctx.VirtualClient.Status().Update(ctx.Context, updated)
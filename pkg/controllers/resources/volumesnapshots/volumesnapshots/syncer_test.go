@@ -8,12 +8,15 @@ import (
 	"gotest.tools/assert"
 
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshotcontents"
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -100,6 +103,17 @@ func TestSync(t *testing.T) {
 	vWithStatus := vPVSourceSnapshot.DeepCopy()
 	vWithStatus.Status = pWithStatus.Status
 
+	pWithBoundStatus := pPVSourceSnapshot.DeepCopy()
+	pWithBoundStatus.Status = &volumesnapshotv1.VolumeSnapshotStatus{
+		BoundVolumeSnapshotContentName: pointer.String(translate.Default.PhysicalNameClusterScoped(vVolumeSnapshotContent.Name)),
+		ReadyToUse:                     pointer.Bool(true),
+	}
+	vWithBoundStatus := vPVSourceSnapshot.DeepCopy()
+	vWithBoundStatus.Status = &volumesnapshotv1.VolumeSnapshotStatus{
+		BoundVolumeSnapshotContentName: pointer.String(vVolumeSnapshotContent.Name),
+		ReadyToUse:                     pointer.Bool(true),
+	}
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name:                 "Create with PersistentVolume source",
@@ -213,6 +227,27 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Sync status with the virtual name of the bound VolumeSnapshotContent",
+			InitialVirtualState:  []runtime.Object{vPVSourceSnapshot.DeepCopy(), vVolumeSnapshotContent.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pWithBoundStatus},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumesnapshotv1.SchemeGroupVersion.WithKind("VolumeSnapshot"): {vWithBoundStatus},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumesnapshotv1.SchemeGroupVersion.WithKind("VolumeSnapshot"): {pWithBoundStatus},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &volumesnapshotv1.VolumeSnapshotContent{}, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
+					return []string{volumesnapshotcontents.NewVolumeSnapshotContentTranslator()(rawObj.GetName(), rawObj)}
+				})
+				assert.NilError(t, err)
+
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err = syncer.(*volumeSnapshotSyncer).Sync(syncCtx, pWithBoundStatus, vPVSourceSnapshot.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Delete in host when virtual is being deleted",
 			InitialVirtualState:  []runtime.Object{vDeletingSnapshot},
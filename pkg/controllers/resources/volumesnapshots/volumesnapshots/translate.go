@@ -8,8 +8,10 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -67,3 +69,25 @@ func (s *volumeSnapshotSyncer) translateUpdateBackwards(pObj, vObj *volumesnapsh
 	}
 	return updated
 }
+
+// translateStatusBackwards returns the status of the physical volume snapshot with the name of the
+// virtual volume snapshot content it is bound to
+func (s *volumeSnapshotSyncer) translateStatusBackwards(ctx *synccontext.SyncContext, pVS *volumesnapshotv1.VolumeSnapshot) (*volumesnapshotv1.VolumeSnapshotStatus, error) {
+	status := pVS.Status.DeepCopy()
+	if status == nil || status.BoundVolumeSnapshotContentName == nil {
+		return status, nil
+	}
+
+	vVSC := &volumesnapshotv1.VolumeSnapshotContent{}
+	err := clienthelper.GetByIndex(ctx.Context, ctx.VirtualClient, vVSC, constants.IndexByPhysicalName, *status.BoundVolumeSnapshotContentName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return status, nil
+		}
+
+		return nil, err
+	}
+
+	status.BoundVolumeSnapshotContentName = &vVSC.Name
+	return status, nil
+}
@@ -0,0 +1,123 @@
+package rootca
+
+import (
+	"bytes"
+	"context"
+	"os"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// ConfigMapName is the name of the config map that holds the virtual cluster ca in every namespace
+	ConfigMapName = "kube-root-ca.crt"
+
+	// ConfigMapKey is the key of the ca bundle within the config map
+	ConfigMapKey = "ca.crt"
+
+	// DescriptionAnnotation is set by the kube controller manager on the published config map
+	DescriptionAnnotation = "kubernetes.io/description"
+)
+
+// description is the same description the root ca publisher of the kube controller manager uses
+const description = "Contains a CA bundle that can be used to verify the kube-apiserver when using internal endpoints such as the internal service IP or kubernetes.default.svc. " +
+	"No other usage is guaranteed across distributions of Kubernetes clusters."
+
+// RootCAReconciler publishes the ca of the virtual cluster as kube-root-ca.crt config map into
+// every virtual namespace, so workloads can verify the virtual api server. The ca file is read
+// on every reconcile, which picks up a rotated ca with the next namespace or config map event.
+type RootCAReconciler struct {
+	client.Client
+
+	CAFile string
+	Log    loghelper.Logger
+}
+
+func (r *RootCAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ns := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: req.Name}, ns)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	} else if ns.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	ca, err := os.ReadFile(r.CAFile)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: ConfigMapName}, configMap)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		r.Log.Infof("publish root ca in namespace %s", ns.Name)
+		err = r.Create(ctx, NewConfigMap(ns.Name, ca))
+		if kerrors.IsAlreadyExists(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !NeedsUpdate(configMap, ca) {
+		return ctrl.Result{}, nil
+	}
+
+	r.Log.Infof("update root ca in namespace %s", ns.Name)
+	configMap.Data = map[string]string{ConfigMapKey: string(ca)}
+	configMap.BinaryData = nil
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[DescriptionAnnotation] = description
+	return ctrl.Result{}, r.Update(ctx, configMap)
+}
+
+// NewConfigMap returns the kube-root-ca.crt config map of the given namespace
+func NewConfigMap(namespace string, ca []byte) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      ConfigMapName,
+			Annotations: map[string]string{
+				DescriptionAnnotation: description,
+			},
+		},
+		Data: map[string]string{
+			ConfigMapKey: string(ca),
+		},
+	}
+}
+
+// NeedsUpdate returns true if the config map does not hold exactly the given ca
+func NeedsUpdate(configMap *corev1.ConfigMap, ca []byte) bool {
+	return len(configMap.Data) != 1 || len(configMap.BinaryData) != 0 || !bytes.Equal([]byte(configMap.Data[ConfigMapKey]), ca)
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *RootCAReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("root_ca_publisher").
+		For(&corev1.Namespace{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []ctrl.Request {
+			return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: obj.GetNamespace()}}}
+		}), builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == ConfigMapName
+		}))).
+		Complete(r)
+}
@@ -0,0 +1,52 @@
+package rootca
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	err := os.WriteFile(caFile, []byte("virtual-ca"), 0600)
+	assert.NilError(t, err)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	hostCA := NewConfigMap("test", []byte("host-ca"))
+	testCases := []struct {
+		name    string
+		objects []client.Object
+	}{
+		{
+			name:    "Create missing config map",
+			objects: []client.Object{namespace.DeepCopy()},
+		},
+		{
+			name:    "Replace ca of other cluster",
+			objects: []client.Object{namespace.DeepCopy(), hostCA.DeepCopy()},
+		},
+	}
+
+	for _, testCase := range testCases {
+		fakeClient := fake.NewClientBuilder().WithObjects(testCase.objects...).Build()
+		r := &RootCAReconciler{Client: fakeClient, CAFile: caFile, Log: loghelper.New("test")}
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "test"}})
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		configMap := &corev1.ConfigMap{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: ConfigMapName}, configMap)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, configMap.Data[ConfigMapKey], "virtual-ca", "unexpected ca in test case %s", testCase.name)
+		assert.Equal(t, NeedsUpdate(configMap, []byte("virtual-ca")), false, "unexpected config map in test case %s", testCase.name)
+	}
+}
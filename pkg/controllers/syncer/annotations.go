@@ -0,0 +1,50 @@
+package syncer
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncBackAnnotations copies the annotations of pObj that are owned by host controllers to vObj
+// and removes them from vObj if they were removed from pObj. vObj is updated in place.
+func syncBackAnnotations(ctx *synccontext.SyncContext, pObj, vObj client.Object) error {
+	newAnnotations, changed := syncBackKeys(pObj.GetAnnotations(), vObj.GetAnnotations(), translate.IsSyncBackAnnotation)
+	if !changed {
+		return nil
+	}
+
+	ctx.Log.Infof("sync back host annotations of %s to virtual object", pObj.GetName())
+	patch := client.MergeFrom(vObj.DeepCopyObject().(client.Object))
+	vObj.SetAnnotations(newAnnotations)
+	return ctx.VirtualClient.Patch(ctx.Context, vObj, patch)
+}
+
+// syncBackKeys returns vValues with the keys owned by the host copied from pValues and the owned
+// keys that are missing in pValues removed, and whether anything changed
+func syncBackKeys(pValues, vValues map[string]string, owned func(key string) bool) (map[string]string, bool) {
+	newValues := map[string]string{}
+	changed := false
+	for key, value := range vValues {
+		if owned(key) {
+			if _, ok := pValues[key]; !ok {
+				changed = true
+				continue
+			}
+		}
+
+		newValues[key] = value
+	}
+	for key, value := range pValues {
+		if !owned(key) {
+			continue
+		}
+
+		if existing, ok := vValues[key]; !ok || existing != value {
+			newValues[key] = value
+			changed = true
+		}
+	}
+
+	return newValues, changed
+}
@@ -0,0 +1,77 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSyncBackAnnotations(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	translate.SyncBackAnnotations = []string{"scanner.example.com/*", "cloud.example.com/lb-id"}
+	defer func() {
+		translate.SyncBackAnnotations = nil
+	}()
+
+	vService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"user":                       "value",
+				"scanner.example.com/report": "outdated",
+				"scanner.example.com/score":  "removed",
+			},
+		},
+	}
+	pService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName("test", "default"),
+			Namespace: "test",
+			Annotations: map[string]string{
+				"user":                       "value",
+				"host":                       "not synced back",
+				"scanner.example.com/report": "passed",
+				"cloud.example.com/lb-id":    "lb-1",
+			},
+		},
+	}
+
+	virtualClient := testingutil.NewFakeClient(testingutil.NewScheme(), vService.DeepCopy())
+	ctx := &synccontext.SyncContext{
+		Context:       context.Background(),
+		Log:           loghelper.New("test"),
+		VirtualClient: virtualClient,
+	}
+
+	vObj := &corev1.Service{}
+	err := virtualClient.Get(ctx.Context, types.NamespacedName{Name: "test", Namespace: "default"}, vObj)
+	assert.NilError(t, err)
+	err = syncBackAnnotations(ctx, pService, vObj)
+	assert.NilError(t, err)
+
+	expectedAnnotations := map[string]string{
+		"user":                       "value",
+		"scanner.example.com/report": "passed",
+		"cloud.example.com/lb-id":    "lb-1",
+	}
+	assert.DeepEqual(t, vObj.Annotations, expectedAnnotations)
+	err = virtualClient.Get(ctx.Context, types.NamespacedName{Name: "test", Namespace: "default"}, vObj)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vObj.Annotations, expectedAnnotations)
+
+	// changes in the virtual cluster don't overwrite the host annotations
+	vObj.Annotations["scanner.example.com/report"] = "changed"
+	_, updatedAnnotations, _ := translate.Default.ApplyMetadataUpdate(vObj, pService, nil)
+	assert.Equal(t, updatedAnnotations["scanner.example.com/report"], "passed")
+	assert.Equal(t, updatedAnnotations["cloud.example.com/lb-id"], "lb-1")
+	assert.Equal(t, updatedAnnotations["host"], "not synced back")
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 
 	controllercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	"github.com/loft-sh/vcluster/pkg/operations"
+	"github.com/loft-sh/vcluster/pkg/scheduler"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/resourcenames"
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,6 +35,16 @@ type RegisterContext struct {
 
 	VirtualManager  ctrl.Manager
 	PhysicalManager ctrl.Manager
+
+	// Operations is the registry syncers register with to be reachable
+	// through the operations api
+	Operations *operations.Registry
+
+	// HostCache holds the host nodes and pods of all namespaces
+	HostCache *scheduler.HostCache
+
+	// ResourceNames maps extended resource names between the virtual and the host cluster
+	ResourceNames *resourcenames.Mapping
 }
 
 func ConvertContext(registerContext *RegisterContext, logName string) *SyncContext {
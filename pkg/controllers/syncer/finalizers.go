@@ -0,0 +1,117 @@
+package syncer
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncFinalizers propagates the finalizers of vObj and pObj according to the finalizer sync policy.
+// Both objects are updated in place.
+func syncFinalizers(ctx *synccontext.SyncContext, pObj, vObj client.Object) error {
+	policy := translate.FinalizerSyncPolicyFor(vObj)
+	if policy == nil {
+		return nil
+	}
+
+	// finalizers of virtual controllers are propagated to the physical object
+	if len(policy.Down) > 0 {
+		newFinalizers := mergeFinalizers(pObj, vObj, policy.IsDown)
+		if !equality.Semantic.DeepEqual(newFinalizers, pObj.GetFinalizers()) {
+			ctx.Log.Infof("update finalizers of physical object %s", pObj.GetName())
+			err := patchFinalizers(ctx, ctx.PhysicalClient, pObj, newFinalizers)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// finalizers of host controllers are propagated to the virtual object
+	if len(policy.Up) > 0 {
+		newFinalizers := mergeFinalizers(vObj, pObj, policy.IsUp)
+		if !equality.Semantic.DeepEqual(newFinalizers, vObj.GetFinalizers()) {
+			ctx.Log.Infof("update finalizers of virtual object %s", vObj.GetName())
+			err := patchFinalizers(ctx, ctx.VirtualClient, vObj, newFinalizers)
+			if err != nil {
+				return err
+			}
+		}
+
+		// host controllers only run their finalizers if the physical object is deleted
+		if vObj.GetDeletionTimestamp() != nil && pObj.GetDeletionTimestamp() == nil && hasFinalizer(vObj, policy.IsUp) {
+			_, err := DeleteObject(ctx, pObj, "virtual object is deleted and waits for host finalizers")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stripFinalizers removes the finalizers matched by owned from obj, e.g. if the object they
+// were propagated from doesn't exist anymore
+func stripFinalizers(ctx *synccontext.SyncContext, kubeClient client.Client, obj client.Object, owned func(string) bool) error {
+	if !hasFinalizer(obj, owned) {
+		return nil
+	}
+
+	newFinalizers := []string{}
+	for _, finalizer := range obj.GetFinalizers() {
+		if !owned(finalizer) {
+			newFinalizers = append(newFinalizers, finalizer)
+		}
+	}
+
+	ctx.Log.Infof("remove propagated finalizers of %s", obj.GetName())
+	return patchFinalizers(ctx, kubeClient, obj, newFinalizers)
+}
+
+// mergeFinalizers returns the finalizers of to without the ones matched by owned, plus the ones of
+// from that are matched by owned. Finalizers can't be added to objects that are deleted.
+func mergeFinalizers(to, from client.Object, owned func(string) bool) []string {
+	newFinalizers := []string{}
+	existing := map[string]bool{}
+	for _, finalizer := range to.GetFinalizers() {
+		existing[finalizer] = true
+		if owned(finalizer) && !containsFinalizer(from.GetFinalizers(), finalizer) {
+			continue
+		}
+
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+	if to.GetDeletionTimestamp() == nil {
+		for _, finalizer := range from.GetFinalizers() {
+			if owned(finalizer) && !existing[finalizer] {
+				newFinalizers = append(newFinalizers, finalizer)
+			}
+		}
+	}
+
+	return newFinalizers
+}
+
+func patchFinalizers(ctx *synccontext.SyncContext, kubeClient client.Client, obj client.Object, finalizers []string) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	obj.SetFinalizers(finalizers)
+	return kubeClient.Patch(ctx.Context, obj, patch)
+}
+
+func hasFinalizer(obj client.Object, owned func(string) bool) bool {
+	for _, finalizer := range obj.GetFinalizers() {
+		if owned(finalizer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFinalizer(finalizers []string, finalizer string) bool {
+	for _, f := range finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+
+	return false
+}
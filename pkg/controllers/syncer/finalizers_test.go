@@ -0,0 +1,84 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSyncFinalizers(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	translate.FinalizerSyncPolicies = map[string]*translate.FinalizerSyncPolicy{
+		"services": {Down: []string{"virtual.example.com/*"}, Up: []string{"service.kubernetes.io/load-balancer-cleanup"}},
+	}
+	defer func() {
+		translate.FinalizerSyncPolicies = nil
+	}()
+
+	vService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "default",
+			Finalizers: []string{"virtual.example.com/cleanup", "virtual.other.com/stripped"},
+		},
+	}
+
+	// finalizers of virtual controllers are set on creation
+	pService := translate.Default.ApplyMetadata(vService, nil).(*corev1.Service)
+	assert.DeepEqual(t, pService.Finalizers, []string{"virtual.example.com/cleanup"})
+
+	// host controllers add their finalizers
+	pService.Finalizers = append(pService.Finalizers, "service.kubernetes.io/load-balancer-cleanup", "host.example.com/not-propagated")
+
+	virtualClient := testingutil.NewFakeClient(testingutil.NewScheme(), vService.DeepCopy())
+	physicalClient := testingutil.NewFakeClient(testingutil.NewScheme(), pService.DeepCopy())
+	ctx := &synccontext.SyncContext{
+		Context:        context.Background(),
+		Log:            loghelper.New("test"),
+		VirtualClient:  virtualClient,
+		PhysicalClient: physicalClient,
+	}
+
+	vObj := &corev1.Service{}
+	err := virtualClient.Get(ctx.Context, types.NamespacedName{Name: "test", Namespace: "default"}, vObj)
+	assert.NilError(t, err)
+	pObj := &corev1.Service{}
+	err = physicalClient.Get(ctx.Context, types.NamespacedName{Name: pService.Name, Namespace: pService.Namespace}, pObj)
+	assert.NilError(t, err)
+
+	// the virtual controller is done
+	vObj.Finalizers = []string{"virtual.other.com/stripped"}
+	err = syncFinalizers(ctx, pObj, vObj)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pObj.Finalizers, []string{"service.kubernetes.io/load-balancer-cleanup", "host.example.com/not-propagated"})
+	assert.DeepEqual(t, vObj.Finalizers, []string{"virtual.other.com/stripped", "service.kubernetes.io/load-balancer-cleanup"})
+	err = virtualClient.Get(ctx.Context, types.NamespacedName{Name: "test", Namespace: "default"}, vObj)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vObj.Finalizers, []string{"virtual.other.com/stripped", "service.kubernetes.io/load-balancer-cleanup"})
+
+	// host finalizers are removed when the physical object is gone
+	policy := translate.FinalizerSyncPolicyFor(vObj)
+	err = stripFinalizers(ctx, virtualClient, vObj, policy.IsUp)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vObj.Finalizers, []string{"virtual.other.com/stripped"})
+}
+
+func TestParseFinalizerSyncPolicies(t *testing.T) {
+	policies, err := translate.ParseFinalizerSyncPolicies([]string{"services:up=service.kubernetes.io/load-balancer-cleanup", "*:down=example.com/*"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, policies, map[string]*translate.FinalizerSyncPolicy{
+		"services": {Up: []string{"service.kubernetes.io/load-balancer-cleanup"}},
+		"*":        {Down: []string{"example.com/*"}},
+	})
+
+	_, err = translate.ParseFinalizerSyncPolicies([]string{"services:immutable=example.com/cleanup"})
+	assert.ErrorContains(t, err, "direction must be one of")
+}
@@ -0,0 +1,26 @@
+package syncer
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncBackLabels copies the labels of pObj that are owned by host tooling according to the label
+// sync policy to vObj and removes them from vObj if they were removed from pObj. vObj is updated in place.
+func syncBackLabels(ctx *synccontext.SyncContext, pObj, vObj client.Object) error {
+	policy := translate.LabelSyncPolicyFor(vObj)
+	if policy == nil || len(policy.Up) == 0 {
+		return nil
+	}
+
+	newLabels, changed := syncBackKeys(pObj.GetLabels(), vObj.GetLabels(), policy.IsUp)
+	if !changed {
+		return nil
+	}
+
+	ctx.Log.Infof("sync back host labels of %s to virtual object", pObj.GetName())
+	patch := client.MergeFrom(vObj.DeepCopyObject().(client.Object))
+	vObj.SetLabels(newLabels)
+	return ctx.VirtualClient.Patch(ctx.Context, vObj, patch)
+}
@@ -0,0 +1,98 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParseLabelSyncPolicies(t *testing.T) {
+	policies, err := translate.ParseLabelSyncPolicies([]string{"pods:down=app.kubernetes.io/name", "pods:up=topology.example.com/*", "*:immutable=team"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, policies, map[string]*translate.LabelSyncPolicy{
+		"pods": {Down: []string{"app.kubernetes.io/name"}, Up: []string{"topology.example.com/*"}},
+		"*":    {Immutable: []string{"team"}},
+	})
+
+	for _, invalid := range []string{"pods=team", "pods:sideways=team", "pods:down=", "pods:down=invalid key"} {
+		_, err = translate.ParseLabelSyncPolicies([]string{invalid})
+		assert.Assert(t, err != nil, "expected error for %s", invalid)
+	}
+}
+
+func TestSyncBackLabels(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	translate.LabelSyncPolicies = map[string]*translate.LabelSyncPolicy{
+		"services": {Down: []string{"app.kubernetes.io/name"}, Up: []string{"scanner.example.com/*"}},
+		"*":        {Immutable: []string{"team"}},
+	}
+	defer func() {
+		translate.LabelSyncPolicies = nil
+	}()
+
+	vService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/name":     "web",
+				"team":                       "a",
+				"scanner.example.com/report": "outdated",
+				"scanner.example.com/score":  "removed",
+			},
+		},
+	}
+
+	// labels are synced down as is, except the ones owned by host tooling
+	pService := translate.Default.ApplyMetadata(vService, nil).(*corev1.Service)
+	assert.Equal(t, pService.Labels["app.kubernetes.io/name"], "web")
+	assert.Equal(t, pService.Labels[translate.Default.ConvertLabelKey("team")], "a")
+	_, ok := pService.Labels["scanner.example.com/report"]
+	assert.Assert(t, !ok)
+
+	// host tooling adds labels
+	pService.Labels["scanner.example.com/report"] = "passed"
+	pService.Labels["cloud.example.com/lb-id"] = "lb-1"
+
+	virtualClient := testingutil.NewFakeClient(testingutil.NewScheme(), vService.DeepCopy())
+	ctx := &synccontext.SyncContext{
+		Context:       context.Background(),
+		Log:           loghelper.New("test"),
+		VirtualClient: virtualClient,
+	}
+
+	vObj := &corev1.Service{}
+	err := virtualClient.Get(ctx.Context, types.NamespacedName{Name: "test", Namespace: "default"}, vObj)
+	assert.NilError(t, err)
+	err = syncBackLabels(ctx, pService, vObj)
+	assert.NilError(t, err)
+
+	expectedLabels := map[string]string{
+		"app.kubernetes.io/name":     "web",
+		"team":                       "a",
+		"scanner.example.com/report": "passed",
+	}
+	assert.DeepEqual(t, vObj.Labels, expectedLabels)
+	err = virtualClient.Get(ctx.Context, types.NamespacedName{Name: "test", Namespace: "default"}, vObj)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vObj.Labels, expectedLabels)
+
+	// changes in the virtual cluster don't overwrite host labels and immutable labels
+	vObj.Labels["scanner.example.com/report"] = "changed"
+	vObj.Labels["team"] = "b"
+	vObj.Labels["app.kubernetes.io/name"] = "api"
+	_, _, updatedLabels := translate.Default.ApplyMetadataUpdate(vObj, pService, nil)
+	assert.Equal(t, updatedLabels["scanner.example.com/report"], "passed")
+	assert.Equal(t, updatedLabels[translate.Default.ConvertLabelKey("team")], "a")
+	assert.Equal(t, updatedLabels["app.kubernetes.io/name"], "api")
+	_, ok = updatedLabels["cloud.example.com/lb-id"]
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,199 @@
+package syncer
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/operations"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const operationsEventBufferSize = 1024
+
+var _ operations.Target = &syncerController{}
+
+func (r *syncerController) Resync(ctx context.Context, namespace, name string) (int, error) {
+	if name != "" {
+		vObj := r.syncer.Resource()
+		vObj.SetNamespace(namespace)
+		vObj.SetName(name)
+		return 1, enqueue(ctx, r.virtualEvents, vObj)
+	}
+
+	vObjs, err := r.list(ctx, r.virtualClient, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, vObj := range vObjs {
+		err = enqueue(ctx, r.virtualEvents, vObj)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(vObjs), nil
+}
+
+func (r *syncerController) GarbageCollect(ctx context.Context) (int, error) {
+	pObjs, err := r.list(ctx, r.physicalClient, "")
+	if err != nil {
+		return 0, err
+	}
+
+	// physical objects are enqueued through enqueuePhysical, which will
+	// filter out objects that are not managed by this syncer
+	for _, pObj := range pObjs {
+		err = enqueue(ctx, r.physicalEvents, pObj)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(pObjs), nil
+}
+
+func (r *syncerController) Pause(ctx context.Context, namespace, name string, paused bool) error {
+	vObj := r.syncer.Resource()
+	err := r.virtualClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, vObj)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.setPaused(ctx, vObj, paused)
+	return err
+}
+
+func (r *syncerController) Drain(ctx context.Context, namespace string, paused bool) (int, error) {
+	vObjs, err := r.list(ctx, r.virtualClient, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, vObj := range vObjs {
+		updated, err := r.setPaused(ctx, vObj, paused)
+		if err != nil {
+			return changed, err
+		} else if updated {
+			changed++
+		}
+	}
+
+	return changed, nil
+}
+
+func (r *syncerController) Paused(ctx context.Context, namespace string) ([]types.NamespacedName, error) {
+	vObjs, err := r.list(ctx, r.virtualClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	paused := []types.NamespacedName{}
+	for _, vObj := range vObjs {
+		if operations.IsPaused(vObj) {
+			paused = append(paused, types.NamespacedName{Namespace: vObj.GetNamespace(), Name: vObj.GetName()})
+		}
+	}
+
+	return paused, nil
+}
+
+// setPaused adds or removes the paused annotation on the virtual object. Removing the
+// annotation triggers a reconcile of the object, so no extra resync is needed on resume.
+func (r *syncerController) setPaused(ctx context.Context, vObj client.Object, paused bool) (bool, error) {
+	if operations.IsPaused(vObj) == paused {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(vObj.DeepCopyObject().(client.Object))
+	annotations := vObj.GetAnnotations()
+	if paused {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[operations.PausedAnnotation] = "true"
+	} else {
+		delete(annotations, operations.PausedAnnotation)
+	}
+	vObj.SetAnnotations(annotations)
+
+	err := r.virtualClient.Patch(ctx, vObj, patch)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *syncerController) Mappings(ctx context.Context, namespace string) ([]operations.Mapping, error) {
+	vObjs, err := r.list(ctx, r.virtualClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make([]operations.Mapping, 0, len(vObjs))
+	for _, vObj := range vObjs {
+		vName := types.NamespacedName{Namespace: vObj.GetNamespace(), Name: vObj.GetName()}
+		mappings = append(mappings, operations.Mapping{
+			Virtual:  vName,
+			Physical: r.syncer.VirtualToPhysical(ctx, vName, vObj),
+		})
+	}
+
+	return mappings, nil
+}
+
+// list returns all objects of the syncer resource type the given client has access to
+func (r *syncerController) list(ctx context.Context, kubeClient client.Client, namespace string) ([]client.Object, error) {
+	gvk, err := apiutil.GVKForObject(r.syncer.Resource(), kubeClient.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	var list client.ObjectList
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	obj, err := kubeClient.Scheme().New(listGVK)
+	if err == nil {
+		list = obj.(client.ObjectList)
+	} else {
+		unstructuredList := &unstructured.UnstructuredList{}
+		unstructuredList.SetGroupVersionKind(listGVK)
+		list = unstructuredList
+	}
+
+	err = kubeClient.List(ctx, list, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []client.Object{}
+	err = meta.EachListItem(list, func(obj runtime.Object) error {
+		clientObj, ok := obj.(client.Object)
+		if ok {
+			objs = append(objs, clientObj)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+// enqueue hands the object to the controller, but gives up if the request is cancelled
+// before the controller picks it up, e.g. because it is not running on this replica
+func enqueue(ctx context.Context, events chan<- event.GenericEvent, obj client.Object) error {
+	select {
+	case events <- event.GenericEvent{Object: obj}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
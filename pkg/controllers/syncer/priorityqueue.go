@@ -0,0 +1,146 @@
+package syncer
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// PriorityDefault is the priority of regular requests
+	PriorityDefault = 0
+	// PriorityHigh is the priority of requests that should be processed before regular requests
+	PriorityHigh = 1
+	// PriorityDeletion is the priority of deleted objects
+	PriorityDeletion = 2
+
+	numPriorities = PriorityDeletion + 1
+)
+
+// priorityQueue sits in front of the controller workqueue. As long as the workqueue is shallow,
+// requests are added directly. If it is deep, requests are held back and moved into the workqueue
+// by priority as soon as a worker takes the next request, so deletions and important objects are
+// not stuck behind a large number of creations.
+type priorityQueue struct {
+	maxDepth int
+
+	m       sync.Mutex
+	queue   workqueue.RateLimitingInterface
+	levels  [numPriorities][]reconcile.Request
+	pending map[reconcile.Request]int
+}
+
+func newPriorityQueue(maxDepth int) *priorityQueue {
+	return &priorityQueue{
+		maxDepth: maxDepth,
+		pending:  map[reconcile.Request]int{},
+	}
+}
+
+// Add adds the request with the given priority
+func (p *priorityQueue) Add(q workqueue.RateLimitingInterface, req reconcile.Request, priority int) {
+	if priority < PriorityDefault {
+		priority = PriorityDefault
+	} else if priority > PriorityDeletion {
+		priority = PriorityDeletion
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.queue = q
+	if len(p.pending) == 0 && q.Len() < p.maxDepth {
+		q.Add(req)
+		return
+	}
+
+	existing, ok := p.pending[req]
+	if ok && existing >= priority {
+		return
+	}
+	p.pending[req] = priority
+	p.levels[priority] = append(p.levels[priority], req)
+	p.flush()
+}
+
+// Len returns the number of held back requests
+func (p *priorityQueue) Len() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return len(p.pending)
+}
+
+// Next is called by the workers after each reconcile and moves held back requests into the workqueue
+// before the worker takes the next request
+func (p *priorityQueue) Next() {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.flush()
+}
+
+// flush moves held back requests into the workqueue until it is deep again
+func (p *priorityQueue) flush() {
+	if p.queue == nil || len(p.pending) == 0 {
+		return
+	}
+
+	for priority := numPriorities - 1; priority >= 0; priority-- {
+		for len(p.levels[priority]) > 0 {
+			if p.queue.Len() >= p.maxDepth {
+				return
+			}
+
+			req := p.levels[priority][0]
+			p.levels[priority] = p.levels[priority][1:]
+
+			// skip requests that were moved to a higher priority in the meantime
+			pendingPriority, ok := p.pending[req]
+			if !ok || pendingPriority != priority {
+				continue
+			}
+
+			delete(p.pending, req)
+			p.queue.Add(req)
+		}
+
+		// release the backing array of drained levels
+		p.levels[priority] = nil
+	}
+}
+
+// virtualPriorityHandler enqueues virtual objects with the priority of the syncer
+type virtualPriorityHandler struct {
+	prioritizer Prioritizer
+	queue       *priorityQueue
+}
+
+func (h *virtualPriorityHandler) Create(_ context.Context, evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.Object, false, q)
+}
+
+func (h *virtualPriorityHandler) Update(_ context.Context, evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.ObjectNew, false, q)
+}
+
+func (h *virtualPriorityHandler) Delete(_ context.Context, evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.Object, true, q)
+}
+
+func (h *virtualPriorityHandler) Generic(_ context.Context, evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.Object, false, q)
+}
+
+func (h *virtualPriorityHandler) enqueue(obj client.Object, deleted bool, q workqueue.RateLimitingInterface) {
+	if obj == nil {
+		return
+	}
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	h.queue.Add(q, req, h.prioritizer.Priority(obj, deleted))
+}
@@ -0,0 +1,52 @@
+package syncer
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+	}
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	p := newPriorityQueue(2)
+
+	// shallow workqueue
+	p.Add(q, request("a"), PriorityDefault)
+	p.Add(q, request("b"), PriorityDefault)
+	assert.Equal(t, q.Len(), 2)
+	assert.Equal(t, p.Len(), 0)
+
+	// deep workqueue
+	p.Add(q, request("create-1"), PriorityDefault)
+	p.Add(q, request("create-2"), PriorityDefault)
+	p.Add(q, request("critical"), PriorityHigh)
+	p.Add(q, request("delete"), PriorityDeletion)
+	p.Add(q, request("create-2"), PriorityDeletion)
+	p.Add(q, request("delete"), PriorityDefault)
+	assert.Equal(t, q.Len(), 2)
+	assert.Equal(t, p.Len(), 4)
+
+	// drain the workqueue and flush held back requests in order
+	drain := func() []string {
+		names := []string{}
+		for q.Len() > 0 {
+			item, _ := q.Get()
+			names = append(names, item.(reconcile.Request).Name)
+			q.Done(item)
+		}
+		return names
+	}
+	drain()
+	p.Next()
+	assert.DeepEqual(t, drain(), []string{"delete", "create-2"})
+	p.Next()
+	assert.DeepEqual(t, drain(), []string{"critical", "create-1"})
+	assert.Equal(t, p.Len(), 0)
+}
@@ -0,0 +1,82 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CheckVirtual returns a VirtualQuotaExceeded error if a resource quota in the virtual namespace
+// of vObj is exceeded for a resource vObj counts towards. The usage is aggregated by the resource
+// quota controller of the virtual cluster and already includes vObj, so only usage above the hard
+// limit is treated as exceeded. Quotas with scopes are ignored.
+func CheckVirtual(ctx context.Context, virtualClient client.Client, vObj client.Object) error {
+	quotaList := &corev1.ResourceQuotaList{}
+	err := virtualClient.List(ctx, quotaList, client.InNamespace(vObj.GetNamespace()))
+	if err != nil {
+		return fmt.Errorf("list resource quotas: %w", err)
+	}
+
+	for _, quota := range quotaList.Items {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+
+		exceeded := []string{}
+		for name, hard := range quota.Status.Hard {
+			used, ok := quota.Status.Used[name]
+			if !ok || !countsTowards(vObj, name) || used.Cmp(hard) <= 0 {
+				continue
+			}
+
+			exceeded = append(exceeded, fmt.Sprintf("%s: used %s, limited %s", name, used.String(), hard.String()))
+		}
+		if len(exceeded) > 0 {
+			sort.Strings(exceeded)
+			return syncerrors.New(syncerrors.VirtualQuotaExceeded, fmt.Errorf("resource quota %s of namespace %s exceeded: %s", quota.Name, quota.Namespace, strings.Join(exceeded, ", ")))
+		}
+	}
+
+	return nil
+}
+
+// countsTowards returns if obj is counted in the quota resource with the given name
+func countsTowards(obj client.Object, name corev1.ResourceName) bool {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return name == corev1.ResourcePods || name == "count/pods" || isComputeResource(name)
+	case *corev1.Service:
+		switch name {
+		case corev1.ResourceServices, "count/services":
+			return true
+		case corev1.ResourceServicesLoadBalancers:
+			return o.Spec.Type == corev1.ServiceTypeLoadBalancer
+		case corev1.ResourceServicesNodePorts:
+			return o.Spec.Type == corev1.ServiceTypeLoadBalancer || o.Spec.Type == corev1.ServiceTypeNodePort
+		}
+	case *corev1.PersistentVolumeClaim:
+		return name == corev1.ResourcePersistentVolumeClaims || name == "count/persistentvolumeclaims" || name == corev1.ResourceRequestsStorage || strings.Contains(string(name), ".storageclass.storage.k8s.io/")
+	case *corev1.ConfigMap:
+		return name == corev1.ResourceConfigMaps || name == "count/configmaps"
+	case *corev1.Secret:
+		return name == corev1.ResourceSecrets || name == "count/secrets"
+	}
+
+	return false
+}
+
+func isComputeResource(name corev1.ResourceName) bool {
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+		return true
+	case corev1.ResourceRequestsStorage:
+		return false
+	}
+
+	return strings.HasPrefix(string(name), "requests.") || strings.HasPrefix(string(name), "limits.") || strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix)
+}
@@ -0,0 +1,121 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCheckVirtual(t *testing.T) {
+	newQuota := func(name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: hard,
+			},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: hard,
+				Used: used,
+			},
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+	clusterIPService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	scopedQuota := newQuota("scoped", corev1.ResourceList{
+		corev1.ResourcePods: resource.MustParse("1"),
+	}, corev1.ResourceList{
+		corev1.ResourcePods: resource.MustParse("2"),
+	})
+	scopedQuota.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+
+	testCases := []struct {
+		name string
+
+		quotas []runtime.Object
+		obj    client.Object
+
+		expectedErr string
+	}{
+		{
+			name: "no quota",
+			obj:  pod,
+		},
+		{
+			name: "quota fully used",
+			quotas: []runtime.Object{newQuota("test", corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("2"),
+			}, corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("2"),
+			})},
+			obj: pod,
+		},
+		{
+			name: "quota exceeded",
+			quotas: []runtime.Object{newQuota("test", corev1.ResourceList{
+				corev1.ResourcePods:        resource.MustParse("2"),
+				corev1.ResourceLimitsCPU:   resource.MustParse("1"),
+				corev1.ResourceConfigMaps:  resource.MustParse("1"),
+				corev1.ResourceRequestsCPU: resource.MustParse("1"),
+			}, corev1.ResourceList{
+				corev1.ResourcePods:        resource.MustParse("2"),
+				corev1.ResourceLimitsCPU:   resource.MustParse("1500m"),
+				corev1.ResourceConfigMaps:  resource.MustParse("2"),
+				corev1.ResourceRequestsCPU: resource.MustParse("1"),
+			})},
+			obj:         pod,
+			expectedErr: "resource quota test of namespace test exceeded: limits.cpu: used 1500m, limited 1",
+		},
+		{
+			name: "quota of other resource exceeded",
+			quotas: []runtime.Object{newQuota("test", corev1.ResourceList{
+				corev1.ResourceServicesLoadBalancers: resource.MustParse("0"),
+				corev1.ResourcePods:                  resource.MustParse("0"),
+			}, corev1.ResourceList{
+				corev1.ResourceServicesLoadBalancers: resource.MustParse("1"),
+				corev1.ResourcePods:                  resource.MustParse("1"),
+			})},
+			obj: clusterIPService,
+		},
+		{
+			name:   "scoped quota exceeded",
+			quotas: []runtime.Object{scopedQuota},
+			obj:    pod,
+		},
+	}
+
+	for _, testCase := range testCases {
+		virtualClient := testingutil.NewFakeClient(testingutil.NewScheme(), testCase.quotas...)
+		err := CheckVirtual(context.Background(), virtualClient, testCase.obj)
+		if testCase.expectedErr == "" {
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.Error(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, syncerrors.Classify(err), syncerrors.VirtualQuotaExceeded, "unexpected class in test case %s", testCase.name)
+	}
+}
@@ -9,20 +9,30 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
+	"github.com/loft-sh/vcluster/pkg/operations"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	controller2 "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// maxConcurrentReconciles is the number of workers of a syncer
+const maxConcurrentReconciles = 10
+
 func RegisterSyncer(ctx *synccontext.RegisterContext, syncer Syncer) error {
 	options := &Options{}
 	optionsProvider, ok := syncer.(OptionsProvider)
@@ -40,6 +50,11 @@ func RegisterSyncer(ctx *synccontext.RegisterContext, syncer Syncer) error {
 
 		virtualClient: ctx.VirtualManager.GetClient(),
 		options:       options,
+
+		multiNamespaceMode: ctx.Options.MultiNamespaceMode,
+
+		virtualEvents:  make(chan event.GenericEvent, operationsEventBufferSize),
+		physicalEvents: make(chan event.GenericEvent, operationsEventBufferSize),
 	}
 
 	return controller.Register(ctx)
@@ -57,9 +72,39 @@ type syncerController struct {
 
 	virtualClient client.Client
 	options       *Options
+
+	// multiNamespaceMode is true if virtual namespaces are synced to host namespaces
+	multiNamespaceMode bool
+
+	// virtualEvents and physicalEvents are used to enqueue objects
+	// through the operations api
+	virtualEvents  chan event.GenericEvent
+	physicalEvents chan event.GenericEvent
+
+	// priorityQueue is used if the syncer implements Prioritizer
+	priorityQueue *priorityQueue
+
+	// operations is used to pause reconciling while the syncers are quiesced
+	operations *operations.Registry
 }
 
 func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if r.priorityQueue != nil {
+		defer r.priorityQueue.Next()
+	}
+	if r.operations != nil {
+		defer r.operations.StartSync()()
+	}
+
+	result, err := r.reconcile(ctx, req)
+	if err != nil {
+		syncerrors.Record(r.syncer.Name(), err)
+	}
+
+	return result, err
+}
+
+func (r *syncerController) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reconcileStart := time.Now()
 	log := loghelper.NewFromExisting(r.log.Base(), req.Name)
 	syncContext := &synccontext.SyncContext{
@@ -98,6 +143,10 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, nil
 	}
 
+	// check if the object was paused through the operations api, a paused object is
+	// not created or updated, but deletion and cleanup still run
+	paused := vObj != nil && vObj.GetDeletionTimestamp() == nil && operations.IsPaused(vObj)
+
 	// translate to physical name
 	pObj := r.syncer.Resource()
 	err = r.physicalClient.Get(ctx, r.syncer.VirtualToPhysical(ctx, req.NamespacedName, vObj), pObj)
@@ -117,6 +166,19 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	// check what function we should call
 	if vObj != nil && pObj == nil {
+		// the host controllers are done, if the physical object of a deleted virtual object is gone
+		if vObj.GetDeletionTimestamp() != nil && len(translate.FinalizerSyncPolicies) > 0 {
+			policy := translate.FinalizerSyncPolicyFor(vObj)
+			if hasFinalizer(vObj, policy.IsUp) {
+				return ctrl.Result{}, stripFinalizers(syncContext, r.virtualClient, vObj, policy.IsUp)
+			}
+		}
+
+		if paused {
+			log.Debugf("skip sync down, because object is paused")
+			return ctrl.Result{}, nil
+		}
+
 		return captureSyncTelemetry(r.syncer.SyncDown(syncContext, vObj))(vObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 	} else if vObj != nil && pObj != nil {
 		// make sure the object uid matches
@@ -127,10 +189,49 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 				return ctrl.Result{RequeueAfter: time.Second}, nil
 			}
 
+			// wait until the physical object is adopted, if it is restored into a recreated virtual namespace
+			if r.multiNamespaceMode && pObj.GetNamespace() != "" {
+				retained, err := r.isRetained(ctx, pObj.GetNamespace())
+				if err != nil {
+					return ctrl.Result{}, err
+				} else if retained {
+					return ctrl.Result{RequeueAfter: time.Second}, nil
+				}
+			}
+
 			// delete physical object
 			return captureSyncTelemetry(DeleteObject(syncContext, pObj, "virtual object uid is different"))(pObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 		}
 
+		if paused {
+			log.Debugf("skip sync, because object is paused")
+			return ctrl.Result{}, nil
+		}
+
+		// sync back annotations that are owned by host controllers
+		if len(translate.SyncBackAnnotations) > 0 {
+			err = syncBackAnnotations(syncContext, pObj, vObj)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// sync back labels that are owned by host tooling
+		if len(translate.LabelSyncPolicies) > 0 {
+			err = syncBackLabels(syncContext, pObj, vObj)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// propagate finalizers between virtual and host controllers
+		if len(translate.FinalizerSyncPolicies) > 0 {
+			err = syncFinalizers(syncContext, pObj, vObj)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
 		return captureSyncTelemetry(r.syncer.Sync(syncContext, pObj, vObj))(vObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 	} else if vObj == nil && pObj != nil {
 		if pObj.GetAnnotations() != nil {
@@ -140,18 +241,48 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			}
 		}
 
+		// keep objects of deleted virtual namespaces if the host namespace is retained
+		if r.multiNamespaceMode && pObj.GetNamespace() != "" {
+			retained, err := r.isRetained(ctx, pObj.GetNamespace())
+			if err != nil {
+				return ctrl.Result{}, err
+			} else if retained {
+				log.Debugf("skip delete, because physical namespace %s is retained", pObj.GetNamespace())
+				return ctrl.Result{}, nil
+			}
+		}
+
 		// check if up syncer
 		upSyncer, ok := r.syncer.(UpSyncer)
 		if ok {
 			return captureSyncTelemetry(upSyncer.SyncUp(syncContext, pObj))(pObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 		}
 
+		// the virtual controllers are done, if the virtual object is gone
+		if len(translate.FinalizerSyncPolicies) > 0 {
+			err = stripFinalizers(syncContext, r.physicalClient, pObj, translate.FinalizerSyncPolicyFor(pObj).IsDown)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
 		return captureSyncTelemetry(DeleteObject(syncContext, pObj, "virtual object was deleted"))(pObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// isRetained checks if the physical namespace is retained after its virtual namespace was deleted
+func (r *syncerController) isRetained(ctx context.Context, pNamespace string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	err := r.physicalClient.Get(ctx, types.NamespacedName{Name: pNamespace}, namespace)
+	if err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	return namespace.Annotations[translate.RetainAnnotation] == "true", nil
+}
+
 func (r *syncerController) excludePhysical(pObj client.Object) bool {
 	excluder, ok := r.syncer.(ObjectExcluder)
 	if ok {
@@ -192,26 +323,26 @@ func (r *syncerController) excludeVirtual(vObj client.Object) bool {
 
 // Create is called in response to an create event - e.g. Pod Creation.
 func (r *syncerController) Create(ctx context.Context, evt event.CreateEvent, q workqueue.RateLimitingInterface) {
-	r.enqueuePhysical(ctx, evt.Object, q)
+	r.enqueuePhysical(ctx, evt.Object, false, q)
 }
 
 // Update is called in response to an update event -  e.g. Pod Updated.
 func (r *syncerController) Update(ctx context.Context, evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
-	r.enqueuePhysical(ctx, evt.ObjectNew, q)
+	r.enqueuePhysical(ctx, evt.ObjectNew, false, q)
 }
 
 // Delete is called in response to a delete event - e.g. Pod Deleted.
 func (r *syncerController) Delete(ctx context.Context, evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
-	r.enqueuePhysical(ctx, evt.Object, q)
+	r.enqueuePhysical(ctx, evt.Object, true, q)
 }
 
 // Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
 // external trigger request - e.g. reconcile Autoscaling, or a Webhook.
 func (r *syncerController) Generic(ctx context.Context, evt event.GenericEvent, q workqueue.RateLimitingInterface) {
-	r.enqueuePhysical(ctx, evt.Object, q)
+	r.enqueuePhysical(ctx, evt.Object, false, q)
 }
 
-func (r *syncerController) enqueuePhysical(ctx context.Context, obj client.Object, q workqueue.RateLimitingInterface) {
+func (r *syncerController) enqueuePhysical(ctx context.Context, obj client.Object, deleted bool, q workqueue.RateLimitingInterface) {
 	if obj == nil {
 		return
 	}
@@ -225,19 +356,42 @@ func (r *syncerController) enqueuePhysical(ctx context.Context, obj client.Objec
 	}
 
 	name := r.syncer.PhysicalToVirtual(ctx, obj)
-	if name.Name != "" {
-		q.Add(reconcile.Request{NamespacedName: name})
+	if name.Name == "" {
+		return
+	}
+
+	prioritizer, ok := r.syncer.(Prioritizer)
+	if ok && r.priorityQueue != nil {
+		r.priorityQueue.Add(q, reconcile.Request{NamespacedName: name}, prioritizer.Priority(obj, deleted))
+		return
 	}
+
+	q.Add(reconcile.Request{NamespacedName: name})
 }
 
 func (r *syncerController) Register(ctx *synccontext.RegisterContext) error {
+	r.operations = ctx.Operations
 	controller := ctrl.NewControllerManagedBy(ctx.VirtualManager).
 		WithOptions(controller2.Options{
-			MaxConcurrentReconciles: 10,
+			MaxConcurrentReconciles: maxConcurrentReconciles,
 		}).
 		Named(r.syncer.Name()).
 		WatchesRawSource(source.Kind(ctx.PhysicalManager.GetCache(), r.syncer.Resource()), r).
-		For(r.syncer.Resource())
+		WatchesRawSource(&source.Channel{Source: r.physicalEvents}, r).
+		WatchesRawSource(&source.Channel{Source: r.virtualEvents}, &handler.EnqueueRequestForObject{})
+
+	// syncers with priorities hold back requests while the workqueue is deep, the virtual events are
+	// enqueued by the priority handler instead of the one of For
+	prioritizer, ok := r.syncer.(Prioritizer)
+	if ok {
+		r.priorityQueue = newPriorityQueue(maxConcurrentReconciles)
+		controller = controller.
+			For(r.syncer.Resource(), builder.WithPredicates(predicate.NewPredicateFuncs(func(client.Object) bool { return false }))).
+			Watches(r.syncer.Resource(), &virtualPriorityHandler{prioritizer: prioritizer, queue: r.priorityQueue})
+	} else {
+		controller = controller.For(r.syncer.Resource())
+	}
+
 	var err error
 	modifier, ok := r.syncer.(ControllerModifier)
 	if ok {
@@ -246,7 +400,15 @@ func (r *syncerController) Register(ctx *synccontext.RegisterContext) error {
 			return err
 		}
 	}
-	return controller.Complete(r)
+	err = controller.Complete(r)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Operations != nil {
+		ctx.Operations.Register(r.syncer.Name(), r)
+	}
+	return nil
 }
 
 func DeleteObject(ctx *synccontext.SyncContext, pObj client.Object, reason string) (ctrl.Result, error) {
@@ -0,0 +1,122 @@
+package syncerrors
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Class is a stable classification of a sync error. Classes are used as event reasons and
+// metric labels, so they should never be renamed.
+type Class string
+
+const (
+	// TranslationError means the virtual object could not be translated into a host object
+	TranslationError Class = "TranslationError"
+	// HostQuotaExceeded means a resource quota rejected the object
+	HostQuotaExceeded Class = "HostQuotaExceeded"
+	// VirtualQuotaExceeded means a resource quota of the virtual namespace is exceeded
+	VirtualQuotaExceeded Class = "VirtualQuotaExceeded"
+	// HostWebhookDenied means an admission webhook rejected the object
+	HostWebhookDenied Class = "HostWebhookDenied"
+	// Conflict means the object was changed concurrently or already exists
+	Conflict Class = "Conflict"
+	// NotFoundRace means an object was deleted while it was synced
+	NotFoundRace Class = "NotFoundRace"
+	// Forbidden means the syncer is not allowed to change the object
+	Forbidden Class = "Forbidden"
+	// Invalid means the api server rejected the object as invalid
+	Invalid Class = "Invalid"
+	// Unavailable means the api server could not be reached or is overloaded
+	Unavailable Class = "Unavailable"
+	// Unknown is used for every error that does not fit into any other class
+	Unknown Class = "Unknown"
+)
+
+var syncErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "vcluster_syncer_errors_total",
+	Help: "Total number of sync errors per syncer and error class",
+}, []string{"syncer", "class"})
+
+func init() {
+	metrics.Registry.MustRegister(syncErrors)
+}
+
+// Error is an error with an explicit class
+type Error struct {
+	Class Class
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns err with the given class, or nil if err is nil
+func New(class Class, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Class: class, Err: err}
+}
+
+// NewTranslationError marks err as an error that occurred while translating an object
+func NewTranslationError(err error) error {
+	return New(TranslationError, err)
+}
+
+// Classify returns the class of err. Explicitly classified errors keep their class, api errors
+// are classified by their status reason and message.
+func Classify(err error) Class {
+	if err == nil {
+		return ""
+	}
+
+	classified := &Error{}
+	if errors.As(err, &classified) {
+		return classified.Class
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "exceeded quota"):
+		return HostQuotaExceeded
+	case strings.Contains(message, "admission webhook") && strings.Contains(message, "denied the request"):
+		return HostWebhookDenied
+	case kerrors.IsConflict(err), kerrors.IsAlreadyExists(err):
+		return Conflict
+	case kerrors.IsNotFound(err), kerrors.IsGone(err):
+		return NotFoundRace
+	case kerrors.IsForbidden(err), kerrors.IsUnauthorized(err):
+		return Forbidden
+	case kerrors.IsInvalid(err), kerrors.IsBadRequest(err):
+		return Invalid
+	case kerrors.IsTimeout(err), kerrors.IsServerTimeout(err), kerrors.IsTooManyRequests(err), kerrors.IsServiceUnavailable(err), kerrors.IsInternalError(err):
+		return Unavailable
+	}
+
+	return Unknown
+}
+
+// Reason returns the event reason for err
+func Reason(err error) string {
+	return string(Classify(err))
+}
+
+// Record counts err for the given syncer and returns its class
+func Record(syncer string, err error) Class {
+	class := Classify(err)
+	if class != "" {
+		syncErrors.WithLabelValues(syncer, string(class)).Inc()
+	}
+
+	return class
+}
@@ -0,0 +1,79 @@
+package syncerrors
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	podsResource := schema.GroupResource{Resource: "pods"}
+	testCases := []struct {
+		name string
+
+		err error
+
+		expectedClass Class
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:          "translation error",
+			err:           fmt.Errorf("sync pod: %w", NewTranslationError(fmt.Errorf("no storage class defined"))),
+			expectedClass: TranslationError,
+		},
+		{
+			name:          "quota exceeded",
+			err:           kerrors.NewForbidden(podsResource, "test", fmt.Errorf("exceeded quota: compute, requested: cpu=2, used: cpu=8, limited: cpu=8")),
+			expectedClass: HostQuotaExceeded,
+		},
+		{
+			name:          "webhook denied",
+			err:           kerrors.NewBadRequest(`admission webhook "validate.example.com" denied the request: image not allowed`),
+			expectedClass: HostWebhookDenied,
+		},
+		{
+			name:          "conflict",
+			err:           kerrors.NewConflict(podsResource, "test", fmt.Errorf("the object has been modified")),
+			expectedClass: Conflict,
+		},
+		{
+			name:          "already exists",
+			err:           kerrors.NewAlreadyExists(podsResource, "test"),
+			expectedClass: Conflict,
+		},
+		{
+			name:          "wrapped not found",
+			err:           fmt.Errorf("get pod: %w", kerrors.NewNotFound(podsResource, "test")),
+			expectedClass: NotFoundRace,
+		},
+		{
+			name:          "forbidden",
+			err:           kerrors.NewForbidden(podsResource, "test", fmt.Errorf("not allowed")),
+			expectedClass: Forbidden,
+		},
+		{
+			name:          "invalid",
+			err:           kerrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, "test", nil),
+			expectedClass: Invalid,
+		},
+		{
+			name:          "unavailable",
+			err:           kerrors.NewTooManyRequests("slow down", 1),
+			expectedClass: Unavailable,
+		},
+		{
+			name:          "unknown",
+			err:           fmt.Errorf("something went wrong"),
+			expectedClass: Unknown,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, Classify(testCase.err), testCase.expectedClass, "unexpected class in test case %s", testCase.name)
+	}
+}
@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -12,6 +13,7 @@ import (
 	controllercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/operations"
 	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -52,12 +54,16 @@ func NewFakeRegisterContext(pClient *testingutil.FakeIndexClient, vClient *testi
 			Name:            DefaultTestVclusterName,
 			ServiceName:     DefaultTestVclusterServiceName,
 			TargetNamespace: DefaultTestTargetNamespace,
+
+			PriorityClassMinValue: math.MinInt32,
+			PriorityClassMaxValue: 1000000000,
 		},
 		Controllers:            controllercontext.ExistingControllers.Clone(),
 		CurrentNamespace:       DefaultTestCurrentNamespace,
 		CurrentNamespaceClient: pClient,
 		VirtualManager:         newFakeManager(vClient),
 		PhysicalManager:        newFakeManager(pClient),
+		Operations:             operations.NewRegistry(),
 	}
 }
 
@@ -7,16 +7,22 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/quota"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncerrors"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// quotaRetryInterval is the interval objects are requeued in if a quota prevents their creation
+const quotaRetryInterval = time.Minute
+
 func NewNamespacedTranslator(ctx *context.RegisterContext, name string, obj client.Object, excludedAnnotations ...string) NamespacedTranslator {
 	return &namespacedTranslator{
 		name: name,
@@ -24,6 +30,8 @@ func NewNamespacedTranslator(ctx *context.RegisterContext, name string, obj clie
 		syncedLabels:        ctx.Options.SyncLabels,
 		excludedAnnotations: excludedAnnotations,
 
+		enforceVirtualQuota: ctx.Options.EnforceVirtualResourceQuotas,
+
 		virtualClient: ctx.VirtualManager.GetClient(),
 		obj:           obj,
 
@@ -38,6 +46,8 @@ type namespacedTranslator struct {
 	excludedAnnotations []string
 	syncedLabels        []string
 
+	enforceVirtualQuota bool
+
 	virtualClient client.Client
 	obj           client.Object
 
@@ -67,15 +77,33 @@ func (n *namespacedTranslator) RegisterIndices(ctx *context.RegisterContext) err
 }
 
 func (n *namespacedTranslator) SyncDownCreate(ctx *context.SyncContext, vObj, pObj client.Object) (ctrl.Result, error) {
+	if n.enforceVirtualQuota {
+		err := quota.CheckVirtual(ctx.Context, ctx.VirtualClient, vObj)
+		if syncerrors.Classify(err) == syncerrors.VirtualQuotaExceeded {
+			ctx.Log.Infof("delay syncing %s %s/%s to physical cluster: %v", n.name, vObj.GetNamespace(), vObj.GetName(), err)
+			syncerrors.Record(n.name, err)
+			n.eventRecorder.Eventf(vObj, "Warning", syncerrors.Reason(err), "Not syncing to physical cluster: %v", err)
+			return ctrl.Result{RequeueAfter: quotaRetryInterval}, nil
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	ctx.Log.Infof("create physical %s %s/%s", n.name, pObj.GetNamespace(), pObj.GetName())
 	err := ctx.PhysicalClient.Create(ctx.Context, pObj)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			ctx.Log.Debugf("error syncing %s %s/%s to physical cluster: %v", n.name, vObj.GetNamespace(), vObj.GetName(), err)
 			return ctrl.Result{RequeueAfter: time.Second}, nil
+		} else if syncerrors.Classify(err) == syncerrors.HostQuotaExceeded {
+			// changes of the host quota don't requeue the virtual object, so we retry periodically
+			ctx.Log.Infof("error syncing %s %s/%s to physical cluster: %v", n.name, vObj.GetNamespace(), vObj.GetName(), err)
+			syncerrors.Record(n.name, err)
+			n.eventRecorder.Eventf(vObj, "Warning", syncerrors.Reason(err), "Quota of host namespace %s is the limiting factor, retrying in %s: %v", pObj.GetNamespace(), quotaRetryInterval, err)
+			return ctrl.Result{RequeueAfter: quotaRetryInterval}, nil
 		}
 		ctx.Log.Infof("error syncing %s %s/%s to physical cluster: %v", n.name, vObj.GetNamespace(), vObj.GetName(), err)
-		n.eventRecorder.Eventf(vObj, "Warning", "SyncError", "Error syncing to physical cluster: %v", err)
+		n.eventRecorder.Eventf(vObj, "Warning", syncerrors.Reason(err), "Error syncing to physical cluster: %v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -88,7 +116,7 @@ func (n *namespacedTranslator) SyncDownUpdate(ctx *context.SyncContext, vObj, pO
 		ctx.Log.Infof("updating physical %s/%s, because virtual %s have changed", pObj.GetNamespace(), pObj.GetName(), n.name)
 		err := ctx.PhysicalClient.Update(ctx.Context, pObj)
 		if err != nil {
-			n.eventRecorder.Eventf(vObj, "Warning", "SyncError", "Error syncing to physical cluster: %v", err)
+			n.eventRecorder.Eventf(vObj, "Warning", syncerrors.Reason(err), "Error syncing to physical cluster: %v", err)
 			return ctrl.Result{}, err
 		}
 	}
@@ -96,6 +124,29 @@ func (n *namespacedTranslator) SyncDownUpdate(ctx *context.SyncContext, vObj, pO
 	return ctrl.Result{}, nil
 }
 
+func (n *namespacedTranslator) SyncDownRecreate(ctx *context.SyncContext, vObj, pObj, newPObj client.Object, reason string) (ctrl.Result, error) {
+	ctx.Log.Infof("recreate physical %s %s/%s, because %s", n.name, pObj.GetNamespace(), pObj.GetName(), reason)
+	deleteOptions := &client.DeleteOptions{}
+	if uid := pObj.GetUID(); uid != "" {
+		// make sure we don't delete an object that was already recreated
+		deleteOptions.Preconditions = &metav1.Preconditions{UID: &uid}
+	}
+	err := ctx.PhysicalClient.Delete(ctx.Context, pObj, deleteOptions)
+	if err != nil && !kerrors.IsNotFound(err) {
+		n.eventRecorder.Eventf(vObj, "Warning", syncerrors.Reason(err), "Error recreating in physical cluster: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	result, err := n.SyncDownCreate(ctx, vObj, newPObj)
+	if kerrors.IsAlreadyExists(err) {
+		// the old object is still terminating, e.g. because of a finalizer
+		ctx.Log.Debugf("physical %s %s/%s is still terminating, retrying", n.name, pObj.GetNamespace(), pObj.GetName())
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	return result, err
+}
+
 func (n *namespacedTranslator) IsManaged(ctx context2.Context, pObj client.Object) (bool, error) {
 	return translate.Default.IsManaged(pObj), nil
 }
@@ -142,6 +193,7 @@ func (n *namespacedTranslator) TranslateMetadata(ctx context2.Context, vObj clie
 
 	// reset metadata & translate name and namespace
 	translate.ResetObjectMetadata(m)
+	m.SetFinalizers(translate.DownFinalizers(vObj))
 	m.SetName(n.VirtualToPhysical(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj).Name)
 	if vObj.GetNamespace() != "" {
 		m.SetNamespace(translate.Default.PhysicalNamespace(vObj.GetNamespace()))
@@ -57,6 +57,9 @@ type NamespacedTranslator interface {
 	// SyncDownUpdate updates the given pObj (if not nil) in the target namespace
 	SyncDownUpdate(ctx *syncercontext.SyncContext, vObj, pObj client.Object) (ctrl.Result, error)
 
+	// SyncDownRecreate deletes the existing pObj and creates newPObj in its place within the same reconcile
+	SyncDownRecreate(ctx *syncercontext.SyncContext, vObj, pObj, newPObj client.Object, reason string) (ctrl.Result, error)
+
 	// Function to override default VirtualToPhysical name translation
 	SetNameTranslator(nameTranslator translate.PhysicalNamespacedNameTranslator)
 }
@@ -61,6 +61,12 @@ type Initializer interface {
 	Init(registerContext *synccontext.RegisterContext) error
 }
 
+// Prioritizer is used to process some objects before others if the workqueue of the syncer is deep,
+// e.g. after a restart of the syncer. Higher priorities are processed first.
+type Prioritizer interface {
+	Priority(obj client.Object, deleted bool) int
+}
+
 type Options struct {
 	// DisableUIDDeletion disables automatic deletion of physical objects if the uid between physical
 	// and virtual doesn't match anymore.
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"text/template"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
@@ -26,6 +27,7 @@ const (
 	VarLogInDebug         = "LOG_IN_DEBUG"
 	defaultUID            = int64(1001)
 	defaultGID            = int64(1001)
+	defaultClusterDomain  = "cluster.local"
 )
 
 var ErrNoCoreDNSManifests = fmt.Errorf("no coredns manifests found")
@@ -76,11 +78,12 @@ func getManifestVariables(defaultImageRegistry string, serverVersion *version.In
 	} else {
 		vars[VarLogInDebug] = ""
 	}
-	vars[VarHostDNS] = getNameserver()
+	vars[VarHostDNS] = GetHostNameserver()
 	return vars
 }
 
-func getNameserver() string {
+// GetHostNameserver returns the first nameserver of the syncer, which is the DNS of the host cluster
+func GetHostNameserver() string {
 	raw, err := os.ReadFile("/etc/resolv.conf")
 	if err != nil {
 		return "/etc/resolv.conf"
@@ -94,6 +97,28 @@ func getNameserver() string {
 	return nameservers[0]
 }
 
+// GetHostClusterDomain returns the cluster domain of the host cluster, which is taken from the
+// svc.<cluster domain> entry of the search list of the syncer. Defaults to cluster.local.
+func GetHostClusterDomain() string {
+	raw, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		return defaultClusterDomain
+	}
+
+	return hostClusterDomain(GetSearchDomains(raw))
+}
+
+func hostClusterDomain(searchDomains []string) string {
+	for _, domain := range searchDomains {
+		clusterDomain, ok := strings.CutPrefix(strings.TrimSuffix(domain, "."), "svc.")
+		if ok && clusterDomain != "" {
+			return clusterDomain
+		}
+	}
+
+	return defaultClusterDomain
+}
+
 // GetGroupID retrieves the current group id and if the current process is running
 // as root we fallback to GID 1001
 func GetGroupID() int64 {
@@ -0,0 +1,203 @@
+package coredns
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// StubDomainsKey holds a map of domains to the nameservers that resolve them
+	StubDomainsKey = "stubDomains"
+	// UpstreamNameserversKey holds a list of nameservers that resolve all other domains
+	UpstreamNameserversKey = "upstreamNameservers"
+	// ZoneFileSuffix is the suffix of keys that hold a zone file, e.g. example.com.db
+	ZoneFileSuffix = ".db"
+
+	// CustomKeyPrefix is the prefix of the keys in the coredns-custom ConfigMap that are
+	// managed by vcluster
+	CustomKeyPrefix = "vcluster-"
+
+	customConfigPath = "/etc/coredns/custom"
+	serverPort       = 1053
+)
+
+var forwardRegexp = regexp.MustCompile(`(?m)^([ \t]*forward[ \t]+\.)((?:[ \t]+[^\s{}]+)+)`)
+
+// Customization is the DNS configuration that is added to the CoreDNS of the vcluster
+type Customization struct {
+	// StubDomains maps domains to the nameservers that resolve them
+	StubDomains map[string][]string
+
+	// UpstreamNameservers replace the nameservers of the root zone if set
+	UpstreamNameservers []string
+
+	// ZoneFiles maps zones to the zone files they are served from
+	ZoneFiles map[string]string
+}
+
+// ParseCustomization parses and validates the data of a customization ConfigMap
+func ParseCustomization(data map[string]string) (*Customization, error) {
+	customization := &Customization{
+		StubDomains: map[string][]string{},
+		ZoneFiles:   map[string]string{},
+	}
+	for key, value := range data {
+		switch {
+		case key == StubDomainsKey:
+			stubDomains := map[string][]string{}
+			err := yaml.Unmarshal([]byte(value), &stubDomains)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %v", StubDomainsKey, err)
+			}
+
+			for domain, nameservers := range stubDomains {
+				domain, err = normalizeDomain(domain)
+				if err != nil {
+					return nil, fmt.Errorf("invalid stub domain: %v", err)
+				} else if len(nameservers) == 0 {
+					return nil, fmt.Errorf("invalid stub domain %s: at least one nameserver is required", domain)
+				}
+				err = validateNameservers(nameservers)
+				if err != nil {
+					return nil, fmt.Errorf("invalid stub domain %s: %v", domain, err)
+				}
+
+				customization.StubDomains[domain] = nameservers
+			}
+		case key == UpstreamNameserversKey:
+			err := yaml.Unmarshal([]byte(value), &customization.UpstreamNameservers)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %v", UpstreamNameserversKey, err)
+			}
+
+			err = validateNameservers(customization.UpstreamNameservers)
+			if err != nil {
+				return nil, fmt.Errorf("invalid upstream nameservers: %v", err)
+			}
+		case strings.HasSuffix(key, ZoneFileSuffix):
+			zone, err := normalizeDomain(strings.TrimSuffix(key, ZoneFileSuffix))
+			if err != nil {
+				return nil, fmt.Errorf("invalid zone file %s: %v", key, err)
+			} else if !hasSOARecord(value) {
+				return nil, fmt.Errorf("invalid zone file %s: zone file has no SOA record", key)
+			}
+
+			customization.ZoneFiles[zone] = value
+		default:
+			return nil, fmt.Errorf("unknown key %s, expected %s, %s or a zone file ending with %s", key, StubDomainsKey, UpstreamNameserversKey, ZoneFileSuffix)
+		}
+	}
+
+	for zone := range customization.ZoneFiles {
+		if _, ok := customization.StubDomains[zone]; ok {
+			return nil, fmt.Errorf("domain %s is defined as stub domain and zone file", zone)
+		}
+	}
+
+	return customization, nil
+}
+
+// CustomFiles returns the files that are added to the coredns-custom ConfigMap, which CoreDNS
+// imports server blocks from
+func (c *Customization) CustomFiles() map[string]string {
+	files := map[string]string{}
+	if len(c.StubDomains) > 0 {
+		domains := make([]string, 0, len(c.StubDomains))
+		for domain := range c.StubDomains {
+			domains = append(domains, domain)
+		}
+		sort.Strings(domains)
+
+		serverBlocks := &strings.Builder{}
+		for _, domain := range domains {
+			fmt.Fprintf(serverBlocks, "%s:%d {\n    errors\n    cache 30\n    forward . %s\n}\n", domain, serverPort, strings.Join(c.StubDomains[domain], " "))
+		}
+		files[CustomKeyPrefix+"stubdomains.server"] = serverBlocks.String()
+	}
+	if len(c.ZoneFiles) > 0 {
+		zones := make([]string, 0, len(c.ZoneFiles))
+		for zone := range c.ZoneFiles {
+			zones = append(zones, zone)
+		}
+		sort.Strings(zones)
+
+		serverBlocks := &strings.Builder{}
+		for _, zone := range zones {
+			zoneFile := CustomKeyPrefix + zone + ZoneFileSuffix
+			files[zoneFile] = c.ZoneFiles[zone]
+			fmt.Fprintf(serverBlocks, "%s:%d {\n    errors\n    file %s/%s %s\n}\n", zone, serverPort, customConfigPath, zoneFile, zone)
+		}
+		files[CustomKeyPrefix+"zones.server"] = serverBlocks.String()
+	}
+
+	return files
+}
+
+// GetUpstreamNameservers returns the nameservers of the root zone forward plugin in the Corefile
+func GetUpstreamNameservers(corefile string) ([]string, error) {
+	matches := forwardRegexp.FindAllStringSubmatch(corefile, -1)
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("expected exactly one forward plugin for the root zone in the Corefile, found %d", len(matches))
+	}
+
+	return strings.Fields(matches[0][2]), nil
+}
+
+// SetUpstreamNameservers replaces the nameservers of the root zone forward plugin in the Corefile
+func SetUpstreamNameservers(corefile string, nameservers []string) (string, error) {
+	_, err := GetUpstreamNameservers(corefile)
+	if err != nil {
+		return "", err
+	}
+
+	return forwardRegexp.ReplaceAllString(corefile, "${1} "+strings.Join(nameservers, " ")), nil
+}
+
+func normalizeDomain(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if domain == "" {
+		return "", fmt.Errorf("the root zone can only be changed through %s", UpstreamNameserversKey)
+	} else if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return "", fmt.Errorf("%s: %s", domain, strings.Join(errs, ", "))
+	}
+
+	return domain, nil
+}
+
+func validateNameservers(nameservers []string) error {
+	for _, nameserver := range nameservers {
+		host, port, err := net.SplitHostPort(nameserver)
+		if err != nil {
+			host = nameserver
+		} else if portNumber, err := strconv.Atoi(port); err != nil || portNumber < 1 || portNumber > 65535 {
+			return fmt.Errorf("nameserver %s has an invalid port", nameserver)
+		}
+		if net.ParseIP(host) == nil {
+			return fmt.Errorf("nameserver %s is not an ip address", nameserver)
+		}
+	}
+
+	return nil
+}
+
+func hasSOARecord(zoneFile string) bool {
+	for _, line := range strings.Split(zoneFile, "\n") {
+		if commentIndex := strings.Index(line, ";"); commentIndex != -1 {
+			line = line[:commentIndex]
+		}
+		for _, field := range strings.Fields(line) {
+			if strings.EqualFold(field, "SOA") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
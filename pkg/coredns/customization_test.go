@@ -0,0 +1,114 @@
+package coredns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const testZoneFile = `$ORIGIN example.org.
+@ 3600 IN SOA ns.example.org. admin.example.org. 1 7200 3600 1209600 3600
+www IN A 10.0.0.1
+`
+
+func TestParseCustomization(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          map[string]string
+		expectedFiles map[string]string
+		expectedErr   string
+	}{
+		{
+			name:          "Empty",
+			expectedFiles: map[string]string{},
+		},
+		{
+			name: "Stub domains and zone files",
+			data: map[string]string{
+				StubDomainsKey:                 `{"corp.example.com.": ["10.0.0.10", "10.0.0.11:5353"], "acme.local": ["fd00::10"]}`,
+				UpstreamNameserversKey:         `["1.1.1.1"]`,
+				"example.org" + ZoneFileSuffix: testZoneFile,
+			},
+			expectedFiles: map[string]string{
+				"vcluster-stubdomains.server": "acme.local:1053 {\n    errors\n    cache 30\n    forward . fd00::10\n}\ncorp.example.com:1053 {\n    errors\n    cache 30\n    forward . 10.0.0.10 10.0.0.11:5353\n}\n",
+				"vcluster-example.org.db":     testZoneFile,
+				"vcluster-zones.server":       "example.org:1053 {\n    errors\n    file /etc/coredns/custom/vcluster-example.org.db example.org\n}\n",
+			},
+		},
+		{
+			name:        "Invalid nameserver",
+			data:        map[string]string{UpstreamNameserversKey: "- dns.example.com"},
+			expectedErr: "invalid upstream nameservers: nameserver dns.example.com is not an ip address",
+		},
+		{
+			name:        "Invalid port",
+			data:        map[string]string{StubDomainsKey: `{"example.com": ["10.0.0.10:99999"]}`},
+			expectedErr: "invalid stub domain example.com: nameserver 10.0.0.10:99999 has an invalid port",
+		},
+		{
+			name:        "Root stub domain",
+			data:        map[string]string{StubDomainsKey: `{".": ["10.0.0.10"]}`},
+			expectedErr: "invalid stub domain: the root zone can only be changed through upstreamNameservers",
+		},
+		{
+			name:        "Zone file without SOA record",
+			data:        map[string]string{"example.org" + ZoneFileSuffix: "www IN A 10.0.0.1 ; no SOA\n"},
+			expectedErr: "invalid zone file example.org.db: zone file has no SOA record",
+		},
+		{
+			name: "Stub domain and zone file",
+			data: map[string]string{
+				StubDomainsKey:                 `{"example.org": ["10.0.0.10"]}`,
+				"example.org" + ZoneFileSuffix: testZoneFile,
+			},
+			expectedErr: "domain example.org is defined as stub domain and zone file",
+		},
+		{
+			name:        "Unknown key",
+			data:        map[string]string{"stubdomains": "{}"},
+			expectedErr: "unknown key stubdomains, expected stubDomains, upstreamNameservers or a zone file ending with .db",
+		},
+	}
+
+	for _, testCase := range testCases {
+		customization, err := ParseCustomization(testCase.data)
+		if testCase.expectedErr != "" {
+			assert.Error(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, customization.CustomFiles(), testCase.expectedFiles)
+	}
+}
+
+func TestSetUpstreamNameservers(t *testing.T) {
+	corefile := `.:1053 {
+    errors
+    forward . /etc/resolv.conf 8.8.8.8 {
+      policy sequential
+    }
+    cache 30
+}
+
+import /etc/coredns/custom/*.server`
+
+	nameservers, err := GetUpstreamNameservers(corefile)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, nameservers, []string{"/etc/resolv.conf", "8.8.8.8"})
+
+	newCorefile, err := SetUpstreamNameservers(corefile, []string{"1.1.1.1", "1.0.0.1"})
+	assert.NilError(t, err)
+	assert.Equal(t, newCorefile, `.:1053 {
+    errors
+    forward . 1.1.1.1 1.0.0.1 {
+      policy sequential
+    }
+    cache 30
+}
+
+import /etc/coredns/custom/*.server`)
+
+	_, err = SetUpstreamNameservers(".:1053 {\n    errors\n}\n", []string{"1.1.1.1"})
+	assert.Error(t, err, "expected exactly one forward plugin for the root zone in the Corefile, found 0")
+}
@@ -0,0 +1,67 @@
+package coredns
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// HostService is a host cluster service that can be resolved from inside the vcluster
+type HostService struct {
+	Namespace string
+
+	// Name of the service or * for all services of the namespace
+	Name string
+}
+
+// ParseHostServices parses host services in the form namespace/service or namespace/*
+func ParseHostServices(rawServices []string) ([]HostService, error) {
+	services := []HostService{}
+	for _, rawService := range rawServices {
+		namespace, name, found := strings.Cut(rawService, "/")
+		if !found {
+			return nil, fmt.Errorf("invalid host service %s, expected namespace/service or namespace/*", rawService)
+		} else if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid host service %s: %s", rawService, strings.Join(errs, ", "))
+		} else if errs := validation.IsDNS1123Label(name); name != "*" && len(errs) > 0 {
+			return nil, fmt.Errorf("invalid host service %s: %s", rawService, strings.Join(errs, ", "))
+		}
+
+		services = append(services, HostService{Namespace: namespace, Name: name})
+	}
+
+	return services, nil
+}
+
+// HostServicesZone returns the zone host services are resolved in, e.g. svc.host.cluster.local
+func HostServicesZone(clusterDomain string) string {
+	return "svc.host." + clusterDomain
+}
+
+// HostServicesServerBlock returns a server block that resolves the given host services as
+// <service>.<namespace>.svc.host.<cluster domain> through the host cluster DNS, which serves them
+// below svc.<host cluster domain>. All other names of the zone are answered with NXDOMAIN.
+func HostServicesServerBlock(services []HostService, clusterDomain, hostClusterDomain, nameserver string) string {
+	zone := HostServicesZone(clusterDomain)
+	hostZone := "svc." + hostClusterDomain
+	serverBlock := &strings.Builder{}
+	fmt.Fprintf(serverBlock, "%s:%d {\n    errors\n", zone, serverPort)
+	for _, service := range services {
+		name := "[a-z0-9-]+"
+		if service.Name != "*" {
+			name = regexp.QuoteMeta(service.Name)
+		}
+
+		fmt.Fprintf(serverBlock, "    rewrite stop {\n")
+		fmt.Fprintf(serverBlock, "        name regex ^(%s)\\.(%s)\\.%s\\.$ {1}.{2}.%s.\n", name, service.Namespace, regexp.QuoteMeta(zone), hostZone)
+		fmt.Fprintf(serverBlock, "        answer name ^(%s)\\.(%s)\\.%s\\.$ {1}.{2}.%s.\n", name, service.Namespace, regexp.QuoteMeta(hostZone), zone)
+		fmt.Fprintf(serverBlock, "    }\n")
+	}
+
+	// names that were not rewritten are still part of the zone
+	fmt.Fprintf(serverBlock, "    template ANY ANY %s {\n        rcode NXDOMAIN\n    }\n", zone)
+	fmt.Fprintf(serverBlock, "    forward . %s\n    cache 30\n}\n", nameserver)
+	return serverBlock.String()
+}
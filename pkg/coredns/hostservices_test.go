@@ -0,0 +1,72 @@
+package coredns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseHostServices(t *testing.T) {
+	testCases := []struct {
+		name             string
+		rawServices      []string
+		expectedServices []HostService
+		expectedErr      string
+	}{
+		{
+			name:             "Services",
+			rawServices:      []string{"shared/postgres", "monitoring/*"},
+			expectedServices: []HostService{{Namespace: "shared", Name: "postgres"}, {Namespace: "monitoring", Name: "*"}},
+		},
+		{
+			name:        "Missing namespace",
+			rawServices: []string{"postgres"},
+			expectedErr: "invalid host service postgres, expected namespace/service or namespace/*",
+		},
+		{
+			name:        "Wildcard namespace",
+			rawServices: []string{"*/postgres"},
+			expectedErr: "invalid host service */postgres: a lowercase RFC 1123 label",
+		},
+	}
+
+	for _, testCase := range testCases {
+		services, err := ParseHostServices(testCase.rawServices)
+		if testCase.expectedErr != "" {
+			assert.ErrorContains(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, services, testCase.expectedServices)
+	}
+}
+
+func TestHostServicesServerBlock(t *testing.T) {
+	services := []HostService{{Namespace: "shared", Name: "postgres"}, {Namespace: "monitoring", Name: "*"}}
+	assert.Equal(t, HostServicesServerBlock(services, "cluster.local", "k8s.example", "10.96.0.10"), `svc.host.cluster.local:1053 {
+    errors
+    rewrite stop {
+        name regex ^(postgres)\.(shared)\.svc\.host\.cluster\.local\.$ {1}.{2}.svc.k8s.example.
+        answer name ^(postgres)\.(shared)\.svc\.k8s\.example\.$ {1}.{2}.svc.host.cluster.local.
+    }
+    rewrite stop {
+        name regex ^([a-z0-9-]+)\.(monitoring)\.svc\.host\.cluster\.local\.$ {1}.{2}.svc.k8s.example.
+        answer name ^([a-z0-9-]+)\.(monitoring)\.svc\.k8s\.example\.$ {1}.{2}.svc.host.cluster.local.
+    }
+    template ANY ANY svc.host.cluster.local {
+        rcode NXDOMAIN
+    }
+    forward . 10.96.0.10
+    cache 30
+}
+`)
+}
+
+func TestHostClusterDomain(t *testing.T) {
+	resolvConf := []byte("search vcluster.svc.cluster.local svc.cluster.local cluster.local\nnameserver 10.96.0.10\noptions ndots:5\n")
+	assert.DeepEqual(t, GetSearchDomains(resolvConf), []string{"vcluster.svc.cluster.local", "svc.cluster.local", "cluster.local"})
+	assert.Equal(t, hostClusterDomain(GetSearchDomains(resolvConf)), "cluster.local")
+	assert.Equal(t, hostClusterDomain([]string{"team-a.svc.k8s.example.", "svc.k8s.example."}), "k8s.example")
+	assert.Equal(t, hostClusterDomain(nil), "cluster.local")
+}
@@ -3,6 +3,7 @@ package coredns
 import (
 	"bytes"
 	"regexp"
+	"strings"
 )
 
 var (
@@ -29,6 +30,19 @@ func GetNameservers(resolvConf []byte) []string {
 	return nameservers
 }
 
+// GetSearchDomains returns the search domains (if any) listed in /etc/resolv.conf
+func GetSearchDomains(resolvConf []byte) []string {
+	domains := []string{}
+	for _, line := range getLines(resolvConf, []byte("#")) {
+		fields := strings.Fields(string(line))
+		if len(fields) > 0 && fields[0] == "search" {
+			// the last search line wins
+			domains = fields[1:]
+		}
+	}
+	return domains
+}
+
 // getLines parses input into lines and strips away comments.
 func getLines(input []byte, commentMarker []byte) [][]byte {
 	lines := bytes.Split(input, []byte("\n"))
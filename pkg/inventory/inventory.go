@@ -0,0 +1,352 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DataKey is the key of the inventory within an inventory shard configmap
+	DataKey = "inventory.json"
+
+	// ShardsKey is the key of the shard configmap names within the inventory configmap
+	ShardsKey = "shards"
+
+	// ShardLabel holds the vcluster name on inventory shard configmaps
+	ShardLabel = "vcluster.loft.sh/inventory"
+)
+
+// ShardSize is the maximum size of the objects and bindings of an inventory shard
+var ShardSize = 512 * 1024
+
+// Inventory is a snapshot of the physical objects vcluster has synced. It is taken right before
+// a datastore backup, so a restore can re-adopt existing physical objects instead of guessing
+// from names only.
+type Inventory struct {
+	// Created is the time the snapshot was taken
+	Created metav1.Time `json:"created"`
+
+	// Objects are the synced physical objects
+	Objects []Object `json:"objects,omitempty"`
+
+	// Bindings are the persistent volume claim to volume bindings of synced claims
+	Bindings []Binding `json:"bindings,omitempty"`
+}
+
+// Object is a single synced physical object
+type Object struct {
+	Kind string `json:"kind"`
+
+	Virtual    types.NamespacedName `json:"virtual"`
+	VirtualUID types.UID            `json:"virtualUID,omitempty"`
+
+	Physical    types.NamespacedName `json:"physical"`
+	PhysicalUID types.UID            `json:"physicalUID"`
+}
+
+// Binding is the volume binding of a synced persistent volume claim
+type Binding struct {
+	VirtualClaim  types.NamespacedName `json:"virtualClaim"`
+	PhysicalClaim types.NamespacedName `json:"physicalClaim"`
+	Volume        string               `json:"volume"`
+}
+
+// Kinds are the physical object kinds that are part of an inventory
+var Kinds = map[string]func() client.ObjectList{
+	"Pod":                   func() client.ObjectList { return &corev1.PodList{} },
+	"Service":               func() client.ObjectList { return &corev1.ServiceList{} },
+	"ConfigMap":             func() client.ObjectList { return &corev1.ConfigMapList{} },
+	"Secret":                func() client.ObjectList { return &corev1.SecretList{} },
+	"PersistentVolumeClaim": func() client.ObjectList { return &corev1.PersistentVolumeClaimList{} },
+}
+
+// Snapshot lists all physical objects that are managed by this vcluster. In single namespace mode
+// only the target namespace is listed.
+func Snapshot(ctx context.Context, physicalClient client.Reader) (*Inventory, error) {
+	listOptions := []client.ListOption{}
+	if translate.Default.SingleNamespaceTarget() {
+		targetNamespace, err := translate.Default.LegacyGetTargetNamespace()
+		if err != nil {
+			return nil, err
+		}
+
+		listOptions = append(listOptions, client.InNamespace(targetNamespace))
+	}
+
+	inventory := &Inventory{Created: metav1.NewTime(time.Now())}
+	for kind, newList := range Kinds {
+		list := newList()
+		err := physicalClient.List(ctx, list, listOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", kind, err)
+		}
+
+		err = meta.EachListItem(list, func(obj runtime.Object) error {
+			pObj, ok := obj.(client.Object)
+			if !ok || !translate.Default.IsManaged(pObj) {
+				return nil
+			}
+
+			annotations := pObj.GetAnnotations()
+			vName := types.NamespacedName{Namespace: annotations[translate.NamespaceAnnotation], Name: annotations[translate.NameAnnotation]}
+			pName := types.NamespacedName{Namespace: pObj.GetNamespace(), Name: pObj.GetName()}
+			inventory.Objects = append(inventory.Objects, Object{
+				Kind:        kind,
+				Virtual:     vName,
+				VirtualUID:  types.UID(annotations[translate.UIDAnnotation]),
+				Physical:    pName,
+				PhysicalUID: pObj.GetUID(),
+			})
+
+			pvc, ok := pObj.(*corev1.PersistentVolumeClaim)
+			if ok && pvc.Spec.VolumeName != "" {
+				inventory.Bindings = append(inventory.Bindings, Binding{
+					VirtualClaim:  vName,
+					PhysicalClaim: pName,
+					Volume:        pvc.Spec.VolumeName,
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(inventory.Objects, func(i, j int) bool {
+		if inventory.Objects[i].Kind != inventory.Objects[j].Kind {
+			return inventory.Objects[i].Kind < inventory.Objects[j].Kind
+		}
+		return inventory.Objects[i].Physical.String() < inventory.Objects[j].Physical.String()
+	})
+	sort.Slice(inventory.Bindings, func(i, j int) bool {
+		return inventory.Bindings[i].PhysicalClaim.String() < inventory.Bindings[j].PhysicalClaim.String()
+	})
+	return inventory, nil
+}
+
+// ConfigMapName returns the name of the configmap that references the stored inventory shards of the vcluster
+func ConfigMapName(vClusterName string) string {
+	return "vc-" + vClusterName + "-inventory"
+}
+
+// Store writes the inventory into configmaps in the vcluster namespace, which makes sure it is
+// part of any backup that includes the vcluster datastore. The inventory is split into shards
+// that stay well below the object size limit. New shards are written first and only referenced
+// from the index configmap afterwards, so an interrupted store leaves the previous inventory intact.
+func Store(ctx context.Context, currentNamespaceClient client.Client, currentNamespace, vClusterName string, inventory *Inventory) error {
+	shards, err := split(inventory)
+	if err != nil {
+		return err
+	}
+
+	generation := utilrand.String(5)
+	shardNames := []string{}
+	for i, shard := range shards {
+		shardConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: currentNamespace,
+				Name:      fmt.Sprintf("%s-%s-%d", ConfigMapName(vClusterName), generation, i),
+				Labels:    map[string]string{ShardLabel: vClusterName},
+			},
+			Data: map[string]string{DataKey: string(shard)},
+		}
+		err = currentNamespaceClient.Create(ctx, shardConfigMap)
+		if err != nil {
+			return fmt.Errorf("create inventory shard: %w", err)
+		}
+
+		shardNames = append(shardNames, shardConfigMap.Name)
+	}
+
+	rawShardNames, err := json.Marshal(shardNames)
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: currentNamespace,
+			Name:      ConfigMapName(vClusterName),
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, currentNamespaceClient, configMap, func() error {
+		configMap.Data = map[string]string{ShardsKey: string(rawShardNames)}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// delete the shards of previous inventories
+	shardList := &corev1.ConfigMapList{}
+	err = currentNamespaceClient.List(ctx, shardList, client.InNamespace(currentNamespace), client.MatchingLabels{ShardLabel: vClusterName})
+	if err != nil {
+		return err
+	}
+	for i := range shardList.Items {
+		if strings.HasPrefix(shardList.Items[i].Name, ConfigMapName(vClusterName)+"-"+generation+"-") {
+			continue
+		}
+
+		err = currentNamespaceClient.Delete(ctx, &shardList.Items[i])
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("delete inventory shard: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// split returns the inventory as json shards that each stay below ShardSize
+func split(inventory *Inventory) ([][]byte, error) {
+	shards := [][]byte{}
+	shard := &Inventory{Created: inventory.Created}
+	size := 0
+	add := func(entrySize int) error {
+		if size+entrySize <= ShardSize || size == 0 {
+			size += entrySize
+			return nil
+		}
+
+		raw, err := json.Marshal(shard)
+		if err != nil {
+			return err
+		}
+
+		shards = append(shards, raw)
+		shard = &Inventory{Created: inventory.Created}
+		size = entrySize
+		return nil
+	}
+	for _, object := range inventory.Objects {
+		raw, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		err = add(len(raw))
+		if err != nil {
+			return nil, err
+		}
+
+		shard.Objects = append(shard.Objects, object)
+	}
+	for _, binding := range inventory.Bindings {
+		raw, err := json.Marshal(binding)
+		if err != nil {
+			return nil, err
+		}
+		err = add(len(raw))
+		if err != nil {
+			return nil, err
+		}
+
+		shard.Bindings = append(shard.Bindings, binding)
+	}
+
+	raw, err := json.Marshal(shard)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(shards, raw), nil
+}
+
+// Load returns the last stored inventory or nil if there is none
+func Load(ctx context.Context, currentNamespaceClient client.Client, currentNamespace, vClusterName string) (*Inventory, error) {
+	configMap := &corev1.ConfigMap{}
+	err := currentNamespaceClient.Get(ctx, types.NamespacedName{Namespace: currentNamespace, Name: ConfigMapName(vClusterName)}, configMap)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	shardNames := []string{}
+	err = json.Unmarshal([]byte(configMap.Data[ShardsKey]), &shardNames)
+	if err != nil {
+		return nil, fmt.Errorf("parse inventory shards: %w", err)
+	}
+
+	inventory := &Inventory{}
+	for _, shardName := range shardNames {
+		shardConfigMap := &corev1.ConfigMap{}
+		err = currentNamespaceClient.Get(ctx, types.NamespacedName{Namespace: currentNamespace, Name: shardName}, shardConfigMap)
+		if err != nil {
+			return nil, fmt.Errorf("get inventory shard %s: %w", shardName, err)
+		}
+
+		shard := &Inventory{}
+		err = json.Unmarshal([]byte(shardConfigMap.Data[DataKey]), shard)
+		if err != nil {
+			return nil, fmt.Errorf("parse inventory shard %s: %w", shardName, err)
+		}
+
+		inventory.Created = shard.Created
+		inventory.Objects = append(inventory.Objects, shard.Objects...)
+		inventory.Bindings = append(inventory.Bindings, shard.Bindings...)
+	}
+
+	return inventory, nil
+}
+
+// Snapshotter takes and stores inventories of a vcluster
+type Snapshotter struct {
+	PhysicalClient client.Reader
+
+	CurrentNamespace       string
+	CurrentNamespaceClient client.Client
+
+	Name string
+
+	// Quiesce runs the given function while no syncer is reconciling, so the snapshot is consistent
+	Quiesce func(fn func() error) error
+}
+
+// Snapshot takes a new inventory and stores it
+func (s *Snapshotter) Snapshot(ctx context.Context) (*Inventory, error) {
+	var inventory *Inventory
+	snapshot := func() error {
+		var err error
+		inventory, err = Snapshot(ctx, s.PhysicalClient)
+		return err
+	}
+
+	var err error
+	if s.Quiesce != nil {
+		err = s.Quiesce(snapshot)
+	} else {
+		err = snapshot()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = Store(ctx, s.CurrentNamespaceClient, s.CurrentNamespace, s.Name, inventory)
+	if err != nil {
+		return nil, fmt.Errorf("store inventory: %w", err)
+	}
+
+	return inventory, nil
+}
+
+// Latest returns the last stored inventory
+func (s *Snapshotter) Latest(ctx context.Context) (*Inventory, error) {
+	return Load(ctx, s.CurrentNamespaceClient, s.CurrentNamespace, s.Name)
+}
@@ -0,0 +1,164 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSnapshot(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	pPodName := translate.Default.PhysicalName("pod", "default")
+	pClaimName := translate.Default.PhysicalName("claim", "default")
+	managed := func(name, uid, vName, vUID string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			UID:       types.UID(uid),
+			Labels:    map[string]string{translate.MarkerLabel: translate.Suffix},
+			Annotations: map[string]string{
+				translate.NameAnnotation:      vName,
+				translate.NamespaceAnnotation: "default",
+				translate.UIDAnnotation:       vUID,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name string
+
+		objects []runtime.Object
+
+		expectedObjects  []Object
+		expectedBindings []Binding
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "managed objects",
+			objects: []runtime.Object{
+				&corev1.Pod{ObjectMeta: managed(pPodName, "p-pod", "pod", "v-pod")},
+				&corev1.PersistentVolumeClaim{
+					ObjectMeta: managed(pClaimName, "p-claim", "claim", "v-claim"),
+					Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+				},
+			},
+			expectedObjects: []Object{
+				{
+					Kind:        "PersistentVolumeClaim",
+					Virtual:     types.NamespacedName{Namespace: "default", Name: "claim"},
+					VirtualUID:  "v-claim",
+					Physical:    types.NamespacedName{Namespace: "test", Name: pClaimName},
+					PhysicalUID: "p-claim",
+				},
+				{
+					Kind:        "Pod",
+					Virtual:     types.NamespacedName{Namespace: "default", Name: "pod"},
+					VirtualUID:  "v-pod",
+					Physical:    types.NamespacedName{Namespace: "test", Name: pPodName},
+					PhysicalUID: "p-pod",
+				},
+			},
+			expectedBindings: []Binding{
+				{
+					VirtualClaim:  types.NamespacedName{Namespace: "default", Name: "claim"},
+					PhysicalClaim: types.NamespacedName{Namespace: "test", Name: pClaimName},
+					Volume:        "pv-1",
+				},
+			},
+		},
+		{
+			name: "unmanaged and other namespace objects",
+			objects: []runtime.Object{
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "test"}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Name:      pPodName,
+					Namespace: "other",
+					Labels:    map[string]string{translate.MarkerLabel: translate.Suffix},
+				}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		pClient := testingutil.NewFakeClient(testingutil.NewScheme(), testCase.objects...)
+		inventory, err := Snapshot(context.Background(), pClient)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(inventory.Objects, testCase.expectedObjects), "unexpected objects in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(inventory.Bindings, testCase.expectedBindings), "unexpected bindings in test case %s", testCase.name)
+	}
+}
+
+func TestStoreAndLoad(t *testing.T) {
+	ctx := context.Background()
+	currentNamespaceClient := testingutil.NewFakeClient(testingutil.NewScheme())
+
+	inventory, err := Load(ctx, currentNamespaceClient, "vcluster", "test")
+	assert.NilError(t, err)
+	assert.Assert(t, inventory == nil)
+
+	// store small shards
+	defer func(shardSize int) { ShardSize = shardSize }(ShardSize)
+	ShardSize = 200
+	stored := &Inventory{Bindings: []Binding{{Volume: "pv-1"}}}
+	for _, name := range []string{"pod-a", "pod-b", "pod-c"} {
+		stored.Objects = append(stored.Objects, Object{
+			Kind:     "Pod",
+			Virtual:  types.NamespacedName{Namespace: "default", Name: name},
+			Physical: types.NamespacedName{Namespace: "vcluster", Name: name + "-x-default-x-test"},
+		})
+	}
+	countShards := func() int {
+		shardList := &corev1.ConfigMapList{}
+		err := currentNamespaceClient.List(ctx, shardList, client.MatchingLabels{ShardLabel: "test"})
+		assert.NilError(t, err)
+		return len(shardList.Items)
+	}
+	err = Store(ctx, currentNamespaceClient, "vcluster", "test", stored)
+	assert.NilError(t, err)
+	assert.Equal(t, countShards(), 4)
+
+	inventory, err = Load(ctx, currentNamespaceClient, "vcluster", "test")
+	assert.NilError(t, err)
+	assert.Assert(t, cmp.DeepEqual(inventory.Objects, stored.Objects))
+	assert.Assert(t, cmp.DeepEqual(inventory.Bindings, stored.Bindings))
+
+	// the shards of the previous inventory are replaced
+	stored.Objects = stored.Objects[:1]
+	err = Store(ctx, currentNamespaceClient, "vcluster", "test", stored)
+	assert.NilError(t, err)
+	assert.Equal(t, countShards(), 2)
+
+	inventory, err = Load(ctx, currentNamespaceClient, "vcluster", "test")
+	assert.NilError(t, err)
+	assert.Assert(t, cmp.DeepEqual(inventory.Objects, stored.Objects))
+}
+
+func TestSnapshotterQuiesce(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	quiesced := false
+	snapshotter := &Snapshotter{
+		PhysicalClient:         testingutil.NewFakeClient(testingutil.NewScheme()),
+		CurrentNamespace:       "vcluster",
+		CurrentNamespaceClient: testingutil.NewFakeClient(testingutil.NewScheme()),
+		Name:                   "test",
+		Quiesce: func(fn func() error) error {
+			quiesced = true
+			return fn()
+		},
+	}
+
+	_, err := snapshotter.Snapshot(context.Background())
+	assert.NilError(t, err)
+	assert.Assert(t, quiesced)
+}
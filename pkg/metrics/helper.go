@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
 	"github.com/loft-sh/vcluster/pkg/constants"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sort"
-	"strings"
 )
 
+// cgroupPodUIDRegEx matches the pod uid in cgroup paths of the cgroupfs driver (pod<uid>)
+// and the systemd driver (pod<uid with underscores>.slice)
+var cgroupPodUIDRegEx = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+
 func Decode(data []byte) ([]*dto.MetricFamily, error) {
 	var parser expfmt.TextParser
 	metricFamilies, err := parser.TextToMetricFamilies(strings.NewReader(string(data)))
@@ -61,6 +68,7 @@ func Rewrite(ctx context.Context, metricsFamilies []*dto.MetricFamily, vClient c
 		for _, m := range fam.Metric {
 			var (
 				pod                   string
+				podUID                types.UID
 				persistentvolumeclaim string
 				namespace             string
 			)
@@ -96,6 +104,7 @@ func Rewrite(ctx context.Context, metricsFamilies []*dto.MetricFamily, vClient c
 
 				pod = podList.Items[0].Name
 				namespace = podList.Items[0].Namespace
+				podUID = podList.Items[0].UID
 			}
 
 			// rewrite persistentvolumeclaim
@@ -127,6 +136,11 @@ func Rewrite(ctx context.Context, metricsFamilies []*dto.MetricFamily, vClient c
 				if l.GetName() == "persistentvolumeclaim" {
 					l.Value = &persistentvolumeclaim
 				}
+				// cadvisor metrics contain the host pod uid in the cgroup path
+				if l.GetName() == "id" && podUID != "" {
+					id := RewriteCgroupPodUID(l.GetValue(), podUID)
+					l.Value = &id
+				}
 			}
 
 			// add the rewritten metric
@@ -141,3 +155,14 @@ func Rewrite(ctx context.Context, metricsFamilies []*dto.MetricFamily, vClient c
 
 	return resultMetricsFamily, nil
 }
+
+// RewriteCgroupPodUID replaces the pod uid in the cgroup path with the given uid
+func RewriteCgroupPodUID(path string, uid types.UID) string {
+	return cgroupPodUIDRegEx.ReplaceAllStringFunc(path, func(match string) string {
+		if strings.Contains(match, "_") {
+			return "pod" + strings.ReplaceAll(string(uid), "-", "_")
+		}
+
+		return "pod" + string(uid)
+	})
+}
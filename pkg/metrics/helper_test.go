@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/prometheus/common/expfmt"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRewriteCgroupPodUID(t *testing.T) {
+	uid := "6e0c6a2b-3f1d-4c5e-9a7b-1c2d3e4f5a6b"
+	hostUID := "0f9e8d7c-6b5a-4c3d-8e1f-2a3b4c5d6e7f"
+	testCases := map[string]string{
+		"/kubepods/burstable/pod" + hostUID + "/abc": "/kubepods/burstable/pod" + uid + "/abc",
+		"/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + strings.ReplaceAll(hostUID, "-", "_") + ".slice": "/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + strings.ReplaceAll(uid, "-", "_") + ".slice",
+		"/system.slice/kubelet.service": "/system.slice/kubelet.service",
+	}
+
+	for path, expected := range testCases {
+		assert.Equal(t, RewriteCgroupPodUID(path, types.UID(uid)), expected, "unexpected path for %s", path)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nginx",
+			Namespace: "default",
+			UID:       "6e0c6a2b-3f1d-4c5e-9a7b-1c2d3e4f5a6b",
+		},
+	}
+	vClient := fake.NewClientBuilder().
+		WithObjects(vPod).
+		WithIndex(&corev1.Pod{}, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
+			return []string{translate.Default.PhysicalNamespace(rawObj.GetNamespace()) + "/" + translate.Default.PhysicalName(rawObj.GetName(), rawObj.GetNamespace())}
+		}).
+		Build()
+
+	pName := translate.Default.PhysicalName("nginx", "default")
+	data := `# TYPE container_cpu_usage_seconds_total counter
+container_cpu_usage_seconds_total{container="nginx",id="/kubepods/burstable/pod0f9e8d7c-6b5a-4c3d-8e1f-2a3b4c5d6e7f/abc",namespace="test",pod="` + pName + `"} 1
+container_cpu_usage_seconds_total{container="other",id="/kubepods/burstable/pod1f9e8d7c-6b5a-4c3d-8e1f-2a3b4c5d6e7f/def",namespace="other",pod="other"} 2
+container_cpu_usage_seconds_total{id="/"} 3
+`
+	families, err := Decode([]byte(data))
+	assert.NilError(t, err)
+	families, err = Rewrite(context.TODO(), families, vClient)
+	assert.NilError(t, err)
+	out, err := Encode(families, expfmt.FmtText)
+	assert.NilError(t, err)
+
+	expected := `# TYPE container_cpu_usage_seconds_total counter
+container_cpu_usage_seconds_total{container="nginx",id="/kubepods/burstable/pod6e0c6a2b-3f1d-4c5e-9a7b-1c2d3e4f5a6b/abc",namespace="default",pod="nginx"} 1
+container_cpu_usage_seconds_total{id="/"} 3
+`
+	assert.Equal(t, string(out), expected)
+}
@@ -2,15 +2,21 @@ package metricsapiservice
 
 import (
 	"context"
+	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	vclustercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	custommetricsv1beta2 "k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+	externalmetricsv1beta1 "k8s.io/metrics/pkg/apis/external_metrics/v1beta1"
 	"k8s.io/metrics/pkg/apis/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -21,12 +27,38 @@ import (
 const (
 	MetricsVersion    = "v1beta1"
 	MetricsAPIService = MetricsVersion + "." + metrics.GroupName // "v1beta1.metrics.k8s.io"
+
+	CustomMetricsVersion    = "v1beta2"
+	CustomMetricsAPIService = CustomMetricsVersion + "." + custommetricsv1beta2.GroupName // "v1beta2.custom.metrics.k8s.io"
+
+	ExternalMetricsVersion    = "v1beta1"
+	ExternalMetricsAPIService = ExternalMetricsVersion + "." + externalmetricsv1beta1.GroupName // "v1beta1.external.metrics.k8s.io"
+
+	// ProxiedAPIServiceLabel marks api services that were registered for an api of the host cluster
+	// that is passed through into the virtual cluster
+	ProxiedAPIServiceLabel = "vcluster.loft.sh/proxied-api-service"
 )
 
-func checkExistingAPIService(ctx context.Context, client client.Client) bool {
+// ParseProxiedAPIServices parses api service names in the form version.group, e.g.
+// v1beta1.external.metrics.k8s.io, into the group versions they serve
+func ParseProxiedAPIServices(names []string) ([]schema.GroupVersion, error) {
+	groupVersions := []schema.GroupVersion{}
+	for _, name := range names {
+		splitted := strings.SplitN(name, ".", 2)
+		if len(splitted) != 2 || splitted[0] == "" || splitted[1] == "" {
+			return nil, fmt.Errorf("invalid api service %s, expected format version.group", name)
+		}
+
+		groupVersions = append(groupVersions, schema.GroupVersion{Group: splitted[1], Version: splitted[0]})
+	}
+
+	return groupVersions, nil
+}
+
+func checkExistingAPIService(ctx context.Context, client client.Client, name string) bool {
 	var exists bool
 	_ = applyOperation(ctx, func(ctx context.Context) (bool, error) {
-		err := client.Get(ctx, types.NamespacedName{Name: MetricsAPIService}, &apiregistrationv1.APIService{})
+		err := client.Get(ctx, types.NamespacedName{Name: name}, &apiregistrationv1.APIService{})
 		if err != nil {
 			if kerrors.IsNotFound(err) {
 				return true, nil
@@ -51,11 +83,11 @@ func applyOperation(ctx context.Context, operationFunc wait.ConditionWithContext
 	}, operationFunc)
 }
 
-func deleteOperation(ctx context.Context, client client.Client) wait.ConditionWithContextFunc {
+func deleteOperation(ctx context.Context, client client.Client, name string) wait.ConditionWithContextFunc {
 	return func(ctx context.Context) (bool, error) {
 		err := client.Delete(ctx, &apiregistrationv1.APIService{
 			ObjectMeta: v1.ObjectMeta{
-				Name: MetricsAPIService,
+				Name: name,
 			},
 		})
 		if err != nil {
@@ -70,22 +102,23 @@ func deleteOperation(ctx context.Context, client client.Client) wait.ConditionWi
 	}
 }
 
-func createOperation(ctx context.Context, client client.Client) wait.ConditionWithContextFunc {
+func createOperation(ctx context.Context, client client.Client, group, version string, labels map[string]string) wait.ConditionWithContextFunc {
 	return func(ctx context.Context) (bool, error) {
 		spec := apiregistrationv1.APIServiceSpec{
-			Group:                metrics.GroupName,
+			Group:                group,
 			GroupPriorityMinimum: 100,
-			Version:              MetricsVersion,
+			Version:              version,
 			VersionPriority:      100,
 		}
 
 		apiService := &apiregistrationv1.APIService{
 			ObjectMeta: v1.ObjectMeta{
-				Name: MetricsAPIService,
+				Name: version + "." + group,
 			},
 		}
 
 		_, err := controllerutil.CreateOrUpdate(ctx, client, apiService, func() error {
+			apiService.Labels = labels
 			apiService.Spec = spec
 			return nil
 		})
@@ -103,12 +136,70 @@ func createOperation(ctx context.Context, client client.Client) wait.ConditionWi
 }
 
 func RegisterOrDeregisterAPIService(ctx context.Context, options *vclustercontext.VirtualClusterOptions, client client.Client) error {
+	// api services that are passed through are not deregistered here, as they are registered again below
+	proxied := sets.New(options.ProxyAPIServices...)
+	err := registerOrDeregister(ctx, client, options.ProxyMetricsServer || proxied.Has(MetricsAPIService), metrics.GroupName, MetricsVersion)
+	if err != nil {
+		return err
+	}
+
+	err = registerOrDeregister(ctx, client, options.ProxyCustomMetricsServer || proxied.Has(CustomMetricsAPIService), custommetricsv1beta2.GroupName, CustomMetricsVersion)
+	if err != nil {
+		return err
+	}
+
+	err = registerOrDeregister(ctx, client, options.ProxyExternalMetricsServer || proxied.Has(ExternalMetricsAPIService), externalmetricsv1beta1.GroupName, ExternalMetricsVersion)
+	if err != nil {
+		return err
+	}
+
+	return registerOrDeregisterProxied(ctx, client, options.ProxyAPIServices)
+}
+
+// registerOrDeregisterProxied registers the api services of the passed through host apis and
+// removes the ones that were registered previously but are not configured anymore
+func registerOrDeregisterProxied(ctx context.Context, kubeClient client.Client, names []string) error {
+	groupVersions, err := ParseProxiedAPIServices(names)
+	if err != nil {
+		return err
+	}
+
+	configured := map[string]bool{}
+	for _, groupVersion := range groupVersions {
+		configured[groupVersion.Version+"."+groupVersion.Group] = true
+		err = applyOperation(ctx, createOperation(ctx, kubeClient, groupVersion.Group, groupVersion.Version, map[string]string{ProxiedAPIServiceLabel: "true"}))
+		if err != nil {
+			return err
+		}
+	}
+
+	apiServiceList := &apiregistrationv1.APIServiceList{}
+	err = kubeClient.List(ctx, apiServiceList, client.MatchingLabels{ProxiedAPIServiceLabel: "true"})
+	if err != nil {
+		return err
+	}
+	for _, apiService := range apiServiceList.Items {
+		if configured[apiService.Name] {
+			continue
+		}
+
+		err = applyOperation(ctx, deleteOperation(ctx, kubeClient, apiService.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func registerOrDeregister(ctx context.Context, client client.Client, enabled bool, group, version string) error {
 	// check if the api service should get created
-	exists := checkExistingAPIService(ctx, client)
-	if options.ProxyMetricsServer {
-		return applyOperation(ctx, createOperation(ctx, client))
-	} else if !options.ProxyMetricsServer && exists {
-		return applyOperation(ctx, deleteOperation(ctx, client))
+	name := version + "." + group
+	exists := checkExistingAPIService(ctx, client, name)
+	if enabled {
+		return applyOperation(ctx, createOperation(ctx, client, group, version, nil))
+	} else if exists {
+		return applyOperation(ctx, deleteOperation(ctx, client, name))
 	}
 
 	return nil
@@ -0,0 +1,101 @@
+package operations
+
+import (
+	"context"
+	"math"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ViewClusterRoleName allows to list paused objects and mappings and is aggregated into the view role
+	ViewClusterRoleName = "vcluster:operations:view"
+	// AdminClusterRoleName allows all operations and is aggregated into the admin role
+	AdminClusterRoleName = "vcluster:operations:admin"
+)
+
+// RegisterOrDeregisterAPIService makes sure the operations group shows up in the virtual cluster
+// discovery and that the default user facing roles include it. Requests for the group never reach
+// the virtual api server, as they are served by the syncer proxy directly.
+func RegisterOrDeregisterAPIService(ctx context.Context, enabled bool, virtualClient client.Client) error {
+	return wait.ExponentialBackoffWithContext(ctx, wait.Backoff{
+		Duration: time.Second,
+		Factor:   1.5,
+		Cap:      time.Minute,
+		Steps:    math.MaxInt32,
+	}, func(ctx context.Context) (bool, error) {
+		apiService := &apiregistrationv1.APIService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: APIServiceName,
+			},
+		}
+
+		clusterRoles := []*rbacv1.ClusterRole{
+			{ObjectMeta: metav1.ObjectMeta{Name: ViewClusterRoleName}},
+			{ObjectMeta: metav1.ObjectMeta{Name: AdminClusterRoleName}},
+		}
+
+		if !enabled {
+			for _, obj := range []client.Object{apiService, clusterRoles[0], clusterRoles[1]} {
+				err := virtualClient.Delete(ctx, obj)
+				if err != nil && !kerrors.IsNotFound(err) {
+					return false, err
+				}
+			}
+
+			return true, nil
+		}
+
+		_, err := controllerutil.CreateOrUpdate(ctx, virtualClient, clusterRoles[0], func() error {
+			clusterRoles[0].Labels = map[string]string{"rbac.authorization.k8s.io/aggregate-to-view": "true"}
+			clusterRoles[0].Rules = []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{GroupName},
+					Resources: []string{PausesResource, MappingsResource},
+					Verbs:     []string{"list"},
+				},
+			}
+			return nil
+		})
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return false, err
+		}
+
+		_, err = controllerutil.CreateOrUpdate(ctx, virtualClient, clusterRoles[1], func() error {
+			clusterRoles[1].Labels = map[string]string{"rbac.authorization.k8s.io/aggregate-to-admin": "true"}
+			clusterRoles[1].Rules = []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{GroupName},
+					Resources: []string{ResyncsResource, GarbageCollectionsResource, PausesResource, DrainsResource, MappingsResource, InventoriesResource},
+					Verbs:     []string{"create", "list"},
+				},
+			}
+			return nil
+		})
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return false, err
+		}
+
+		_, err = controllerutil.CreateOrUpdate(ctx, virtualClient, apiService, func() error {
+			apiService.Spec = apiregistrationv1.APIServiceSpec{
+				Group:                GroupName,
+				GroupPriorityMinimum: 100,
+				Version:              Version,
+				VersionPriority:      100,
+			}
+			return nil
+		})
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return false, err
+		}
+
+		return true, nil
+	})
+}
@@ -0,0 +1,191 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/loft-sh/vcluster/pkg/inventory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	GroupName = "operations.vcluster.loft.sh"
+	Version   = "v1"
+
+	// APIServiceName is the name of the APIService registered in the virtual cluster
+	APIServiceName = Version + "." + GroupName
+
+	ResyncsResource            = "resyncs"
+	GarbageCollectionsResource = "garbagecollections"
+	PausesResource             = "pauses"
+	DrainsResource             = "drains"
+	MappingsResource           = "mappings"
+	InventoriesResource        = "inventories"
+)
+
+// PausedAnnotation marks a virtual object that should currently not be created or updated in the
+// host cluster, deleting it and cleaning up its physical object still works.
+// It is stored on the virtual object itself, so pauses survive syncer restarts.
+const PausedAnnotation = "vcluster.loft.sh/paused"
+
+// Target is implemented by every syncer controller that can be operated on
+type Target interface {
+	// Resync enqueues the virtual object with the given name. If the name is empty,
+	// all virtual objects (optionally limited to the namespace) are enqueued.
+	Resync(ctx context.Context, namespace, name string) (int, error)
+
+	// GarbageCollect enqueues all physical objects managed by the syncer, which
+	// makes sure physical objects without a virtual counterpart are deleted.
+	GarbageCollect(ctx context.Context) (int, error)
+
+	// Pause pauses or resumes syncing of a single virtual object
+	Pause(ctx context.Context, namespace, name string, paused bool) error
+
+	// Drain pauses or resumes syncing of all virtual objects of the syncer,
+	// optionally limited to the namespace, and returns the number of changed objects
+	Drain(ctx context.Context, namespace string, paused bool) (int, error)
+
+	// Paused returns the currently paused virtual objects
+	Paused(ctx context.Context, namespace string) ([]types.NamespacedName, error)
+
+	// Mappings returns the virtual to physical name mappings of the syncer
+	Mappings(ctx context.Context, namespace string) ([]Mapping, error)
+}
+
+// Inventory takes and returns snapshots of the synced physical state. Backup tooling takes
+// a snapshot right before backing up the datastore, so both are consistent with each other.
+type Inventory interface {
+	Snapshot(ctx context.Context) (*inventory.Inventory, error)
+	Latest(ctx context.Context) (*inventory.Inventory, error)
+}
+
+// Mapping is a single virtual to physical name mapping
+type Mapping struct {
+	Virtual  types.NamespacedName `json:"virtual"`
+	Physical types.NamespacedName `json:"physical"`
+}
+
+// Request is the body of a resync, garbage collection, pause or drain request
+type Request struct {
+	// Syncer is the name of the syncer, e.g. pods
+	Syncer string `json:"syncer"`
+
+	// Namespace of the virtual object
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the virtual object
+	Name string `json:"name,omitempty"`
+
+	// Paused is only used for pause and drain requests and resumes the objects if false
+	Paused *bool `json:"paused,omitempty"`
+}
+
+// Result is returned by the operations api
+type Result struct {
+	Syncer string `json:"syncer,omitempty"`
+
+	// Enqueued is the number of objects that were enqueued
+	Enqueued int `json:"enqueued,omitempty"`
+
+	// Changed is the number of objects that were paused or resumed
+	Changed int `json:"changed,omitempty"`
+
+	// Paused holds the currently paused objects of the syncer
+	Paused []types.NamespacedName `json:"paused,omitempty"`
+
+	// Mappings holds the name mappings of the syncer
+	Mappings []Mapping `json:"mappings,omitempty"`
+
+	// Inventory holds the snapshot of the synced physical state
+	Inventory *inventory.Inventory `json:"inventory,omitempty"`
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		targets: map[string]Target{},
+	}
+}
+
+// Registry holds the syncers that can be operated on through the operations api
+type Registry struct {
+	m sync.RWMutex
+
+	targets   map[string]Target
+	inventory Inventory
+
+	// syncing is read locked by the syncers while reconciling and locked while the syncers are quiesced
+	syncing sync.RWMutex
+}
+
+func (r *Registry) Register(syncer string, target Target) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.targets[syncer] = target
+}
+
+func (r *Registry) Target(syncer string) (Target, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	target, ok := r.targets[syncer]
+	if !ok {
+		return nil, fmt.Errorf("syncer %s not found", syncer)
+	}
+
+	return target, nil
+}
+
+func (r *Registry) SetInventory(inventory Inventory) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.inventory = inventory
+}
+
+func (r *Registry) Inventory() (Inventory, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if r.inventory == nil {
+		return nil, fmt.Errorf("inventory is not available")
+	}
+
+	return r.inventory, nil
+}
+
+// StartSync is called by the syncers before reconciling and blocks while the syncers are quiesced.
+// The returned function has to be called once the reconcile is done.
+func (r *Registry) StartSync() func() {
+	r.syncing.RLock()
+	return r.syncing.RUnlock
+}
+
+// Quiesce waits for all running reconciles to finish and runs fn while no syncer is reconciling
+func (r *Registry) Quiesce(fn func() error) error {
+	r.syncing.Lock()
+	defer r.syncing.Unlock()
+
+	return fn()
+}
+
+func (r *Registry) Syncers() []string {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	syncers := make([]string, 0, len(r.targets))
+	for syncer := range r.targets {
+		syncers = append(syncers, syncer)
+	}
+
+	sort.Strings(syncers)
+	return syncers
+}
+
+// IsPaused returns true if the virtual object carries the paused annotation
+func IsPaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[PausedAnnotation] == "true"
+}
@@ -0,0 +1,140 @@
+package operations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeTarget struct{}
+
+func (f *fakeTarget) Resync(ctx context.Context, namespace, name string) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeTarget) GarbageCollect(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeTarget) Pause(ctx context.Context, namespace, name string, paused bool) error {
+	return nil
+}
+
+func (f *fakeTarget) Drain(ctx context.Context, namespace string, paused bool) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeTarget) Paused(ctx context.Context, namespace string) ([]types.NamespacedName, error) {
+	return nil, nil
+}
+
+func (f *fakeTarget) Mappings(ctx context.Context, namespace string) ([]Mapping, error) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	testCases := []struct {
+		name            string
+		register        []string
+		lookup          string
+		expectedSyncers []string
+		expectedErr     string
+	}{
+		{
+			name:            "empty registry",
+			lookup:          "pods",
+			expectedSyncers: []string{},
+			expectedErr:     "syncer pods not found",
+		},
+		{
+			name:            "registered syncer",
+			register:        []string{"services", "pods", "configmaps"},
+			lookup:          "pods",
+			expectedSyncers: []string{"configmaps", "pods", "services"},
+		},
+		{
+			name:            "unknown syncer",
+			register:        []string{"pods"},
+			lookup:          "secrets",
+			expectedSyncers: []string{"pods"},
+			expectedErr:     "syncer secrets not found",
+		},
+		{
+			name:            "duplicate registration",
+			register:        []string{"pods", "pods"},
+			lookup:          "pods",
+			expectedSyncers: []string{"pods"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		registry := NewRegistry()
+		for _, syncer := range testCase.register {
+			registry.Register(syncer, &fakeTarget{})
+		}
+
+		target, err := registry.Target(testCase.lookup)
+		if testCase.expectedErr != "" {
+			assert.Error(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+		} else {
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+			assert.Assert(t, target != nil, "expected target in test case %s", testCase.name)
+		}
+		assert.Assert(t, cmp.DeepEqual(registry.Syncers(), testCase.expectedSyncers), "unexpected syncers in test case %s", testCase.name)
+	}
+}
+
+func TestIsPaused(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name:        "paused",
+			annotations: map[string]string{PausedAnnotation: "true"},
+			expected:    true,
+		},
+		{
+			name:        "resumed",
+			annotations: map[string]string{PausedAnnotation: "false"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		obj := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: testCase.annotations}}
+		assert.Equal(t, IsPaused(obj), testCase.expected, "unexpected result in test case %s", testCase.name)
+	}
+}
+
+func TestQuiesce(t *testing.T) {
+	registry := NewRegistry()
+	done := registry.StartSync()
+
+	// quiesce has to wait for the running reconcile
+	quiesced := make(chan struct{})
+	go func() {
+		_ = registry.Quiesce(func() error {
+			close(quiesced)
+			return nil
+		})
+	}()
+	select {
+	case <-quiesced:
+		t.Fatal("quiesced while a reconcile was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	done()
+	<-quiesced
+	registry.StartSync()()
+}
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// IndexPodByNode indexes the non terminated host pods by the node they are running on
+	IndexPodByNode = "indexpodbynode"
+
+	// MaxExtenderPriority is the highest score an extender can return
+	MaxExtenderPriority int64 = 10
+
+	// URLPrefix is the path on the https port of the syncer the kube-scheduler sends the extender requests to
+	URLPrefix = "/vcluster/scheduler-extender"
+
+	// PrioritizePath is the path the kube-scheduler sends the prioritize requests to
+	PrioritizePath = URLPrefix + "/prioritize"
+)
+
+// ExtenderArgs is the prioritize request of the kube-scheduler extender api (k8s.io/kube-scheduler/extender/v1).
+// Nodes is set if the extender is not node cache capable, NodeNames otherwise.
+type ExtenderArgs struct {
+	Pod       *corev1.Pod
+	Nodes     *corev1.NodeList
+	NodeNames *[]string
+}
+
+// HostPriority is the score of a single node of the kube-scheduler extender api
+type HostPriority struct {
+	Host  string
+	Score int64
+}
+
+// Extender scores virtual nodes by the free capacity of the corresponding host nodes. In contrast to the
+// allocatable resources of the synced nodes, the free capacity includes the requests of pods other tenants
+// placed on the host node just now, which reduces pods that are bound in the virtual cluster but stay
+// pending in the host cluster.
+type Extender struct {
+	// HostReader reads the host nodes and the host pods of all namespaces by IndexPodByNode
+	HostReader client.Reader
+
+	Log loghelper.Logger
+}
+
+// Prioritize returns a score for each node the pod could be scheduled on
+func (e *Extender) Prioritize(ctx context.Context, args *ExtenderArgs) ([]HostPriority, error) {
+	nodeNames := []string{}
+	if args.Nodes != nil {
+		for _, node := range args.Nodes.Items {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	} else if args.NodeNames != nil {
+		nodeNames = *args.NodeNames
+	}
+
+	podRequests := corev1.ResourceList{}
+	if args.Pod != nil {
+		podRequests, _ = resourcehelper.PodRequestsAndLimits(args.Pod)
+	}
+
+	priorities := make([]HostPriority, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		score, err := e.score(ctx, nodeName, podRequests)
+		if err != nil {
+			return nil, err
+		}
+
+		priorities = append(priorities, HostPriority{Host: nodeName, Score: score})
+	}
+
+	return priorities, nil
+}
+
+func (e *Extender) score(ctx context.Context, nodeName string, podRequests corev1.ResourceList) (int64, error) {
+	pNode := &corev1.Node{}
+	err := e.HostReader.Get(ctx, types.NamespacedName{Name: nodeName}, pNode)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// fake nodes have no host node, so they are neither preferred nor avoided
+			return MaxExtenderPriority / 2, nil
+		}
+		return 0, err
+	}
+
+	requested, err := RequestedOnNode(ctx, e.HostReader, nodeName)
+	if err != nil {
+		return 0, err
+	}
+
+	return Score(pNode.Status.Allocatable, requested, podRequests), nil
+}
+
+// Requested returns the sum of the resource requests of the pods
+func Requested(pods []corev1.Pod) corev1.ResourceList {
+	requested := corev1.ResourceList{}
+	for i := range pods {
+		requests, _ := resourcehelper.PodRequestsAndLimits(&pods[i])
+		for name, quantity := range requests {
+			value := requested[name]
+			value.Add(quantity)
+			requested[name] = value
+		}
+	}
+
+	return requested
+}
+
+// Fits returns true if every resource the pod requests is still available on the node
+func Fits(allocatable corev1.ResourceList, requested corev1.ResourceList, podRequests corev1.ResourceList) bool {
+	for name, quantity := range podRequests {
+		if quantity.IsZero() {
+			continue
+		}
+
+		available := allocatable[name].DeepCopy()
+		available.Sub(requested[name])
+		if available.Cmp(quantity) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Score returns the share of cpu and memory that is still free on the node after the pod was placed,
+// scaled to MaxExtenderPriority. Nodes the pod doesn't fit on anymore get a score of 0.
+func Score(allocatable corev1.ResourceList, requested corev1.ResourceList, podRequests corev1.ResourceList) int64 {
+	var free float64
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		capacity := allocatable[name]
+		if capacity.IsZero() {
+			return 0
+		}
+
+		used := requested[name]
+		available := capacity.DeepCopy()
+		available.Sub(used)
+		available.Sub(podRequests[name])
+		if available.Sign() < 0 {
+			return 0
+		}
+
+		free += fraction(available, capacity)
+	}
+
+	return int64(free / 2 * float64(MaxExtenderPriority))
+}
+
+func fraction(a, b resource.Quantity) float64 {
+	return float64(a.MilliValue()) / float64(b.MilliValue())
+}
+
+// IndexPod returns the node of the host pod, if the pod still occupies resources on it
+func IndexPod(rawObj client.Object) []string {
+	pod := rawObj.(*corev1.Pod)
+	if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return nil
+	}
+
+	return []string{pod.Spec.NodeName}
+}
+
+func (e *Extender) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	args := &ExtenderArgs{}
+	err := json.NewDecoder(r.Body).Decode(args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	priorities, err := e.Prioritize(r.Context(), args)
+	if err != nil {
+		e.Log.Infof("error prioritizing nodes: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(priorities)
+	if err != nil {
+		e.Log.Infof("error writing prioritize response: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScore(t *testing.T) {
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+
+	testCases := []struct {
+		name          string
+		allocatable   corev1.ResourceList
+		requested     corev1.ResourceList
+		podRequests   corev1.ResourceList
+		expectedScore int64
+	}{
+		{
+			name:          "Empty node",
+			allocatable:   allocatable,
+			expectedScore: MaxExtenderPriority,
+		},
+		{
+			name:        "Half used node",
+			allocatable: allocatable,
+			requested: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			podRequests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1"),
+			},
+			expectedScore: 5,
+		},
+		{
+			name:        "Pod does not fit",
+			allocatable: allocatable,
+			requested: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("3500m"),
+			},
+			podRequests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1"),
+			},
+			expectedScore: 0,
+		},
+		{
+			name:          "No allocatable resources",
+			expectedScore: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		score := Score(testCase.allocatable, testCase.requested, testCase.podRequests)
+		assert.Equal(t, score, testCase.expectedScore, "unexpected score in test case %s", testCase.name)
+	}
+}
+
+func TestFits(t *testing.T) {
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+
+	testCases := []struct {
+		name        string
+		requested   corev1.ResourceList
+		podRequests corev1.ResourceList
+		expected    bool
+	}{
+		{
+			name:     "Pod without requests",
+			expected: true,
+		},
+		{
+			name:        "Pod fits exactly",
+			requested:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+			podRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			expected:    true,
+		},
+		{
+			name:        "Not enough memory",
+			requested:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("7Gi")},
+			podRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+		{
+			name:        "Too many pods",
+			requested:   corev1.ResourceList{corev1.ResourcePods: resource.MustParse("110")},
+			podRequests: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+		},
+		{
+			name:        "Missing extended resource",
+			podRequests: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+		},
+	}
+
+	for _, testCase := range testCases {
+		fits := Fits(allocatable, testCase.requested, testCase.podRequests)
+		assert.Equal(t, fits, testCase.expected, "unexpected result in test case %s", testCase.name)
+	}
+}
+
+func TestPrioritize(t *testing.T) {
+	newNode := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		}
+	}
+	newPod := func(name, nodeName string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other-tenant", Name: name},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{
+					{
+						Name: "test",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("2"),
+								corev1.ResourceMemory: resource.MustParse("2Gi"),
+							},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	hostReader := fake.NewClientBuilder().
+		WithObjects(
+			newNode("node-1"),
+			newNode("node-2"),
+			newPod("running", "node-1", corev1.PodRunning),
+			newPod("completed", "node-2", corev1.PodSucceeded),
+		).
+		WithIndex(&corev1.Pod{}, IndexPodByNode, IndexPod).
+		Build()
+	extender := &Extender{HostReader: hostReader, Log: loghelper.New("test")}
+
+	args := &ExtenderArgs{
+		Pod:       &corev1.Pod{},
+		NodeNames: &[]string{"node-1", "node-2", "fake-node"},
+	}
+	body, err := json.Marshal(args)
+	assert.NilError(t, err)
+
+	recorder := httptest.NewRecorder()
+	extender.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PrioritizePath, bytes.NewReader(body)))
+	assert.Equal(t, recorder.Code, http.StatusOK)
+
+	priorities := []HostPriority{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &priorities)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, priorities, []HostPriority{
+		{Host: "node-1", Score: 0},
+		{Host: "node-2", Score: MaxExtenderPriority},
+		{Host: "fake-node", Score: MaxExtenderPriority / 2},
+	})
+
+	// nodes are passed as objects if the extender is not node cache capable
+	priorities, err = extender.Prioritize(context.TODO(), &ExtenderArgs{
+		Nodes: &corev1.NodeList{Items: []corev1.Node{*newNode("node-2")}},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, priorities, []HostPriority{{Host: "node-2", Score: MaxExtenderPriority}})
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// HostCache holds the host nodes and the host pods of all namespaces indexed by IndexPodByNode.
+// The scheduler extender and the pod syncer share it, it is created and started on first use.
+type HostCache struct {
+	manager manager.Manager
+
+	once   sync.Once
+	reader client.Reader
+	err    error
+}
+
+// NewHostCache returns a host cache for the cluster of the manager
+func NewHostCache(manager manager.Manager) *HostCache {
+	return &HostCache{manager: manager}
+}
+
+// Reader starts the cache if needed and returns it once it is synced
+func (h *HostCache) Reader(ctx context.Context) (client.Reader, error) {
+	h.once.Do(func() {
+		h.reader, h.err = h.start(ctx)
+	})
+
+	return h.reader, h.err
+}
+
+func (h *HostCache) start(ctx context.Context) (client.Reader, error) {
+	// the manager cache might be scoped to the target namespace, so the host cache is a separate one
+	hostCache, err := cache.New(h.manager.GetConfig(), cache.Options{
+		Scheme: h.manager.GetScheme(),
+		Mapper: h.manager.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create host cache: %w", err)
+	}
+	err = hostCache.IndexField(ctx, &corev1.Pod{}, IndexPodByNode, IndexPod)
+	if err != nil {
+		return nil, fmt.Errorf("index pods by node: %w", err)
+	}
+	_, err = hostCache.GetInformer(ctx, &corev1.Node{})
+	if err != nil {
+		return nil, fmt.Errorf("watch nodes: %w", err)
+	}
+
+	// stop waiting for the cache, if it fails to start
+	startErr := make(chan error, 1)
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		err := hostCache.Start(ctx)
+		if err != nil {
+			startErr <- err
+			cancel()
+		}
+	}()
+	if !hostCache.WaitForCacheSync(waitCtx) {
+		select {
+		case err := <-startErr:
+			return nil, fmt.Errorf("start host cache: %w", err)
+		default:
+			return nil, fmt.Errorf("host cache didn't sync")
+		}
+	}
+
+	return hostCache, nil
+}
+
+// RequestedOnNode returns the sum of the resource requests and the number of pods, as
+// corev1.ResourcePods, of the host pods that occupy resources on the node
+func RequestedOnNode(ctx context.Context, hostReader client.Reader, nodeName string) (corev1.ResourceList, error) {
+	podList := &corev1.PodList{}
+	err := hostReader.List(ctx, podList, client.MatchingFields{IndexPodByNode: nodeName})
+	if err != nil {
+		return nil, err
+	}
+
+	requested := Requested(podList.Items)
+	requested[corev1.ResourcePods] = *resource.NewQuantity(int64(len(podList.Items)), resource.DecimalSI)
+	return requested, nil
+}
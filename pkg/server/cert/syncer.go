@@ -3,9 +3,11 @@ package cert
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +25,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// TLSSANsAnnotation on the vcluster service adds the comma separated hostnames and ips to the
+// serving certificate without restarting the vcluster
+const TLSSANsAnnotation = "vcluster.loft.sh/tls-sans"
+
 type Syncer interface {
 	dynamiccertificates.Notifier
 	dynamiccertificates.ControllerRunner
@@ -30,6 +36,17 @@ type Syncer interface {
 }
 
 func NewSyncer(ctx context.Context, currentNamespace string, currentNamespaceClient client.Client, options *ctrlcontext.VirtualClusterOptions) (Syncer, error) {
+	addSANs := append([]string{}, options.TLSSANs...)
+	if options.KubeConfigServer != "" {
+		// the kube config only works if the certificate is valid for its server
+		server, err := url.Parse(KubeConfigServerURL(options.KubeConfigServer))
+		if err != nil || server.Hostname() == "" {
+			return nil, fmt.Errorf("invalid out-kube-config-server %s, expected an url like https://my-vcluster.domain.com or a host like my-vcluster.domain.com:443", options.KubeConfigServer)
+		}
+
+		addSANs = append(addSANs, server.Hostname())
+	}
+
 	return &syncer{
 		clusterDomain: options.ClusterDomain,
 
@@ -38,7 +55,7 @@ func NewSyncer(ctx context.Context, currentNamespace string, currentNamespaceCli
 
 		fakeKubeletIPs: options.FakeKubeletIPs,
 
-		addSANs:   options.TLSSANs,
+		addSANs:   addSANs,
 		listeners: []dynamiccertificates.Listener{},
 
 		serviceName:           options.ServiceName,
@@ -47,6 +64,16 @@ func NewSyncer(ctx context.Context, currentNamespace string, currentNamespaceCli
 	}, nil
 }
 
+// KubeConfigServerURL returns the url of the kube config server, which can be given as url or as
+// host with an optional port, in which case https is used
+func KubeConfigServerURL(server string) string {
+	if !strings.Contains(server, "://") {
+		return "https://" + server
+	}
+
+	return server
+}
+
 type syncer struct {
 	clusterDomain string
 
@@ -119,6 +146,11 @@ func (s *syncer) getSANs(ctx context.Context) ([]string, error) {
 	}
 
 	retSANs = append(retSANs, svc.Spec.ClusterIP)
+	for _, san := range strings.Split(svc.Annotations[TLSSANsAnnotation], ",") {
+		if san = strings.TrimSpace(san); san != "" {
+			retSANs = append(retSANs, san)
+		}
+	}
 
 	// add pod IP
 	podIP := os.Getenv("POD_IP")
@@ -0,0 +1,59 @@
+package cert
+
+import (
+	"context"
+	"testing"
+
+	ctrlcontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetSANs(t *testing.T) {
+	t.Setenv("POD_IP", "")
+	currentNamespaceClient := fake.NewClientBuilder().WithObjects(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "vcluster",
+			Namespace:   "test",
+			Annotations: map[string]string{TLSSANsAnnotation: "lb.example.com, 192.168.0.10"},
+		},
+		Spec: corev1.ServiceSpec{ClusterIP: "10.96.0.5"},
+	}).Build()
+
+	s, err := NewSyncer(context.Background(), "test", currentNamespaceClient, &ctrlcontext.VirtualClusterOptions{
+		ServiceName:      "vcluster",
+		TLSSANs:          []string{"vcluster.local"},
+		KubeConfigServer: "https://vcluster.example.com:6443",
+	})
+	assert.NilError(t, err)
+
+	sans, err := s.(*syncer).getSANs(context.Background())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sans, []string{
+		"*.nodes.vcluster.com",
+		"10.96.0.5",
+		"192.168.0.10",
+		"lb.example.com",
+		"vcluster",
+		"vcluster.example.com",
+		"vcluster.local",
+		"vcluster.test",
+	})
+
+	for _, kubeConfigServer := range []string{"vcluster.example.com", "vcluster.example.com:8443"} {
+		s, err = NewSyncer(context.Background(), "test", currentNamespaceClient, &ctrlcontext.VirtualClusterOptions{
+			ServiceName:      "vcluster",
+			KubeConfigServer: kubeConfigServer,
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, s.(*syncer).addSANs, []string{"vcluster.example.com"})
+	}
+
+	_, err = NewSyncer(context.Background(), "test", currentNamespaceClient, &ctrlcontext.VirtualClusterOptions{
+		ServiceName:      "vcluster",
+		KubeConfigServer: "https://:6443",
+	})
+	assert.ErrorContains(t, err, "invalid out-kube-config-server")
+}
@@ -0,0 +1,202 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/server/handler"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithAPIServiceProxy passes the given aggregated apis of the host cluster through into the
+// virtual cluster. Only get and list requests of namespaced resources are supported, which are
+// proxied to the physical namespace. Objects in the response are mapped back to their virtual
+// names and objects that don't belong to the virtual namespace are removed. Items without metadata,
+// like metric values, are passed through unchanged and so is the label selector, as it might
+// select metric series instead of objects.
+func WithAPIServiceProxy(h http.Handler, cachedVirtualClient client.Client, hostConfig *rest.Config, groupVersions []schema.GroupVersion) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		if isProxiedAPIResourceListRequest(info, groupVersions) {
+			proxyHandler, err := handler.Handler("", hostConfig, nil)
+			if err != nil {
+				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+				return
+			}
+
+			req.Header.Del("Authorization")
+			apiResourceListProxy := &APIResourceListProxy{
+				codecFactory:   serializer.NewCodecFactory(cachedVirtualClient.Scheme()),
+				handler:        proxyHandler,
+				request:        req,
+				requestInfo:    info,
+				responseWriter: w,
+			}
+			apiResourceListProxy.HandleRequest()
+			return
+		}
+
+		if !isProxiedAPIRequest(info, groupVersions) {
+			h.ServeHTTP(w, req)
+			return
+		} else if info.Verb != RequestVerbGet && info.Verb != RequestVerbList {
+			requestpkg.FailWithStatus(w, req, http.StatusMethodNotAllowed, fmt.Errorf("only get and list requests are supported for %s", info.APIGroup))
+			return
+		} else if info.Namespace == "" {
+			requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("%s of %s are only available within a namespace in the virtual cluster", info.Resource, info.APIGroup))
+			return
+		}
+
+		// the path looks like /apis/{group}/{version}/namespaces/{namespace}/{resource}/{name}/...
+		splitted := strings.Split(req.URL.Path, "/")
+		if len(splitted) < 7 {
+			requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("unexpected path %s", req.URL.Path))
+			return
+		}
+		splitted[5] = translate.Default.PhysicalNamespace(info.Namespace)
+		if info.Name != "" && len(splitted) > 7 {
+			splitted[7] = translate.Default.PhysicalName(info.Name, info.Namespace)
+		}
+		req.URL.Path = strings.Join(splitted, "/")
+
+		proxyHandler, err := handler.Handler("", hostConfig, nil)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		// returned objects are rewritten, so we always want json
+		req.Header.Del("Authorization")
+		req.Header.Set("Accept", "application/json")
+		code, header, data, err := executeRequest(req, proxyHandler)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, serializer.NewCodecFactory(cachedVirtualClient.Scheme()), corev1.SchemeGroupVersion, w, req)
+			return
+		} else if code != http.StatusOK {
+			writeWithHeader(w, code, header, data)
+			return
+		}
+
+		newData, err := rewriteProxiedAPIResponse(data, info.Namespace)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set(HeaderContentType, header.Get(HeaderContentType))
+		_, err = w.Write(newData)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+	})
+}
+
+// rewriteProxiedAPIResponse maps the returned object or the items of a returned list back to
+// vNamespace and removes list items that don't belong to it
+func rewriteProxiedAPIResponse(data []byte, vNamespace string) ([]byte, error) {
+	obj := map[string]interface{}{}
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := obj["items"].([]interface{})
+	if !ok {
+		if !rewriteProxiedObject(obj, vNamespace) {
+			return nil, fmt.Errorf("returned object does not belong to namespace %s", vNamespace)
+		}
+
+		return json.Marshal(obj)
+	}
+
+	newItems := []interface{}{}
+	for _, item := range items {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok || !rewriteProxiedObject(itemObj, vNamespace) {
+			continue
+		}
+
+		newItems = append(newItems, itemObj)
+	}
+	obj["items"] = newItems
+
+	return json.Marshal(obj)
+}
+
+// rewriteProxiedObject sets the virtual name and namespace in the metadata of obj and returns
+// false if obj is not an object of vNamespace. Objects without metadata are kept as they are.
+func rewriteProxiedObject(obj map[string]interface{}, vNamespace string) bool {
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	if namespace != translate.Default.PhysicalNamespace(vNamespace) {
+		return false
+	}
+
+	// objects that were synced by vcluster carry their virtual name
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	if vName, ok := annotations[translate.NameAnnotation].(string); ok && vName != "" {
+		if annotations[translate.NamespaceAnnotation] != vNamespace {
+			return false
+		}
+
+		metadata["name"] = vName
+		metadata["namespace"] = vNamespace
+		return true
+	}
+
+	if translate.Default.SingleNamespaceTarget() {
+		suffix := "-x-" + vNamespace + "-x-" + translate.Suffix
+		if !strings.HasSuffix(name, suffix) {
+			return false
+		}
+
+		metadata["name"] = strings.TrimSuffix(name, suffix)
+	}
+	metadata["namespace"] = vNamespace
+	return true
+}
+
+func isProxiedAPIResourceListRequest(r *request.RequestInfo, groupVersions []schema.GroupVersion) bool {
+	for _, groupVersion := range groupVersions {
+		if r.Path == "/apis/"+groupVersion.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isProxiedAPIRequest(r *request.RequestInfo, groupVersions []schema.GroupVersion) bool {
+	if !r.IsResourceRequest {
+		return false
+	}
+
+	for _, groupVersion := range groupVersions {
+		if r.APIGroup == groupVersion.Group && r.APIVersion == groupVersion.Version {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	externalmetricsv1beta1 "k8s.io/metrics/pkg/apis/external_metrics/v1beta1"
+)
+
+func TestRewriteProxiedAPIResponse(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	newObject := func(name, namespace string, annotations map[string]string) map[string]interface{} {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("example.com/v1")
+		obj.SetKind("Widget")
+		obj.SetName(name)
+		obj.SetNamespace(namespace)
+		obj.SetAnnotations(annotations)
+		return obj.Object
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "WidgetList",
+		"metadata":   map[string]interface{}{},
+		"items": []interface{}{
+			newObject(translate.Default.PhysicalName("queue", "default"), "test", nil),
+			newObject("synced", "test", map[string]string{
+				translate.NameAnnotation:      "worker",
+				translate.NamespaceAnnotation: "default",
+			}),
+			newObject(translate.Default.PhysicalName("queue", "other"), "test", nil),
+			newObject("queue", "host-namespace", nil),
+		},
+	})
+	assert.NilError(t, err)
+
+	newData, err := rewriteProxiedAPIResponse(data, "default")
+	assert.NilError(t, err)
+
+	list := &unstructured.UnstructuredList{}
+	err = list.UnmarshalJSON(newData)
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Items), 2)
+	assert.Equal(t, list.Items[0].GetName(), "queue")
+	assert.Equal(t, list.Items[0].GetNamespace(), "default")
+	assert.Equal(t, list.Items[1].GetName(), "worker")
+	assert.Equal(t, list.Items[1].GetNamespace(), "default")
+
+	// single objects of other namespaces are rejected
+	data, err = json.Marshal(newObject("queue", "host-namespace", nil))
+	assert.NilError(t, err)
+	_, err = rewriteProxiedAPIResponse(data, "default")
+	assert.ErrorContains(t, err, "does not belong to namespace default")
+
+	// metric values have no metadata and are passed through
+	metricValueList := &externalmetricsv1beta1.ExternalMetricValueList{
+		TypeMeta: metav1.TypeMeta{APIVersion: externalmetricsv1beta1.SchemeGroupVersion.String(), Kind: "ExternalMetricValueList"},
+		Items: []externalmetricsv1beta1.ExternalMetricValue{
+			{
+				MetricName:   "queue_length",
+				MetricLabels: map[string]string{"queue": "jobs"},
+				Value:        resource.MustParse("5"),
+			},
+		},
+	}
+	data, err = json.Marshal(metricValueList)
+	assert.NilError(t, err)
+	newData, err = rewriteProxiedAPIResponse(data, "default")
+	assert.NilError(t, err)
+
+	newMetricValueList := &externalmetricsv1beta1.ExternalMetricValueList{}
+	err = json.Unmarshal(newData, newMetricValueList)
+	assert.NilError(t, err)
+	assert.Equal(t, len(newMetricValueList.Items), 1)
+	assert.Equal(t, newMetricValueList.Items[0].MetricName, "queue_length")
+	assert.DeepEqual(t, newMetricValueList.Items[0].MetricLabels, map[string]string{"queue": "jobs"})
+
+	// in multi namespace mode names are kept
+	translate.Default = translate.NewMultiNamespaceTranslator("test")
+	data, err = json.Marshal(newObject("queue", translate.Default.PhysicalNamespace("default"), nil))
+	assert.NilError(t, err)
+	newData, err = rewriteProxiedAPIResponse(data, "default")
+	assert.NilError(t, err)
+
+	obj := &metav1.PartialObjectMetadata{}
+	err = json.Unmarshal(newData, obj)
+	assert.NilError(t, err)
+	assert.Equal(t, obj.Name, "queue")
+	assert.Equal(t, obj.Namespace, "default")
+}
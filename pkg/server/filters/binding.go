@@ -0,0 +1,87 @@
+package filters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithPodBinding validates pod bindings of schedulers that run inside the virtual cluster. Bindings are
+// only passed to the virtual api server if the target node exists in the virtual cluster, so the pod syncer
+// can sync the bound pod with the chosen node name.
+func WithPodBinding(h http.Handler, cachedVirtualClient client.Client) http.Handler {
+	s := serializer.NewCodecFactory(cachedVirtualClient.Scheme())
+	decoder := encoding.NewDecoder(cachedVirtualClient.Scheme(), false)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok || !isPodBinding(info) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		err = validateBinding(req.Context(), decoder, cachedVirtualClient, body)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		h.ServeHTTP(w, req)
+	})
+}
+
+// isPodBinding returns true for the binding subresource of pods and the deprecated bindings resource
+func isPodBinding(info *request.RequestInfo) bool {
+	if !info.IsResourceRequest || info.Verb != "create" || info.APIGroup != "" {
+		return false
+	}
+
+	return (info.Resource == "pods" && info.Subresource == "binding") || (info.Resource == "bindings" && info.Subresource == "")
+}
+
+func validateBinding(ctx context.Context, decoder encoding.Decoder, virtualClient client.Client, body []byte) error {
+	bindingGVK := corev1.SchemeGroupVersion.WithKind("Binding")
+	obj, err := decoder.Decode(body, &bindingGVK)
+	if err != nil {
+		return kerrors.NewBadRequest(fmt.Sprintf("decode binding: %v", err))
+	}
+
+	binding, ok := obj.(*corev1.Binding)
+	if !ok {
+		return kerrors.NewBadRequest(fmt.Sprintf("expected binding, got %T", obj))
+	}
+
+	// the virtual api server rejects targets that are no nodes
+	if binding.Target.Kind != "" && binding.Target.Kind != "Node" {
+		return nil
+	}
+
+	err = virtualClient.Get(ctx, types.NamespacedName{Name: binding.Target.Name}, &corev1.Node{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return kerrors.NewBadRequest(fmt.Sprintf("node %s does not exist in the virtual cluster", binding.Target.Name))
+		}
+
+		return kerrors.NewInternalError(err)
+	}
+
+	return nil
+}
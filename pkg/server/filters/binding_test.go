@@ -0,0 +1,96 @@
+package filters
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWithPodBinding(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		verb        string
+		resource    string
+		subresource string
+		body        string
+
+		expectedStatus int
+	}{
+		{
+			name:           "bind to virtual node",
+			verb:           "create",
+			resource:       "pods",
+			subresource:    "binding",
+			body:           `{"apiVersion":"v1","kind":"Binding","metadata":{"name":"test"},"target":{"kind":"Node","name":"node-1"}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bind to missing node",
+			verb:           "create",
+			resource:       "pods",
+			subresource:    "binding",
+			body:           `{"apiVersion":"v1","kind":"Binding","metadata":{"name":"test"},"target":{"kind":"Node","name":"node-2"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "deprecated bindings resource",
+			verb:           "create",
+			resource:       "bindings",
+			body:           `{"metadata":{"name":"test"},"target":{"name":"node-2"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			verb:           "create",
+			resource:       "pods",
+			subresource:    "binding",
+			body:           `{"target":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "other subresource",
+			verb:           "create",
+			resource:       "pods",
+			subresource:    "eviction",
+			body:           `{"metadata":{"name":"test"}}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	virtualClient := fake.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}).Build()
+	for _, testCase := range testCases {
+		var received string
+		h := WithPodBinding(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, err := io.ReadAll(req.Body)
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+			received = string(body)
+		}), virtualClient)
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testCase.body))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+			IsResourceRequest: true,
+			Verb:              testCase.verb,
+			APIVersion:        "v1",
+			Namespace:         "default",
+			Resource:          testCase.resource,
+			Subresource:       testCase.subresource,
+		})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req.WithContext(ctx))
+
+		assert.Equal(t, recorder.Code, testCase.expectedStatus, "unexpected status in test case %s", testCase.name)
+		if testCase.expectedStatus == http.StatusOK {
+			assert.Equal(t, received, testCase.body, "unexpected body in test case %s", testCase.name)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/server/handler"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	custommetricsv1beta2 "k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// customMetricsObject is a resource of the custom metrics api that can be proxied
+type customMetricsObject struct {
+	kind    string
+	newList func() client.ObjectList
+}
+
+// customMetricsObjects are the resources of the custom metrics api that are proxied, as they are
+// synced to the host cluster with translated names
+var customMetricsObjects = map[string]customMetricsObject{
+	PodResource: {
+		kind:    "Pod",
+		newList: func() client.ObjectList { return &corev1.PodList{} },
+	},
+	"services": {
+		kind:    "Service",
+		newList: func() client.ObjectList { return &corev1.ServiceList{} },
+	},
+	"persistentvolumeclaims": {
+		kind:    "PersistentVolumeClaim",
+		newList: func() client.ObjectList { return &corev1.PersistentVolumeClaimList{} },
+	},
+	"ingresses.networking.k8s.io": {
+		kind:    "Ingress",
+		newList: func() client.ObjectList { return &networkingv1.IngressList{} },
+	},
+}
+
+// WithCustomMetricsServerProxy proxies metrics of the host custom metrics api into the virtual
+// cluster. Only pods, services, persistent volume claims and ingresses are supported, as other
+// objects are either not synced or not distinguishable in the host cluster.
+func WithCustomMetricsServerProxy(h http.Handler, cachedVirtualClient client.Client, hostConfig *rest.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		if isCustomMetricsAPIResourceListRequest(info) {
+			proxyHandler, err := handler.Handler("", hostConfig, nil)
+			if err != nil {
+				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+				return
+			}
+
+			req.Header.Del("Authorization")
+			apiResourceListProxy := &APIResourceListProxy{
+				codecFactory:   serializer.NewCodecFactory(cachedVirtualClient.Scheme()),
+				handler:        proxyHandler,
+				request:        req,
+				requestInfo:    info,
+				responseWriter: w,
+			}
+			apiResourceListProxy.HandleRequest()
+			return
+		}
+
+		if !isCustomMetricsRequest(info) {
+			h.ServeHTTP(w, req)
+			return
+		}
+		object, ok := customMetricsObjects[info.Resource]
+		if !ok || info.Namespace == "" {
+			requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("custom metrics of %s are not available in the virtual cluster", info.Resource))
+			return
+		}
+
+		// the path looks like /apis/custom.metrics.k8s.io/v1beta2/namespaces/{namespace}/{resource}/{name}/{metric}
+		splitted := strings.Split(req.URL.Path, "/")
+		if len(splitted) < 9 {
+			requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("unexpected custom metrics path %s", req.URL.Path))
+			return
+		}
+		splitted[5] = translate.Default.PhysicalNamespace(info.Namespace)
+		if info.Name != custommetricsv1beta2.AllObjects {
+			splitted[7] = translate.Default.PhysicalName(info.Name, info.Namespace)
+		}
+		req.URL.Path = strings.Join(splitted, "/")
+
+		err := translateLabelSelectors(req)
+		if err != nil {
+			klog.Infof("error translating label selectors %v", err)
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		vObjs, err := getVirtualObjectsInNamespace(req.Context(), cachedVirtualClient, info.Namespace, object.newList())
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		proxyHandler, err := handler.Handler("", hostConfig, nil)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		// metric values are rewritten, so we always want json
+		req.Header.Del("Authorization")
+		req.Header.Set("Accept", "application/json")
+		code, header, data, err := executeRequest(req, proxyHandler)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, serializer.NewCodecFactory(cachedVirtualClient.Scheme()), corev1.SchemeGroupVersion, w, req)
+			return
+		} else if code != http.StatusOK {
+			writeWithHeader(w, code, header, data)
+			return
+		}
+
+		newData, err := rewriteCustomMetrics(data, object.kind, vObjs)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set(HeaderContentType, header.Get(HeaderContentType))
+		_, err = w.Write(newData)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+	})
+}
+
+// rewriteCustomMetrics translates the described objects back to the virtual objects and removes
+// metrics of objects that do not belong to the virtual cluster
+func rewriteCustomMetrics(data []byte, kind string, vObjs []client.Object) ([]byte, error) {
+	metricValueList := &custommetricsv1beta2.MetricValueList{}
+	err := json.Unmarshal(data, metricValueList)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualObjects := map[types.NamespacedName]client.Object{}
+	for _, vObj := range vObjs {
+		virtualObjects[types.NamespacedName{
+			Name:      translate.Default.PhysicalName(vObj.GetName(), vObj.GetNamespace()),
+			Namespace: translate.Default.PhysicalNamespace(vObj.GetNamespace()),
+		}] = vObj
+	}
+
+	items := []custommetricsv1beta2.MetricValue{}
+	for _, metricValue := range metricValueList.Items {
+		vObj, ok := virtualObjects[types.NamespacedName{
+			Name:      metricValue.DescribedObject.Name,
+			Namespace: metricValue.DescribedObject.Namespace,
+		}]
+		if !ok || metricValue.DescribedObject.Kind != kind {
+			continue
+		}
+
+		metricValue.DescribedObject.Name = vObj.GetName()
+		metricValue.DescribedObject.Namespace = vObj.GetNamespace()
+		metricValue.DescribedObject.UID = vObj.GetUID()
+		metricValue.DescribedObject.ResourceVersion = ""
+		items = append(items, metricValue)
+	}
+	metricValueList.Items = items
+
+	return json.Marshal(metricValueList)
+}
+
+func getVirtualObjectsInNamespace(ctx context.Context, vClient client.Client, namespace string, list client.ObjectList) ([]client.Object, error) {
+	err := vClient.List(ctx, list, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []client.Object{}
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+func isCustomMetricsAPIResourceListRequest(r *request.RequestInfo) bool {
+	return r.Path == "/apis/"+custommetricsv1beta2.SchemeGroupVersion.String()
+}
+
+func isCustomMetricsRequest(r *request.RequestInfo) bool {
+	return r.IsResourceRequest &&
+		r.APIGroup == custommetricsv1beta2.SchemeGroupVersion.Group &&
+		r.APIVersion == custommetricsv1beta2.SchemeGroupVersion.Version
+}
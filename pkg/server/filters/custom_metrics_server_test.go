@@ -0,0 +1,108 @@
+package filters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	custommetricsv1beta2 "k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRewriteCustomPodMetrics(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	vPods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nginx",
+				Namespace: "default",
+				UID:       "virtual-uid",
+			},
+		},
+	}
+	metricValue := func(name, namespace string) custommetricsv1beta2.MetricValue {
+		return custommetricsv1beta2.MetricValue{
+			DescribedObject: corev1.ObjectReference{
+				Kind:       "Pod",
+				APIVersion: "/v1",
+				Name:       name,
+				Namespace:  namespace,
+				UID:        "physical-uid",
+			},
+			Metric: custommetricsv1beta2.MetricIdentifier{
+				Name: "requests_per_second",
+			},
+			Value: resource.MustParse("10"),
+		}
+	}
+
+	data, err := json.Marshal(&custommetricsv1beta2.MetricValueList{
+		Items: []custommetricsv1beta2.MetricValue{
+			metricValue(translate.Default.PhysicalName("nginx", "default"), "test"),
+			metricValue("other-pod", "test"),
+		},
+	})
+	assert.NilError(t, err)
+
+	newData, err := rewriteCustomMetrics(data, "Pod", []client.Object{&vPods[0]})
+	assert.NilError(t, err)
+
+	metricValueList := &custommetricsv1beta2.MetricValueList{}
+	err = json.Unmarshal(newData, metricValueList)
+	assert.NilError(t, err)
+	assert.Equal(t, len(metricValueList.Items), 1)
+	assert.Equal(t, metricValueList.Items[0].DescribedObject.Name, "nginx")
+	assert.Equal(t, metricValueList.Items[0].DescribedObject.Namespace, "default")
+	assert.Equal(t, string(metricValueList.Items[0].DescribedObject.UID), "virtual-uid")
+	assert.Equal(t, metricValueList.Items[0].Value.String(), "10")
+}
+
+func TestRewriteCustomServiceMetrics(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	vService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nginx",
+			Namespace: "default",
+			UID:       "virtual-uid",
+		},
+	}
+	metricValue := func(kind string) custommetricsv1beta2.MetricValue {
+		return custommetricsv1beta2.MetricValue{
+			DescribedObject: corev1.ObjectReference{
+				Kind:       kind,
+				APIVersion: "/v1",
+				Name:       translate.Default.PhysicalName("nginx", "default"),
+				Namespace:  "test",
+			},
+			Metric: custommetricsv1beta2.MetricIdentifier{
+				Name: "requests_per_second",
+			},
+			Value: resource.MustParse("5"),
+		}
+	}
+
+	data, err := json.Marshal(&custommetricsv1beta2.MetricValueList{
+		Items: []custommetricsv1beta2.MetricValue{
+			metricValue("Service"),
+			metricValue("Pod"),
+		},
+	})
+	assert.NilError(t, err)
+
+	newData, err := rewriteCustomMetrics(data, "Service", []client.Object{vService})
+	assert.NilError(t, err)
+
+	metricValueList := &custommetricsv1beta2.MetricValueList{}
+	err = json.Unmarshal(newData, metricValueList)
+	assert.NilError(t, err)
+	assert.Equal(t, len(metricValueList.Items), 1)
+	assert.Equal(t, metricValueList.Items[0].DescribedObject.Kind, "Service")
+	assert.Equal(t, metricValueList.Items[0].DescribedObject.Name, "nginx")
+	assert.Equal(t, string(metricValueList.Items[0].DescribedObject.UID), "virtual-uid")
+}
@@ -0,0 +1,268 @@
+package filters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// discoveryCacheSettleTime is the time the virtual api server needs to pick up a changed api. Responses
+// that were fetched within this time after a change are served, but not cached.
+const discoveryCacheSettleTime = 5 * time.Second
+
+// discoveryCacheFetchTimeout is the timeout of a single fetch from the virtual cluster. The fetch is shared
+// by all waiting requests, so it doesn't use the context of the request that started it.
+const discoveryCacheFetchTimeout = time.Minute
+
+// maxDiscoveryCacheEntries bounds the cache, every accept header and openapi v3 group is a separate entry
+const maxDiscoveryCacheEntries = 1000
+
+// discoveryCacheHeaders are the response headers that are stored with a cached response
+var discoveryCacheHeaders = []string{"Content-Type", "Cache-Control", "ETag", "Last-Modified", "Vary"}
+
+// discoveryCacheInvalidators are the kinds that change the discovery and openapi documents of the virtual cluster
+var discoveryCacheInvalidators = []schema.GroupVersionKind{
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+	{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"},
+}
+
+// NonResourceDiscoveryPaths are the paths served from the discovery cache. As they are not proxied to the
+// virtual cluster anymore, they have to be authorized against the virtual cluster rbac.
+var NonResourceDiscoveryPaths = []string{"/api", "/api/*", "/apis", "/apis/*", "/openapi/*"}
+
+// WithDiscoveryCache serves the discovery and openapi documents of the virtual cluster from a cache, which
+// is invalidated as soon as a custom resource definition or api service changes in the virtual cluster.
+// With a lot of synced custom resource definitions, building these documents is expensive for the virtual
+// api server. OpenAPI v3 documents are fetched lazily per group version the first time they are requested.
+func WithDiscoveryCache(ctx context.Context, h http.Handler, virtualConfig *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper, ttl time.Duration) (http.Handler, error) {
+	httpClient, err := rest.HTTPClientFor(virtualConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryCache := newDiscoveryCache(httpClient, virtualConfig.Host, ttl)
+	err = discoveryCache.watchInvalidators(ctx, virtualConfig, scheme, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !isDiscoveryRequest(req) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		discoveryCache.ServeHTTP(w, req)
+	}), nil
+}
+
+func isDiscoveryRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	if path == "/api" || path == "/apis" || path == "/openapi/v2" || path == "/openapi/v3" || strings.HasPrefix(path, "/openapi/v3/") {
+		return true
+	} else if !strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/apis/") {
+		return false
+	}
+
+	// /api/v1, /apis/group and /apis/group/version are discovery requests, everything below is a resource
+	info, ok := request.RequestInfoFrom(req.Context())
+	return ok && !info.IsResourceRequest
+}
+
+type discoveryCache struct {
+	client *http.Client
+	host   string
+	ttl    time.Duration
+
+	entriesLock sync.Mutex
+	entries     map[string]*discoveryCacheEntry
+	lastChange  time.Time
+
+	requests singleflight.Group
+}
+
+type discoveryCacheEntry struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	fetched    time.Time
+}
+
+func newDiscoveryCache(client *http.Client, host string, ttl time.Duration) *discoveryCache {
+	return &discoveryCache{
+		client:  client,
+		host:    strings.TrimSuffix(host, "/"),
+		ttl:     ttl,
+		entries: map[string]*discoveryCacheEntry{},
+	}
+}
+
+func (c *discoveryCache) watchInvalidators(ctx context.Context, virtualConfig *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper) error {
+	// the proxy also runs on replicas that are not leading, so it cannot rely on the informers of the controllers
+	informers, err := cache.New(virtualConfig, cache.Options{
+		Scheme: scheme,
+		Mapper: mapper,
+	})
+	if err != nil {
+		return err
+	}
+
+	handler := toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.invalidate() },
+		UpdateFunc: func(oldObj, newObj interface{}) { c.invalidate() },
+		DeleteFunc: func(obj interface{}) { c.invalidate() },
+	}
+	for _, gvk := range discoveryCacheInvalidators {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		informer, err := informers.GetInformer(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("get %s informer: %w", gvk.Kind, err)
+		}
+
+		_, err = informer.AddEventHandler(handler)
+		if err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		err := informers.Start(ctx)
+		if err != nil {
+			klog.Errorf("error starting discovery cache informers: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (c *discoveryCache) invalidate() {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
+	c.entries = map[string]*discoveryCacheEntry{}
+	c.lastChange = time.Now()
+}
+
+func (c *discoveryCache) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// discovery documents are content negotiated, so the accept header is part of the key
+	key := req.URL.Path + "?" + req.URL.RawQuery + "#" + req.Header.Get("Accept")
+	entry := c.get(key)
+	if entry == nil {
+		resultChan := c.requests.DoChan(key, func() (interface{}, error) {
+			return c.fetch(req, key)
+		})
+
+		var result singleflight.Result
+		select {
+		case <-req.Context().Done():
+			return
+		case result = <-resultChan:
+		}
+		if result.Err != nil {
+			klog.Errorf("error fetching %s from the virtual cluster: %v", req.URL.Path, result.Err)
+			http.Error(w, result.Err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		entry = result.Val.(*discoveryCacheEntry)
+	}
+
+	for k, v := range entry.header {
+		w.Header()[k] = v
+	}
+	if etag := entry.header.Get("ETag"); etag != "" && req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(entry.statusCode)
+	_, _ = w.Write(entry.body)
+}
+
+func (c *discoveryCache) get(key string) *discoveryCacheEntry {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.fetched) > c.ttl {
+		return nil
+	}
+
+	return entry
+}
+
+func (c *discoveryCache) fetch(req *http.Request, key string) (*discoveryCacheEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryCacheFetchTimeout)
+	defer cancel()
+
+	fetchReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.host+req.URL.RequestURI(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept := req.Header.Get("Accept"); accept != "" {
+		fetchReq.Header.Set("Accept", accept)
+	}
+
+	fetched := time.Now()
+	resp, err := c.client.Do(fetchReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &discoveryCacheEntry{
+		statusCode: resp.StatusCode,
+		header:     http.Header{},
+		body:       body,
+		fetched:    fetched,
+	}
+	for _, header := range discoveryCacheHeaders {
+		if values := resp.Header.Values(header); len(values) > 0 {
+			entry.header[http.CanonicalHeaderKey(header)] = values
+		}
+	}
+	if resp.StatusCode == http.StatusOK {
+		c.store(key, entry)
+	}
+
+	return entry, nil
+}
+
+func (c *discoveryCache) store(key string, entry *discoveryCacheEntry) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
+	// the virtual api server might not have picked up the latest change yet
+	if entry.fetched.Sub(c.lastChange) < discoveryCacheSettleTime {
+		return
+	} else if len(c.entries) >= maxDiscoveryCacheEntries {
+		c.entries = map[string]*discoveryCacheEntry{}
+	}
+
+	c.entries[key] = entry
+}
@@ -0,0 +1,148 @@
+package filters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestDiscoveryCache(t *testing.T) {
+	fetches := map[string]int{}
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetches[req.URL.RequestURI()]++
+		if req.URL.Path == "/apis/missing.example.com" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", req.Header.Get("Accept"))
+		w.Header().Set("ETag", `"`+req.URL.Path+`"`)
+		_, _ = w.Write([]byte(req.URL.Path))
+	}))
+	defer backend.Close()
+
+	discoveryCache := newDiscoveryCache(backend.Client(), backend.URL, time.Minute)
+	serve := func(uri, accept, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, uri, nil)
+		req.Header.Set("Accept", accept)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		w := httptest.NewRecorder()
+		discoveryCache.ServeHTTP(w, req)
+		return w
+	}
+
+	// responses are cached per accept header
+	for i := 0; i < 3; i++ {
+		w := serve("/apis", "application/json", "")
+		assert.Equal(t, w.Code, http.StatusOK)
+		assert.Equal(t, w.Body.String(), "/apis")
+		assert.Equal(t, w.Header().Get("Content-Type"), "application/json")
+		serve("/apis", "application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList", "")
+	}
+	assert.Equal(t, fetches["/apis"], 2)
+
+	// openapi v3 documents are fetched lazily per group version
+	serve("/openapi/v3/apis/apps/v1?hash=1", "application/json", "")
+	serve("/openapi/v3/apis/apps/v1?hash=1", "application/json", "")
+	assert.Equal(t, fetches["/openapi/v3/apis/apps/v1?hash=1"], 1)
+	assert.Equal(t, fetches["/openapi/v3/apis/batch/v1?hash=1"], 0)
+
+	// matching etags are not sent again
+	w := serve("/apis", "application/json", `"/apis"`)
+	assert.Equal(t, w.Code, http.StatusNotModified)
+	assert.Equal(t, w.Body.Len(), 0)
+
+	// failed requests are not cached
+	assert.Equal(t, serve("/apis/missing.example.com", "application/json", "").Code, http.StatusNotFound)
+	assert.Equal(t, serve("/apis/missing.example.com", "application/json", "").Code, http.StatusNotFound)
+	assert.Equal(t, fetches["/apis/missing.example.com"], 2)
+
+	// a changed api invalidates the cache and nothing is cached until the api server settled
+	discoveryCache.invalidate()
+	serve("/apis", "application/json", "")
+	serve("/apis", "application/json", "")
+	assert.Equal(t, fetches["/apis"], 4)
+
+	discoveryCache.lastChange = time.Now().Add(-discoveryCacheSettleTime)
+	serve("/apis", "application/json", "")
+	serve("/apis", "application/json", "")
+	assert.Equal(t, fetches["/apis"], 5)
+
+	// expired entries are fetched again
+	discoveryCache.ttl = 0
+	serve("/apis", "application/json", "")
+	assert.Equal(t, fetches["/apis"], 6)
+}
+
+func TestDiscoveryCacheCancelled(t *testing.T) {
+	release := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(req.URL.Path))
+	}))
+	defer backend.Close()
+
+	// the first caller cancels while the fetch is running, the waiting caller still gets the response
+	discoveryCache := newDiscoveryCache(backend.Client(), backend.URL, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan struct{})
+	go func() {
+		defer close(cancelled)
+		discoveryCache.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/apis", nil).WithContext(ctx))
+	}()
+
+	waiting := make(chan *httptest.ResponseRecorder)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		w := httptest.NewRecorder()
+		discoveryCache.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/apis", nil))
+		waiting <- w
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-cancelled
+	close(release)
+
+	w := <-waiting
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), "/apis")
+}
+
+func TestIsDiscoveryRequest(t *testing.T) {
+	testCases := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{method: http.MethodGet, path: "/api", expected: true},
+		{method: http.MethodGet, path: "/api/v1", expected: true},
+		{method: http.MethodGet, path: "/apis/", expected: true},
+		{method: http.MethodGet, path: "/apis/apps", expected: true},
+		{method: http.MethodGet, path: "/apis/apps/v1", expected: true},
+		{method: http.MethodGet, path: "/openapi/v2", expected: true},
+		{method: http.MethodGet, path: "/openapi/v3/apis/apps/v1", expected: true},
+		{method: http.MethodGet, path: "/api/v1/pods", expected: false},
+		{method: http.MethodGet, path: "/apis/apps/v1/namespaces/default/deployments", expected: false},
+		{method: http.MethodGet, path: "/healthz", expected: false},
+		{method: http.MethodPost, path: "/apis", expected: false},
+	}
+
+	resolver := &request.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"), GrouplessAPIPrefixes: sets.NewString("api")}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, testCase.path, nil)
+		info, err := resolver.NewRequestInfo(req)
+		assert.NilError(t, err)
+		req = req.WithContext(request.WithRequestInfo(req.Context(), info))
+		assert.Equal(t, isDiscoveryRequest(req), testCase.expected, "unexpected result for %s %s", testCase.method, testCase.path)
+	}
+}
@@ -0,0 +1,340 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AllowExecAnnotation on a virtual pod allows exec and attach into its containers if exec
+	// requires an annotation
+	AllowExecAnnotation = "vcluster.loft.sh/allow-exec"
+
+	// AllowExecVerb is the rbac verb on pods a user needs to set the AllowExecAnnotation
+	AllowExecVerb = "allow-exec"
+)
+
+// execTarget is the pod and container of an exec, attach or run request
+type execTarget struct {
+	subresource string
+
+	namespace string
+	name      string
+
+	physicalNamespace string
+	physicalName      string
+
+	container string
+	command   []string
+
+	pod *corev1.Pod
+}
+
+// WithExecAudit logs every exec and attach request that is proxied to a physical pod with the
+// virtual user, the target pod and container and the command, so host side compliance tooling
+// can audit them. This includes requests through the node proxy and the fake kubelet. If
+// requireAnnotation is true, exec and attach are only allowed into virtual pods with the
+// AllowExecAnnotation set to true and only users allowed to use the AllowExecVerb on pods
+// may set the annotation through the syncer.
+func WithExecAudit(h http.Handler, cachedVirtualClient, uncachedVirtualClient client.Client, audit, requireAnnotation bool) http.Handler {
+	s := serializer.NewCodecFactory(cachedVirtualClient.Scheme())
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		if requireAnnotation {
+			allowed, err := authorizeAllowExecAnnotation(req, info, uncachedVirtualClient)
+			if err != nil {
+				responsewriters.ErrorNegotiated(kerrors.NewInternalError(err), s, corev1.SchemeGroupVersion, w, req)
+				return
+			} else if !allowed {
+				err = kerrors.NewForbidden(corev1.Resource("pods"), info.Name, fmt.Errorf("setting the %s annotation requires the %s verb on pods", AllowExecAnnotation, AllowExecVerb))
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+		}
+
+		target, err := execTargetFrom(req, info, cachedVirtualClient)
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				err = kerrors.NewInternalError(err)
+			}
+
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		} else if target == nil {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		allowed := !requireAnnotation || target.pod.Annotations[AllowExecAnnotation] == "true"
+		if audit || !allowed {
+			userName, groups := "", []string{}
+			if userInfo, ok := request.UserFrom(req.Context()); ok {
+				userName, groups = userInfo.GetName(), userInfo.GetGroups()
+			}
+
+			klog.InfoS("Audit "+target.subresource,
+				"user", userName,
+				"groups", groups,
+				"namespace", target.namespace,
+				"pod", target.name,
+				"physicalNamespace", target.physicalNamespace,
+				"physicalPod", target.physicalName,
+				"container", target.container,
+				"command", target.command,
+				"allowed", allowed,
+			)
+		}
+		if !allowed {
+			err = kerrors.NewForbidden(corev1.Resource("pods/"+target.subresource), target.name, fmt.Errorf("pod is missing the %s: \"true\" annotation", AllowExecAnnotation))
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+// podTemplateResource is a resource that contains a pod or a pod template
+type podTemplateResource struct {
+	kind string
+
+	// annotations is the path to the annotations of the pod or pod template
+	annotations []string
+}
+
+// podTemplateResources are the resources whose writes may set the AllowExecAnnotation on pods,
+// either directly or through the pod templates that workload controllers copy
+var podTemplateResources = map[schema.GroupResource]podTemplateResource{
+	{Resource: "pods"}:                        {kind: "Pod", annotations: []string{"metadata", "annotations"}},
+	{Resource: "podtemplates"}:                {kind: "PodTemplate", annotations: []string{"template", "metadata", "annotations"}},
+	{Resource: "replicationcontrollers"}:      {kind: "ReplicationController", annotations: []string{"spec", "template", "metadata", "annotations"}},
+	{Group: "apps", Resource: "deployments"}:  {kind: "Deployment", annotations: []string{"spec", "template", "metadata", "annotations"}},
+	{Group: "apps", Resource: "replicasets"}:  {kind: "ReplicaSet", annotations: []string{"spec", "template", "metadata", "annotations"}},
+	{Group: "apps", Resource: "statefulsets"}: {kind: "StatefulSet", annotations: []string{"spec", "template", "metadata", "annotations"}},
+	{Group: "apps", Resource: "daemonsets"}:   {kind: "DaemonSet", annotations: []string{"spec", "template", "metadata", "annotations"}},
+	{Group: "batch", Resource: "jobs"}:        {kind: "Job", annotations: []string{"spec", "template", "metadata", "annotations"}},
+	{Group: "batch", Resource: "cronjobs"}:    {kind: "CronJob", annotations: []string{"spec", "jobTemplate", "spec", "template", "metadata", "annotations"}},
+}
+
+// authorizeAllowExecAnnotation checks if the user may write the AllowExecAnnotation. Only writes of pods and
+// pod templates that add or change the annotation compared to the stored object are checked.
+func authorizeAllowExecAnnotation(req *http.Request, info *request.RequestInfo, uncachedVirtualClient client.Client) (bool, error) {
+	if !info.IsResourceRequest || (info.Verb != "create" && info.Verb != "update" && info.Verb != "patch") || req.Body == nil {
+		return true, nil
+	}
+	resource, ok := podTemplateResources[schema.GroupResource{Group: info.APIGroup, Resource: info.Resource}]
+	if !ok {
+		return true, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return false, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	changed, err := allowExecAnnotationChanged(req, info, resource, body, uncachedVirtualClient)
+	if err != nil {
+		return false, err
+	} else if !changed {
+		return true, nil
+	}
+
+	userInfo, ok := request.UserFrom(req.Context())
+	if !ok {
+		return false, nil
+	}
+
+	accessReview := &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:   userInfo.GetName(),
+			UID:    userInfo.GetUID(),
+			Groups: userInfo.GetGroups(),
+			Extra:  clienthelper.ConvertExtra(userInfo.GetExtra()),
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace: info.Namespace,
+				Verb:      AllowExecVerb,
+				Resource:  "pods",
+			},
+		},
+	}
+	err = uncachedVirtualClient.Create(req.Context(), accessReview)
+	if err != nil {
+		return false, err
+	}
+
+	return accessReview.Status.Allowed && !accessReview.Status.Denied, nil
+}
+
+// allowExecAnnotationChanged checks if the write adds the AllowExecAnnotation or changes its value compared to
+// the stored object. Merge patches set the annotation to the value in the patch, json patches are applied to the
+// stored object. Bodies that are neither json nor yaml, e.g. protobuf, count as a change if they contain the annotation.
+func allowExecAnnotationChanged(req *http.Request, info *request.RequestInfo, resource podTemplateResource, body []byte, uncachedVirtualClient client.Client) (bool, error) {
+	path := append(append([]string{}, resource.annotations...), AllowExecAnnotation)
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if contentType == string(types.ApplyPatchType) {
+		var err error
+		body, err = yaml.YAMLToJSON(body)
+		if err != nil {
+			return false, err
+		}
+	} else if !json.Valid(body) {
+		return bytes.Contains(body, []byte(AllowExecAnnotation)), nil
+	}
+
+	var stored map[string]interface{}
+	getStored := func() (map[string]interface{}, error) {
+		if stored != nil || info.Verb == "create" {
+			return stored, nil
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: info.APIGroup, Version: info.APIVersion, Kind: resource.kind})
+		err := uncachedVirtualClient.Get(req.Context(), types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, obj)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		stored = obj.Object
+		return stored, nil
+	}
+
+	if contentType == string(types.JSONPatchType) {
+		original, err := getStored()
+		if err != nil || original == nil {
+			return false, err
+		}
+		rawOriginal, err := json.Marshal(original)
+		if err != nil {
+			return false, err
+		}
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return false, err
+		}
+		body, err = patch.Apply(rawOriginal)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	written := map[string]interface{}{}
+	err := json.Unmarshal(body, &written)
+	if err != nil {
+		return false, err
+	}
+	value, found, _ := unstructured.NestedString(written, path...)
+	if !found {
+		return false, nil
+	}
+
+	original, err := getStored()
+	if err != nil {
+		return false, err
+	}
+	storedValue, storedFound, _ := unstructured.NestedString(original, path...)
+	return !storedFound || value != storedValue, nil
+}
+
+// execTargetFrom returns the target of an exec, attach or run request through the pods api, the
+// node proxy or the fake kubelet and nil for all other requests
+func execTargetFrom(req *http.Request, info *request.RequestInfo, cachedVirtualClient client.Client) (*execTarget, error) {
+	if isPodExec(info) {
+		// the scheme has no conversions for the exec options, so the query is read directly
+		target := &execTarget{
+			subresource:       info.Subresource,
+			namespace:         info.Namespace,
+			name:              info.Name,
+			physicalNamespace: translate.Default.PhysicalNamespace(info.Namespace),
+			physicalName:      translate.Default.PhysicalName(info.Name, info.Namespace),
+			container:         req.URL.Query().Get("container"),
+			command:           req.URL.Query()["command"],
+			pod:               &corev1.Pod{},
+		}
+		err := cachedVirtualClient.Get(req.Context(), types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, target.pod)
+		if err != nil {
+			return nil, err
+		}
+		if target.container == "" {
+			target.container = defaultContainer(target.pod)
+		}
+
+		return target, nil
+	}
+
+	// kubelet paths are /exec/{namespace}/{pod}/[{uid}/]{container} with the physical pod
+	kubeletPath := ""
+	if _, ok := NodeNameFrom(req.Context()); ok {
+		kubeletPath = req.URL.Path
+	} else if isNodesProxy(info) {
+		splitted := strings.SplitN(req.URL.Path, "/", 7)
+		if len(splitted) == 7 {
+			kubeletPath = splitted[6]
+		}
+	}
+	splitted := strings.Split(strings.Trim(kubeletPath, "/"), "/")
+	if len(splitted) < 4 || (splitted[0] != "exec" && splitted[0] != "attach" && splitted[0] != "run") {
+		return nil, nil
+	}
+
+	target := &execTarget{
+		subresource:       splitted[0],
+		physicalNamespace: splitted[1],
+		physicalName:      splitted[2],
+		container:         splitted[len(splitted)-1],
+		command:           req.URL.Query()["command"],
+		pod:               &corev1.Pod{},
+	}
+	if cmd := req.URL.Query().Get("cmd"); cmd != "" {
+		target.command = strings.Fields(cmd)
+	}
+	err := clienthelper.GetByIndex(req.Context(), cachedVirtualClient, target.pod, constants.IndexByPhysicalName, target.physicalNamespace+"/"+target.physicalName)
+	if err != nil {
+		return nil, err
+	}
+
+	target.namespace = target.pod.Namespace
+	target.name = target.pod.Name
+	return target, nil
+}
+
+func isPodExec(info *request.RequestInfo) bool {
+	return info.IsResourceRequest && info.APIGroup == "" && info.Resource == "pods" && (info.Subresource == "exec" || info.Subresource == "attach")
+}
+
+// defaultContainer returns the container the api server picks if the request doesn't specify one
+func defaultContainer(pod *corev1.Pod) string {
+	if len(pod.Spec.Containers) == 1 {
+		return pod.Spec.Containers[0].Name
+	}
+
+	return ""
+}
@@ -0,0 +1,334 @@
+package filters
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestWithExecAudit(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	testCases := []struct {
+		name string
+
+		pod               string
+		subresource       string
+		query             string
+		requireAnnotation bool
+
+		expectedStatus int
+	}{
+		{
+			name:           "exec",
+			pod:            "locked",
+			subresource:    "exec",
+			query:          "?command=sh&container=nginx&stdin=true",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:              "exec into annotated pod",
+			pod:               "allowed",
+			subresource:       "exec",
+			query:             "?command=sh",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:              "exec into pod without annotation",
+			pod:               "locked",
+			subresource:       "exec",
+			query:             "?command=sh",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusForbidden,
+		},
+		{
+			name:              "attach to pod without annotation",
+			pod:               "locked",
+			subresource:       "attach",
+			query:             "?stdin=true",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusForbidden,
+		},
+		{
+			name:              "logs of pod without annotation",
+			pod:               "locked",
+			subresource:       "log",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:           "exec into missing pod",
+			pod:            "missing",
+			subresource:    "exec",
+			query:          "?command=sh",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	virtualClient := newExecAuditVirtualClient()
+	for _, testCase := range testCases {
+		h := WithExecAudit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), virtualClient, virtualClient, true, testCase.requireAnnotation)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/pods/"+testCase.pod+"/"+testCase.subresource+testCase.query, nil)
+		ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+			IsResourceRequest: true,
+			Verb:              "create",
+			APIVersion:        "v1",
+			Namespace:         "default",
+			Resource:          "pods",
+			Subresource:       testCase.subresource,
+			Name:              testCase.pod,
+		})
+		ctx = request.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req.WithContext(ctx))
+
+		assert.Equal(t, recorder.Code, testCase.expectedStatus, "unexpected status in test case %s", testCase.name)
+	}
+}
+
+func newExecAuditVirtualClient() client.Client {
+	return fake.NewClientBuilder().WithObjects(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: "default", Annotations: map[string]string{AllowExecAnnotation: "true"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx"}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "locked", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx"}}},
+		},
+	).WithIndex(&corev1.Pod{}, constants.IndexByPhysicalName, func(obj client.Object) []string {
+		return []string{translate.Default.PhysicalNamespace(obj.GetNamespace()) + "/" + translate.Default.PhysicalName(obj.GetName(), obj.GetNamespace())}
+	}).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			accessReview, ok := obj.(*authv1.SubjectAccessReview)
+			if !ok {
+				return c.Create(ctx, obj, opts...)
+			}
+
+			accessReview.Status.Allowed = accessReview.Spec.User == "admin" && accessReview.Spec.ResourceAttributes.Verb == AllowExecVerb
+			return nil
+		},
+	}).Build()
+}
+
+func TestWithExecAuditKubelet(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	testCases := []struct {
+		name string
+
+		path              string
+		nodeName          string
+		requireAnnotation bool
+
+		expectedStatus int
+	}{
+		{
+			name:              "node proxy exec into annotated pod",
+			path:              "/api/v1/nodes/node1/proxy/exec/test/" + translate.Default.PhysicalName("allowed", "default") + "/nginx?command=sh",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:              "node proxy exec into pod without annotation",
+			path:              "/api/v1/nodes/node1/proxy/exec/test/" + translate.Default.PhysicalName("locked", "default") + "/nginx?command=sh",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusForbidden,
+		},
+		{
+			name:           "node proxy exec into host pod",
+			path:           "/api/v1/nodes/node1/proxy/exec/kube-system/coredns/coredns?command=sh",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "node proxy logs",
+			path:           "/api/v1/nodes/node1/proxy/containerLogs/kube-system/coredns/coredns",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:              "fake kubelet run in pod without annotation",
+			path:              "/run/test/" + translate.Default.PhysicalName("locked", "default") + "/uid/nginx?cmd=ls+-la",
+			nodeName:          "node1",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusForbidden,
+		},
+		{
+			name:              "fake kubelet attach to annotated pod",
+			path:              "/attach/test/" + translate.Default.PhysicalName("allowed", "default") + "/nginx",
+			nodeName:          "node1",
+			requireAnnotation: true,
+			expectedStatus:    http.StatusOK,
+		},
+	}
+
+	virtualClient := newExecAuditVirtualClient()
+	for _, testCase := range testCases {
+		h := WithExecAudit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), virtualClient, virtualClient, true, testCase.requireAnnotation)
+
+		req := httptest.NewRequest(http.MethodPost, testCase.path, nil)
+		info := &request.RequestInfo{IsResourceRequest: true, Verb: "create", APIVersion: "v1", Resource: "nodes", Subresource: "proxy", Name: "node1"}
+		ctx := req.Context()
+		if testCase.nodeName != "" {
+			info = &request.RequestInfo{Verb: "post", Path: req.URL.Path}
+			ctx = context.WithValue(ctx, nodeNameKey, testCase.nodeName)
+		}
+		ctx = request.WithRequestInfo(ctx, info)
+		ctx = request.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req.WithContext(ctx))
+
+		assert.Equal(t, recorder.Code, testCase.expectedStatus, "unexpected status in test case %s", testCase.name)
+	}
+}
+
+func TestWithExecAuditAnnotation(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	testCases := []struct {
+		name string
+
+		user        string
+		resource    string
+		apiGroup    string
+		verb        string
+		objectName  string
+		contentType string
+		body        string
+
+		expectedStatus int
+	}{
+		{
+			name:           "create pod with annotation",
+			user:           "alice",
+			resource:       "pods",
+			verb:           "create",
+			body:           `{"metadata":{"name":"nginx","annotations":{"vcluster.loft.sh/allow-exec":"true"}}}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "patch deployment template with annotation",
+			user:           "alice",
+			resource:       "deployments",
+			apiGroup:       "apps",
+			verb:           "patch",
+			objectName:     "nginx",
+			body:           `{"spec":{"template":{"metadata":{"annotations":{"vcluster.loft.sh/allow-exec":"true"}}}}}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "create pod with annotation with allow-exec verb",
+			user:           "admin",
+			resource:       "pods",
+			verb:           "create",
+			body:           `{"metadata":{"name":"nginx","annotations":{"vcluster.loft.sh/allow-exec":"true"}}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "create pod without annotation",
+			user:           "alice",
+			resource:       "pods",
+			verb:           "create",
+			body:           `{"metadata":{"name":"nginx"}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "update pod with unchanged annotation",
+			user:           "alice",
+			resource:       "pods",
+			verb:           "update",
+			objectName:     "allowed",
+			body:           `{"metadata":{"name":"allowed","labels":{"app":"nginx"},"annotations":{"vcluster.loft.sh/allow-exec":"true"}}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "patch pod without changing annotation",
+			user:           "alice",
+			resource:       "pods",
+			verb:           "patch",
+			objectName:     "allowed",
+			contentType:    string(types.MergePatchType),
+			body:           `{"metadata":{"labels":{"app":"nginx"}}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "patch pod removing annotation",
+			user:           "alice",
+			resource:       "pods",
+			verb:           "patch",
+			objectName:     "allowed",
+			contentType:    string(types.StrategicMergePatchType),
+			body:           `{"metadata":{"annotations":{"vcluster.loft.sh/allow-exec":null}}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "json patch pod adding annotation",
+			user:           "alice",
+			resource:       "pods",
+			verb:           "patch",
+			objectName:     "locked",
+			contentType:    string(types.JSONPatchType),
+			body:           `[{"op":"add","path":"/metadata/annotations","value":{"vcluster.loft.sh\/allow-exec":"true"}}]`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "apply pod changing annotation",
+			user:           "alice",
+			resource:       "pods",
+			verb:           "patch",
+			objectName:     "allowed",
+			contentType:    string(types.ApplyPatchType),
+			body:           "metadata:\n  annotations:\n    vcluster.loft.sh/allow-exec: \"yes\"\n",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "create configmap with annotation",
+			user:           "alice",
+			resource:       "configmaps",
+			verb:           "create",
+			body:           `{"metadata":{"name":"nginx","annotations":{"vcluster.loft.sh/allow-exec":"true"}}}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	virtualClient := newExecAuditVirtualClient()
+	for _, testCase := range testCases {
+		h := WithExecAudit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			assert.Equal(t, string(body), testCase.body, "unexpected body in test case %s", testCase.name)
+		}), virtualClient, virtualClient, false, true)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/"+testCase.resource, strings.NewReader(testCase.body))
+		if testCase.contentType != "" {
+			req.Header.Set("Content-Type", testCase.contentType)
+		}
+		ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+			IsResourceRequest: true,
+			Verb:              testCase.verb,
+			APIGroup:          testCase.apiGroup,
+			APIVersion:        "v1",
+			Namespace:         "default",
+			Resource:          testCase.resource,
+			Name:              testCase.objectName,
+		})
+		ctx = request.WithUser(ctx, &user.DefaultInfo{Name: testCase.user})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req.WithContext(ctx))
+
+		assert.Equal(t, recorder.Code, testCase.expectedStatus, "unexpected status in test case %s", testCase.name)
+	}
+}
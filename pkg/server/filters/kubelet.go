@@ -4,12 +4,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
-	"k8s.io/client-go/rest"
 	"net/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func WithFakeKubelet(h http.Handler, localConfig *rest.Config, cachedVirtualClient client.Client) http.Handler {
+func WithFakeKubelet(h http.Handler, proxyHandler http.Handler, cachedVirtualClient client.Client) http.Handler {
 	s := serializer.NewCodecFactory(cachedVirtualClient.Scheme())
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		nodeName, found := NodeNameFrom(req.Context())
@@ -23,7 +22,7 @@ func WithFakeKubelet(h http.Handler, localConfig *rest.Config, cachedVirtualClie
 			req.URL.Path = "/api/v1/nodes/" + nodeName + "/proxy" + req.URL.Path
 
 			// execute the request
-			_, err := handleNodeRequest(localConfig, cachedVirtualClient, w, req)
+			_, err := handleNodeRequest(proxyHandler, cachedVirtualClient, w, req)
 			if err != nil {
 				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
 				return
@@ -13,7 +13,6 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/metrics"
-	"github.com/loft-sh/vcluster/pkg/server/handler"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
 	"github.com/prometheus/common/expfmt"
@@ -22,7 +21,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	"k8s.io/apiserver/pkg/endpoints/request"
-	"k8s.io/client-go/rest"
 	statsv1alpha1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -31,7 +29,7 @@ const (
 	KubectlCommandHeader = "Kubectl-Command"
 )
 
-func WithMetricsProxy(h http.Handler, localConfig *rest.Config, cachedVirtualClient client.Client) http.Handler {
+func WithMetricsProxy(h http.Handler, proxyHandler http.Handler, cachedVirtualClient client.Client) http.Handler {
 	s := serializer.NewCodecFactory(cachedVirtualClient.Scheme())
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		info, ok := request.RequestInfoFrom(req.Context())
@@ -72,7 +70,7 @@ func WithMetricsProxy(h http.Handler, localConfig *rest.Config, cachedVirtualCli
 			req.URL.Path = strings.Join(splitted, "/")
 
 			// execute the request
-			_, err := handleNodeRequest(localConfig, cachedVirtualClient, w, req)
+			_, err := handleNodeRequest(proxyHandler, cachedVirtualClient, w, req)
 			if err != nil {
 				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
 				return
@@ -113,15 +111,18 @@ func rewritePrometheusMetrics(req *http.Request, data []byte, vClient client.Cli
 	return metrics.Encode(metricsFamilies, expfmt.Negotiate(req.Header))
 }
 
-func handleNodeRequest(localConfig *rest.Config, vClient client.Client, w http.ResponseWriter, req *http.Request) (bool, error) {
+func handleNodeRequest(proxyHandler http.Handler, vClient client.Client, w http.ResponseWriter, req *http.Request) (bool, error) {
 	// authorization was done here already so we will just go forward with the rewrite
 	req.Header.Del("Authorization")
-	h, err := handler.Handler("", localConfig, nil)
-	if err != nil {
-		return false, err
+
+	// only metrics and stats have to be rewritten, everything else such as logs, exec
+	// or port-forward through the kubelet api is streamed directly
+	if !IsKubeletMetrics(req.URL.Path) && !IsKubeletStats(req.URL.Path) {
+		proxyHandler.ServeHTTP(w, req)
+		return true, nil
 	}
 
-	code, header, data, err := executeRequest(req, h)
+	code, header, data, err := executeRequest(req, proxyHandler)
 	if err != nil {
 		return false, err
 	} else if code != http.StatusOK {
@@ -131,19 +132,24 @@ func handleNodeRequest(localConfig *rest.Config, vClient client.Client, w http.R
 
 	// now rewrite the metrics
 	newData := data
+	contentType := header.Get("Content-Type")
 	if IsKubeletMetrics(req.URL.Path) {
 		newData, err = rewritePrometheusMetrics(req, data, vClient)
 		if err != nil {
 			return false, err
 		}
+		contentType = string(expfmt.Negotiate(req.Header))
 	} else if IsKubeletStats(req.URL.Path) {
 		newData, err = rewriteStats(req.Context(), data, vClient)
 		if err != nil {
 			return false, err
 		}
+		contentType = "application/json"
 	}
 
-	w.Header().Set("Content-Type", string(expfmt.Negotiate(req.Header)))
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
 	w.WriteHeader(code)
 	_, _ = w.Write(newData)
 	return true, nil
@@ -181,11 +187,15 @@ func rewriteStats(ctx context.Context, data []byte, vClient client.Client) ([]by
 			if volume.PVCRef != nil {
 				vPVC := &corev1.PersistentVolumeClaim{}
 				err = clienthelper.GetByIndex(ctx, vClient, vPVC, constants.IndexByPhysicalName, volume.PVCRef.Namespace+"/"+volume.PVCRef.Name)
-				if err != nil {
+				if kerrors.IsNotFound(err) {
+					// the claim might have been deleted in the meantime, so don't expose the host claim
+					volume.PVCRef = nil
+				} else if err != nil {
 					return nil, err
+				} else {
+					volume.PVCRef.Name = vPVC.Name
+					volume.PVCRef.Namespace = vPVC.Namespace
 				}
-				volume.PVCRef.Name = vPVC.Name
-				volume.PVCRef.Namespace = vPVC.Namespace
 			}
 
 			newVolumes = append(newVolumes, volume)
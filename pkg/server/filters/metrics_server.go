@@ -12,6 +12,7 @@ import (
 	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -54,7 +55,15 @@ func WithMetricsServerProxy(ctx *vclustercontext.ControllerContext, h http.Handl
 		if isMetricsServerProxyRequest(info) {
 			splitted := strings.Split(req.URL.Path, "/")
 
-			err := translateLabelSelectors(req)
+			// node labels are not translated and the virtual node labels might differ from the host
+			// node labels, so node selectors are applied to the virtual nodes instead
+			var err error
+			nodeSelector := labels.Everything()
+			if info.Resource == NodeResource {
+				nodeSelector, err = removeLabelSelector(req)
+			} else {
+				err = translateLabelSelectors(req)
+			}
 			if err != nil {
 				klog.Infof("error translating label selectors %v", err)
 				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
@@ -74,12 +83,24 @@ func WithMetricsServerProxy(ctx *vclustercontext.ControllerContext, h http.Handl
 
 			// request is for get particular pod
 			if info.Resource == PodResource && info.Verb == RequestVerbGet {
-				klog.Infof("physical namespace: %s", translate.Default.PhysicalNamespace(info.Namespace))
-				klog.Infof("physical name: %s", translate.Default.PhysicalName(info.Name, info.Namespace))
 				namespace := translate.Default.PhysicalNamespace(info.Namespace)
 				name := translate.Default.PhysicalName(info.Name, info.Namespace)
 
+				// only return metrics of pods that exist in the virtual cluster
+				vPod := &corev1.Pod{}
+				err := cachedVirtualClient.Get(req.Context(), types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, vPod)
+				if err != nil {
+					if kerrors.IsNotFound(err) {
+						requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("podmetrics %s/%s not found", info.Namespace, info.Name))
+						return
+					}
+
+					requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+					return
+				}
+
 				metricsServerProxy.resourceType = PodResource
+				metricsServerProxy.podsInNamespace = []corev1.Pod{*vPod}
 
 				// replace the translated name and namespace
 				splitted[5] = namespace
@@ -111,17 +132,9 @@ func WithMetricsServerProxy(ctx *vclustercontext.ControllerContext, h http.Handl
 				req.URL.Path = strings.Join(splitted, "/")
 			}
 
-			acceptHeader := req.Header.Get("Accept")
 			if info.Resource == NodeResource {
-				if strings.Contains(acceptHeader, "as=Table;") {
-					// respond a 403 for now as we don't want to expose all host nodes with the table response
-					// TODO: rewrite node table response to only show nodes synced in the vcluster
-					requestpkg.FailWithStatus(w, req, http.StatusForbidden, fmt.Errorf("cannot list nodes in table response format"))
-					return
-				}
-
 				// fetch and fill vcluster synced nodes
-				nodeList, err := getVirtualNodes(req.Context(), cachedVirtualClient)
+				nodeList, err := getVirtualNodes(req.Context(), cachedVirtualClient, nodeSelector)
 				if err != nil {
 					requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
 					return
@@ -170,31 +183,49 @@ func WithMetricsServerProxy(ctx *vclustercontext.ControllerContext, h http.Handl
 	})
 }
 
+// translateLabelSelectors translates the keys of the label selector of the request to the
+// physical label keys. Equality and set based requirements are supported.
 func translateLabelSelectors(req *http.Request) error {
-	translatedSelectors := make(map[string]string)
-
 	query := req.URL.Query()
 	labelSelectors := query.Get(LabelSelectorQueryParam)
 
+	translatedLabelSelectors := labels.NewSelector()
 	if labelSelectors != "" {
-		selectors, err := labels.ConvertSelectorToLabelsMap(labelSelectors)
+		selector, err := labels.Parse(labelSelectors)
 		if err != nil {
 			return err
 		}
 
-		for k, v := range selectors {
-			translatedKey := translate.Default.ConvertLabelKey(k)
-			translatedSelectors[translatedKey] = v
+		requirements, _ := selector.Requirements()
+		for _, requirement := range requirements {
+			translatedRequirement, err := labels.NewRequirement(translate.Default.ConvertLabelKey(requirement.Key()), requirement.Operator(), requirement.Values().List())
+			if err != nil {
+				return err
+			}
+
+			translatedLabelSelectors = translatedLabelSelectors.Add(*translatedRequirement)
 		}
 	}
 
-	translatedLabelSelectors := labels.SelectorFromSet(translatedSelectors)
 	query.Set(LabelSelectorQueryParam, translatedLabelSelectors.String())
 	req.URL.RawQuery = query.Encode()
 
 	return nil
 }
 
+// removeLabelSelector removes the label selector from the request and returns it
+func removeLabelSelector(req *http.Request) (labels.Selector, error) {
+	query := req.URL.Query()
+	selector, err := labels.Parse(query.Get(LabelSelectorQueryParam))
+	if err != nil {
+		return nil, err
+	}
+
+	query.Del(LabelSelectorQueryParam)
+	req.URL.RawQuery = query.Encode()
+	return selector, nil
+}
+
 func isAPIResourceListRequest(r *request.RequestInfo) bool {
 	return r.Path == "/apis/metrics.k8s.io/v1beta1"
 }
@@ -264,7 +295,7 @@ type RowData struct {
 }
 
 func (p *MetricsServerProxy) HandleRequest() {
-	if p.resourceType == PodResource && p.verb == RequestVerbList {
+	if (p.resourceType == PodResource && p.verb == RequestVerbList) || p.resourceType == NodeResource {
 		acceptHeader := p.request.Header.Get("Accept")
 		if strings.Contains(acceptHeader, "as=Table;") {
 			// use it while back conversion before writing response
@@ -303,7 +334,11 @@ func (p *MetricsServerProxy) HandleRequest() {
 		}
 	} else if p.resourceType == NodeResource {
 		// filter nodes synced with vcluster
-		newData, err = p.filterVirtualNodes(data)
+		if p.tableFormatRequested {
+			newData, err = p.filterVirtualNodesTable(data)
+		} else {
+			newData, err = p.filterVirtualNodes(data)
+		}
 		if err != nil {
 			if errors.Is(err, ErrorNodeNotInVcluster) {
 				requestpkg.FailWithStatus(p.responseWriter, p.request, http.StatusNotFound, err)
@@ -379,6 +414,68 @@ func (p *MetricsServerProxy) filterVirtualNodes(data []byte) ([]byte, error) {
 	return newData, nil
 }
 
+// filterVirtualNodesTable removes the rows of host nodes that are not synced into the vcluster
+func (p *MetricsServerProxy) filterVirtualNodesTable(data []byte) ([]byte, error) {
+	table := &metav1.Table{}
+	err := json.Unmarshal(data, table)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualNodeMap := make(map[string]corev1.Node)
+	for _, node := range p.nodesInVcluster {
+		virtualNodeMap[node.Name] = node
+	}
+
+	filteredTableRows := []metav1.TableRow{}
+	for _, row := range table.Rows {
+		pom := &metav1.PartialObjectMetadata{}
+		if len(row.Object.Raw) > 0 {
+			err = json.Unmarshal(row.Object.Raw, pom)
+			if err != nil {
+				klog.Infof("can't convert to partial object %v", err)
+			}
+		}
+
+		// the object is omitted if the client requested includeObject=None
+		name := pom.Name
+		if name == "" && len(row.Cells) > 0 {
+			name, _ = row.Cells[0].(string)
+		}
+
+		vNode, ok := virtualNodeMap[name]
+		if !ok {
+			continue
+		}
+
+		if pom.Name != "" {
+			// reset node metrics labels
+			pom.Labels = vNode.Labels
+			row.Object.Raw, err = json.Marshal(pom)
+			if err != nil {
+				klog.Infof("can't convert partial object to raw extension %v", err)
+			}
+		}
+
+		filteredTableRows = append(filteredTableRows, row)
+	}
+
+	if p.verb == RequestVerbGet && len(filteredTableRows) == 0 {
+		return nil, ErrorNodeNotInVcluster
+	}
+
+	// rewrite the filtered rows back to original table
+	table.Rows = filteredTableRows
+
+	newData, err := json.Marshal(table)
+	if err != nil {
+		klog.Errorf("error marshalling node metrics back to response %v", err)
+		return nil, err
+	}
+
+	return newData, nil
+}
+
 func (p *MetricsServerProxy) rewritePodMetricsGetData(data []byte) ([]byte, error) {
 	podMetrics := &metricsv1beta1.PodMetrics{}
 	err := json.Unmarshal(data, podMetrics)
@@ -389,6 +486,11 @@ func (p *MetricsServerProxy) rewritePodMetricsGetData(data []byte) ([]byte, erro
 	podMetrics.Name = p.requestInfo.Name
 	podMetrics.Namespace = p.requestInfo.Namespace
 
+	// reset pod metadata labels
+	if len(p.podsInNamespace) > 0 {
+		podMetrics.Labels = p.podsInNamespace[0].Labels
+	}
+
 	newData, err := json.Marshal(podMetrics)
 	if err != nil {
 		klog.Errorf("error marshalling pod metrics back to response %v", err)
@@ -529,10 +631,10 @@ func getVirtualPodObjectsInNamespace(ctx context.Context, vClient client.Client,
 	return podList.Items, nil
 }
 
-func getVirtualNodes(ctx context.Context, vClient client.Client) ([]corev1.Node, error) {
+func getVirtualNodes(ctx context.Context, vClient client.Client, selector labels.Selector) ([]corev1.Node, error) {
 	nodeList := &corev1.NodeList{}
 
-	err := vClient.List(ctx, nodeList)
+	err := vClient.List(ctx, nodeList, client.MatchingLabelsSelector{Selector: selector})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,162 @@
+package filters
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestTranslateLabelSelectors(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	req := httptest.NewRequest("GET", "/apis/metrics.k8s.io/v1beta1/namespaces/test/pods?labelSelector=app%3Dnginx%2Ctier+in+%28web%2Capi%29%2C%21canary", nil)
+	err := translateLabelSelectors(req)
+	assert.NilError(t, err)
+
+	app := translate.Default.ConvertLabelKey("app")
+	tier := translate.Default.ConvertLabelKey("tier")
+	canary := translate.Default.ConvertLabelKey("canary")
+	expected, err := labels.Parse(app + "=nginx," + tier + " in (web,api),!" + canary)
+	assert.NilError(t, err)
+	assert.Equal(t, req.URL.Query().Get(LabelSelectorQueryParam), expected.String())
+
+	// invalid selectors are rejected
+	req = httptest.NewRequest("GET", "/apis/metrics.k8s.io/v1beta1/namespaces/test/pods?labelSelector=app%3D%3D%3Dnginx", nil)
+	err = translateLabelSelectors(req)
+	assert.Assert(t, err != nil)
+}
+
+func TestRewritePodMetrics(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	vPods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nginx",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "nginx"},
+			},
+		},
+	}
+	podMetrics := func(name string) metricsv1beta1.PodMetrics {
+		return metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    map[string]string{translate.Default.ConvertLabelKey("app"): "nginx"},
+			},
+			Containers: []metricsv1beta1.ContainerMetrics{{Name: "nginx"}},
+		}
+	}
+
+	// list requests only return metrics of virtual pods
+	data, err := json.Marshal(&metricsv1beta1.PodMetricsList{
+		Items: []metricsv1beta1.PodMetrics{
+			podMetrics(translate.Default.PhysicalName("nginx", "default")),
+			podMetrics("other-pod"),
+		},
+	})
+	assert.NilError(t, err)
+
+	p := &MetricsServerProxy{
+		requestInfo:     &request.RequestInfo{Namespace: "default", Name: "nginx"},
+		podsInNamespace: vPods,
+	}
+	newData, err := p.rewritePodMetricsListData(data)
+	assert.NilError(t, err)
+
+	podMetricsList := &metricsv1beta1.PodMetricsList{}
+	err = json.Unmarshal(newData, podMetricsList)
+	assert.NilError(t, err)
+	assert.Equal(t, len(podMetricsList.Items), 1)
+	assert.Equal(t, podMetricsList.Items[0].Name, "nginx")
+	assert.Equal(t, podMetricsList.Items[0].Namespace, "default")
+	assert.DeepEqual(t, podMetricsList.Items[0].Labels, vPods[0].Labels)
+
+	// get requests return the virtual name and labels
+	single := podMetrics(translate.Default.PhysicalName("nginx", "default"))
+	data, err = json.Marshal(&single)
+	assert.NilError(t, err)
+	newData, err = p.rewritePodMetricsGetData(data)
+	assert.NilError(t, err)
+
+	podMetric := &metricsv1beta1.PodMetrics{}
+	err = json.Unmarshal(newData, podMetric)
+	assert.NilError(t, err)
+	assert.Equal(t, podMetric.Name, "nginx")
+	assert.Equal(t, podMetric.Namespace, "default")
+	assert.DeepEqual(t, podMetric.Labels, vPods[0].Labels)
+}
+
+func TestNodeMetricsSelectors(t *testing.T) {
+	req := httptest.NewRequest("GET", "/apis/metrics.k8s.io/v1beta1/nodes?labelSelector=pool%3Dsmall&limit=10", nil)
+	selector, err := removeLabelSelector(req)
+	assert.NilError(t, err)
+
+	// node selectors are not sent to the host but applied to the virtual nodes
+	assert.Equal(t, selector.String(), "pool=small")
+	assert.Equal(t, req.URL.Query().Get(LabelSelectorQueryParam), "")
+	assert.Equal(t, req.URL.Query().Get("limit"), "10")
+
+	req = httptest.NewRequest("GET", "/apis/metrics.k8s.io/v1beta1/nodes", nil)
+	selector, err = removeLabelSelector(req)
+	assert.NilError(t, err)
+	assert.Assert(t, selector.Empty())
+}
+
+func TestFilterVirtualNodesTable(t *testing.T) {
+	vNodeLabels := map[string]string{"pool": "small"}
+	row := func(name string, withObject bool) metav1.TableRow {
+		row := metav1.TableRow{Cells: []interface{}{name, "10m", "100Mi"}}
+		if withObject {
+			raw, err := json.Marshal(&metav1.PartialObjectMetadata{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"host": "label"}},
+			})
+			assert.NilError(t, err)
+			row.Object = runtime.RawExtension{Raw: raw}
+		}
+		return row
+	}
+	data, err := json.Marshal(&metav1.Table{
+		Rows: []metav1.TableRow{row("node-1", true), row("node-2", false), row("other-node", true)},
+	})
+	assert.NilError(t, err)
+
+	p := &MetricsServerProxy{
+		verb: RequestVerbList,
+		nodesInVcluster: []corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: vNodeLabels}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		},
+	}
+	newData, err := p.filterVirtualNodesTable(data)
+	assert.NilError(t, err)
+
+	table := &metav1.Table{}
+	err = json.Unmarshal(newData, table)
+	assert.NilError(t, err)
+	assert.Equal(t, len(table.Rows), 2)
+	assert.Equal(t, table.Rows[0].Cells[0], "node-1")
+	assert.Equal(t, table.Rows[1].Cells[0], "node-2")
+
+	pom := &metav1.PartialObjectMetadata{}
+	err = json.Unmarshal(table.Rows[0].Object.Raw, pom)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pom.Labels, vNodeLabels)
+
+	// get requests for host nodes that are not synced are not found
+	data, err = json.Marshal(&metav1.Table{Rows: []metav1.TableRow{row("other-node", true)}})
+	assert.NilError(t, err)
+	p.verb = RequestVerbGet
+	_, err = p.filterVirtualNodesTable(data)
+	assert.Equal(t, err, ErrorNodeNotInVcluster)
+}
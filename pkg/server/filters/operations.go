@@ -0,0 +1,178 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/operations"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+var operationsAPIPath = "/apis/" + operations.GroupName + "/" + operations.Version
+
+// maxOperationRequestBodySize limits the size of a request body, operation requests are tiny
+const maxOperationRequestBodySize = 64 * 1024
+
+// WithOperations serves the operations.vcluster.loft.sh api, which allows to resync, garbage collect,
+// pause, drain or inspect the objects of a syncer from within the virtual cluster. Authorization for the
+// resources of this group is delegated to the virtual cluster rbac.
+func WithOperations(h http.Handler, registry *operations.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == operationsAPIPath {
+			requestpkg.SucceedWithObject(w, operationsAPIResourceList())
+			return
+		}
+
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok || !info.IsResourceRequest || info.APIGroup != operations.GroupName {
+			h.ServeHTTP(w, req)
+			return
+		} else if info.APIVersion != operations.Version || info.Subresource != "" {
+			requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("the server could not find the requested resource"))
+			return
+		}
+
+		if info.Resource == operations.InventoriesResource {
+			serveInventory(w, req, info, registry)
+			return
+		}
+
+		req.Body = http.MaxBytesReader(w, req.Body, maxOperationRequestBodySize)
+		operationRequest, err := parseOperationRequest(req, info)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusBadRequest, err)
+			return
+		}
+
+		target, err := registry.Target(operationRequest.Syncer)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusNotFound, err)
+			return
+		}
+
+		result := &operations.Result{Syncer: operationRequest.Syncer}
+		switch {
+		case info.Resource == operations.ResyncsResource && info.Verb == "create":
+			result.Enqueued, err = target.Resync(req.Context(), operationRequest.Namespace, operationRequest.Name)
+		case info.Resource == operations.GarbageCollectionsResource && info.Verb == "create":
+			result.Enqueued, err = target.GarbageCollect(req.Context())
+		case info.Resource == operations.PausesResource && info.Verb == "create":
+			if operationRequest.Name == "" {
+				requestpkg.FailWithStatus(w, req, http.StatusBadRequest, fmt.Errorf("name is required"))
+				return
+			}
+
+			err = target.Pause(req.Context(), operationRequest.Namespace, operationRequest.Name, isPaused(operationRequest))
+			if err == nil {
+				result.Changed = 1
+				result.Paused, err = target.Paused(req.Context(), operationRequest.Namespace)
+			}
+		case info.Resource == operations.PausesResource && info.Verb == "list":
+			result.Paused, err = target.Paused(req.Context(), operationRequest.Namespace)
+		case info.Resource == operations.DrainsResource && info.Verb == "create":
+			result.Changed, err = target.Drain(req.Context(), operationRequest.Namespace, isPaused(operationRequest))
+		case info.Resource == operations.MappingsResource && info.Verb == "list":
+			result.Mappings, err = target.Mappings(req.Context(), operationRequest.Namespace)
+		default:
+			requestpkg.FailWithStatus(w, req, http.StatusMethodNotAllowed, fmt.Errorf("%s is not supported for %s", info.Verb, info.Resource))
+			return
+		}
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				requestpkg.FailWithStatus(w, req, http.StatusNotFound, err)
+				return
+			}
+
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		klog.Infof("operations api: %s %s for syncer %s (namespace: %s, name: %s)", info.Verb, info.Resource, operationRequest.Syncer, operationRequest.Namespace, operationRequest.Name)
+		requestpkg.SucceedWithObject(w, result)
+	})
+}
+
+// serveInventory takes a new snapshot of the synced physical state on create and returns the
+// last stored snapshot on list
+func serveInventory(w http.ResponseWriter, req *http.Request, info *request.RequestInfo, registry *operations.Registry) {
+	if info.Namespace != "" {
+		requestpkg.FailWithStatus(w, req, http.StatusBadRequest, fmt.Errorf("%s is not namespaced", info.Resource))
+		return
+	}
+
+	inventory, err := registry.Inventory()
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusNotFound, err)
+		return
+	}
+
+	result := &operations.Result{}
+	switch info.Verb {
+	case "create":
+		result.Inventory, err = inventory.Snapshot(req.Context())
+	case "list":
+		result.Inventory, err = inventory.Latest(req.Context())
+	default:
+		requestpkg.FailWithStatus(w, req, http.StatusMethodNotAllowed, fmt.Errorf("%s is not supported for %s", info.Verb, info.Resource))
+		return
+	}
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	klog.Infof("operations api: %s %s", info.Verb, info.Resource)
+	requestpkg.SucceedWithObject(w, result)
+}
+
+// parseOperationRequest reads the operation from the request body or the query. Authorization
+// only sees the namespace of the url, so a namespace in the body has to match it.
+func parseOperationRequest(req *http.Request, info *request.RequestInfo) (*operations.Request, error) {
+	operationRequest := &operations.Request{}
+	if req.Method == http.MethodPost {
+		err := json.NewDecoder(req.Body).Decode(operationRequest)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding request body: %w", err)
+		}
+	} else {
+		operationRequest.Syncer = req.URL.Query().Get("syncer")
+	}
+
+	if operationRequest.Syncer == "" {
+		return nil, fmt.Errorf("syncer is required")
+	} else if operationRequest.Namespace != "" && operationRequest.Namespace != info.Namespace {
+		return nil, fmt.Errorf("namespace %s in request does not match the request url namespace %q", operationRequest.Namespace, info.Namespace)
+	} else if info.Namespace != "" && info.Resource == operations.GarbageCollectionsResource {
+		return nil, fmt.Errorf("%s is not namespaced", info.Resource)
+	}
+
+	operationRequest.Namespace = info.Namespace
+	return operationRequest, nil
+}
+
+func isPaused(operationRequest *operations.Request) bool {
+	return operationRequest.Paused == nil || *operationRequest.Paused
+}
+
+func operationsAPIResourceList() *metav1.APIResourceList {
+	return &metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: operations.GroupName + "/" + operations.Version,
+		APIResources: []metav1.APIResource{
+			{Name: operations.ResyncsResource, Namespaced: true, Kind: "Resync", Verbs: []string{"create"}},
+			{Name: operations.GarbageCollectionsResource, Kind: "GarbageCollection", Verbs: []string{"create"}},
+			{Name: operations.PausesResource, Namespaced: true, Kind: "Pause", Verbs: []string{"create", "list"}},
+			{Name: operations.DrainsResource, Namespaced: true, Kind: "Drain", Verbs: []string{"create"}},
+			{Name: operations.MappingsResource, Namespaced: true, Kind: "Mapping", Verbs: []string{"list"}},
+			{Name: operations.InventoriesResource, Kind: "Inventory", Verbs: []string{"create", "list"}},
+		},
+	}
+}
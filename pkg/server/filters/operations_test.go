@@ -0,0 +1,304 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/operations"
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+type fakeOperationsTarget struct {
+	paused map[types.NamespacedName]bool
+}
+
+func (f *fakeOperationsTarget) Resync(ctx context.Context, namespace, name string) (int, error) {
+	return 1, nil
+}
+
+func (f *fakeOperationsTarget) GarbageCollect(ctx context.Context) (int, error) {
+	return 1, nil
+}
+
+func (f *fakeOperationsTarget) Pause(ctx context.Context, namespace, name string, paused bool) error {
+	if paused {
+		f.paused[types.NamespacedName{Namespace: namespace, Name: name}] = true
+	} else {
+		delete(f.paused, types.NamespacedName{Namespace: namespace, Name: name})
+	}
+	return nil
+}
+
+func (f *fakeOperationsTarget) Drain(ctx context.Context, namespace string, paused bool) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeOperationsTarget) Paused(ctx context.Context, namespace string) ([]types.NamespacedName, error) {
+	paused := []types.NamespacedName{}
+	for name := range f.paused {
+		if namespace == "" || name.Namespace == namespace {
+			paused = append(paused, name)
+		}
+	}
+	return paused, nil
+}
+
+func (f *fakeOperationsTarget) Mappings(ctx context.Context, namespace string) ([]operations.Mapping, error) {
+	return nil, nil
+}
+
+func TestWithOperations(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		paused    []types.NamespacedName
+		method    string
+		body      string
+		syncer    string
+		info      request.RequestInfo
+		nextCalls int
+
+		expectedCode   int
+		expectedPaused []types.NamespacedName
+	}{
+		{
+			name:   "pause",
+			method: http.MethodPost,
+			body:   `{"syncer":"pods","name":"test"}`,
+			info: request.RequestInfo{
+				IsResourceRequest: true,
+				APIGroup:          operations.GroupName,
+				APIVersion:        operations.Version,
+				Namespace:         "default",
+				Resource:          operations.PausesResource,
+				Verb:              "create",
+			},
+			expectedCode:   http.StatusOK,
+			expectedPaused: []types.NamespacedName{{Namespace: "default", Name: "test"}},
+		},
+		{
+			name:   "resume",
+			paused: []types.NamespacedName{{Namespace: "default", Name: "test"}},
+			method: http.MethodPost,
+			body:   `{"syncer":"pods","namespace":"default","name":"test","paused":false}`,
+			info: request.RequestInfo{
+				IsResourceRequest: true,
+				APIGroup:          operations.GroupName,
+				APIVersion:        operations.Version,
+				Namespace:         "default",
+				Resource:          operations.PausesResource,
+				Verb:              "create",
+			},
+			expectedCode:   http.StatusOK,
+			expectedPaused: []types.NamespacedName{},
+		},
+		{
+			name:   "unknown syncer",
+			method: http.MethodPost,
+			body:   `{"syncer":"unknown","name":"test"}`,
+			info: request.RequestInfo{
+				IsResourceRequest: true,
+				APIGroup:          operations.GroupName,
+				APIVersion:        operations.Version,
+				Namespace:         "default",
+				Resource:          operations.PausesResource,
+				Verb:              "create",
+			},
+			expectedCode:   http.StatusNotFound,
+			expectedPaused: []types.NamespacedName{},
+		},
+		{
+			name:   "unsupported verb",
+			method: http.MethodDelete,
+			syncer: "pods",
+			info: request.RequestInfo{
+				IsResourceRequest: true,
+				APIGroup:          operations.GroupName,
+				APIVersion:        operations.Version,
+				Namespace:         "default",
+				Resource:          operations.PausesResource,
+				Verb:              "delete",
+			},
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedPaused: []types.NamespacedName{},
+		},
+		{
+			name:   "namespace mismatch",
+			method: http.MethodPost,
+			body:   `{"syncer":"pods","namespace":"kube-system","name":"test"}`,
+			info: request.RequestInfo{
+				IsResourceRequest: true,
+				APIGroup:          operations.GroupName,
+				APIVersion:        operations.Version,
+				Namespace:         "default",
+				Resource:          operations.PausesResource,
+				Verb:              "create",
+			},
+			expectedCode:   http.StatusBadRequest,
+			expectedPaused: []types.NamespacedName{},
+		},
+		{
+			name:   "namespace without url namespace",
+			method: http.MethodPost,
+			body:   `{"syncer":"pods","namespace":"kube-system","name":"test"}`,
+			info: request.RequestInfo{
+				IsResourceRequest: true,
+				APIGroup:          operations.GroupName,
+				APIVersion:        operations.Version,
+				Resource:          operations.PausesResource,
+				Verb:              "create",
+			},
+			expectedCode:   http.StatusBadRequest,
+			expectedPaused: []types.NamespacedName{},
+		},
+		{
+			name:   "other group",
+			method: http.MethodGet,
+			info: request.RequestInfo{
+				IsResourceRequest: true,
+				APIVersion:        "v1",
+				Namespace:         "default",
+				Resource:          "pods",
+				Verb:              "list",
+			},
+			nextCalls:      1,
+			expectedCode:   http.StatusOK,
+			expectedPaused: []types.NamespacedName{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		target := &fakeOperationsTarget{paused: map[types.NamespacedName]bool{}}
+		for _, name := range testCase.paused {
+			target.paused[name] = true
+		}
+		registry := operations.NewRegistry()
+		registry.Register("pods", target)
+
+		nextCalls := 0
+		h := WithOperations(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			nextCalls++
+		}), registry)
+
+		url := "/apis/" + operations.GroupName + "/" + operations.Version + "/" + testCase.info.Resource
+		if testCase.syncer != "" {
+			url += "?syncer=" + testCase.syncer
+		}
+		info := testCase.info
+		req := httptest.NewRequest(testCase.method, url, strings.NewReader(testCase.body))
+		req = req.WithContext(request.WithRequestInfo(req.Context(), &info))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, w.Code, testCase.expectedCode, "unexpected status code in test case %s: %s", testCase.name, w.Body.String())
+		assert.Equal(t, nextCalls, testCase.nextCalls, "unexpected next handler calls in test case %s", testCase.name)
+		paused, _ := target.Paused(context.Background(), "")
+		assert.Assert(t, cmp.DeepEqual(paused, testCase.expectedPaused), "unexpected paused objects in test case %s", testCase.name)
+		if testCase.expectedCode == http.StatusOK && testCase.nextCalls == 0 {
+			result := &operations.Result{}
+			err := json.Unmarshal(w.Body.Bytes(), result)
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+			assert.Equal(t, len(result.Paused), len(testCase.expectedPaused), "unexpected result in test case %s", testCase.name)
+			for i := range result.Paused {
+				assert.Equal(t, result.Paused[i], testCase.expectedPaused[i], "unexpected result in test case %s", testCase.name)
+			}
+		}
+	}
+}
+
+func TestParseOperationRequest(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		method string
+		body   string
+		query  string
+		info   request.RequestInfo
+
+		expectedRequest *operations.Request
+		expectedErr     bool
+	}{
+		{
+			name:            "namespace from url",
+			method:          http.MethodPost,
+			body:            `{"syncer":"pods","name":"test"}`,
+			info:            request.RequestInfo{Namespace: "default", Resource: operations.PausesResource},
+			expectedRequest: &operations.Request{Syncer: "pods", Namespace: "default", Name: "test"},
+		},
+		{
+			name:            "matching namespace",
+			method:          http.MethodPost,
+			body:            `{"syncer":"pods","namespace":"default","name":"test"}`,
+			info:            request.RequestInfo{Namespace: "default", Resource: operations.PausesResource},
+			expectedRequest: &operations.Request{Syncer: "pods", Namespace: "default", Name: "test"},
+		},
+		{
+			name:        "mismatching namespace",
+			method:      http.MethodPost,
+			body:        `{"syncer":"pods","namespace":"kube-system","name":"test"}`,
+			info:        request.RequestInfo{Namespace: "default", Resource: operations.PausesResource},
+			expectedErr: true,
+		},
+		{
+			name:        "namespace without url namespace",
+			method:      http.MethodPost,
+			body:        `{"syncer":"pods","namespace":"kube-system"}`,
+			info:        request.RequestInfo{Resource: operations.ResyncsResource},
+			expectedErr: true,
+		},
+		{
+			name:            "cluster wide",
+			method:          http.MethodPost,
+			body:            `{"syncer":"pods"}`,
+			info:            request.RequestInfo{Resource: operations.ResyncsResource},
+			expectedRequest: &operations.Request{Syncer: "pods"},
+		},
+		{
+			name:        "namespaced garbage collection",
+			method:      http.MethodPost,
+			body:        `{"syncer":"pods"}`,
+			info:        request.RequestInfo{Namespace: "default", Resource: operations.GarbageCollectionsResource},
+			expectedErr: true,
+		},
+		{
+			name:            "list",
+			method:          http.MethodGet,
+			query:           "?syncer=pods",
+			info:            request.RequestInfo{Namespace: "default", Resource: operations.MappingsResource},
+			expectedRequest: &operations.Request{Syncer: "pods", Namespace: "default"},
+		},
+		{
+			name:        "missing syncer",
+			method:      http.MethodGet,
+			info:        request.RequestInfo{Namespace: "default", Resource: operations.MappingsResource},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid body",
+			method:      http.MethodPost,
+			body:        `{"syncer":`,
+			info:        request.RequestInfo{Resource: operations.ResyncsResource},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		info := testCase.info
+		req := httptest.NewRequest(testCase.method, "/"+testCase.query, strings.NewReader(testCase.body))
+		operationRequest, err := parseOperationRequest(req, &info)
+		if testCase.expectedErr {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(operationRequest, testCase.expectedRequest), "unexpected request in test case %s", testCase.name)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/authorization/delegatingauthorizer"
-	"github.com/loft-sh/vcluster/pkg/server/handler"
 	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
@@ -17,12 +16,11 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	"k8s.io/apiserver/pkg/endpoints/request"
-	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func WithRedirect(h http.Handler, localConfig *rest.Config, localScheme *runtime.Scheme, uncachedVirtualClient client.Client, admit admission.Interface, resources []delegatingauthorizer.GroupVersionResourceVerb) http.Handler {
+func WithRedirect(h http.Handler, proxyHandler http.Handler, localScheme *runtime.Scheme, uncachedVirtualClient client.Client, admit admission.Interface, resources []delegatingauthorizer.GroupVersionResourceVerb) http.Handler {
 	s := serializer.NewCodecFactory(localScheme)
 	parameterCodec := runtime.NewParameterCodec(uncachedVirtualClient.Scheme())
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -65,14 +63,8 @@ func WithRedirect(h http.Handler, localConfig *rest.Config, localScheme *runtime
 				}
 			}
 
-			h, err := handler.Handler("", localConfig, nil)
-			if err != nil {
-				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
-				return
-			}
-
 			req.Header.Del("Authorization")
-			h.ServeHTTP(w, req)
+			proxyHandler.ServeHTTP(w, req)
 			return
 		}
 
@@ -0,0 +1,48 @@
+package filters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/scheduler"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithSchedulerExtender serves the scheduler extender that scores virtual nodes by the free capacity of the
+// host nodes under scheduler.URLPrefix. Only the virtual kube-scheduler, which authenticates with its client
+// certificate, may call it. The host cache is started with ctx on the first request.
+func WithSchedulerExtender(ctx context.Context, h http.Handler, hostCache *scheduler.HostCache) http.Handler {
+	log := loghelper.New("scheduler-extender")
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != scheduler.URLPrefix && !strings.HasPrefix(req.URL.Path, scheduler.URLPrefix+"/") {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok || userInfo.GetName() != user.KubeScheduler {
+			requestpkg.FailWithStatus(w, req, http.StatusForbidden, fmt.Errorf("only %s may use the scheduler extender", user.KubeScheduler))
+			return
+		} else if req.URL.Path != scheduler.PrioritizePath {
+			requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("the server could not find the requested resource"))
+			return
+		}
+
+		hostReader, err := hostCache.Reader(ctx)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		extender := &scheduler.Extender{
+			HostReader: hostReader,
+			Log:        log,
+		}
+		extender.ServeHTTP(w, req)
+	})
+}
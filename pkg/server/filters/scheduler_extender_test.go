@@ -0,0 +1,56 @@
+package filters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/scheduler"
+	"gotest.tools/assert"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestWithSchedulerExtender(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		path string
+		user string
+
+		expectedStatus int
+	}{
+		{
+			name:           "other path",
+			path:           "/api/v1/pods",
+			user:           "alice",
+			expectedStatus: http.StatusTeapot,
+		},
+		{
+			name:           "prioritize by other user",
+			path:           scheduler.PrioritizePath,
+			user:           "alice",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unknown extender verb",
+			path:           scheduler.URLPrefix + "/filter",
+			user:           user.KubeScheduler,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		h := WithSchedulerExtender(context.Background(), http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}), scheduler.NewHostCache(nil))
+
+		req := httptest.NewRequest(http.MethodPost, testCase.path, nil)
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: testCase.user}))
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+
+		assert.Equal(t, recorder.Code, testCase.expectedStatus, "unexpected status in test case %s", testCase.name)
+	}
+}
@@ -153,6 +153,7 @@ func updateService(req *http.Request, decoder encoding.Decoder, localClient clie
 	pService.Spec.Type = newVService.Spec.Type
 	pService.Spec.Ports = newVService.Spec.Ports
 	pService.Spec.ClusterIP = ""
+	pService.Spec.IPFamilyPolicy = services.TranslateIPFamilyPolicy(newVService)
 	err = localClient.Patch(ctx, pService, client.MergeFrom(originalPService))
 	if err != nil {
 		return nil, err
@@ -160,6 +161,9 @@ func updateService(req *http.Request, decoder encoding.Decoder, localClient clie
 
 	// now we have the cluster ip that we can apply to the new service
 	newVService.Spec.ClusterIP = pService.Spec.ClusterIP
+	newVService.Spec.ClusterIPs = pService.Spec.ClusterIPs
+	newVService.Spec.IPFamilies = pService.Spec.IPFamilies
+	newVService.Spec.IPFamilyPolicy = services.TranslateIPFamilyPolicyBackwards(pService, newVService)
 	// also we need to apply newly allocated node ports
 	newVService.Spec.HealthCheckNodePort = pService.Spec.HealthCheckNodePort
 	newVService.Spec.Ports = pService.Spec.Ports
@@ -212,6 +216,8 @@ func createService(req *http.Request, decoder encoding.Decoder, localClient clie
 	}
 	newService.Annotations[services.ServiceBlockDeletion] = "true"
 	newService.Spec.Selector = translate.Default.TranslateLabels(vService.Spec.Selector, vService.Namespace, nil)
+	newService.Spec.IPFamilies = nil
+	newService.Spec.IPFamilyPolicy = services.TranslateIPFamilyPolicy(vService)
 	err = localClient.Create(req.Context(), newService)
 	if err != nil {
 		klog.Infof("Error creating service in physical cluster: %v", err)
@@ -224,6 +230,8 @@ func createService(req *http.Request, decoder encoding.Decoder, localClient clie
 
 	vService.Spec.ClusterIP = newService.Spec.ClusterIP
 	vService.Spec.ClusterIPs = newService.Spec.ClusterIPs
+	vService.Spec.IPFamilies = newService.Spec.IPFamilies
+	vService.Spec.IPFamilyPolicy = services.TranslateIPFamilyPolicyBackwards(newService, vService)
 	vService.Spec.Ports = newService.Spec.Ports
 	vService.Spec.HealthCheckNodePort = newService.Spec.HealthCheckNodePort
 	vService.Status = newService.Status
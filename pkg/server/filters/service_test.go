@@ -0,0 +1,89 @@
+package filters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCreateServiceIPFamilyPolicy(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+	singleStack := corev1.IPFamilyPolicySingleStack
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+
+	testCases := []struct {
+		name string
+
+		policy      *corev1.IPFamilyPolicy
+		ipFamilies  []corev1.IPFamily
+		serviceType corev1.ServiceType
+
+		expectedHostPolicy    *corev1.IPFamilyPolicy
+		expectedVirtualPolicy *corev1.IPFamilyPolicy
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:                  "single stack",
+			policy:                &singleStack,
+			ipFamilies:            []corev1.IPFamily{corev1.IPv6Protocol},
+			expectedHostPolicy:    &singleStack,
+			expectedVirtualPolicy: &singleStack,
+		},
+		{
+			name:                  "require dual stack is relaxed",
+			policy:                &requireDualStack,
+			ipFamilies:            []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			expectedHostPolicy:    &preferDualStack,
+			expectedVirtualPolicy: &preferDualStack,
+		},
+		{
+			name:        "external name",
+			policy:      &singleStack,
+			serviceType: corev1.ServiceTypeExternalName,
+		},
+	}
+
+	for _, testCase := range testCases {
+		vService := &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+			Spec: corev1.ServiceSpec{
+				Type:           testCase.serviceType,
+				IPFamilyPolicy: testCase.policy,
+				IPFamilies:     testCase.ipFamilies,
+			},
+		}
+		body, err := json.Marshal(vService)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		scheme := testingutil.NewScheme()
+		localClient := testingutil.NewFakeClient(scheme)
+		virtualClient := testingutil.NewFakeClient(scheme)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/services", strings.NewReader(string(body)))
+		_, err = createService(req, encoding.NewDecoder(scheme, false), localClient, virtualClient, "default", nil)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+
+		pService := &corev1.Service{}
+		err = localClient.Get(req.Context(), types.NamespacedName{Namespace: "test", Name: translate.Default.PhysicalName("nginx", "default")}, pService)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, pService.Spec.IPFamilyPolicy, testCase.expectedHostPolicy)
+		assert.Equal(t, len(pService.Spec.IPFamilies), 0, "unexpected host ip families in test case %s", testCase.name)
+
+		err = virtualClient.Get(req.Context(), types.NamespacedName{Namespace: "default", Name: "nginx"}, vService)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, vService.Spec.IPFamilyPolicy, testCase.expectedVirtualPolicy)
+	}
+}
@@ -0,0 +1,203 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// userAnnotatedResources are the workloads that get annotated with the virtual user
+var userAnnotatedResources = map[string]map[string]bool{
+	"":      {"pods": true},
+	"apps":  {"deployments": true, "replicasets": true, "statefulsets": true, "daemonsets": true},
+	"batch": {"jobs": true, "cronjobs": true},
+}
+
+// userAnnotationPath is the json pointer of the user annotation
+var userAnnotationPath = "/metadata/annotations/" + strings.ReplaceAll(podtranslate.UserAnnotation, "/", "~1")
+
+// WithUserAnnotation annotates workloads that are created or modified through the vcluster proxy
+// with the authenticated virtual user, so the pod syncer can stamp the user onto physical pods.
+// The annotation is overridden in json, yaml and protobuf bodies, merge patches and server side
+// apply requests. Json patches that try to set it and bodies of other media types are rejected.
+func WithUserAnnotation(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok || !info.IsResourceRequest || info.Subresource != "" || !userAnnotatedResources[info.APIGroup][info.Resource] {
+			h.ServeHTTP(w, req)
+			return
+		} else if info.Verb != "create" && info.Verb != "update" && info.Verb != "patch" {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok || userInfo.GetName() == "" {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		switch {
+		case info.Verb != "patch" && contentType == runtime.ContentTypeJSON,
+			info.Verb == "patch" && (contentType == string(types.MergePatchType) || contentType == string(types.StrategicMergePatchType)):
+			body, err = setUserAnnotation(body, userInfo.GetName())
+		case info.Verb != "patch" && contentType == runtime.ContentTypeYAML,
+			info.Verb == "patch" && contentType == string(types.ApplyPatchType):
+			// json is valid yaml, so the body is passed on as json
+			body, err = yaml.YAMLToJSON(body)
+			if err == nil {
+				body, err = setUserAnnotation(body, userInfo.GetName())
+			}
+			if info.Verb != "patch" {
+				req.Header.Set("Content-Type", runtime.ContentTypeJSON)
+			}
+		case info.Verb != "patch" && contentType == runtime.ContentTypeProtobuf:
+			body, err = setUserAnnotationProtobuf(body, userInfo.GetName())
+		case info.Verb == "patch" && contentType == string(types.JSONPatchType):
+			err = validateJSONPatch(body)
+			if err != nil {
+				requestpkg.FailWithStatus(w, req, http.StatusForbidden, err)
+				return
+			}
+		default:
+			requestpkg.FailWithStatus(w, req, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported media type %q", contentType))
+			return
+		}
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusBadRequest, err)
+			return
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		h.ServeHTTP(w, req)
+	})
+}
+
+// setUserAnnotation sets the user annotation within the object or merge patch
+func setUserAnnotation(body []byte, user string) ([]byte, error) {
+	obj := map[string]interface{}{}
+	err := json.Unmarshal(body, &obj)
+	if err != nil {
+		return nil, fmt.Errorf("decode request body: %w", err)
+	}
+
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = map[string]interface{}{}
+		metadata["annotations"] = annotations
+	}
+	annotations[podtranslate.UserAnnotation] = user
+
+	return json.Marshal(obj)
+}
+
+// setUserAnnotationProtobuf sets the user annotation within a protobuf encoded object
+func setUserAnnotationProtobuf(body []byte, user string) ([]byte, error) {
+	info, ok := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), runtime.ContentTypeProtobuf)
+	if !ok {
+		return nil, fmt.Errorf("no protobuf serializer found")
+	}
+
+	obj, _, err := info.Serializer.Decode(body, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decode request body: %w", err)
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := accessor.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[podtranslate.UserAnnotation] = user
+	accessor.SetAnnotations(annotations)
+
+	buf := &bytes.Buffer{}
+	err = info.Serializer.Encode(obj, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// validateJSONPatch returns an error if one of the operations of the json patch could set the user annotation
+func validateJSONPatch(body []byte) error {
+	operations := []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}{}
+	err := json.Unmarshal(body, &operations)
+	if err != nil {
+		return fmt.Errorf("decode json patch: %w", err)
+	}
+
+	for _, operation := range operations {
+		if operation.Op == "test" || operation.Op == "remove" {
+			continue
+		}
+
+		switch operation.Path {
+		case userAnnotationPath:
+		case "", "/metadata", "/metadata/annotations":
+			// objects replacing the metadata or the annotations must not contain the user annotation
+			if operation.Op != "move" && operation.Op != "copy" && !containsUserAnnotation(operation.Path, operation.Value) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		return fmt.Errorf("the %s annotation can't be set through a json patch", podtranslate.UserAnnotation)
+	}
+
+	return nil
+}
+
+func containsUserAnnotation(path string, value json.RawMessage) bool {
+	obj := map[string]interface{}{}
+	if json.Unmarshal(value, &obj) != nil {
+		return false
+	}
+
+	if path == "" {
+		obj, _ = obj["metadata"].(map[string]interface{})
+	}
+	if path == "" || path == "/metadata" {
+		obj, _ = obj["annotations"].(map[string]interface{})
+	}
+
+	_, ok := obj[podtranslate.UserAnnotation]
+	return ok
+}
@@ -0,0 +1,187 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestWithUserAnnotation(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		verb        string
+		apiGroup    string
+		resource    string
+		contentType string
+		body        string
+
+		expectedUser   string
+		expectedStatus int
+	}{
+		{
+			name:         "create deployment",
+			verb:         "create",
+			apiGroup:     "apps",
+			resource:     "deployments",
+			contentType:  "application/json",
+			body:         `{"metadata":{"name":"test"}}`,
+			expectedUser: "alice",
+		},
+		{
+			name:         "overwrite user annotation",
+			verb:         "update",
+			resource:     "pods",
+			contentType:  "application/json",
+			body:         `{"metadata":{"name":"test","annotations":{"vcluster.loft.sh/user":"bob"}}}`,
+			expectedUser: "alice",
+		},
+		{
+			name:         "merge patch",
+			verb:         "patch",
+			apiGroup:     "batch",
+			resource:     "jobs",
+			contentType:  "application/merge-patch+json",
+			body:         `{"spec":{"suspend":true}}`,
+			expectedUser: "alice",
+		},
+		{
+			name:        "json patch",
+			verb:        "patch",
+			apiGroup:    "batch",
+			resource:    "jobs",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"remove","path":"/spec/suspend"}]`,
+		},
+		{
+			name:         "server side apply",
+			verb:         "patch",
+			resource:     "pods",
+			contentType:  "application/apply-patch+yaml",
+			body:         "metadata:\n  name: test\n  annotations:\n    vcluster.loft.sh/user: bob\n",
+			expectedUser: "alice",
+		},
+		{
+			name:         "create from yaml",
+			verb:         "create",
+			apiGroup:     "apps",
+			resource:     "deployments",
+			contentType:  "application/yaml",
+			body:         "metadata:\n  name: test\n",
+			expectedUser: "alice",
+		},
+		{
+			name:           "json patch setting the user annotation",
+			verb:           "patch",
+			resource:       "pods",
+			contentType:    "application/json-patch+json",
+			body:           `[{"op":"add","path":"/metadata/annotations/vcluster.loft.sh~1user","value":"bob"}]`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "json patch replacing the annotations",
+			verb:           "patch",
+			resource:       "pods",
+			contentType:    "application/json-patch+json",
+			body:           `[{"op":"replace","path":"/metadata/annotations","value":{"vcluster.loft.sh/user":"bob"}}]`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unsupported media type",
+			verb:           "create",
+			resource:       "pods",
+			contentType:    "text/plain",
+			body:           `test`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "other resource",
+			verb:        "create",
+			resource:    "configmaps",
+			contentType: "application/json",
+			body:        `{"metadata":{"name":"test"}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var received string
+		h := WithUserAnnotation(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, err := io.ReadAll(req.Body)
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+			assert.Equal(t, req.ContentLength, int64(len(body)), "unexpected content length in test case %s", testCase.name)
+			received = string(body)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testCase.body))
+		req.Header.Set("Content-Type", testCase.contentType)
+		ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+			IsResourceRequest: true,
+			Verb:              testCase.verb,
+			APIGroup:          testCase.apiGroup,
+			Resource:          testCase.resource,
+		})
+		ctx = request.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req.WithContext(ctx))
+		if testCase.expectedStatus != 0 {
+			assert.Equal(t, recorder.Code, testCase.expectedStatus, "unexpected status in test case %s", testCase.name)
+			continue
+		}
+
+		if testCase.expectedUser == "" {
+			assert.Equal(t, received, testCase.body, "unexpected body in test case %s", testCase.name)
+			continue
+		}
+
+		obj := map[string]interface{}{}
+		assert.NilError(t, json.Unmarshal([]byte(received), &obj), "unexpected error in test case %s", testCase.name)
+		annotations := obj["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+		assert.Equal(t, annotations[podtranslate.UserAnnotation], testCase.expectedUser, "unexpected user in test case %s", testCase.name)
+	}
+}
+
+func TestWithUserAnnotationProtobuf(t *testing.T) {
+	info, _ := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), runtime.ContentTypeProtobuf)
+	body := &bytes.Buffer{}
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: map[string]string{podtranslate.UserAnnotation: "bob"}},
+	}
+	assert.NilError(t, info.Serializer.Encode(pod, body))
+
+	var received *corev1.Pod
+	h := WithUserAnnotation(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NilError(t, err)
+		obj, _, err := info.Serializer.Decode(body, nil, nil)
+		assert.NilError(t, err)
+		received = obj.(*corev1.Pod)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", runtime.ContentTypeProtobuf)
+	ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              "create",
+		Resource:          "pods",
+	})
+	ctx = request.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+	h.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+	assert.Assert(t, received != nil)
+	assert.Equal(t, received.Name, "test")
+	assert.Equal(t, received.Annotations[podtranslate.UserAnnotation], "alice")
+}
@@ -68,17 +68,12 @@ func Handler(prefix string, cfg *rest.Config, transport http.RoundTripper) (http
 		}
 	}
 
-	upgradeTransport, err := makeUpgradeTransport(cfg)
+	upgradeTransport, err := makeUpgradeTransport(cfg, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	responder := &responder{}
-	proxy := proxy.NewUpgradeAwareHandler(target, transport, false, false, responder)
-	proxy.UpgradeTransport = upgradeTransport
-	proxy.UseRequestLocation = true
-
-	handler := http.Handler(proxy)
+	handler := http.Handler(newProxy(target, transport, upgradeTransport))
 	if len(prefix) > 0 {
 		handler = StripLeaveSlash(prefix, handler)
 	}
@@ -86,6 +81,13 @@ func Handler(prefix string, cfg *rest.Config, transport http.RoundTripper) (http
 	return handler, nil
 }
 
+func newProxy(target *url.URL, transport http.RoundTripper, upgradeTransport proxy.UpgradeRequestRoundTripper) *proxy.UpgradeAwareHandler {
+	proxy := proxy.NewUpgradeAwareHandler(target, transport, false, false, &responder{})
+	proxy.UpgradeTransport = upgradeTransport
+	proxy.UseRequestLocation = true
+	return proxy
+}
+
 // like http.StripPrefix, but always leaves an initial slash. (so that our
 // regexps will work.)
 func StripLeaveSlash(prefix string, h http.Handler) http.Handler {
@@ -104,8 +106,9 @@ func StripLeaveSlash(prefix string, h http.Handler) http.Handler {
 }
 
 // makeUpgradeTransport creates a transport that explicitly bypasses HTTP2 support
-// for proxy connections that must upgrade.
-func makeUpgradeTransport(config *rest.Config) (proxy.UpgradeRequestRoundTripper, error) {
+// for proxy connections that must upgrade. If idleTimeout is set, upgraded connections
+// are closed after they were idle for this duration.
+func makeUpgradeTransport(config *rest.Config, idleTimeout time.Duration) (proxy.UpgradeRequestRoundTripper, error) {
 	transportConfig, err := config.TransportConfig()
 	if err != nil {
 		return nil, err
@@ -114,12 +117,20 @@ func makeUpgradeTransport(config *rest.Config) (proxy.UpgradeRequestRoundTripper
 	if err != nil {
 		return nil, err
 	}
+	dial := (&net.Dialer{
+		// Timeout:   30 * time.Second,
+		KeepAlive: 120 * time.Second,
+	}).DialContext
+	if config.Dial != nil {
+		// e.g. dial through the tunnel server
+		dial = config.Dial
+	}
+	if idleTimeout > 0 {
+		dial = withIdleTimeout(dial, idleTimeout)
+	}
 	rt := utilnet.SetOldTransportDefaults(&http.Transport{
 		TLSClientConfig: tlsConfig,
-		DialContext: (&net.Dialer{
-			// Timeout:   30 * time.Second,
-			KeepAlive: 120 * time.Second,
-		}).DialContext,
+		DialContext:     dial,
 	})
 	upgrader, err := transport.HTTPWrappersForConfig(transportConfig, proxy.MirrorRequest)
 	if err != nil {
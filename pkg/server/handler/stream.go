@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	activeStreams = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vcluster_proxy_streams_active",
+		Help: "Number of upgraded streaming connections (exec, attach, port-forward) currently proxied by the syncer",
+	}, []string{"subresource"})
+	streamsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vcluster_proxy_streams_total",
+		Help: "Total number of upgraded streaming connections proxied by the syncer",
+	}, []string{"subresource"})
+	streamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vcluster_proxy_stream_duration_seconds",
+		Help:    "Duration of upgraded streaming connections proxied by the syncer",
+		Buckets: []float64{1, 10, 60, 300, 900, 3600, 4 * 3600, 24 * 3600},
+	}, []string{"subresource"})
+	idleClosedStreams = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "vcluster_proxy_streams_idle_closed_total",
+		Help: "Total number of upgraded streaming connections closed by the syncer because they were idle",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(activeStreams, streamsTotal, streamDuration, idleClosedStreams)
+}
+
+// StreamingHandler returns a handler that proxies requests to the api server of cfg, including
+// upgraded streaming requests such as exec, attach and port-forward over SPDY or WebSockets.
+// In contrast to Handler it is created once and shared by all requests, so the connections
+// of the underlying transport are reused. Upgraded connections without any traffic in either
+// direction for idleTimeout are closed, 0 disables the idle timeout.
+func StreamingHandler(cfg *rest.Config, idleTimeout time.Duration) (http.Handler, error) {
+	host := cfg.Host
+	if !strings.HasSuffix(host, "/") {
+		host = host + "/"
+	}
+	target, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := rest.TransportFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	upgradeTransport, err := makeUpgradeTransport(cfg, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return withStreamMetrics(newProxy(target, transport, upgradeTransport)), nil
+}
+
+func withStreamMetrics(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !httpstream.IsUpgradeRequest(req) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		subresource := "other"
+		if info, ok := request.RequestInfoFrom(req.Context()); ok && info.Subresource != "" {
+			subresource = info.Subresource
+		}
+
+		start := time.Now()
+		activeStreams.WithLabelValues(subresource).Inc()
+		streamsTotal.WithLabelValues(subresource).Inc()
+		defer func() {
+			activeStreams.WithLabelValues(subresource).Dec()
+			streamDuration.WithLabelValues(subresource).Observe(time.Since(start).Seconds())
+		}()
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+func withIdleTimeout(dial dialFunc, idleTimeout time.Duration) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return newIdleTimeoutConn(conn, idleTimeout), nil
+	}
+}
+
+// idleTimeoutConn closes the underlying connection if nothing was read or written for the timeout
+type idleTimeoutConn struct {
+	net.Conn
+
+	timeout      time.Duration
+	lastActivity atomic.Int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newIdleTimeoutConn(conn net.Conn, timeout time.Duration) *idleTimeoutConn {
+	c := &idleTimeoutConn{
+		Conn:    conn,
+		timeout: timeout,
+		done:    make(chan struct{}),
+	}
+	c.touch()
+	go c.watch()
+	return c
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.touch()
+	}
+	return n, err
+}
+
+func (c *idleTimeoutConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.touch()
+	}
+	return n, err
+}
+
+func (c *idleTimeoutConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	return c.Conn.Close()
+}
+
+func (c *idleTimeoutConn) touch() {
+	c.lastActivity.Store(time.Now().UnixNano())
+}
+
+func (c *idleTimeoutConn) watch() {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-timer.C:
+			idle := time.Since(time.Unix(0, c.lastActivity.Load()))
+			if idle < c.timeout {
+				timer.Reset(c.timeout - idle)
+				continue
+			}
+
+			klog.V(1).Infof("Closing streaming connection to %s after it was idle for %s", c.RemoteAddr(), idle.Round(time.Second))
+			idleClosedStreams.Inc()
+			_ = c.Close()
+			return
+		}
+	}
+}
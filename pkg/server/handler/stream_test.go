@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestIdleTimeoutConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	conn := newIdleTimeoutConn(client, 200*time.Millisecond)
+	go func() {
+		_, _ = io.Copy(server, server)
+	}()
+
+	// traffic keeps the connection open
+	buf := make([]byte, 4)
+	for i := 0; i < 3; i++ {
+		time.Sleep(100 * time.Millisecond)
+		_, err := conn.Write([]byte("ping"))
+		assert.NilError(t, err)
+		_, err = io.ReadFull(conn, buf)
+		assert.NilError(t, err)
+	}
+
+	// an idle connection is closed
+	select {
+	case <-conn.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("idle connection was not closed")
+	}
+	_, err := conn.Write([]byte("ping"))
+	assert.Equal(t, err, io.ErrClosedPipe)
+}
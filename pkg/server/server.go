@@ -18,6 +18,8 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes/nodeservice"
+	"github.com/loft-sh/vcluster/pkg/metricsapiservice"
+	"github.com/loft-sh/vcluster/pkg/operations"
 	"github.com/loft-sh/vcluster/pkg/server/cert"
 	"github.com/loft-sh/vcluster/pkg/server/filters"
 	"github.com/loft-sh/vcluster/pkg/server/handler"
@@ -26,10 +28,12 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"github.com/loft-sh/vcluster/pkg/util/serverhelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/loft-sh/vcluster/pkg/util/tunnel"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
@@ -54,6 +58,8 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	aggregatorapiserver "k8s.io/kube-aggregator/pkg/apiserver"
+	custommetricsv1beta2 "k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+	externalmetricsv1beta1 "k8s.io/metrics/pkg/apis/external_metrics/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -66,7 +72,12 @@ type Server struct {
 	currentNamespace       string
 	currentNamespaceClient client.Client
 
-	fakeKubeletIPs bool
+	fakeKubeletIPs      bool
+	operationsAPI       bool
+	discoveryCache      bool
+	customMetricsServer bool
+
+	proxiedAPIServices []schema.GroupVersion
 
 	certSyncer cert.Syncer
 	handler    *http.ServeMux
@@ -162,7 +173,10 @@ func NewServer(ctx *context2.ControllerContext, requestHeaderCaFile, clientCaFil
 		certSyncer:            certSyncer,
 		handler:               http.NewServeMux(),
 
-		fakeKubeletIPs: ctx.Options.FakeKubeletIPs,
+		fakeKubeletIPs:      ctx.Options.FakeKubeletIPs,
+		operationsAPI:       ctx.Options.OperationsAPI,
+		discoveryCache:      ctx.Options.DiscoveryCacheTTL > 0,
+		customMetricsServer: ctx.Options.ProxyCustomMetricsServer,
 
 		currentNamespace:       ctx.CurrentNamespace,
 		currentNamespaceClient: cachedLocalClient,
@@ -198,25 +212,87 @@ func NewServer(ctx *context2.ControllerContext, requestHeaderCaFile, clientCaFil
 		},
 	}
 
+	// the connections to the host api server for kubelet requests and the webhook calls of the
+	// syncer are dialed through the tunnel server if configured, the virtual api server dials
+	// through the same tunnel server with its egress selector configuration
+	kubeletConfig := localConfig
+	var tunnelDialer *tunnel.Dialer
+	if ctx.Options.TunnelServer != "" {
+		tunnelDialer, err = tunnel.NewDialer(ctx.Options.TunnelServer, ctx.Options.TunnelCAFile, ctx.Options.TunnelCertFile, ctx.Options.TunnelKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "create tunnel dialer")
+		}
+
+		kubeletConfig = tunnelDialer.WrapConfig(localConfig)
+	}
+
+	// kubelet requests share a single proxy handler, so connections to the host cluster are reused
+	kubeletHandler, err := handler.StreamingHandler(kubeletConfig, ctx.Options.StreamIdleTimeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "create kubelet proxy handler")
+	}
+
 	// init plugins
-	admissionHandler, err := initAdmission(ctx.Context, virtualConfig)
+	admissionHandler, err := initAdmission(ctx.Context, virtualConfig, tunnelDialer)
 	if err != nil {
 		return nil, errors.Wrap(err, "init admission")
 	}
 
 	h := handler.ImpersonatingHandler("", virtualConfig)
+	if ctx.Options.DiscoveryCacheTTL > 0 {
+		h, err = filters.WithDiscoveryCache(ctx.Context, h, virtualConfig, uncachedVirtualClient.Scheme(), uncachedVirtualClient.RESTMapper(), ctx.Options.DiscoveryCacheTTL)
+		if err != nil {
+			return nil, errors.Wrap(err, "create discovery cache")
+		}
+	}
 	h = filters.WithServiceCreateRedirect(h, uncachedLocalClient, uncachedVirtualClient, virtualConfig, ctx.Options.SyncLabels)
-	h = filters.WithRedirect(h, localConfig, uncachedLocalClient.Scheme(), uncachedVirtualClient, admissionHandler, s.redirectResources)
-	h = filters.WithMetricsProxy(h, localConfig, cachedVirtualClient)
+	h = filters.WithRedirect(h, kubeletHandler, uncachedLocalClient.Scheme(), uncachedVirtualClient, admissionHandler, s.redirectResources)
+	h = filters.WithMetricsProxy(h, kubeletHandler, cachedVirtualClient)
 
 	if ctx.Options.ProxyMetricsServer {
 		h = filters.WithMetricsServerProxy(ctx, h, cachedLocalClient, cachedVirtualClient, localConfig)
 	}
+	if ctx.Options.ProxyCustomMetricsServer {
+		h = filters.WithCustomMetricsServerProxy(h, cachedVirtualClient, localConfig)
+	}
+	proxiedAPIServices, err := metricsapiservice.ParseProxiedAPIServices(ctx.Options.ProxyAPIServices)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse proxied api services")
+	}
+	if ctx.Options.ProxyExternalMetricsServer {
+		// external metrics don't reference any objects, so they are passed through like any other api
+		proxiedAPIServices = append(proxiedAPIServices, externalmetricsv1beta1.SchemeGroupVersion)
+	}
+	if len(proxiedAPIServices) > 0 {
+		h = filters.WithAPIServiceProxy(h, cachedVirtualClient, localConfig, proxiedAPIServices)
+		s.proxiedAPIServices = proxiedAPIServices
+	}
+
+	if ctx.Options.OperationsAPI {
+		h = filters.WithOperations(h, ctx.Operations)
+	}
+
+	if ctx.Options.UserAnnotation != "" {
+		h = filters.WithUserAnnotation(h)
+	}
+
+	if ctx.Options.EnableScheduler || len(ctx.Options.ExternalSchedulers) > 0 {
+		h = filters.WithPodBinding(h, cachedVirtualClient)
+	}
+
+	if ctx.Options.SchedulerExtender {
+		h = filters.WithSchedulerExtender(ctx.Context, h, ctx.HostCache)
+	}
 
 	if ctx.Options.DeprecatedSyncNodeChanges {
 		h = filters.WithNodeChanges(ctx.Context, h, uncachedLocalClient, uncachedVirtualClient, virtualConfig)
 	}
-	h = filters.WithFakeKubelet(h, localConfig, cachedVirtualClient)
+	h = filters.WithFakeKubelet(h, kubeletHandler, cachedVirtualClient)
+
+	// exec audit wraps the node proxy and the fake kubelet, so exec through the kubelet api is covered as well
+	if ctx.Options.AuditExec || ctx.Options.ExecRequireAnnotation {
+		h = filters.WithExecAudit(h, cachedVirtualClient, uncachedVirtualClient, ctx.Options.AuditExec, ctx.Options.ExecRequireAnnotation)
+	}
 	h = filters.WithK3sConnect(h)
 
 	if os.Getenv("DEBUG") == "true" {
@@ -255,9 +331,40 @@ func (s *Server) ServeOnListenerTLS(address string, port int, stopChan <-chan st
 		},
 	}
 	redirectAuthResources = append(redirectAuthResources, s.redirectResources...)
+	if s.operationsAPI {
+		// the operations api is served by the syncer, so it has to be authorized against the virtual cluster rbac
+		redirectAuthResources = append(redirectAuthResources, delegatingauthorizer.GroupVersionResourceVerb{
+			GroupVersionResource: schema.GroupVersionResource{Group: operations.GroupName, Version: "*", Resource: "*"},
+			Verb:                 "*",
+			SubResource:          "*",
+		})
+	}
+	if s.customMetricsServer {
+		// custom metrics are requested with the permissions of the syncer, so they have to be authorized against the virtual cluster rbac
+		redirectAuthResources = append(redirectAuthResources, delegatingauthorizer.GroupVersionResourceVerb{
+			GroupVersionResource: schema.GroupVersionResource{Group: custommetricsv1beta2.SchemeGroupVersion.Group, Version: "*", Resource: "*"},
+			Verb:                 "*",
+			SubResource:          "*",
+		})
+	}
+	for _, groupVersion := range s.proxiedAPIServices {
+		// proxied apis are requested with the permissions of the syncer, so they have to be authorized against the virtual cluster rbac
+		redirectAuthResources = append(redirectAuthResources, delegatingauthorizer.GroupVersionResourceVerb{
+			GroupVersionResource: groupVersion.WithResource("*"),
+			Verb:                 "*",
+			SubResource:          "*",
+		})
+	}
+	redirectAuthNonResources := []delegatingauthorizer.PathVerb{}
+	if s.discoveryCache {
+		// cached discovery documents never reach the virtual cluster, so they have to be authorized against its rbac
+		for _, path := range filters.NonResourceDiscoveryPaths {
+			redirectAuthNonResources = append(redirectAuthNonResources, delegatingauthorizer.PathVerb{Path: path, Verb: "get"})
+		}
+	}
 	serverConfig.Authorization.Authorizer = union.New(
 		kubeletauthorizer.New(s.uncachedVirtualClient),
-		delegatingauthorizer.New(s.uncachedVirtualClient, redirectAuthResources, nil),
+		delegatingauthorizer.New(s.uncachedVirtualClient, redirectAuthResources, redirectAuthNonResources),
 		impersonationauthorizer.New(s.uncachedVirtualClient),
 		allowall.New(),
 	)
@@ -437,7 +544,7 @@ func WithOriginalUser(h http.Handler) http.Handler {
 	})
 }
 
-func initAdmission(ctx context.Context, vConfig *rest.Config) (admission.Interface, error) {
+func initAdmission(ctx context.Context, vConfig *rest.Config, tunnelDialer *tunnel.Dialer) (admission.Interface, error) {
 	vClient, err := kubernetes.NewForConfig(vConfig)
 	if err != nil {
 		return nil, err
@@ -449,7 +556,8 @@ func initAdmission(ctx context.Context, vConfig *rest.Config) (admission.Interfa
 	)
 	authInfoResolverWrapper := func(resolver webhook.AuthenticationInfoResolver) webhook.AuthenticationInfoResolver {
 		return &kubeConfigProvider{
-			vConfig: vConfig,
+			vConfig:      vConfig,
+			tunnelDialer: tunnelDialer,
 		}
 	}
 
@@ -477,7 +585,8 @@ func initAdmission(ctx context.Context, vConfig *rest.Config) (admission.Interfa
 }
 
 type kubeConfigProvider struct {
-	vConfig *rest.Config
+	vConfig      *rest.Config
+	tunnelDialer *tunnel.Dialer
 }
 
 func (c *kubeConfigProvider) ClientConfigFor(hostPort string) (*rest.Config, error) {
@@ -494,7 +603,11 @@ func (c *kubeConfigProvider) clientConfig(target string) (*rest.Config, error) {
 	}
 
 	// anonymous
-	return setGlobalDefaults(&rest.Config{}), nil
+	config := &rest.Config{}
+	if c.tunnelDialer != nil {
+		config.Dial = c.tunnelDialer.DialContext
+	}
+	return setGlobalDefaults(config), nil
 }
 
 func setGlobalDefaults(config *rest.Config) *rest.Config {
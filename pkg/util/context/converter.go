@@ -17,5 +17,10 @@ func ToRegisterContext(ctx *context.ControllerContext) *synccontext.RegisterCont
 
 		VirtualManager:  ctx.VirtualManager,
 		PhysicalManager: ctx.LocalManager,
+
+		Operations: ctx.Operations,
+		HostCache:  ctx.HostCache,
+
+		ResourceNames: ctx.ResourceNames,
 	}
 }
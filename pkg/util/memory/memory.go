@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ballast is a large allocation that is never written to. It raises the heap size the garbage
+// collector targets without using physical memory, so a syncer with a small live heap doesn't
+// run the garbage collector all the time.
+var ballast []byte
+
+// Configure applies the garbage collector settings of the syncer. A gcPercent of 0 and empty
+// limits keep the GOGC and GOMEMLIMIT environment variables or the go defaults.
+func Configure(gcPercent int, memoryLimit, memoryBallast string) error {
+	if gcPercent != 0 {
+		debug.SetGCPercent(gcPercent)
+		klog.Infof("Set garbage collector target percentage to %d", gcPercent)
+	}
+
+	if memoryLimit != "" {
+		limit, err := resource.ParseQuantity(memoryLimit)
+		if err != nil {
+			return fmt.Errorf("parse memory limit %s: %w", memoryLimit, err)
+		} else if limit.Value() <= 0 {
+			return fmt.Errorf("memory limit %s must be greater than 0", memoryLimit)
+		}
+
+		debug.SetMemoryLimit(limit.Value())
+		klog.Infof("Set soft memory limit to %s", memoryLimit)
+	}
+
+	if memoryBallast != "" {
+		size, err := resource.ParseQuantity(memoryBallast)
+		if err != nil {
+			return fmt.Errorf("parse memory ballast %s: %w", memoryBallast, err)
+		} else if size.Value() < 0 {
+			return fmt.Errorf("memory ballast %s must not be negative", memoryBallast)
+		}
+
+		ballast = make([]byte, size.Value())
+		klog.Infof("Allocated memory ballast of %s", memoryBallast)
+	}
+
+	return nil
+}
+
+// StripManagedFields is a cache transform that removes the managed fields of cached objects,
+// which are never read by the syncer but often make up a large part of an object
+func StripManagedFields(obj interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// not an object, e.g. a deleted final state unknown tombstone
+		return obj, nil
+	}
+
+	accessor.SetManagedFields(nil)
+	return obj, nil
+}
+
+// UncachedObjects returns the objects of the given kinds that should be read directly from the
+// api server instead of a cache. Core kinds are given by name such as Secret, all other kinds
+// with their group such as Ingress.networking.k8s.io. Rarely used resources that aren't watched
+// by any controller are never loaded into memory this way.
+func UncachedObjects(scheme *runtime.Scheme, kinds []string) ([]client.Object, error) {
+	objects := []client.Object{}
+	for _, kind := range kinds {
+		name, group, _ := strings.Cut(kind, ".")
+
+		found := false
+		for gvk := range scheme.AllKnownTypes() {
+			if gvk.Kind != name || gvk.Group != group || gvk.Version == runtime.APIVersionInternal {
+				continue
+			}
+
+			obj, err := scheme.New(gvk)
+			if err != nil {
+				return nil, err
+			}
+
+			clientObj, ok := obj.(client.Object)
+			if !ok {
+				continue
+			}
+
+			objects = append(objects, clientObj)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown kind %s", kind)
+		}
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUncachedObjects(t *testing.T) {
+	testCases := []struct {
+		name  string
+		kinds []string
+
+		expectedErr   bool
+		expectedKinds []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:          "core kind",
+			kinds:         []string{"Secret"},
+			expectedKinds: []string{"Secret"},
+		},
+		{
+			name:          "all versions of a kind with group",
+			kinds:         []string{"Ingress.networking.k8s.io"},
+			expectedKinds: []string{"Ingress", "Ingress"},
+		},
+		{
+			name:        "kind in wrong group",
+			kinds:       []string{"Ingress"},
+			expectedErr: true,
+		},
+		{
+			name:        "unknown kind",
+			kinds:       []string{"Unknown"},
+			expectedErr: true,
+		},
+	}
+
+	scheme := testingutil.NewScheme()
+	for _, testCase := range testCases {
+		objects, err := UncachedObjects(scheme, testCase.kinds)
+		if testCase.expectedErr {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Equal(t, len(objects), len(testCase.expectedKinds), "unexpected objects in test case %s", testCase.name)
+		for i, obj := range objects {
+			gvks, _, err := scheme.ObjectKinds(obj)
+			assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+			assert.Equal(t, gvks[0].Kind, testCase.expectedKinds[i], "unexpected kind in test case %s", testCase.name)
+		}
+	}
+}
+
+func TestStripManagedFields(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:          "test",
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+	}}
+
+	obj, err := StripManagedFields(pod)
+	assert.NilError(t, err)
+	assert.Equal(t, obj.(*corev1.Pod).Name, "test")
+	assert.Assert(t, obj.(*corev1.Pod).ManagedFields == nil)
+
+	// tombstones are passed through
+	obj, err = StripManagedFields("tombstone")
+	assert.NilError(t, err)
+	assert.Equal(t, obj, "tombstone")
+}
@@ -0,0 +1,30 @@
+package namemapping
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse parses mappings in the form virtual=host and returns the host names by virtual name.
+// A virtual name may only be mapped to one host name. If reversible is set, a host name may
+// also only be mapped from one virtual name, so the mapping can be applied in both directions.
+func Parse(mappings []string, reversible bool) (map[string]string, error) {
+	out := map[string]string{}
+	virtualNames := map[string]string{}
+	for _, mapping := range mappings {
+		virtualName, hostName, ok := strings.Cut(mapping, "=")
+		virtualName, hostName = strings.TrimSpace(virtualName), strings.TrimSpace(hostName)
+		if !ok || virtualName == "" || hostName == "" {
+			return nil, fmt.Errorf("incorrect format, expected: virtual=host got: %s", mapping)
+		} else if other, ok := out[virtualName]; ok && other != hostName {
+			return nil, fmt.Errorf("%s is mapped to both %s and %s", virtualName, other, hostName)
+		} else if other, ok := virtualNames[hostName]; reversible && ok && other != virtualName {
+			return nil, fmt.Errorf("%s is mapped from both %s and %s", hostName, other, virtualName)
+		}
+
+		out[virtualName] = hostName
+		virtualNames[hostName] = virtualName
+	}
+
+	return out, nil
+}
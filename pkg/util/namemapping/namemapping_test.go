@@ -0,0 +1,71 @@
+package namemapping
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mappings   []string
+		reversible bool
+
+		expectedMapping map[string]string
+		expectedError   string
+	}{
+		{
+			name:            "No mappings",
+			expectedMapping: map[string]string{},
+		},
+		{
+			name:            "Mappings",
+			mappings:        []string{"fast=premium-ssd", " slow = standard "},
+			expectedMapping: map[string]string{"fast": "premium-ssd", "slow": "standard"},
+		},
+		{
+			name:            "Duplicate mapping",
+			mappings:        []string{"fast=premium-ssd", "fast=premium-ssd"},
+			reversible:      true,
+			expectedMapping: map[string]string{"fast": "premium-ssd"},
+		},
+		{
+			name:          "Missing host name",
+			mappings:      []string{"fast="},
+			expectedError: "incorrect format, expected: virtual=host got: fast=",
+		},
+		{
+			name:          "Missing separator",
+			mappings:      []string{"fast"},
+			expectedError: "incorrect format, expected: virtual=host got: fast",
+		},
+		{
+			name:          "Virtual name mapped twice",
+			mappings:      []string{"fast=premium-ssd", "fast=standard"},
+			expectedError: "fast is mapped to both premium-ssd and standard",
+		},
+		{
+			name:            "Host name mapped twice",
+			mappings:        []string{"fast=premium-ssd", "faster=premium-ssd"},
+			expectedMapping: map[string]string{"fast": "premium-ssd", "faster": "premium-ssd"},
+		},
+		{
+			name:          "Host name mapped twice reversible",
+			mappings:      []string{"fast=premium-ssd", "faster=premium-ssd"},
+			reversible:    true,
+			expectedError: "premium-ssd is mapped from both fast and faster",
+		},
+	}
+
+	for _, testCase := range testCases {
+		mapping, err := Parse(testCase.mappings, testCase.reversible)
+		if testCase.expectedError != "" {
+			assert.Error(t, err, testCase.expectedError, "unexpected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, mapping, testCase.expectedMapping)
+	}
+}
@@ -0,0 +1,119 @@
+package nodepools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/toleration"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// Label is set on virtual nodes to the name of their node pool. Pods select a pool with this label in
+// their node selector.
+const Label = "vcluster.loft.sh/node-pool"
+
+// Pool groups host nodes by their labels, so they can be offered as a separate capacity tier in the
+// virtual cluster
+type Pool struct {
+	// Name of the pool, used as value of the node pool label
+	Name string `json:"name"`
+
+	// NodeSelector selects the host nodes of the pool by their labels
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Labels are added to the virtual nodes of the pool
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Tolerations are added to the host pods of the pool, in the form of --enforce-toleration
+	Tolerations []string `json:"tolerations,omitempty"`
+
+	// HiddenTaints are hidden from the virtual nodes of the pool, in the form of --hide-node-taint.
+	// They apply before the global ones.
+	HiddenTaints []string `json:"hiddenTaints,omitempty"`
+
+	// AllocatableFactors replace the global factors for the virtual nodes of the pool, in the form of
+	// --node-allocatable-factor
+	AllocatableFactors []string `json:"allocatableFactors,omitempty"`
+
+	tolerations []corev1.Toleration
+}
+
+// Pools is an ordered list of node pools, the first matching pool of a node wins
+type Pools []*Pool
+
+// Parse parses the pools from their yaml or json representation. Returns nil if there are no pools.
+func Parse(rawPools string) (Pools, error) {
+	if strings.TrimSpace(rawPools) == "" {
+		return nil, nil
+	}
+
+	pools := Pools{}
+	err := yaml.UnmarshalStrict([]byte(rawPools), &pools)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node pools: %w", err)
+	}
+
+	names := map[string]bool{}
+	for _, pool := range pools {
+		if errs := validation.IsValidLabelValue(pool.Name); pool.Name == "" || len(errs) > 0 {
+			return nil, fmt.Errorf("invalid node pool name %q: %s", pool.Name, strings.Join(errs, ", "))
+		} else if names[pool.Name] {
+			return nil, fmt.Errorf("node pool %s is defined more than once", pool.Name)
+		} else if len(pool.NodeSelector) == 0 {
+			return nil, fmt.Errorf("node pool %s needs a node selector", pool.Name)
+		}
+		names[pool.Name] = true
+
+		for _, t := range pool.Tolerations {
+			tol, err := toleration.ParseToleration(t)
+			if err != nil {
+				return nil, fmt.Errorf("invalid toleration %s of node pool %s: %w", t, pool.Name, err)
+			}
+
+			pool.tolerations = append(pool.tolerations, tol)
+		}
+	}
+
+	return pools, nil
+}
+
+// ForNode returns the first pool that selects the host node labels
+func (p Pools) ForNode(nodeLabels map[string]string) *Pool {
+	for _, pool := range p {
+		if labels.SelectorFromSet(pool.NodeSelector).Matches(labels.Set(nodeLabels)) {
+			return pool
+		}
+	}
+
+	return nil
+}
+
+// ByName returns the pool with the name
+func (p Pools) ByName(name string) *Pool {
+	for _, pool := range p {
+		if pool.Name == name {
+			return pool
+		}
+	}
+
+	return nil
+}
+
+// VirtualLabels returns the labels of the virtual nodes of the pool, including the node pool label
+func (p *Pool) VirtualLabels() map[string]string {
+	virtualLabels := map[string]string{}
+	for k, v := range p.Labels {
+		virtualLabels[k] = v
+	}
+	virtualLabels[Label] = p.Name
+
+	return virtualLabels
+}
+
+// ParsedTolerations returns the tolerations of the pool
+func (p *Pool) ParsedTolerations() []corev1.Toleration {
+	return p.tolerations
+}
@@ -0,0 +1,102 @@
+package nodepools
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rawPools      string
+		expectedNames []string
+		expectedErr   string
+	}{
+		{
+			name: "No pools",
+		},
+		{
+			name: "Yaml",
+			rawPools: `
+- name: standard
+  nodeSelector:
+    node.kubernetes.io/instance-type: m5.xlarge
+- name: gpu
+  nodeSelector:
+    example.com/gpu: "true"
+  tolerations:
+  - nvidia.com/gpu:NoSchedule
+`,
+			expectedNames: []string{"standard", "gpu"},
+		},
+		{
+			name:          "Json",
+			rawPools:      `[{"name":"gpu","nodeSelector":{"example.com/gpu":"true"},"labels":{"tier":"premium"}}]`,
+			expectedNames: []string{"gpu"},
+		},
+		{
+			name:        "Missing node selector",
+			rawPools:    `[{"name":"gpu"}]`,
+			expectedErr: "node pool gpu needs a node selector",
+		},
+		{
+			name:        "Duplicate pool",
+			rawPools:    `[{"name":"gpu","nodeSelector":{"a":"b"}},{"name":"gpu","nodeSelector":{"c":"d"}}]`,
+			expectedErr: "node pool gpu is defined more than once",
+		},
+		{
+			name:        "Invalid name",
+			rawPools:    `[{"name":"gpu pool","nodeSelector":{"a":"b"}}]`,
+			expectedErr: "invalid node pool name \"gpu pool\"",
+		},
+		{
+			name:        "Unknown field",
+			rawPools:    `[{"name":"gpu","selector":{"a":"b"}}]`,
+			expectedErr: "unknown field \"selector\"",
+		},
+	}
+
+	for _, testCase := range testCases {
+		pools, err := Parse(testCase.rawPools)
+		if testCase.expectedErr != "" {
+			assert.ErrorContains(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		names := []string{}
+		for _, pool := range pools {
+			names = append(names, pool.Name)
+		}
+		if testCase.expectedNames == nil {
+			testCase.expectedNames = []string{}
+		}
+		assert.DeepEqual(t, names, testCase.expectedNames)
+	}
+}
+
+func TestForNode(t *testing.T) {
+	pools, err := Parse(`
+- name: gpu
+  nodeSelector:
+    example.com/gpu: "true"
+  labels:
+    tier: premium
+  tolerations:
+  - nvidia.com/gpu:NoSchedule
+- name: standard
+  nodeSelector:
+    example.com/pool: standard
+`)
+	assert.NilError(t, err)
+
+	pool := pools.ForNode(map[string]string{"example.com/gpu": "true", "example.com/pool": "standard"})
+	assert.Equal(t, pool.Name, "gpu", "first matching pool")
+	assert.DeepEqual(t, pool.VirtualLabels(), map[string]string{Label: "gpu", "tier": "premium"})
+	assert.DeepEqual(t, pool.ParsedTolerations(), []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}})
+	assert.Equal(t, pools.ForNode(map[string]string{"example.com/pool": "standard"}).Name, "standard")
+	assert.Assert(t, pools.ForNode(map[string]string{"example.com/pool": "other"}) == nil)
+	assert.Assert(t, pools.ByName("other") == nil)
+}
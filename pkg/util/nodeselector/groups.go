@@ -0,0 +1,67 @@
+package nodeselector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Group is a node selector of a set of host nodes pods are allowed to run on. Pods prefer the nodes
+// of groups with a higher weight.
+type Group struct {
+	// Weight between 1 and 100 the nodes of the group are preferred with, 0 if the group is not preferred
+	Weight int32
+
+	// Selector selects the host nodes of the group
+	Selector *metav1.LabelSelector
+}
+
+// ParseGroups parses node selector groups in the form [weight:]selector, e.g. 100:pool=a
+func ParseGroups(rawGroups []string) ([]Group, error) {
+	groups := []Group{}
+	for _, rawGroup := range rawGroups {
+		group := Group{}
+		rawSelector := rawGroup
+		if rawWeight, selector, found := strings.Cut(rawGroup, ":"); found {
+			weight, err := strconv.ParseInt(rawWeight, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid node selector group %s: invalid weight %s", rawGroup, rawWeight)
+			} else if weight < 0 || weight > 100 {
+				return nil, fmt.Errorf("invalid node selector group %s: weight has to be between 0 and 100", rawGroup)
+			}
+
+			group.Weight = int32(weight)
+			rawSelector = selector
+		}
+
+		selector, err := metav1.ParseToLabelSelector(rawSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node selector group %s: %w", rawGroup, err)
+		} else if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+			return nil, fmt.Errorf("invalid node selector group %s: at least one requirement has to be defined", rawGroup)
+		}
+
+		group.Selector = selector
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// Selectors returns the label selectors of the groups
+func Selectors(groups []Group) ([]labels.Selector, error) {
+	selectors := make([]labels.Selector, 0, len(groups))
+	for _, group := range groups {
+		selector, err := metav1.LabelSelectorAsSelector(group.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, selector)
+	}
+
+	return selectors, nil
+}
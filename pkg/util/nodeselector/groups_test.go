@@ -0,0 +1,68 @@
+package nodeselector
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestParseGroups(t *testing.T) {
+	testCases := []struct {
+		name            string
+		rawGroups       []string
+		expectedWeights []int32
+		expectedErr     string
+	}{
+		{
+			name:            "No groups",
+			expectedWeights: []int32{},
+		},
+		{
+			name:            "Weighted groups",
+			rawGroups:       []string{"100:pool=a", "10:pool in (b,c)", "pool=d"},
+			expectedWeights: []int32{100, 10, 0},
+		},
+		{
+			name:        "Invalid weight",
+			rawGroups:   []string{"high:pool=a"},
+			expectedErr: "invalid node selector group high:pool=a: invalid weight high",
+		},
+		{
+			name:        "Weight out of range",
+			rawGroups:   []string{"200:pool=a"},
+			expectedErr: "invalid node selector group 200:pool=a: weight has to be between 0 and 100",
+		},
+		{
+			name:        "Empty selector",
+			rawGroups:   []string{"100:"},
+			expectedErr: "invalid node selector group 100:: at least one requirement has to be defined",
+		},
+	}
+
+	for _, testCase := range testCases {
+		groups, err := ParseGroups(testCase.rawGroups)
+		if testCase.expectedErr != "" {
+			assert.ErrorContains(t, err, testCase.expectedErr, "unexpected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		weights := []int32{}
+		for _, group := range groups {
+			weights = append(weights, group.Weight)
+		}
+		assert.DeepEqual(t, weights, testCase.expectedWeights)
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	groups, err := ParseGroups([]string{"100:pool=a", "10:pool in (b,c)"})
+	assert.NilError(t, err)
+	selectors, err := Selectors(groups)
+	assert.NilError(t, err)
+	assert.Equal(t, len(selectors), 2)
+	assert.Assert(t, selectors[0].Matches(labels.Set{"pool": "a"}))
+	assert.Assert(t, !selectors[0].Matches(labels.Set{"pool": "b"}))
+	assert.Assert(t, selectors[1].Matches(labels.Set{"pool": "c"}))
+}
@@ -0,0 +1,25 @@
+package podhelper
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsPodTerminated returns true if the pod has reached a terminal phase. Its containers won't be
+// restarted anymore, so the pod does not need the secrets and config maps it references
+func IsPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// IndexReferencesOfRunningPods returns an indexer that indexes pods by the names of the objects they
+// reference. Terminated pods don't reference anything, so they don't keep these objects synced.
+func IndexReferencesOfRunningPods(references func(pod *corev1.Pod) []string) client.IndexerFunc {
+	return func(rawObj client.Object) []string {
+		pod := rawObj.(*corev1.Pod)
+		if IsPodTerminated(pod) {
+			return nil
+		}
+
+		return references(pod)
+	}
+}
@@ -0,0 +1,44 @@
+package podhelper
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIndexReferencesOfRunningPods(t *testing.T) {
+	indexer := IndexReferencesOfRunningPods(func(pod *corev1.Pod) []string {
+		return []string{"referenced"}
+	})
+
+	testCases := []struct {
+		phase              corev1.PodPhase
+		expectedReferences []string
+	}{
+		{
+			phase:              corev1.PodPending,
+			expectedReferences: []string{"referenced"},
+		},
+		{
+			phase:              corev1.PodRunning,
+			expectedReferences: []string{"referenced"},
+		},
+		{
+			phase:              corev1.PodUnknown,
+			expectedReferences: []string{"referenced"},
+		},
+		{
+			phase: corev1.PodSucceeded,
+		},
+		{
+			phase: corev1.PodFailed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		pod := &corev1.Pod{Status: corev1.PodStatus{Phase: testCase.phase}}
+		assert.Assert(t, cmp.DeepEqual(indexer(pod), testCase.expectedReferences), "unexpected references of %s pod", testCase.phase)
+	}
+}
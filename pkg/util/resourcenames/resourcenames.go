@@ -0,0 +1,124 @@
+package resourcenames
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/namemapping"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Mapping maps extended resource names of the virtual cluster to the ones of the host cluster,
+// e.g. vendor.example/gpu to nvidia.com/gpu
+type Mapping struct {
+	toPhysical map[corev1.ResourceName]corev1.ResourceName
+	toVirtual  map[corev1.ResourceName]corev1.ResourceName
+}
+
+// Parse parses mappings in the form virtual=physical. Returns nil if there are no mappings.
+func Parse(mappings []string) (*Mapping, error) {
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+
+	m := &Mapping{
+		toPhysical: map[corev1.ResourceName]corev1.ResourceName{},
+		toVirtual:  map[corev1.ResourceName]corev1.ResourceName{},
+	}
+	names, err := namemapping.Parse(mappings, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for virtualName, physicalName := range names {
+		for _, name := range []string{virtualName, physicalName} {
+			err := validateExtendedResourceName(corev1.ResourceName(name))
+			if err != nil {
+				return nil, fmt.Errorf("invalid resource name mapping %s=%s: %w", virtualName, physicalName, err)
+			}
+		}
+
+		m.toPhysical[corev1.ResourceName(virtualName)] = corev1.ResourceName(physicalName)
+		m.toVirtual[corev1.ResourceName(physicalName)] = corev1.ResourceName(virtualName)
+	}
+
+	return m, nil
+}
+
+// ToPhysical returns a copy of resources with the virtual resource names replaced
+func (m *Mapping) ToPhysical(resources corev1.ResourceList) corev1.ResourceList {
+	if m == nil {
+		return resources
+	}
+
+	return mapResources(resources, m.toPhysical)
+}
+
+// ToVirtual returns a copy of resources with the physical resource names replaced
+func (m *Mapping) ToVirtual(resources corev1.ResourceList) corev1.ResourceList {
+	if m == nil {
+		return resources
+	}
+
+	return mapResources(resources, m.toVirtual)
+}
+
+// PodToPhysical replaces the virtual resource names in the container resources of pod
+func (m *Mapping) PodToPhysical(pod *corev1.Pod) {
+	if m == nil {
+		return
+	}
+
+	mapPod(pod, m.toPhysical)
+}
+
+// PodToVirtual returns a copy of pod with the physical resource names in the container resources
+// replaced
+func (m *Mapping) PodToVirtual(pod *corev1.Pod) *corev1.Pod {
+	if m == nil {
+		return pod
+	}
+
+	pod = pod.DeepCopy()
+	mapPod(pod, m.toVirtual)
+	return pod
+}
+
+func mapPod(pod *corev1.Pod, names map[corev1.ResourceName]corev1.ResourceName) {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			containers[i].Resources.Requests = mapResources(containers[i].Resources.Requests, names)
+			containers[i].Resources.Limits = mapResources(containers[i].Resources.Limits, names)
+		}
+	}
+}
+
+func mapResources(resources corev1.ResourceList, names map[corev1.ResourceName]corev1.ResourceName) corev1.ResourceList {
+	if resources == nil {
+		return nil
+	}
+
+	newResources := corev1.ResourceList{}
+	for name, quantity := range resources {
+		if newName, ok := names[name]; ok {
+			name = newName
+		}
+
+		newResources[name] = quantity.DeepCopy()
+	}
+
+	return newResources
+}
+
+// validateExtendedResourceName checks that name is a domain prefixed resource name outside of
+// the kubernetes.io domain, as only those can be served by device plugins
+func validateExtendedResourceName(name corev1.ResourceName) error {
+	if !strings.Contains(string(name), "/") || strings.Contains(string(name), corev1.ResourceDefaultNamespacePrefix) || strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix) {
+		return fmt.Errorf("%s is not an extended resource name", name)
+	} else if errs := validation.IsQualifiedName(string(name)); len(errs) > 0 {
+		return fmt.Errorf("%s is not a valid resource name: %s", name, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
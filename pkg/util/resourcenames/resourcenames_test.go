@@ -0,0 +1,72 @@
+package resourcenames
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestParse(t *testing.T) {
+	mapping, err := Parse(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, mapping == nil)
+
+	_, err = Parse([]string{"vendor.example/gpu=nvidia.com/gpu"})
+	assert.NilError(t, err)
+
+	for _, invalid := range []string{
+		"vendor.example/gpu",
+		"cpu=nvidia.com/gpu",
+		"vendor.example/gpu=kubernetes.io/gpu",
+		"requests.vendor.example/gpu=nvidia.com/gpu",
+		"vendor.example/gpu=nvidia.com/gp u",
+	} {
+		_, err = Parse([]string{invalid})
+		assert.Assert(t, err != nil, "expected error for %s", invalid)
+	}
+
+	_, err = Parse([]string{"vendor.example/gpu=nvidia.com/gpu", "vendor.example/gpu=amd.com/gpu"})
+	assert.ErrorContains(t, err, "is mapped to both")
+}
+
+func TestMapping(t *testing.T) {
+	mapping, err := Parse([]string{"vendor.example/gpu=nvidia.com/gpu"})
+	assert.NilError(t, err)
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							"vendor.example/gpu": resource.MustParse("1"),
+							corev1.ResourceCPU:   resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+	}
+	mapping.PodToPhysical(pod)
+	assert.DeepEqual(t, pod.Spec.Containers[0].Resources.Limits, corev1.ResourceList{
+		"nvidia.com/gpu":   resource.MustParse("1"),
+		corev1.ResourceCPU: resource.MustParse("1"),
+	})
+	assert.Assert(t, pod.Spec.Containers[0].Resources.Requests == nil)
+
+	vPod := mapping.PodToVirtual(pod)
+	assert.DeepEqual(t, vPod.Spec.Containers[0].Resources.Limits, corev1.ResourceList{
+		"vendor.example/gpu": resource.MustParse("1"),
+		corev1.ResourceCPU:   resource.MustParse("1"),
+	})
+
+	capacity := mapping.ToVirtual(corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("4")})
+	assert.DeepEqual(t, capacity, corev1.ResourceList{"vendor.example/gpu": resource.MustParse("4")})
+
+	// a nil mapping keeps the names
+	var noMapping *Mapping
+	assert.DeepEqual(t, noMapping.ToVirtual(capacity), capacity)
+}
@@ -23,6 +23,12 @@ const (
 
 	ErrorMessageFind = "The range of valid IPs is "
 	FallbackCIDR     = "10.96.0.0/12"
+
+	// APIServerNamespace and APIServerLabelSelector select the kube-apiserver pods of the host
+	// cluster, which are static pods in clusters created by kubeadm
+	APIServerNamespace     = "kube-system"
+	APIServerLabelSelector = "component=kube-apiserver"
+	APIServerCIDRFlag      = "--service-cluster-ip-range"
 )
 
 func GetCIDRConfigMapName(vclusterName string) string {
@@ -50,7 +56,7 @@ func EnsureServiceCIDRConfigmap(ctx context.Context, workspaceNamespaceClient, c
 	cidrData, ok := cm.Data[CIDRConfigMapKey]
 	// do nothing if a valid CIDR is already present in the expected Configmap data key
 	if exists && ok {
-		_, _, err = net.ParseCIDR(cidrData)
+		_, err = ParseCIDRs(cidrData)
 		if err == nil {
 			return cidrData, err
 		}
@@ -100,6 +106,13 @@ func EnsureServiceCIDRInK0sSecret(ctx context.Context, workspaceNamespaceClient,
 	if warning != "" {
 		klog.Info(warning)
 	}
+	// k0s expects the IPv6 service CIDR in a separate dual-stack config, which vcluster doesn't
+	// generate, so the virtual cluster is single-stack with the primary service CIDR. This is
+	// documented as a limitation of the k0s distro
+	if primary, secondary, found := strings.Cut(cidr, ","); found {
+		klog.Warningf("the host cluster is dual-stack, but k0s supports only a single service CIDR, so the virtual cluster uses %s and services in it can't get cluster ips of %s", primary, secondary)
+		cidr = primary
+	}
 	newData := strings.ReplaceAll(string(configData), K0sCIDRPlaceHolder, cidr)
 
 	originalObject := secret.DeepCopy()
@@ -117,7 +130,35 @@ func EnsureServiceCIDRInK0sSecret(ctx context.Context, workspaceNamespaceClient,
 	return nil
 }
 
+// ParseCIDRs parses a single-stack or dual-stack service CIDR, e.g. 10.96.0.0/12,fd00::/108
+func ParseCIDRs(cidrs string) ([]*net.IPNet, error) {
+	parsed := []*net.IPNet{}
+	for _, cidr := range strings.Split(cidrs, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, ipNet)
+	}
+	if len(parsed) > 2 {
+		return nil, fmt.Errorf("expected at most two service CIDRs, got %d", len(parsed))
+	} else if len(parsed) == 2 && (parsed[0].IP.To4() == nil) == (parsed[1].IP.To4() == nil) {
+		return nil, fmt.Errorf("dual-stack service CIDRs %s need to be of different ip families", cidrs)
+	}
+
+	return parsed, nil
+}
+
 func GetServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace string) (string, string) {
+	// the flags of the kube-apiserver are the most reliable source, but they are usually only
+	// readable with cluster admin permissions
+	cidr, err := getServiceCIDRFromAPIServer(ctx, client)
+	if err == nil {
+		return cidr, ""
+	}
+	klog.V(1).Infof("couldn't read service CIDR from the kube-apiserver flags, will detect it by creating a service: %v", err)
+
 	ipv4CIDR, ipv4Err := getServiceCIDR(ctx, client, namespace, false)
 	ipv6CIDR, ipv6Err := getServiceCIDR(ctx, client, namespace, true)
 	if ipv4Err != nil && ipv6Err != nil {
@@ -168,6 +209,39 @@ func GetServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace
 	return fmt.Sprintf("%s,%s", ipv4CIDR, ipv6CIDR), "failed to find host cluster default Service IP family, defaulting to IPv4 family"
 }
 
+// getServiceCIDRFromAPIServer reads the service CIDR from the --service-cluster-ip-range flag of the
+// kube-apiserver pods in the host cluster
+func getServiceCIDRFromAPIServer(ctx context.Context, client kubernetes.Interface) (string, error) {
+	pods, err := client.CoreV1().Pods(APIServerNamespace).List(ctx, metav1.ListOptions{LabelSelector: APIServerLabelSelector})
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			args := append(append([]string{}, container.Command...), container.Args...)
+			for i, arg := range args {
+				cidr := ""
+				if strings.HasPrefix(arg, APIServerCIDRFlag+"=") {
+					cidr = strings.TrimPrefix(arg, APIServerCIDRFlag+"=")
+				} else if arg == APIServerCIDRFlag && i+1 < len(args) {
+					cidr = args[i+1]
+				} else {
+					continue
+				}
+
+				_, err = ParseCIDRs(cidr)
+				if err != nil {
+					return "", fmt.Errorf("invalid %s flag of kube-apiserver pod %s/%s: %v", APIServerCIDRFlag, pod.Namespace, pod.Name, err)
+				}
+				return cidr, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no kube-apiserver pod with a %s flag found", APIServerCIDRFlag)
+}
+
 func getServiceCIDR(ctx context.Context, client kubernetes.Interface, namespace string, ipv6 bool) (string, error) {
 	clusterIP := "4.4.4.4"
 	if ipv6 {
@@ -0,0 +1,122 @@
+package servicecidr
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseCIDRs(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cidrs       string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "single-stack",
+			cidrs:    "10.96.0.0/12",
+			expected: []string{"10.96.0.0/12"},
+		},
+		{
+			name:     "dual-stack",
+			cidrs:    "fd00::/108,10.96.0.0/12",
+			expected: []string{"fd00::/108", "10.96.0.0/12"},
+		},
+		{
+			name:        "same ip family",
+			cidrs:       "10.96.0.0/12,10.112.0.0/12",
+			expectedErr: "dual-stack service CIDRs 10.96.0.0/12,10.112.0.0/12 need to be of different ip families",
+		},
+		{
+			name:        "too many",
+			cidrs:       "10.96.0.0/12,fd00::/108,10.112.0.0/12",
+			expectedErr: "expected at most two service CIDRs, got 3",
+		},
+		{
+			name:        "invalid",
+			cidrs:       "10.96.0.0",
+			expectedErr: "invalid CIDR address: 10.96.0.0",
+		},
+	}
+
+	for _, testCase := range testCases {
+		parsed, err := ParseCIDRs(testCase.cidrs)
+		if testCase.expectedErr != "" {
+			assert.Error(t, err, testCase.expectedErr, testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, testCase.name)
+		actual := []string{}
+		for _, ipNet := range parsed {
+			actual = append(actual, ipNet.String())
+		}
+		assert.DeepEqual(t, actual, testCase.expected)
+	}
+}
+
+func TestGetServiceCIDRFromAPIServer(t *testing.T) {
+	testCases := []struct {
+		name        string
+		pods        []runtime.Object
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "flag with value",
+			pods:     []runtime.Object{apiServerPod([]string{"kube-apiserver", "--secure-port=6443", "--service-cluster-ip-range=10.96.0.0/12,fd00::/108"}, nil)},
+			expected: "10.96.0.0/12,fd00::/108",
+		},
+		{
+			name:     "flag in args",
+			pods:     []runtime.Object{apiServerPod([]string{"kube-apiserver"}, []string{"--service-cluster-ip-range", "10.43.0.0/16"})},
+			expected: "10.43.0.0/16",
+		},
+		{
+			name:        "invalid flag",
+			pods:        []runtime.Object{apiServerPod([]string{"kube-apiserver", "--service-cluster-ip-range=10.96.0.0"}, nil)},
+			expectedErr: "invalid --service-cluster-ip-range flag of kube-apiserver pod kube-system/kube-apiserver: invalid CIDR address: 10.96.0.0",
+		},
+		{
+			name:        "no kube-apiserver pods",
+			expectedErr: "no kube-apiserver pod with a --service-cluster-ip-range flag found",
+		},
+	}
+
+	for _, testCase := range testCases {
+		client := fake.NewSimpleClientset(testCase.pods...)
+		cidr, err := getServiceCIDRFromAPIServer(context.Background(), client)
+		if testCase.expectedErr != "" {
+			assert.Error(t, err, testCase.expectedErr, testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, testCase.name)
+		assert.Equal(t, cidr, testCase.expected, testCase.name)
+	}
+}
+
+func apiServerPod(command, args []string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-apiserver",
+			Namespace: APIServerNamespace,
+			Labels:    map[string]string{"component": "kube-apiserver"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:    "kube-apiserver",
+					Command: command,
+					Args:    args,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,194 @@
+package taints
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Rule hides or rewrites host node taints that match the key and effect. A key ending with *
+// matches all keys with that prefix and an empty effect matches all effects.
+type Rule struct {
+	key    string
+	effect corev1.TaintEffect
+
+	hide bool
+
+	// newKey and newEffect replace the key and effect of a matching taint, if set
+	newKey    string
+	newEffect corev1.TaintEffect
+}
+
+// ParseRules parses hidden taints in the form key[:effect] and rewrites in the form
+// key[:effect]=[newKey][:newEffect]
+func ParseRules(hide []string, rewrite []string) ([]Rule, error) {
+	rules := []Rule{}
+	for _, h := range hide {
+		key, effect, err := parseTaintSelector(h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hidden node taint %s: %w", h, err)
+		} else if key == "" {
+			return nil, fmt.Errorf("invalid hidden node taint %s: key is empty", h)
+		}
+
+		rules = append(rules, Rule{key: key, effect: effect, hide: true})
+	}
+
+	for _, r := range rewrite {
+		from, to, found := strings.Cut(r, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid node taint rewrite %s: expected format key[:effect]=[newKey][:newEffect]", r)
+		}
+
+		key, effect, err := parseTaintSelector(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node taint rewrite %s: %w", r, err)
+		} else if key == "" {
+			return nil, fmt.Errorf("invalid node taint rewrite %s: key is empty", r)
+		}
+
+		newKey, newEffect, err := parseTaintSelector(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node taint rewrite %s: %w", r, err)
+		} else if strings.HasSuffix(newKey, "*") {
+			return nil, fmt.Errorf("invalid node taint rewrite %s: new key must not contain a wildcard", r)
+		} else if newKey == "" && newEffect == "" {
+			return nil, fmt.Errorf("invalid node taint rewrite %s: either new key or new effect is required", r)
+		}
+
+		rules = append(rules, Rule{key: key, effect: effect, newKey: newKey, newEffect: newEffect})
+	}
+
+	return rules, nil
+}
+
+func parseTaintSelector(selector string) (string, corev1.TaintEffect, error) {
+	key, effect, _ := strings.Cut(selector, ":")
+	switch corev1.TaintEffect(effect) {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return "", "", fmt.Errorf("unknown taint effect %s", effect)
+	}
+
+	return key, corev1.TaintEffect(effect), nil
+}
+
+func (r *Rule) matches(taint *corev1.Taint) bool {
+	if r.effect != "" && r.effect != taint.Effect {
+		return false
+	}
+
+	return translate.MatchesPattern(r.key, taint.Key)
+}
+
+// Translate applies the first matching rule to each host taint. Taints that end up
+// as duplicates after rewriting are only returned once.
+func Translate(taints []corev1.Taint, rules []Rule) []corev1.Taint {
+	if len(rules) == 0 || len(taints) == 0 {
+		return taints
+	}
+
+	translated := []corev1.Taint{}
+nextTaint:
+	for _, taint := range taints {
+		taint := *taint.DeepCopy()
+		for i := range rules {
+			if !rules[i].matches(&taint) {
+				continue
+			} else if rules[i].hide {
+				continue nextTaint
+			}
+
+			if rules[i].newKey != "" {
+				taint.Key = rules[i].newKey
+			}
+			if rules[i].newEffect != "" {
+				taint.Effect = rules[i].newEffect
+			}
+			break
+		}
+
+		for _, existing := range translated {
+			if existing.MatchTaint(&taint) {
+				continue nextTaint
+			}
+		}
+		translated = append(translated, taint)
+	}
+
+	return translated
+}
+
+// TranslateTolerations returns the host tolerations that are needed so that the pod tolerates the host taints
+// whose rewritten form the given virtual tolerations tolerate. Rules with a wildcard key and a new key are
+// skipped, as a toleration can't match a key prefix.
+func TranslateTolerations(tolerations []corev1.Toleration, rules []Rule) []corev1.Toleration {
+	newTolerations := []corev1.Toleration{}
+	for i := range rules {
+		if rules[i].hide || (rules[i].newKey != "" && strings.HasSuffix(rules[i].key, "*")) {
+			continue
+		}
+
+		for _, toleration := range tolerations {
+			pToleration, ok := rules[i].translateToleration(toleration)
+			if !ok || containsToleration(tolerations, pToleration) || containsToleration(newTolerations, pToleration) {
+				continue
+			}
+
+			newTolerations = append(newTolerations, pToleration)
+		}
+	}
+
+	return newTolerations
+}
+
+// translateToleration returns the toleration for the host taints of the rule, if the toleration tolerates
+// their rewritten form
+func (r *Rule) translateToleration(toleration corev1.Toleration) (corev1.Toleration, bool) {
+	// tolerations without a key already tolerate all host taints
+	if toleration.Key == "" {
+		return corev1.Toleration{}, false
+	}
+
+	if r.newKey != "" {
+		if toleration.Key != r.newKey {
+			return corev1.Toleration{}, false
+		}
+
+		toleration.Key = r.key
+	} else if !translate.MatchesPattern(r.key, toleration.Key) {
+		return corev1.Toleration{}, false
+	}
+
+	if r.newEffect != "" {
+		if toleration.Effect != "" && toleration.Effect != r.newEffect {
+			return corev1.Toleration{}, false
+		} else if toleration.Effect == "" && r.newKey == "" {
+			// the toleration already tolerates the host taints with all effects
+			return corev1.Toleration{}, false
+		}
+
+		toleration.Effect = r.effect
+	} else if r.effect != "" && toleration.Effect != "" && toleration.Effect != r.effect {
+		return corev1.Toleration{}, false
+	}
+
+	// toleration seconds are only allowed for NoExecute tolerations
+	if toleration.Effect != corev1.TaintEffectNoExecute {
+		toleration.TolerationSeconds = nil
+	}
+
+	return toleration, true
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(&toleration) {
+			return true
+		}
+	}
+
+	return false
+}
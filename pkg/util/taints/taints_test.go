@@ -0,0 +1,144 @@
+package taints
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTranslateTaints(t *testing.T) {
+	taints := []corev1.Taint{
+		{Key: "node.example.com/internal", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "node.example.com/maintenance", Effect: corev1.TaintEffectNoExecute},
+		{Key: "dedicated", Value: "tenant-a", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+	}
+	testCases := []struct {
+		name           string
+		hide           []string
+		rewrite        []string
+		expectedTaints []corev1.Taint
+		expectedError  bool
+	}{
+		{
+			name:           "No rules",
+			expectedTaints: taints,
+		},
+		{
+			name: "Hide by prefix and effect",
+			hide: []string{"node.example.com/*:NoSchedule"},
+			expectedTaints: []corev1.Taint{
+				{Key: "node.example.com/maintenance", Effect: corev1.TaintEffectNoExecute},
+				{Key: "dedicated", Value: "tenant-a", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		{
+			name:    "Rewrite key and effect",
+			hide:    []string{"node.example.com/*"},
+			rewrite: []string{"dedicated:NoSchedule=:PreferNoSchedule", "gpu=example.com/gpu"},
+			expectedTaints: []corev1.Taint{
+				{Key: "dedicated", Value: "tenant-a", Effect: corev1.TaintEffectPreferNoSchedule},
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		{
+			name:    "Remove duplicates after rewriting",
+			hide:    []string{"dedicated", "gpu"},
+			rewrite: []string{"node.example.com/*=node.example.com/hidden:NoSchedule"},
+			expectedTaints: []corev1.Taint{
+				{Key: "node.example.com/hidden", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		{
+			name:          "Unknown effect",
+			hide:          []string{"gpu:Never"},
+			expectedError: true,
+		},
+		{
+			name:          "Rewrite without target",
+			rewrite:       []string{"gpu="},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		rules, err := ParseRules(testCase.hide, testCase.rewrite)
+		if testCase.expectedError {
+			assert.Assert(t, err != nil, "expected error in test case %s", testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.Assert(t, cmp.DeepEqual(Translate(taints, rules), testCase.expectedTaints), "unexpected taints in test case %s", testCase.name)
+	}
+}
+
+func TestTranslateTolerations(t *testing.T) {
+	rules, err := ParseRules([]string{"node.example.com/*"}, []string{"dedicated:NoSchedule=:PreferNoSchedule", "gpu=example.com/gpu", "spot:NoExecute=example.com/spot:NoSchedule", "drain:NoSchedule=:NoExecute"})
+	assert.NilError(t, err)
+
+	tolerationSeconds := int64(60)
+	testCases := []struct {
+		name                string
+		tolerations         []corev1.Toleration
+		expectedTolerations []corev1.Toleration
+	}{
+		{
+			name:                "No tolerations",
+			expectedTolerations: []corev1.Toleration{},
+		},
+		{
+			name: "Rewritten key",
+			tolerations: []corev1.Toleration{
+				{Key: "example.com/gpu", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedTolerations: []corev1.Toleration{
+				{Key: "gpu", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		{
+			name: "Rewritten effect",
+			tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectPreferNoSchedule},
+				{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+			},
+			expectedTolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		{
+			name: "Rewritten key and effect",
+			tolerations: []corev1.Toleration{
+				{Key: "example.com/spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedTolerations: []corev1.Toleration{
+				{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+		{
+			name: "Rewritten effect drops toleration seconds",
+			tolerations: []corev1.Toleration{
+				{Key: "drain", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &tolerationSeconds},
+			},
+			expectedTolerations: []corev1.Toleration{
+				{Key: "drain", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		{
+			name: "Already tolerated",
+			tolerations: []corev1.Toleration{
+				{Key: "example.com/gpu", Operator: corev1.TolerationOpExists},
+				{Key: "gpu", Operator: corev1.TolerationOpExists},
+				{Operator: corev1.TolerationOpExists},
+			},
+			expectedTolerations: []corev1.Toleration{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Assert(t, cmp.DeepEqual(TranslateTolerations(testCase.tolerations, rules), testCase.expectedTolerations), "unexpected tolerations in test case %s", testCase.name)
+	}
+}
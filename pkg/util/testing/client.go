@@ -8,6 +8,7 @@ import (
 
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -28,6 +29,16 @@ func NewScheme() *runtime.Scheme {
 	if err != nil {
 		panic(err)
 	}
+
+	// gateway api objects are synced as unstructured objects
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "Gateway"},
+		{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "HTTPRoute"},
+		{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "GRPCRoute"},
+	} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
 	return scheme
 }
 
@@ -81,6 +92,9 @@ func (fc *FakeIndexClient) updateIndices(ctx context.Context, obj runtime.Object
 	if err != nil {
 		return err
 	}
+	if unstructuredList, ok := list.(*unstructured.UnstructuredList); ok {
+		unstructuredList.SetGroupVersionKind(listGvk)
+	}
 
 	err = fc.Client.List(ctx, list.(client.ObjectList))
 	if err != nil {
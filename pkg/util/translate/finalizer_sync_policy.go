@@ -0,0 +1,106 @@
+package translate
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// FinalizerSyncDown propagates finalizers of virtual objects to the physical objects
+	FinalizerSyncDown = "down"
+	// FinalizerSyncUp propagates finalizers of physical objects to the virtual objects
+	FinalizerSyncUp = "up"
+)
+
+// FinalizerSyncPolicy controls for a resource which finalizers are propagated between virtual and
+// physical objects. All other finalizers are stripped during sync. A finalizer ending with * matches
+// all finalizers with that prefix.
+type FinalizerSyncPolicy struct {
+	// Down are the finalizers of virtual controllers that are propagated to the physical objects, so
+	// the physical objects are not deleted before the virtual controllers are done
+	Down []string
+	// Up are the finalizers of host controllers that are propagated to the virtual objects, so the
+	// virtual objects are not deleted before the host controllers are done
+	Up []string
+}
+
+// FinalizerSyncPolicies are the finalizer sync policies by resource, e.g. services. The policy of
+// AllResources applies to all resources in addition to their own policy.
+var FinalizerSyncPolicies map[string]*FinalizerSyncPolicy
+
+// ParseFinalizerSyncPolicies parses policies in the form resource:direction=finalizer
+func ParseFinalizerSyncPolicies(policies []string) (map[string]*FinalizerSyncPolicy, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	parsed := map[string]*FinalizerSyncPolicy{}
+	for _, policy := range policies {
+		resource, direction, finalizer, err := splitPolicy(policy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid finalizer sync policy %s: %w", policy, err)
+		}
+
+		if parsed[resource] == nil {
+			parsed[resource] = &FinalizerSyncPolicy{}
+		}
+		switch direction {
+		case FinalizerSyncDown:
+			parsed[resource].Down = append(parsed[resource].Down, finalizer)
+		case FinalizerSyncUp:
+			parsed[resource].Up = append(parsed[resource].Up, finalizer)
+		default:
+			return nil, fmt.Errorf("invalid finalizer sync policy %s, direction must be one of %s or %s", policy, FinalizerSyncDown, FinalizerSyncUp)
+		}
+	}
+
+	return parsed, nil
+}
+
+// FinalizerSyncPolicyFor returns the finalizer sync policy for obj or nil if there is none
+func FinalizerSyncPolicyFor(obj client.Object) *FinalizerSyncPolicy {
+	if len(FinalizerSyncPolicies) == 0 || obj == nil {
+		return nil
+	}
+
+	all := FinalizerSyncPolicies[AllResources]
+	own := FinalizerSyncPolicies[resourceOf(obj)]
+	if all == nil {
+		return own
+	} else if own == nil {
+		return all
+	}
+
+	return &FinalizerSyncPolicy{
+		Down: append(append([]string{}, all.Down...), own.Down...),
+		Up:   append(append([]string{}, all.Up...), own.Up...),
+	}
+}
+
+// IsDown returns if the finalizer is propagated to physical objects
+func (p *FinalizerSyncPolicy) IsDown(finalizer string) bool {
+	return p != nil && MatchesKey(p.Down, finalizer)
+}
+
+// IsUp returns if the finalizer is propagated to virtual objects
+func (p *FinalizerSyncPolicy) IsUp(finalizer string) bool {
+	return p != nil && MatchesKey(p.Up, finalizer)
+}
+
+// DownFinalizers returns the finalizers of vObj that are propagated to its physical object
+func DownFinalizers(vObj client.Object) []string {
+	policy := FinalizerSyncPolicyFor(vObj)
+	if policy == nil {
+		return nil
+	}
+
+	var finalizers []string
+	for _, finalizer := range vObj.GetFinalizers() {
+		if policy.IsDown(finalizer) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+
+	return finalizers
+}
@@ -0,0 +1,208 @@
+package translate
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelSyncDown syncs virtual labels to physical objects as is, in addition to their translated version
+	LabelSyncDown = "down"
+	// LabelSyncUp syncs labels host tooling adds to physical objects back to the virtual objects
+	LabelSyncUp = "up"
+	// LabelSyncImmutable keeps the value labels had when the physical object was created
+	LabelSyncImmutable = "immutable"
+
+	// AllResources is the resource of label sync policies that apply to all resources
+	AllResources = "*"
+)
+
+// LabelSyncPolicy controls for a resource how labels are synced between virtual and physical objects.
+// A key ending with * matches all labels with that prefix.
+type LabelSyncPolicy struct {
+	// Down are the keys of virtual labels that are synced to physical objects as is
+	Down []string
+	// Up are the keys of labels that host tooling adds to physical objects. They are synced back
+	// to the virtual objects and never overwritten in the host cluster.
+	Up []string
+	// Immutable are the keys of virtual labels whose value on the physical object is not changed
+	// after the physical object was created
+	Immutable []string
+}
+
+// LabelSyncPolicies are the label sync policies by resource, e.g. pods. The policy of AllResources
+// applies to all resources in addition to their own policy.
+var LabelSyncPolicies map[string]*LabelSyncPolicy
+
+// ParseLabelSyncPolicies parses policies in the form resource:direction=key
+func ParseLabelSyncPolicies(policies []string) (map[string]*LabelSyncPolicy, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	parsed := map[string]*LabelSyncPolicy{}
+	for _, policy := range policies {
+		resource, direction, key, err := splitPolicy(policy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label sync policy %s: %w", policy, err)
+		}
+
+		if parsed[resource] == nil {
+			parsed[resource] = &LabelSyncPolicy{}
+		}
+		switch direction {
+		case LabelSyncDown:
+			parsed[resource].Down = append(parsed[resource].Down, key)
+		case LabelSyncUp:
+			parsed[resource].Up = append(parsed[resource].Up, key)
+		case LabelSyncImmutable:
+			parsed[resource].Immutable = append(parsed[resource].Immutable, key)
+		default:
+			return nil, fmt.Errorf("invalid label sync policy %s, direction must be one of %s, %s or %s", policy, LabelSyncDown, LabelSyncUp, LabelSyncImmutable)
+		}
+	}
+
+	return parsed, nil
+}
+
+// splitPolicy splits a policy in the form resource:direction=key
+func splitPolicy(policy string) (string, string, string, error) {
+	resourceAndDirection, key := Split(policy, "=")
+	resource, direction := Split(resourceAndDirection, ":")
+	resource, direction, key = strings.ToLower(strings.TrimSpace(resource)), strings.TrimSpace(direction), strings.TrimSpace(key)
+	if resource == "" || direction == "" || key == "" {
+		return "", "", "", fmt.Errorf("expected resource:direction=key")
+	} else if errs := validation.IsQualifiedName(strings.TrimSuffix(strings.TrimSuffix(key, "*"), "/") + "x"); key != "*" && len(errs) > 0 {
+		return "", "", "", fmt.Errorf("invalid key %s: %s", key, strings.Join(errs, ", "))
+	}
+
+	return resource, direction, key, nil
+}
+
+// LabelSyncPolicyFor returns the label sync policy for obj or nil if there is none
+func LabelSyncPolicyFor(obj client.Object) *LabelSyncPolicy {
+	if len(LabelSyncPolicies) == 0 || obj == nil {
+		return nil
+	}
+
+	all := LabelSyncPolicies[AllResources]
+	own := LabelSyncPolicies[resourceOf(obj)]
+	if all == nil {
+		return own
+	} else if own == nil {
+		return all
+	}
+
+	return &LabelSyncPolicy{
+		Down:      append(append([]string{}, all.Down...), own.Down...),
+		Up:        append(append([]string{}, all.Up...), own.Up...),
+		Immutable: append(append([]string{}, all.Immutable...), own.Immutable...),
+	}
+}
+
+// IsUp returns if the label key is synced back to the virtual object
+func (p *LabelSyncPolicy) IsUp(key string) bool {
+	return p != nil && MatchesKey(p.Up, key)
+}
+
+// resourceOf returns the lower case plural resource of obj, e.g. pods
+func resourceOf(obj client.Object) string {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Kind == "" {
+		gvks, _, err := clientgoscheme.Scheme.ObjectKinds(obj)
+		if err != nil || len(gvks) == 0 {
+			return ""
+		}
+
+		gvk = gvks[0]
+	}
+
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	return resource.Resource
+}
+
+// applyLabelSyncPolicy returns a copy of the translated labels of vObj with the label sync policy of
+// vObj applied. convertKey translates a virtual label key into its physical key.
+func applyLabelSyncPolicy(vObj, pObj client.Object, labels map[string]string, convertKey func(string) string) map[string]string {
+	policy := LabelSyncPolicyFor(vObj)
+	if policy == nil {
+		return labels
+	}
+
+	newLabels := map[string]string{}
+	for k, v := range labels {
+		if policy.IsUp(k) {
+			continue
+		}
+
+		newLabels[k] = v
+	}
+
+	vLabels := vObj.GetLabels()
+	for k, v := range vLabels {
+		if MatchesKey(policy.Down, k) && !policy.IsUp(k) {
+			newLabels[k] = v
+		}
+	}
+	if pObj == nil {
+		return newLabels
+	}
+
+	// labels owned by host tooling are kept
+	pLabels := pObj.GetLabels()
+	for k, v := range pLabels {
+		if policy.IsUp(k) {
+			newLabels[k] = v
+		}
+	}
+
+	// immutable labels keep the value they had when the physical object was created
+	immutableKeys := map[string]bool{}
+	for _, key := range policy.Immutable {
+		if !strings.HasSuffix(key, "*") {
+			immutableKeys[key] = true
+		}
+	}
+	for _, m := range []map[string]string{vLabels, pLabels} {
+		for k := range m {
+			if MatchesKey(policy.Immutable, k) {
+				immutableKeys[k] = true
+			}
+		}
+	}
+	for k := range immutableKeys {
+		for _, pKey := range []string{k, convertKey(k)} {
+			if value, ok := pLabels[pKey]; ok {
+				newLabels[pKey] = value
+			}
+		}
+	}
+
+	return newLabels
+}
+
+// MatchesKey returns if key is matched by one of the patterns. A pattern ending with * matches all
+// keys with that prefix.
+func MatchesKey(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if MatchesPattern(pattern, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchesPattern returns if key equals the pattern, or if the pattern ends with * and key has that prefix
+func MatchesPattern(pattern string, key string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(key, prefix)
+	}
+
+	return key == pattern
+}
@@ -15,6 +15,7 @@ import (
 )
 
 var _ Translator = &multiNamespace{}
+var _ NamespaceSelectorTranslator = &multiNamespace{}
 
 func NewMultiNamespaceTranslator(currentNamespace string) Translator {
 	return &multiNamespace{
@@ -133,6 +134,9 @@ func (s *multiNamespace) TranslateLabelsCluster(vObj client.Object, pObj client.
 		}
 	}
 	newLabels[MarkerLabel] = SafeConcatName(s.currentNamespace, "x", Suffix)
+	if vObj != nil {
+		newLabels = applyLabelSyncPolicy(vObj, pObj, newLabels, s.convertLabelKey)
+	}
 	return newLabels
 }
 
@@ -162,6 +166,20 @@ func (s *multiNamespace) TranslateLabelSelectorCluster(labelSelector *metav1.Lab
 	return newLabelSelector
 }
 
+func (s *multiNamespace) TranslateNamespaceSelector(labelSelector *metav1.LabelSelector) *metav1.LabelSelector {
+	if labelSelector == nil {
+		return nil
+	}
+
+	newLabelSelector := s.TranslateLabelSelectorCluster(labelSelector)
+	if newLabelSelector.MatchLabels == nil {
+		newLabelSelector.MatchLabels = map[string]string{}
+	}
+	// only select namespaces that were synced by this vcluster
+	newLabelSelector.MatchLabels[MarkerLabel] = SafeConcatName(s.currentNamespace, "x", Suffix)
+	return newLabelSelector
+}
+
 func (s *multiNamespace) LegacyGetTargetNamespace() (string, error) {
 	return "", fmt.Errorf("unsupported feature in multi-namespace mode")
 }
@@ -190,6 +208,8 @@ func (s *multiNamespace) ApplyAnnotations(src client.Object, to client.Object, e
 	if to != nil {
 		toAnnotations = to.GetAnnotations()
 	}
+	// annotations that are synced back are owned by the target
+	excluded = append(excluded, syncBackAnnotationKeys(src.GetAnnotations(), toAnnotations)...)
 
 	retMap := applyAnnotations(src.GetAnnotations(), toAnnotations, excluded...)
 	retMap[NameAnnotation] = src.GetName()
@@ -207,7 +227,7 @@ func (s *multiNamespace) ApplyLabels(src client.Object, dest client.Object, sync
 	if fromLabels == nil {
 		fromLabels = map[string]string{}
 	}
-	return s.TranslateLabels(fromLabels, src.GetNamespace(), syncedLabels)
+	return applyLabelSyncPolicy(src, dest, s.TranslateLabels(fromLabels, src.GetNamespace(), syncedLabels), s.ConvertLabelKey)
 }
 
 func (s *multiNamespace) TranslateLabels(fromLabels map[string]string, vNamespace string, syncedLabels []string) map[string]string {
@@ -223,6 +243,7 @@ func (s *multiNamespace) SetupMetadataWithName(vObj client.Object, translator Ph
 
 	// reset metadata & translate name and namespace
 	ResetObjectMetadata(m)
+	m.SetFinalizers(DownFinalizers(vObj))
 	m.SetName(translator(m.GetName(), vObj))
 	if vObj.GetNamespace() != "" {
 		m.SetNamespace(s.PhysicalNamespace(vObj.GetNamespace()))
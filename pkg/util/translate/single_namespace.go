@@ -121,6 +121,9 @@ func (s *singleNamespace) TranslateLabelsCluster(vObj client.Object, pObj client
 		}
 	}
 	newLabels[MarkerLabel] = SafeConcatName(s.targetNamespace, "x", Suffix)
+	if vObj != nil {
+		newLabels = applyLabelSyncPolicy(vObj, pObj, newLabels, s.convertNamespacedLabelKey)
+	}
 	return newLabels
 }
 
@@ -178,6 +181,8 @@ func (s *singleNamespace) ApplyAnnotations(src client.Object, to client.Object,
 	if to != nil {
 		toAnnotations = to.GetAnnotations()
 	}
+	// annotations that are synced back are owned by the target
+	excluded = append(excluded, syncBackAnnotationKeys(src.GetAnnotations(), toAnnotations)...)
 
 	retMap := applyAnnotations(src.GetAnnotations(), toAnnotations, excluded...)
 	retMap[NameAnnotation] = src.GetName()
@@ -205,7 +210,7 @@ func (s *singleNamespace) ApplyLabels(src client.Object, dest client.Object, syn
 		}
 	}
 
-	return newLabels
+	return applyLabelSyncPolicy(src, dest, newLabels, s.ConvertLabelKey)
 }
 
 func (s *singleNamespace) TranslateLabels(fromLabels map[string]string, vNamespace string, syncedLabels []string) map[string]string {
@@ -242,6 +247,7 @@ func (s *singleNamespace) SetupMetadataWithName(vObj client.Object, translator P
 
 	// reset metadata & translate name and namespace
 	ResetObjectMetadata(m)
+	m.SetFinalizers(DownFinalizers(vObj))
 	m.SetName(translator(m.GetName(), vObj))
 	if vObj.GetNamespace() != "" {
 		m.SetNamespace(s.PhysicalNamespace(vObj.GetNamespace()))
@@ -38,10 +38,42 @@ var (
 
 const (
 	SkipBacksyncInMultiNamespaceMode = "vcluster.loft.sh/skip-backsync"
+
+	// DeleteAfterAnnotation is set on host namespaces whose virtual namespace was deleted and holds
+	// the time after which the host namespace is deleted
+	DeleteAfterAnnotation = "vcluster.loft.sh/delete-after"
+	// RetainAnnotation is set on host namespaces whose objects are kept until the namespace is deleted
+	RetainAnnotation = "vcluster.loft.sh/retain"
+	// OrphanedLabel is set on host namespaces that are kept after their virtual namespace was deleted
+	OrphanedLabel = "vcluster.loft.sh/orphaned"
 )
 
 var Owner client.Object
 
+// SyncBackAnnotations are the keys of annotations that host controllers add to physical objects.
+// They are synced back to the virtual objects and never overwritten in the host cluster. A key
+// ending with * matches all annotations with that prefix.
+var SyncBackAnnotations []string
+
+// IsSyncBackAnnotation returns if the annotation key is matched by SyncBackAnnotations
+func IsSyncBackAnnotation(key string) bool {
+	return MatchesKey(SyncBackAnnotations, key)
+}
+
+// syncBackAnnotationKeys returns the keys of the given annotations that are synced back
+func syncBackAnnotationKeys(annotations ...map[string]string) []string {
+	keys := []string{}
+	for _, m := range annotations {
+		for key := range m {
+			if IsSyncBackAnnotation(key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
 func GetOwnerReference(object client.Object) []metav1.OwnerReference {
 	if Owner == nil || Owner.GetName() == "" || Owner.GetUID() == "" {
 		return nil
@@ -15,6 +15,14 @@ var (
 
 var Default Translator = &singleNamespace{}
 
+// NamespaceSelectorTranslator is implemented by translators that sync virtual namespaces into
+// their own physical namespaces
+type NamespaceSelectorTranslator interface {
+	// TranslateNamespaceSelector translates a selector of virtual namespaces into a selector of the
+	// physical namespaces they are synced to
+	TranslateNamespaceSelector(labelSelector *metav1.LabelSelector) *metav1.LabelSelector
+}
+
 type Translator interface {
 	// SingleNamespaceTarget signals if we sync all objects into a single namespace
 	SingleNamespaceTarget() bool
@@ -0,0 +1,127 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+const dialTimeout = 30 * time.Second
+
+// Dialer opens connections through an HTTP CONNECT tunnel server, such as a
+// konnectivity server running in http-connect mode. Every connection is an
+// outbound connection from the syncer to the tunnel server, which then connects
+// to the requested address from within the host network.
+type Dialer struct {
+	address   string
+	tlsConfig *tls.Config
+}
+
+// NewDialer creates a new dialer for the tunnel server at address (host:port). If any
+// of the files are set, the connection to the tunnel server is secured with TLS.
+func NewDialer(address, caFile, certFile, keyFile string) (*Dialer, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("invalid tunnel server address %s: %w", address, err)
+	}
+
+	dialer := &Dialer{address: address}
+	if caFile != "" || certFile != "" || keyFile != "" {
+		tlsConfig, err := transport.TLSConfigFor(&transport.Config{
+			TLS: transport.TLSConfig{
+				CAFile:   caFile,
+				CertFile: certFile,
+				KeyFile:  keyFile,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create tunnel tls config: %w", err)
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+
+		host, _, _ := net.SplitHostPort(address)
+		tlsConfig.ServerName = host
+		dialer.tlsConfig = tlsConfig
+	}
+
+	return dialer, nil
+}
+
+// DialContext connects to addr through the tunnel server
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if network != "tcp" && network != "tcp4" && network != "tcp6" {
+		return nil, fmt.Errorf("unsupported network %s for tunnel", network)
+	}
+
+	netDialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := netDialer.DialContext(ctx, "tcp", d.address)
+	if err != nil {
+		return nil, fmt.Errorf("dial tunnel server %s: %w", d.address, err)
+	}
+	if d.tlsConfig != nil {
+		tlsConn := tls.Client(conn, d.tlsConfig.Clone())
+		err = tlsConn.HandshakeContext(ctx)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("tls handshake with tunnel server %s: %w", d.address, err)
+		}
+		conn = tlsConn
+	}
+
+	// abort the handshake if the context is done before the tunnel is established
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(dialTimeout))
+	}
+
+	_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", addr, addr)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("send connect request: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("read connect response: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("tunnel server refused connection to %s: %s", addr, resp.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// WrapConfig returns a copy of config that dials through the tunnel
+func (d *Dialer) WrapConfig(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Dial = d.DialContext
+	return config
+}
+
+// bufferedConn returns data the tunnel server already sent after the connect
+// response before reading from the connection again
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
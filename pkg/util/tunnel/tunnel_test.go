@@ -0,0 +1,79 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+// startTunnelServer starts a minimal HTTP CONNECT server that accepts connections
+// to allowed and answers them with an echo
+func startTunnelServer(t *testing.T, allowed string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				req, err := http.ReadRequest(reader)
+				if err != nil {
+					return
+				}
+				if req.Method != http.MethodConnect || req.Host != allowed {
+					_, _ = io.WriteString(conn, "HTTP/1.1 403 Forbidden\r\n\r\n")
+					return
+				}
+
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				_, _ = io.Copy(conn, reader)
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestDialContext(t *testing.T) {
+	address := startTunnelServer(t, "10.0.0.1:10250")
+	dialer, err := NewDialer(address, "", "", "")
+	assert.NilError(t, err)
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "10.0.0.1:10250")
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "ping")
+	assert.NilError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	assert.NilError(t, err)
+	assert.Equal(t, string(buf), "ping")
+
+	_, err = dialer.DialContext(context.Background(), "tcp", "10.0.0.2:10250")
+	assert.ErrorContains(t, err, "403 Forbidden")
+
+	_, err = dialer.DialContext(context.Background(), "udp", "10.0.0.1:53")
+	assert.ErrorContains(t, err, "unsupported network")
+}
+
+func TestNewDialer(t *testing.T) {
+	_, err := NewDialer("konnectivity-server", "", "", "")
+	assert.ErrorContains(t, err, "invalid tunnel server address")
+
+	_, err = NewDialer("konnectivity-server:8131", "/does/not/exist", "", "")
+	assert.ErrorContains(t, err, "create tunnel tls config")
+}